@@ -12,13 +12,27 @@ type Config struct {
 	LoopDuration         time.Duration
 	LoopDurationOffset   time.Duration
 	LoopStartingState    int
+	StepTicks            int // Ticks run by a single step of the paused simulation
 	ReflexDuration       time.Duration
 	StdLogs              bool
 	TrainWaitInStation   time.Duration
 	TenjinEnabled        bool
 	TenjinTickRate       time.Duration
+	TenjinReportRate     time.Duration // How often Tenjin logs and prints the full metrics
+	TenjinEventBuffer    int           // Events Tenjin holds between ticks, later ones are dropped
+	PredictionRate       time.Duration // How often the trains predict their arrivals and the predictions are gathered
 	PassengerSpawnRate   time.Duration
 	PassengersPerStation int
+	Seed                 int64 // Seed of the random choices of the simulation, 0 picks one at startup
+	RenderTPS            int   // Display updates per second, the simulation keeps its own rate
+	RenderOnChange       bool  // Only redraw the screen when the simulation moved or there was input
+	MaxPassengers        int   // Spawning pauses while this many passengers travel, 0 is unlimited
+	MemoryLimit          int64 // Soft memory limit of the Go runtime in bytes, 0 leaves it unset
+
+	// Runtime profile, "default" or "low_power" for small boards like the
+	// Raspberry Pi Zero, which applies LowPower on top of the rest of the config
+	Profile  string
+	LowPower LowPowerConfig
 
 	// Real-world metrics scaling
 	PixelsPerMeter      float64 // Scale factor: 1 pixel = X meters
@@ -27,6 +41,173 @@ type Config struct {
 	// Simulation time clock
 	SimulationStartHour int     // Starting hour (0-23), e.g., 8 for 8:00 AM
 	SimulationStartMin  int     // Starting minute (0-59)
+
+	// Dates and kinds of service of the simulated days
+	Calendar CalendarConfig
+
+	// Refuse to start when the network data has errors
+	ValidateNetworkOnStartup bool
+
+	// OD demand CSV file, the demand table in the database is used when empty
+	DemandFile string
+
+	// How waiting passengers get on a train: "fifo" boards the platform queue in
+	// order, "random_door" boards whoever is closest to a door
+	BoardingPolicy string
+
+	// Locale of the passenger names, e.g. "es_DO" for Santo Domingo or "en_US"
+	NameLocale string
+
+	// Simulated days the journey timelines of the passengers are kept in the database
+	TimelineDays int
+
+	// Weights of the factors that change how passengers feel
+	Sentiment SentimentConfig
+
+	// When waiting passengers give up on the metro
+	Abandonment AbandonmentConfig
+
+	// Population of passengers that travel home-work-home every day
+	Commuters CommuterConfig
+
+	// Default size of the stations, used when a station doesn't set its own
+	PlatformCapacity int     // Passengers the platforms of a station hold
+	GateThroughput   float64 // Passengers per minute the fare gates let in
+
+	// Walks inside stations and interchanges
+	Walking WalkingConfig
+
+	// What passengers pay and what running the trains costs
+	Fares FareConfig
+
+	// Crowds that special events bring to their stations
+	Events EventConfig
+
+	// When Tenjin recommends interventions
+	Intelligence IntelligenceConfig
+
+	// Safety limits of the commands sent to the trains
+	Commands CommandConfig
+
+	// How Tenjin evens out the headways of the lines
+	Headway HeadwayConfig
+}
+
+// LowPowerConfig trades smoothness for CPU and memory: the display updates less
+// often and only redraws on changes, Tenjin runs and reports less often and the
+// number of passengers and the memory of the process are capped.
+type LowPowerConfig struct {
+	RenderTPS         int
+	TenjinTickRate    time.Duration
+	TenjinReportRate  time.Duration
+	TenjinEventBuffer int
+	PredictionRate    time.Duration
+	MaxPassengers     int
+	MemoryLimit       int64
+}
+
+// CalendarConfig sets the date of the first simulated day and the holidays. Dates
+// are written as 2006-01-02.
+type CalendarConfig struct {
+	StartDate   string             // Date of the first simulated day
+	Holidays    []string           // Dates that run the holiday service
+	DemandScale map[string]float64 // Demand of each day type as a share of the weekday one, used when it has no demand of its own
+}
+
+// SentimentConfig weighs the factors of the sentiment model. Weights are points
+// of sentiment (0-100) per evaluation, unless stated otherwise.
+type SentimentConfig struct {
+	Interval       time.Duration // How often the sentiment of a passenger is evaluated
+	DefaultHeadway time.Duration // Advertised headway when the timetable doesn't give one
+	Wait           float64       // Lost per headway waited beyond half the advertised headway
+	Crowding       float64       // Lost on a full train or platform, scaled from the persona tolerance
+	NoSeat         float64       // Lost while standing
+	Transfer       float64       // Lost once per transfer
+	Delay          float64       // Lost per minute the train is behind schedule
+	DeniedBoarding float64       // Lost every time a full train leaves the passenger behind
+	GateQueue      float64       // Lost while queueing outside the fare gates
+	Recovery       float64       // Gained while the service is good
+}
+
+// AbandonmentConfig sets when passengers leave the station without travelling. Both
+// limits are for an average persona and scale with the patience of each persona.
+type AbandonmentConfig struct {
+	BalkQueueLength int           // Passengers on the platform at which arriving passengers don't even wait, 0 disables balking
+	MaxWait         time.Duration // Simulation time waited before giving up, 0 disables it
+}
+
+// CommuterConfig sets the recurring commuters and how they learn from their trips.
+type CommuterConfig struct {
+	Population     int           // Commuters created when the database has none
+	Memory         float64       // Weight (0-1) of the last trip in the baseline satisfaction
+	BadTrip        float64       // Trip sentiment under which the commuter changes their habits
+	DepartureShift time.Duration // How much earlier they leave after a bad trip
+}
+
+// WalkingConfig sets how long passengers walk inside stations, used when a station
+// or a complex doesn't set its own times.
+type WalkingConfig struct {
+	Entrance time.Duration // From the fare gates to the platforms
+	Transfer time.Duration // Between the platforms of two stations of a complex
+	StopTime time.Duration // Average ride from one stop to the next, weighs walks against stops when planning
+}
+
+// FareConfig sets how passengers pay at the gates, amounts are in the local currency.
+// The base fare is charged when entering, the rest of the fare when leaving.
+type FareConfig struct {
+	Model            string             // "flat", "distance" or "zonal"
+	Base             float64            // Charged when entering
+	PerKm            float64            // Distance fares, per km travelled
+	PerZone          float64            // Zonal fares, per zone crossed
+	TransferDiscount float64            // Share (0-1) of the base fare waived on every transfer
+	Concessions      map[string]float64 // Share of the fare paid by each persona kind, 1 when not listed
+	TrainHourCost    float64            // Operating cost of a train in service for an hour
+}
+
+// EventConfig shapes the surges of special events. Arrivals build up before the
+// start and departures fade out after the end.
+type EventConfig struct {
+	MetroShare  float64       // Share (0-1) of the attendance that travels by metro
+	ArrivalLead time.Duration // How long before the start attendees begin to arrive
+	Dispersal   time.Duration // How long after the end it takes the crowd to leave
+}
+
+// IntelligenceConfig sets when Tenjin detects a problem and recommends an
+// intervention. Headway shares are of the timetable headway of the platform.
+type IntelligenceConfig struct {
+	Crowding     float64       // Crowding level of the platforms (1 is at capacity) from which a station is overcrowded
+	Bunching     float64       // Share of the headway under which a train is bunched with the one ahead
+	Gap          float64       // Share of the headway over which the gap behind a train is too long
+	MaxHold      time.Duration // Longest hold recommended
+	LatenessGain time.Duration // Average delay gained on a segment from which it is chronically late
+	LatenessRuns int           // Runs over a segment averaged before it is judged
+	HighLoad     float64       // Passengers per place on a line from which it needs another train
+	LowLoad      float64       // Passengers per place on a line under which it can spare a train
+	LoadWindow   time.Duration // Simulation time the load of a line is averaged over
+}
+
+// CommandConfig sets what the trains accept from Tenjin, the operator and external
+// agents. Commands outside these limits are rejected.
+type CommandConfig struct {
+	MaxHold    time.Duration // Longest hold at a station
+	MinSpeed   float64       // Lowest speed cap in km/h
+	Separation float64       // Meters a train entering service keeps from a train arriving at its station
+	Buffer     int           // Commands a train holds until its next tick, the rest are rejected
+}
+
+// HeadwayConfig sets the headway regularisation strategy of Tenjin. It holds trains
+// at their next stop and slows down the ones closing on the train ahead, so the
+// trains of a line converge to even spacing, or to the timetable headway when holds
+// can stretch the round trip to it. The holds and speed caps are sent as commands,
+// within the limits of CommandConfig.
+type HeadwayConfig struct {
+	Enabled   bool          // Regularise from startup, it can be switched on and off at runtime
+	Gain      float64       // Share (0-1) of the headway error corrected at every stop
+	MinHold   time.Duration // Shortest hold sent, smaller corrections are left alone
+	MaxHold   time.Duration // Longest hold sent at a stop
+	Slowdown  float64       // Share of the target headway under which a train is slowed down
+	MinShare  float64       // Lowest share of its top speed a train is slowed down to
+	Smoothing time.Duration // Simulation time the coefficient of variation of the headways is averaged over
 }
 
 var DefaultConfig = Config{
@@ -37,13 +218,33 @@ var DefaultConfig = Config{
 	LoopDuration:         time.Second / 60,
 	LoopDurationOffset:   -1 * time.Millisecond,
 	LoopStartingState:    1,
+	StepTicks:            60,
 	ReflexDuration:       2 * time.Second,
 	StdLogs:              true,
 	TrainWaitInStation:   5 * time.Second,
 	TenjinEnabled:        true,
 	TenjinTickRate:       time.Second,
+	TenjinReportRate:     time.Second,
+	TenjinEventBuffer:    20000,
+	PredictionRate:       time.Second,
 	PassengerSpawnRate:   5 * time.Second,
 	PassengersPerStation: 3,
+	Seed:                 0,
+	RenderTPS:            60,
+	RenderOnChange:       false,
+	MaxPassengers:        0,
+	MemoryLimit:          0,
+
+	Profile: ProfileDefault,
+	LowPower: LowPowerConfig{
+		RenderTPS:         15,
+		TenjinTickRate:    5 * time.Second,
+		TenjinReportRate:  time.Minute,
+		TenjinEventBuffer: 5000,
+		PredictionRate:    5 * time.Second,
+		MaxPassengers:     2000,
+		MemoryLimit:       256 << 20, // The Pi Zero has 512 MB
+	},
 
 	// Real-world scaling: 1 pixel = 100 meters (map is ~70km x 50km)
 	PixelsPerMeter:      0.01,  // 1 pixel = 100 meters
@@ -52,4 +253,109 @@ var DefaultConfig = Config{
 	// Simulation starts at 8:00 AM
 	SimulationStartHour: 8,
 	SimulationStartMin:  0,
+
+	// The first simulated day is a Monday, with the holidays of the Dominican Republic
+	Calendar: CalendarConfig{
+		StartDate: "2025-03-03",
+		Holidays: []string{
+			"2025-01-01", "2025-01-06", "2025-01-21", "2025-01-26", "2025-02-27",
+			"2025-04-18", "2025-05-05", "2025-06-19", "2025-08-16", "2025-09-24",
+			"2025-11-10", "2025-12-25",
+		},
+		DemandScale: map[string]float64{
+			"saturday": 0.6,
+			"sunday":   0.4,
+			"holiday":  0.4,
+		},
+	},
+
+	ValidateNetworkOnStartup: true,
+
+	DemandFile: "",
+
+	BoardingPolicy: "fifo",
+
+	NameLocale: "es_DO",
+
+	TimelineDays: 2,
+
+	Sentiment: SentimentConfig{
+		Interval:       5 * time.Second,
+		DefaultHeadway: 5 * time.Minute,
+		Wait:           2.0,
+		Crowding:       1.5,
+		NoSeat:         0.3,
+		Transfer:       3.0,
+		Delay:          0.2,
+		DeniedBoarding: 5.0,
+		GateQueue:      1.0,
+		Recovery:       0.5,
+	},
+
+	Abandonment: AbandonmentConfig{
+		BalkQueueLength: 40,
+		MaxWait:         30 * time.Minute,
+	},
+
+	Commuters: CommuterConfig{
+		Population:     40,
+		Memory:         0.3,
+		BadTrip:        50,
+		DepartureShift: 15 * time.Minute,
+	},
+
+	PlatformCapacity: 200,
+	GateThroughput:   60,
+
+	Walking: WalkingConfig{
+		Entrance: time.Minute,
+		Transfer: 3 * time.Minute,
+		StopTime: 2 * time.Minute,
+	},
+
+	Fares: FareConfig{
+		Model:            "flat",
+		Base:             20,
+		PerKm:            2,
+		PerZone:          5,
+		TransferDiscount: 1,
+		Concessions: map[string]float64{
+			"student": 0.5,
+			"elderly": 0.5,
+		},
+		TrainHourCost: 4500,
+	},
+
+	Events: EventConfig{
+		MetroShare:  0.4,
+		ArrivalLead: 90 * time.Minute,
+		Dispersal:   45 * time.Minute,
+	},
+
+	Intelligence: IntelligenceConfig{
+		Crowding:     0.8,
+		Bunching:     0.5,
+		Gap:          1.5,
+		MaxHold:      90 * time.Second,
+		LatenessGain: 30 * time.Second,
+		LatenessRuns: 5,
+		HighLoad:     0.7,
+		LowLoad:      0.05,
+		LoadWindow:   15 * time.Minute,
+	},
+	Commands: CommandConfig{
+		MaxHold:    5 * time.Minute,
+		MinSpeed:   10,
+		Separation: 500,
+		Buffer:     16,
+	},
+	Headway: HeadwayConfig{
+		Enabled:   false,
+		Gain:      1,
+		MinHold:   5 * time.Second,
+		MaxHold:   time.Minute,
+		Slowdown:  0.5,
+		MinShare:  0.5,
+		Smoothing: 5 * time.Minute,
+	},
 }
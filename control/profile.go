@@ -0,0 +1,32 @@
+package control
+
+import (
+	"fmt"
+)
+
+// Runtime profiles, see Config.Profile
+const (
+	ProfileDefault  = "default"
+	ProfileLowPower = "low_power"
+)
+
+// ApplyProfile overrides the config with the settings of its profile. It must run
+// at startup, before anything reads the config.
+func ApplyProfile(c *Config) error {
+	switch c.Profile {
+	case "", ProfileDefault:
+		return nil
+	case ProfileLowPower:
+		lp := c.LowPower
+		c.RenderTPS = lp.RenderTPS
+		c.RenderOnChange = true
+		c.TenjinTickRate = lp.TenjinTickRate
+		c.TenjinReportRate = lp.TenjinReportRate
+		c.TenjinEventBuffer = lp.TenjinEventBuffer
+		c.PredictionRate = lp.PredictionRate
+		c.MaxPassengers = lp.MaxPassengers
+		c.MemoryLimit = lp.MemoryLimit
+		return nil
+	}
+	return fmt.Errorf("unknown profile %q, use %q or %q", c.Profile, ProfileDefault, ProfileLowPower)
+}
@@ -0,0 +1,133 @@
+package data
+
+import (
+	"database/sql"
+	"fmt"
+	"math/rand"
+
+	"github.com/odin-software/metro/control"
+	"github.com/odin-software/metro/internal/baso"
+	"github.com/odin-software/metro/internal/dbstore"
+	"github.com/odin-software/metro/internal/models"
+)
+
+// LoadCommuters returns the commuters stored in the database. When there are none,
+// a new population of control.DefaultConfig.Commuters.Population is created from the
+// seed and stored, so the same people travel every day and across runs, and a fresh
+// database gets the same population for the same seed.
+func LoadCommuters(stations []*models.Station, lines []models.Line, seed int64) []*models.Commuter {
+	db := baso.NewBaso()
+
+	stationsByID := make(map[int64]*models.Station, len(stations))
+	for _, st := range stations {
+		stationsByID[st.ID] = st
+	}
+
+	rows, err := db.ListCommuters()
+	if err != nil {
+		control.Log(fmt.Sprintf("Error loading commuters: %v", err))
+		return nil
+	}
+	if len(rows) == 0 {
+		rng := rand.New(rand.NewSource(seed))
+		rows = generateCommuters(stations, models.NewJourneyPlanner(lines), control.DefaultConfig.Commuters.Population, rng)
+		params := make([]dbstore.CreateCommuterParams, 0, len(rows))
+		for _, row := range rows {
+			params = append(params, dbstore.CreateCommuterParams{
+				ID:               row.ID,
+				Name:             row.Name,
+				Persona:          row.Persona,
+				HomeStationID:    row.HomeStationID,
+				WorkStationID:    row.WorkStationID,
+				MorningDeparture: row.MorningDeparture,
+				EveningDeparture: row.EveningDeparture,
+			})
+		}
+		if err := db.CreateCommuters(params); err != nil {
+			control.Log(fmt.Sprintf("Error saving commuters: %v", err))
+		}
+	}
+
+	commuters := make([]*models.Commuter, 0, len(rows))
+	for _, row := range rows {
+		home, ok1 := stationsByID[row.HomeStationID]
+		work, ok2 := stationsByID[row.WorkStationID]
+		if !ok1 || !ok2 {
+			control.Log(fmt.Sprintf("Commuter %s skipped: unknown station", row.ID))
+			continue
+		}
+		history := models.CommuterHistory{
+			MorningDeparture: int(row.MorningDeparture),
+			EveningDeparture: int(row.EveningDeparture),
+			AvoidLineID:      row.AvoidLineID.Int64,
+			Satisfaction:     row.Satisfaction,
+			Trips:            int(row.Trips),
+			LostTrips:        int(row.LostTrips),
+			BadDays:          int(row.BadDays),
+		}
+		persona := models.PersonaByKind(models.PersonaKind(row.Persona))
+		commuters = append(commuters, models.NewCommuter(row.ID, row.Name, persona, home, work, history))
+	}
+
+	return commuters
+}
+
+// generateCommuters creates commuters living at a random station and working at a
+// station they can reach. They leave home between 6:30 and 9:00 and work between
+// 16:30 and 19:00.
+func generateCommuters(stations []*models.Station, planner *models.JourneyPlanner, population int, rng *rand.Rand) []dbstore.Commuter {
+	names := models.NewNameGenerator(control.DefaultConfig.NameLocale, rng)
+	commuters := make([]dbstore.Commuter, 0, population)
+	if len(stations) == 0 {
+		return commuters
+	}
+
+	for i := 0; len(commuters) < population && i < population*10; i++ {
+		home := stations[rng.Intn(len(stations))]
+		destinations := planner.Destinations(home.ID)
+		if len(destinations) == 0 {
+			continue
+		}
+		work := destinations[rng.Intn(len(destinations))]
+		morning := 6*3600 + 1800 + rng.Intn(150)*60
+		evening := 16*3600 + 1800 + rng.Intn(150)*60
+		persona := models.PickPersona(rng, models.DefaultPersonas, morning)
+
+		commuters = append(commuters, dbstore.Commuter{
+			ID:               fmt.Sprintf("C-%03d", len(commuters)+1),
+			Name:             names.Name(),
+			Persona:          string(persona.Kind),
+			HomeStationID:    home.ID,
+			WorkStationID:    work.ID,
+			MorningDeparture: int64(morning),
+			EveningDeparture: int64(evening),
+			Satisfaction:     100,
+		})
+	}
+	return commuters
+}
+
+// DumpCommutersData saves what the commuters remember from their trips
+func DumpCommutersData(commuters []*models.Commuter) {
+	bs := baso.NewBaso()
+
+	rows := make([]dbstore.UpdateCommuterParams, 0, len(commuters))
+	for _, commuter := range commuters {
+		history := commuter.History()
+		rows = append(rows, dbstore.UpdateCommuterParams{
+			MorningDeparture: int64(history.MorningDeparture),
+			EveningDeparture: int64(history.EveningDeparture),
+			AvoidLineID:      sql.NullInt64{Int64: history.AvoidLineID, Valid: history.AvoidLineID != 0},
+			Satisfaction:     history.Satisfaction,
+			Trips:            int64(history.Trips),
+			LostTrips:        int64(history.LostTrips),
+			BadDays:          int64(history.BadDays),
+			ID:               commuter.ID,
+		})
+	}
+
+	if err := bs.UpdateCommuters(rows); err != nil {
+		// Log error but don't crash - DB sync is non-critical
+		_ = err
+	}
+}
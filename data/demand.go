@@ -0,0 +1,226 @@
+package data
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/odin-software/metro/control"
+	"github.com/odin-software/metro/internal/baso"
+	"github.com/odin-software/metro/internal/dbstore"
+	"github.com/odin-software/metro/internal/models"
+)
+
+// DemandRow is a row of a demand CSV file: passengers per hour going from one
+// station to another during a time band of a day type. Stations are referenced by
+// name so the files don't depend on the IDs of a particular import.
+type DemandRow struct {
+	Start       int // Seconds since midnight
+	End         int // Seconds since midnight, excluded
+	Origin      string
+	Destination string
+	PerHour     float64
+	DayType     models.DayType
+}
+
+var demandHeader = []string{"start", "end", "origin", "destination", "per_hour", "day_type"}
+
+// ParseDemandCSV reads demand rows from a CSV with the header
+// start,end,origin,destination,per_hour and an optional day_type column. Times are
+// HH:MM or HH:MM:SS, the end of the day can be written as 24:00. Rows without a day
+// type are for weekdays.
+func ParseDemandCSV(r io.Reader) ([]DemandRow, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading header: %w", err)
+	}
+	if len(header) != len(demandHeader) && len(header) != len(demandHeader)-1 {
+		return nil, fmt.Errorf("invalid header, expected %s", strings.Join(demandHeader, ","))
+	}
+	for i, h := range header {
+		if strings.ToLower(strings.TrimSpace(h)) != demandHeader[i] {
+			return nil, fmt.Errorf("invalid header, expected %s", strings.Join(demandHeader, ","))
+		}
+	}
+
+	rows := make([]DemandRow, 0)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+
+		start, err := parseBandTime(record[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		end, err := parseBandTime(record[1])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		perHour, err := strconv.ParseFloat(strings.TrimSpace(record[4]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid demand %q", line, record[4])
+		}
+		dayType := models.DayTypeWeekday
+		if len(record) > 5 {
+			dayType, err = models.ParseDayType(strings.ToLower(strings.TrimSpace(record[5])))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+		}
+		rows = append(rows, DemandRow{
+			Start:       start,
+			End:         end,
+			Origin:      strings.TrimSpace(record[2]),
+			Destination: strings.TrimSpace(record[3]),
+			PerHour:     perHour,
+			DayType:     dayType,
+		})
+	}
+	return rows, nil
+}
+
+func parseBandTime(value string) (int, error) {
+	parts := strings.Split(strings.TrimSpace(value), ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, fmt.Errorf("invalid time %q", value)
+	}
+	values := make([]int, 3)
+	for i, part := range parts {
+		v, err := strconv.Atoi(part)
+		if err != nil || v < 0 {
+			return 0, fmt.Errorf("invalid time %q", value)
+		}
+		values[i] = v
+	}
+	seconds := values[0]*3600 + values[1]*60 + values[2]
+	if values[1] > 59 || values[2] > 59 || seconds > 86400 {
+		return 0, fmt.Errorf("invalid time %q", value)
+	}
+	return seconds, nil
+}
+
+func readDemandFile(path string) ([]DemandRow, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseDemandCSV(f)
+}
+
+// stationIDsByName maps the names of the stations in the database to their IDs.
+func stationIDsByName(db *baso.Baso) (map[string]int64, error) {
+	stations, err := db.ListStations()
+	if err != nil {
+		return nil, err
+	}
+	ids := make(map[string]int64, len(stations))
+	for _, st := range stations {
+		ids[st.Name] = st.ID
+	}
+	return ids, nil
+}
+
+// ImportDemandFile replaces the demand stored in the database with the one in the CSV file.
+// It returns the number of OD pairs imported.
+func ImportDemandFile(path string) (int, error) {
+	rows, err := readDemandFile(path)
+	if err != nil {
+		return 0, err
+	}
+	db := baso.NewBaso()
+	ids, err := stationIDsByName(db)
+	if err != nil {
+		return 0, err
+	}
+
+	// Build the profile first so invalid bands are rejected before touching the database.
+	profile := models.NewDemandProfile(nil)
+	params := make([]dbstore.CreateDemandParams, 0, len(rows))
+	for _, row := range rows {
+		origin, ok := ids[row.Origin]
+		if !ok {
+			return 0, fmt.Errorf("unknown station %q", row.Origin)
+		}
+		destination, ok := ids[row.Destination]
+		if !ok {
+			return 0, fmt.Errorf("unknown station %q", row.Destination)
+		}
+		if err := profile.Add(row.DayType, row.Start, row.End, origin, destination, row.PerHour); err != nil {
+			return 0, err
+		}
+		params = append(params, dbstore.CreateDemandParams{
+			BandStart:     int64(row.Start),
+			BandEnd:       int64(row.End),
+			OriginID:      origin,
+			DestinationID: destination,
+			PerHour:       row.PerHour,
+			DayType:       string(row.DayType),
+		})
+	}
+
+	return len(params), db.ReplaceDemand(params)
+}
+
+// LoadDemand loads the OD matrices of every day type from the CSV file in the config,
+// or from the database when no file is configured. Invalid entries are logged and
+// skipped.
+func LoadDemand() *models.DemandProfile {
+	profile := models.NewDemandProfile(control.DefaultConfig.Calendar.DemandScale)
+	db := baso.NewBaso()
+
+	if path := control.DefaultConfig.DemandFile; path != "" {
+		rows, err := readDemandFile(path)
+		if err != nil {
+			control.Log(fmt.Sprintf("Error loading demand file %s: %v", path, err))
+			return profile
+		}
+		ids, err := stationIDsByName(db)
+		if err != nil {
+			control.Log(fmt.Sprintf("Error loading stations for demand: %v", err))
+			return profile
+		}
+		for _, row := range rows {
+			origin, ok1 := ids[row.Origin]
+			destination, ok2 := ids[row.Destination]
+			if !ok1 || !ok2 {
+				control.Log(fmt.Sprintf("Demand %s -> %s skipped: unknown station", row.Origin, row.Destination))
+				continue
+			}
+			if err := profile.Add(row.DayType, row.Start, row.End, origin, destination, row.PerHour); err != nil {
+				control.Log(fmt.Sprintf("Demand %s -> %s skipped: %v", row.Origin, row.Destination, err))
+			}
+		}
+		return profile
+	}
+
+	rows, err := db.ListDemand()
+	if err != nil {
+		control.Log(fmt.Sprintf("Error loading demand: %v", err))
+		return profile
+	}
+	for _, row := range rows {
+		dayType, err := models.ParseDayType(row.DayType)
+		if err == nil {
+			err = profile.Add(dayType, int(row.BandStart), int(row.BandEnd), row.OriginID, row.DestinationID, row.PerHour)
+		}
+		if err != nil {
+			control.Log(fmt.Sprintf("Demand %d skipped: %v", row.ID, err))
+		}
+	}
+	return profile
+}
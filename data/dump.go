@@ -23,10 +23,11 @@ func DumpPassengersData(stations []*models.Station, trains []models.Train) {
 	// Collect all active passengers from stations and trains
 	var allPassengers []*models.Passenger
 
-	// Get passengers from stations (waiting)
+	// Get passengers from stations (waiting and outside the gates)
 	for _, station := range stations {
 		passengers := station.GetWaitingPassengers()
 		allPassengers = append(allPassengers, passengers...)
+		allPassengers = append(allPassengers, station.GetEntryQueue()...)
 	}
 
 	// Get passengers from trains (riding)
@@ -0,0 +1,42 @@
+package data
+
+import (
+	"github.com/odin-software/metro/internal/baso"
+	"github.com/odin-software/metro/internal/models"
+)
+
+// defaultStationColor is used for stations created at runtime.
+const defaultStationColor = "#FFFFFF"
+
+// BasoNetworkStore persists network edits through baso.
+type BasoNetworkStore struct {
+	baso *baso.Baso
+}
+
+// NewBasoNetworkStore creates a new store backed by the database.
+func NewBasoNetworkStore() *BasoNetworkStore {
+	return &BasoNetworkStore{
+		baso: baso.NewBaso(),
+	}
+}
+
+func (s *BasoNetworkStore) AddStation(name string, position models.Vector) (int64, error) {
+	return s.baso.AddStation(name, defaultStationColor, position.X, position.Y)
+}
+
+func (s *BasoNetworkStore) MoveStation(id int64, name string, position models.Vector) error {
+	return s.baso.MoveStation(id, name, position.X, position.Y)
+}
+
+func (s *BasoNetworkStore) AddEdge(fromID, toID int64, points []models.Vector) error {
+	_, err := s.baso.CreateEdgeWithPoints(fromID, toID, points)
+	return err
+}
+
+func (s *BasoNetworkStore) DeleteEdge(fromID, toID int64) error {
+	return s.baso.DeleteEdgesBetween(fromID, toID)
+}
+
+func (s *BasoNetworkStore) ReorderLine(lineID int64, stationIDs []int64) error {
+	return s.baso.ReorderLine(lineID, stationIDs)
+}
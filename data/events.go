@@ -0,0 +1,94 @@
+package data
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/odin-software/metro/control"
+	"github.com/odin-software/metro/internal/baso"
+	"github.com/odin-software/metro/internal/dbstore"
+	"github.com/odin-software/metro/internal/models"
+)
+
+// LoadSpecialEvents returns the special events stored in the database, on the
+// stations that are loaded. Invalid events are logged and skipped.
+func LoadSpecialEvents(stations []*models.Station) []*models.SpecialEvent {
+	db := baso.NewBaso()
+	rows, err := db.ListSpecialEvents()
+	if err != nil {
+		control.Log(fmt.Sprintf("Error loading special events: %v", err))
+		return nil
+	}
+
+	stationsByID := make(map[int64]*models.Station, len(stations))
+	for _, st := range stations {
+		stationsByID[st.ID] = st
+	}
+
+	events := make([]*models.SpecialEvent, 0, len(rows))
+	for _, row := range rows {
+		st, ok := stationsByID[row.StationID]
+		if !ok {
+			control.Log(fmt.Sprintf("Special event %s skipped: unknown station %d", row.Name, row.StationID))
+			continue
+		}
+		ev, err := models.NewSpecialEvent(row.ID, row.Name, st, int(row.Day), int(row.StartTime), int(row.EndTime), int(row.Attendance))
+		if err != nil {
+			control.Log(fmt.Sprintf("Special event %s skipped: %v", row.Name, err))
+			continue
+		}
+		events = append(events, ev)
+	}
+	return events
+}
+
+// CreateSpecialEvent stores a special event from the arguments of `metro add-event`:
+// name, station name, start and end (HH:MM) and attendance, optionally followed by
+// the simulated day (-1 for every day).
+func CreateSpecialEvent(args []string) (dbstore.SpecialEvent, error) {
+	if len(args) < 5 || len(args) > 6 {
+		return dbstore.SpecialEvent{}, fmt.Errorf("expected: <name> <station> <start> <end> <attendance> [day]")
+	}
+	start, err := parseBandTime(args[2])
+	if err != nil {
+		return dbstore.SpecialEvent{}, err
+	}
+	end, err := parseBandTime(args[3])
+	if err != nil {
+		return dbstore.SpecialEvent{}, err
+	}
+	attendance, err := strconv.Atoi(strings.TrimSpace(args[4]))
+	if err != nil {
+		return dbstore.SpecialEvent{}, fmt.Errorf("invalid attendance %q", args[4])
+	}
+	day := 0
+	if len(args) == 6 {
+		if day, err = strconv.Atoi(strings.TrimSpace(args[5])); err != nil {
+			return dbstore.SpecialEvent{}, fmt.Errorf("invalid day %q", args[5])
+		}
+	}
+
+	db := baso.NewBaso()
+	ids, err := stationIDsByName(db)
+	if err != nil {
+		return dbstore.SpecialEvent{}, err
+	}
+	stationID, ok := ids[args[1]]
+	if !ok {
+		return dbstore.SpecialEvent{}, fmt.Errorf("unknown station %q", args[1])
+	}
+
+	// Validate with the model so the database only holds events that can be loaded.
+	if _, err := models.NewSpecialEvent(0, args[0], &models.Station{ID: stationID}, day, start, end, attendance); err != nil {
+		return dbstore.SpecialEvent{}, err
+	}
+	return db.CreateSpecialEvent(dbstore.CreateSpecialEventParams{
+		Name:       args[0],
+		StationID:  stationID,
+		Day:        int64(day),
+		StartTime:  int64(start),
+		EndTime:    int64(end),
+		Attendance: int64(attendance),
+	})
+}
@@ -1,8 +1,11 @@
 package data
 
 import (
+	"fmt"
 	"log"
+	"time"
 
+	"github.com/odin-software/metro/control"
 	"github.com/odin-software/metro/internal/baso"
 	"github.com/odin-software/metro/internal/models"
 )
@@ -14,15 +17,69 @@ func LoadStations() []*models.Station {
 		log.Fatal(err)
 	}
 	result := make([]*models.Station, 0)
+	stationsByComplex := make(map[int64][]*models.Station)
 	for _, station := range stations {
-		result = append(
-			result,
-			models.NewStation(station.ID, station.Name, station.Position),
-		)
+		st := models.NewStation(station.ID, station.Name, station.Position)
+		if station.PlatformCapacity > 0 {
+			st.PlatformCapacity = station.PlatformCapacity
+		}
+		if station.GateThroughput > 0 {
+			st.GateThroughput = station.GateThroughput
+		}
+		if station.EntranceWalk > 0 {
+			st.EntranceWalk = time.Duration(station.EntranceWalk) * time.Second
+		}
+		if station.Zone > 0 {
+			st.Zone = station.Zone
+		}
+		if station.ComplexID != 0 {
+			stationsByComplex[station.ComplexID] = append(stationsByComplex[station.ComplexID], st)
+		}
+		result = append(result, st)
 	}
+	loadComplexes(db, stationsByComplex)
 	return result
 }
 
+// loadComplexes groups the stations of each complex and sets the walks between them.
+func loadComplexes(db *baso.Baso, stationsByComplex map[int64][]*models.Station) {
+	if len(stationsByComplex) == 0 {
+		return
+	}
+	complexes, err := db.ListStationComplexes()
+	if err != nil {
+		control.Log(fmt.Sprintf("Error loading station complexes: %v", err))
+		return
+	}
+
+	complexByStation := make(map[int64]*models.StationComplex)
+	for _, row := range complexes {
+		transferWalk := control.DefaultConfig.Walking.Transfer
+		if row.TransferWalk > 0 {
+			transferWalk = time.Duration(row.TransferWalk) * time.Second
+		}
+		sc := models.NewStationComplex(row.ID, row.Name, transferWalk)
+		for _, st := range stationsByComplex[row.ID] {
+			sc.Add(st)
+			complexByStation[st.ID] = sc
+		}
+	}
+
+	walks, err := db.ListComplexWalks()
+	if err != nil {
+		control.Log(fmt.Sprintf("Error loading complex walks: %v", err))
+		return
+	}
+	for _, walk := range walks {
+		sc, ok := complexByStation[walk.FromStationID]
+		if !ok || complexByStation[walk.ToStationID] != sc {
+			control.Log(fmt.Sprintf("Complex walk %d skipped: stations %d and %d are not in the same complex", walk.ID, walk.FromStationID, walk.ToStationID))
+			continue
+		}
+		sc.SetWalk(walk.FromStationID, walk.ToStationID, time.Duration(walk.Walk)*time.Second)
+	}
+}
+
 func LoadLines(stations []*models.Station) []models.Line {
 	db := baso.NewBaso()
 	lines := db.ListLinesWithStations()
@@ -59,7 +116,7 @@ func LoadLines(stations []*models.Station) []models.Line {
 func LoadTrains(
 	stations []*models.Station,
 	lines []models.Line,
-	central *models.Network[models.Station],
+	central *models.Network[*models.Station],
 	eventChannel chan<- interface{},
 	clock models.ClockInterface,
 ) []models.Train {
@@ -118,40 +175,59 @@ func LoadTrains(
 	return result
 }
 
-func LoadEdges(cn *models.Network[models.Station]) {
+// LoadEdges inserts the edges into the network, between the already loaded stations.
+func LoadEdges(cn *models.Network[*models.Station], stations []*models.Station) {
 	db := baso.NewBaso()
 	edges, err := db.ListEdges()
 	if err != nil {
 		log.Fatal(err)
 	}
+
+	stationsByID := make(map[int64]*models.Station, len(stations))
+	for _, st := range stations {
+		stationsByID[st.ID] = st
+	}
+
 	for _, edge := range edges {
 		edgePoints, err := db.ListEdgePoints(edge.ID)
 		if err != nil {
 			log.Fatal(err)
 		}
-		station1, err := db.GetStationById(edge.Fromid)
-		if err != nil {
-			log.Fatal(err)
+		st1, ok := stationsByID[edge.Fromid]
+		if !ok {
+			log.Fatalf("Station not found with ID: %d", edge.Fromid)
 		}
-		station2, err := db.GetStationById(edge.Toid)
-		if err != nil {
-			log.Fatal(err)
+		st2, ok := stationsByID[edge.Toid]
+		if !ok {
+			log.Fatalf("Station not found with ID: %d", edge.Toid)
 		}
 		eps := make([]models.Vector, 0)
 		for _, ep := range edgePoints {
 			eps = append(eps, models.NewVector(ep.X, ep.Y))
 		}
-		// Converting the type from the database into the memory model.
-		st1 := models.Station{
-			ID:       station1.ID,
-			Name:     station1.Name,
-			Position: models.NewVector(station1.Position.X, station1.Position.Y),
+
+		firstKey, secondKey := cn.KeyOf(st1), cn.KeyOf(st2)
+		if edge.Directed != 0 {
+			err = cn.InsertDirectedEdgeByKey(firstKey, secondKey, eps)
+		} else {
+			err = cn.InsertEdgeByKey(firstKey, secondKey, eps)
 		}
-		st2 := models.Station{
-			ID:       station2.ID,
-			Name:     station2.Name,
-			Position: models.NewVector(station2.Position.X, station2.Position.Y),
+		if err != nil {
+			control.Log(fmt.Sprintf("Error inserting edge %d: %v", edge.ID, err))
+			continue
+		}
+
+		// Platform offsets apply to both directions of undirected edges, except the
+		// directions that have their own geometry.
+		if edge.Directed != 0 {
+			cn.SetEdgeOffsetByKey(firstKey, secondKey, edge.Platformoffset)
+		} else if edge.Platformoffset != 0 {
+			if !cn.IsDirectedByKey(firstKey, secondKey) {
+				cn.SetEdgeOffsetByKey(firstKey, secondKey, edge.Platformoffset)
+			}
+			if !cn.IsDirectedByKey(secondKey, firstKey) {
+				cn.SetEdgeOffsetByKey(secondKey, firstKey, edge.Platformoffset)
+			}
 		}
-		cn.InsertEdge(st1, st2, eps)
 	}
 }
@@ -1,97 +1,274 @@
 package data
 
 import (
-	"context"
 	"fmt"
 	"math/rand"
-	"sync"
-	"time"
+	"sort"
+	"strconv"
 
+	"github.com/odin-software/metro/control"
+	"github.com/odin-software/metro/internal/baso"
 	"github.com/odin-software/metro/internal/models"
 )
 
-// SpawnPassengers creates initial passengers and spawns new ones periodically
-func SpawnPassengers(
-	ctx context.Context,
-	wg *sync.WaitGroup,
+// SpawnClock gives the spawner access to the simulation time.
+type SpawnClock interface {
+	GetCurrentTimeOfDay() int
+	GetElapsedSeconds() float64
+	GetDay() int
+	GetDayType() models.DayType
+}
+
+// PassengerSpawner creates initial passengers and spawns new ones every time it runs.
+// When there is demand data, passengers appear following a Poisson process per OD
+// pair driven by the simulation clock, with the demand of the day type of the day.
+// Otherwise random stations get passengers going to random reachable destinations.
+// Commuters start their trips on weekdays when the clock passes their departure
+// times, and special events add surges of attendees on top of everything else.
+// It is not safe for concurrent use.
+type PassengerSpawner struct {
+	stations            []*models.Station
+	stationsByID        map[int64]*models.Station
+	stationDestinations map[int64][]*models.Station
+	demand              *models.DemandProfile
+	commuters           []*models.Commuter
+	events              []*models.SpecialEvent
+	clock               SpawnClock
+	factory             *passengerFactory
+	lastElapsed         float64 // Simulation seconds at the last spawn
+	lastTimeOfDay       int
+	randomRounds        float64 // Rounds of random spawns due, without demand data
+	limit               int     // Passengers travelling at which spawning pauses, 0 is unlimited
+	travelling          func() int
+	full                bool // Spawning is paused by the limit
+}
+
+// NewPassengerSpawner creates the spawner and the initial passengers. Every random
+// choice comes from rng, so the same seed spawns the same passengers. The IDs of the
+// passengers carry run, see StartRun.
+func NewPassengerSpawner(
 	stations []*models.Station,
 	lines []models.Line,
-	spawnTick *time.Ticker,
+	demand *models.DemandProfile,
+	sentiment *models.SentimentModel,
+	fares *models.FareModel,
+	journal models.JourneyRecorder,
+	commuters []*models.Commuter,
+	events []*models.SpecialEvent,
+	clock SpawnClock,
+	run string,
+	rng *rand.Rand,
 	eventChannel chan<- interface{},
-) {
-	// Build map of station -> reachable destinations (stations on same lines)
-	stationDestinations := buildStationDestinationMap(stations, lines)
-
-	// Initial spawn: create passengers at each station
+) *PassengerSpawner {
+	// Build map of station -> reachable destinations (anywhere in the network)
+	planner := models.NewJourneyPlanner(lines)
+	ps := &PassengerSpawner{
+		stations:            stations,
+		stationsByID:        make(map[int64]*models.Station, len(stations)),
+		stationDestinations: buildStationDestinationMap(stations, planner),
+		demand:              demand,
+		commuters:           commuters,
+		events:              events,
+		clock:               clock,
+		factory: &passengerFactory{
+			planner:      planner,
+			sentiment:    sentiment,
+			fares:        fares,
+			journal:      journal,
+			names:        models.NewNameGenerator(control.DefaultConfig.NameLocale, rng),
+			rng:          rng,
+			clock:        clock,
+			eventChannel: eventChannel,
+			run:          run,
+		},
+		lastElapsed:   clock.GetElapsedSeconds(),
+		lastTimeOfDay: clock.GetCurrentTimeOfDay(),
+	}
 	for _, station := range stations {
-		spawnPassengersAtStation(station, stationDestinations, 3, eventChannel) // 3 per station initially
-	}
-
-	// Random spawning loop
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			case <-spawnTick.C:
-				// Random station gets 1-2 new passengers
-				if len(stations) > 0 {
-					station := stations[rand.Intn(len(stations))]
-					count := rand.Intn(2) + 1
-					spawnPassengersAtStation(station, stationDestinations, count, eventChannel)
-				}
-			}
+		ps.stationsByID[station.ID] = station
+	}
+
+	if demand.IsEmpty() {
+		// Initial spawn: create passengers at each station
+		for _, station := range stations {
+			spawnPassengersAtStation(station, ps.stationDestinations, ps.factory, 3) // 3 per station initially
 		}
-	}()
+	} else {
+		warnUnreachableDemand(demand, planner)
+	}
+	return ps
 }
 
-// buildStationDestinationMap creates a map of station ID -> reachable destinations
-func buildStationDestinationMap(stations []*models.Station, lines []models.Line) map[int64][]*models.Station {
-	stationDestinations := make(map[int64][]*models.Station)
+// SetLimit pauses spawning while travelling returns limit passengers or more, the
+// passengers due meanwhile don't come. A limit of 0 removes it.
+func (ps *PassengerSpawner) SetLimit(limit int, travelling func() int) {
+	ps.limit = limit
+	ps.travelling = travelling
+}
 
-	// For each station, find all reachable destinations (stations on same lines)
+// SetNetwork replaces the stations and lines passengers travel between after an edit
+// of the network. The passengers already travelling keep their itineraries.
+func (ps *PassengerSpawner) SetNetwork(stations []*models.Station, lines []models.Line) {
+	planner := models.NewJourneyPlanner(lines)
+	ps.stations = stations
+	ps.stationsByID = make(map[int64]*models.Station, len(stations))
 	for _, station := range stations {
-		reachableSet := make(map[int64]*models.Station)
-
-		// Find all lines that serve this station
-		for _, line := range lines {
-			stationOnLine := false
-			for _, lineStation := range line.Stations {
-				if lineStation.ID == station.ID {
-					stationOnLine = true
-					break
-				}
-			}
+		ps.stationsByID[station.ID] = station
+	}
+	ps.stationDestinations = buildStationDestinationMap(stations, planner)
+	ps.factory.planner = planner
+}
 
-			// If station is on this line, add all other stations from this line
-			if stationOnLine {
-				for _, dest := range line.Stations {
-					if dest.ID != station.ID {
-						reachableSet[dest.ID] = dest
-					}
-				}
+// Spawn creates the passengers due since the last spawn.
+func (ps *PassengerSpawner) Spawn() {
+	timeOfDay := ps.clock.GetCurrentTimeOfDay()
+	day := ps.clock.GetDay()
+	elapsed := ps.clock.GetElapsedSeconds()
+	seconds := elapsed - ps.lastElapsed
+	ps.lastElapsed = elapsed
+	// Nothing happens while the simulation is paused
+	if seconds <= 0 {
+		return
+	}
+
+	if ps.limit > 0 && ps.travelling != nil {
+		full := ps.travelling() >= ps.limit
+		if full != ps.full {
+			ps.full = full
+			if full {
+				control.Log(fmt.Sprintf("%d passengers travelling, spawning paused", ps.limit))
+			} else {
+				control.Log("Spawning resumed")
 			}
 		}
+		if full {
+			ps.lastTimeOfDay = timeOfDay
+			return
+		}
+	}
+
+	dayType := ps.clock.GetDayType()
+	if dayType == models.DayTypeWeekday {
+		spawnCommuters(ps.commuters, ps.factory, ps.lastTimeOfDay, timeOfDay, day)
+	}
+	ps.lastTimeOfDay = timeOfDay
+	spawnEventSurges(ps.events, ps.stationDestinations, ps.factory, day, timeOfDay, seconds)
+
+	if !ps.demand.IsEmpty() {
+		arrivals := ps.demand.Arrivals(ps.factory.rng, dayType, timeOfDay, seconds)
+		spawnDemandArrivals(arrivals, ps.stationsByID, ps.factory)
+		return
+	}
+
+	// Random station gets 1-2 new passengers every spawn period of simulation time
+	ps.randomRounds += seconds / (control.DefaultConfig.PassengerSpawnRate.Seconds() * control.DefaultConfig.SimulationSpeed)
+	for ; ps.randomRounds >= 1 && len(ps.stations) > 0; ps.randomRounds-- {
+		station := ps.stations[ps.factory.rng.Intn(len(ps.stations))]
+		count := ps.factory.rng.Intn(2) + 1
+		spawnPassengersAtStation(station, ps.stationDestinations, ps.factory, count)
+	}
+}
+
+// spawnCommuters starts the trips of the commuters that depart after prev and until now.
+func spawnCommuters(commuters []*models.Commuter, factory *passengerFactory, prev, now, day int) {
+	for _, commuter := range commuters {
+		for _, commute := range commuter.DueTrips(prev, now) {
+			factory.spawnCommuter(commuter, commute, day)
+		}
+	}
+}
+
+// spawnEventSurges creates the attendees travelling to and from the special events
+// during the last seconds. They come from and go to random stations connected to
+// the station of the event.
+func spawnEventSurges(
+	events []*models.SpecialEvent,
+	stationDestinations map[int64][]*models.Station,
+	factory *passengerFactory,
+	day, timeOfDay int,
+	seconds float64,
+) {
+	for _, ev := range events {
+		arriving, leaving := ev.Surge(factory.rng, control.DefaultConfig.Events, day, timeOfDay, seconds)
+		others := stationDestinations[ev.Station.ID]
+		if len(others) == 0 {
+			continue
+		}
+		for i := 0; i < arriving; i++ {
+			factory.spawnAttendee(ev, others[factory.rng.Intn(len(others))], ev.Station)
+		}
+		for i := 0; i < leaving; i++ {
+			factory.spawnAttendee(ev, ev.Station, others[factory.rng.Intn(len(others))])
+		}
+	}
+}
+
+// spawnDemandArrivals creates the passengers sampled from the demand matrix.
+// Destinations that can't be reached from the origin are skipped.
+func spawnDemandArrivals(
+	arrivals map[models.ODPair]int,
+	stationsByID map[int64]*models.Station,
+	factory *passengerFactory,
+) {
+	// Spawn in a fixed order so a seeded run gets the same passengers.
+	pairs := make([]models.ODPair, 0, len(arrivals))
+	for pair := range arrivals {
+		pairs = append(pairs, pair)
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].Origin != pairs[j].Origin {
+			return pairs[i].Origin < pairs[j].Origin
+		}
+		return pairs[i].Destination < pairs[j].Destination
+	})
 
-		// Convert set to slice
-		reachable := make([]*models.Station, 0, len(reachableSet))
-		for _, dest := range reachableSet {
-			reachable = append(reachable, dest)
+	for _, pair := range pairs {
+		count := arrivals[pair]
+		origin, ok := stationsByID[pair.Origin]
+		if !ok {
+			continue
+		}
+		dest, ok := stationsByID[pair.Destination]
+		if !ok {
+			continue
+		}
+		for i := 0; i < count; i++ {
+			factory.spawn(origin, dest)
 		}
-		stationDestinations[station.ID] = reachable
 	}
+}
+
+// warnUnreachableDemand logs the OD pairs of the demand that no journey can serve.
+func warnUnreachableDemand(demand *models.DemandProfile, planner *models.JourneyPlanner) {
+	warned := make(map[models.ODPair]bool)
+	for _, band := range demand.Bands() {
+		for pair := range band.Rates {
+			if warned[pair] {
+				continue
+			}
+			if !planner.Reachable(pair.Origin, pair.Destination) {
+				warned[pair] = true
+				control.Log(fmt.Sprintf("Demand from station %d to %d is not reachable and will be skipped", pair.Origin, pair.Destination))
+			}
+		}
+	}
+}
 
+// buildStationDestinationMap creates a map of station ID -> reachable destinations,
+// transferring between lines where they share a station
+func buildStationDestinationMap(stations []*models.Station, planner *models.JourneyPlanner) map[int64][]*models.Station {
+	stationDestinations := make(map[int64][]*models.Station)
+	for _, station := range stations {
+		stationDestinations[station.ID] = planner.Destinations(station.ID)
+	}
 	return stationDestinations
 }
 
 func spawnPassengersAtStation(
 	station *models.Station,
 	stationDestinations map[int64][]*models.Station,
+	factory *passengerFactory,
 	count int,
-	eventChannel chan<- interface{},
 ) {
 	reachableStations := stationDestinations[station.ID]
 	if len(reachableStations) == 0 {
@@ -101,12 +278,106 @@ func spawnPassengersAtStation(
 
 	for i := 0; i < count; i++ {
 		// Pick random reachable destination
-		dest := reachableStations[rand.Intn(len(reachableStations))]
+		dest := reachableStations[factory.rng.Intn(len(reachableStations))]
+		factory.spawn(station, dest)
+	}
+}
 
-		id := fmt.Sprintf("P-%d-%d", time.Now().Unix(), rand.Intn(10000))
-		name := fmt.Sprintf("Passenger-%d", rand.Intn(1000))
+// passengerFactory creates passengers with a persona, a name and a planned itinerary.
+// It is not safe for concurrent use.
+type passengerFactory struct {
+	planner      *models.JourneyPlanner
+	sentiment    *models.SentimentModel
+	fares        *models.FareModel
+	journal      models.JourneyRecorder
+	names        *models.NameGenerator
+	rng          *rand.Rand
+	clock        SpawnClock
+	eventChannel chan<- interface{}
+	run          string // Stamp of the run, keeps the IDs of each run apart
+	count        int    // Passengers created
+}
 
-		passenger := models.NewPassenger(id, name, station, dest, eventChannel)
-		station.AddPassenger(passenger)
+// StartRun stores a new run of the simulation and returns the stamp of the IDs of its
+// passengers. Timelines are stored by passenger ID, so the IDs of a run must never be
+// reused by another one, even with the same seed.
+func StartRun(seed int64) string {
+	run, err := baso.NewBaso().CreateSimulationRun(seed)
+	if err != nil {
+		control.Log(fmt.Sprintf("Error storing the run, timelines may mix with other runs: %v", err))
+	}
+	return runStamp(run.ID, seed)
+}
+
+// runStamp stamps the IDs of the passengers with the number of the run and its seed.
+func runStamp(run, seed int64) string {
+	return strconv.FormatInt(run, 36) + "." + strconv.FormatUint(uint64(seed), 36)
+}
+
+// spawn creates a passenger waiting at the station, unreachable destinations are skipped.
+func (pf *passengerFactory) spawn(station, dest *models.Station) {
+	if passenger := pf.newPassenger(station, dest); passenger != nil {
+		pf.admit(passenger, station)
+	}
+}
+
+// spawnAttendee creates a passenger travelling to or from a special event.
+func (pf *passengerFactory) spawnAttendee(event *models.SpecialEvent, station, dest *models.Station) {
+	if passenger := pf.newPassenger(station, dest); passenger != nil {
+		passenger.Event = event
+		pf.admit(passenger, station)
+	}
+}
+
+// newPassenger returns a one-off passenger with a planned itinerary, nil when the
+// destination can't be reached.
+func (pf *passengerFactory) newPassenger(station, dest *models.Station) *models.Passenger {
+	itinerary, err := pf.planner.Plan(station.ID, dest.ID)
+	if err != nil {
+		return nil
+	}
+
+	pf.count++
+	id := fmt.Sprintf("P-%s-%d", pf.run, pf.count)
+	persona := models.PickPersona(pf.rng, models.DefaultPersonas, pf.clock.GetCurrentTimeOfDay())
+
+	passenger := models.NewPassenger(id, pf.names.Name(), persona, station, dest, pf.eventChannel)
+	passenger.Itinerary = itinerary
+	return passenger
+}
+
+// spawnCommuter starts a daily trip of a commuter, on the route they prefer.
+func (pf *passengerFactory) spawnCommuter(commuter *models.Commuter, commute models.Commute, day int) {
+	from, to := commuter.Trip(commute)
+	itinerary, err := pf.planner.Plan(from.ID, to.ID)
+	if avoid := commuter.History().AvoidLineID; avoid != 0 {
+		if avoiding, avoidErr := pf.planner.PlanAvoiding(from.ID, to.ID, avoid); avoidErr == nil {
+			itinerary, err = avoiding, nil
+		} else {
+			commuter.ClearAvoidedLine()
+		}
+	}
+	if err != nil {
+		return
+	}
+
+	id := fmt.Sprintf("%s-%s-%d-%d", commuter.ID, pf.run, day, commute)
+	passenger := models.NewPassenger(id, commuter.Name, commuter.Persona, from, to, pf.eventChannel)
+	passenger.Itinerary = itinerary
+	passenger.StartCommute(commuter, commute, day)
+	pf.admit(passenger, from)
+}
+
+// admit queues the passenger at the fare gates of the station, unless the queues make them leave.
+func (pf *passengerFactory) admit(passenger *models.Passenger, station *models.Station) {
+	passenger.SentimentModel = pf.sentiment
+	passenger.Fares = pf.fares
+	if pf.journal != nil {
+		passenger.StartTimeline(pf.journal)
+	}
+	if passenger.Balks(station) {
+		passenger.Abandon(models.AbandonBalk)
+		return
 	}
+	station.Enter(passenger)
 }
@@ -0,0 +1,58 @@
+package data
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/odin-software/metro/internal/models"
+)
+
+// fakeSpawnClock is a clock stopped at 8:00 of the first weekday.
+type fakeSpawnClock struct{}
+
+func (fakeSpawnClock) GetCurrentTimeOfDay() int   { return 8 * 3600 }
+func (fakeSpawnClock) GetElapsedSeconds() float64 { return 0 }
+func (fakeSpawnClock) GetDay() int                { return 0 }
+func (fakeSpawnClock) GetDayType() models.DayType { return models.DayTypeWeekday }
+
+// newTestFactory returns a passenger factory of a run on a line from A to B.
+func newTestFactory(run int64) (*passengerFactory, *models.Station, *models.Station) {
+	a, b := &models.Station{ID: 1, Name: "A"}, &models.Station{ID: 2, Name: "B"}
+	rng := rand.New(rand.NewSource(1))
+	return &passengerFactory{
+		planner: models.NewJourneyPlanner([]models.Line{{ID: 1, Name: "L1", Stations: []*models.Station{a, b}}}),
+		names:   models.NewNameGenerator(models.DefaultNameLocale, rng),
+		rng:     rng,
+		clock:   fakeSpawnClock{},
+		run:     runStamp(run, 1),
+	}, a, b
+}
+
+func TestPassengerIDsAreUnique(t *testing.T) {
+	seen := make(map[string]bool)
+	for run := 0; run < 3; run++ {
+		factory, a, b := newTestFactory(int64(run))
+		for i := 0; i < 100; i++ {
+			passenger := factory.newPassenger(a, b)
+			if passenger == nil {
+				t.Fatal("The passenger should have been created.")
+			}
+			if seen[passenger.ID] {
+				t.Fatal("Passengers of the same or another run should never share an ID.")
+			}
+			seen[passenger.ID] = true
+		}
+	}
+}
+
+func TestRunStamp(t *testing.T) {
+	if runStamp(3, 42) != runStamp(3, 42) {
+		t.Fatal("The same run should always get the same stamp.")
+	}
+	if runStamp(3, 42) == runStamp(4, 42) || runStamp(3, 42) == runStamp(3, 43) {
+		t.Fatal("Runs with another number or seed should get another stamp.")
+	}
+	if runStamp(1, -1) == runStamp(1, 1) {
+		t.Fatal("Negative seeds should get a stamp of their own.")
+	}
+}
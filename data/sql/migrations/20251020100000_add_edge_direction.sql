@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A directed edge only describes the geometry from fromId to toId, the opposite
+-- direction needs its own edge. Undirected edges (the default) reuse the reversed
+-- geometry for the opposite direction.
+-- platformOffset shifts the track (and the platform at both ends) to the right of
+-- the travel direction, in pixels, so opposite directions run on parallel tracks.
+ALTER TABLE edge ADD COLUMN directed INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE edge ADD COLUMN platformOffset REAL NOT NULL DEFAULT 0;
+
+CREATE INDEX idx_edge_from_to ON edge(fromId, toId);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX idx_edge_from_to;
+ALTER TABLE edge DROP COLUMN platformOffset;
+ALTER TABLE edge DROP COLUMN directed;
+-- +goose StatementEnd
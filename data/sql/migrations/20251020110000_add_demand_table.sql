@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Demand table stores origin-destination matrices per time band
+-- band_start and band_end are in seconds since midnight, band_end is excluded
+CREATE TABLE demand (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    band_start INTEGER NOT NULL,
+    band_end INTEGER NOT NULL,
+    origin_id INTEGER NOT NULL,
+    destination_id INTEGER NOT NULL,
+    per_hour REAL NOT NULL, -- passengers per hour from origin to destination
+    FOREIGN KEY(origin_id) REFERENCES station(id) ON DELETE CASCADE,
+    FOREIGN KEY(destination_id) REFERENCES station(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_demand_band ON demand(band_start, band_end);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE demand;
+-- +goose StatementEnd
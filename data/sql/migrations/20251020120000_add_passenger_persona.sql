@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The persona drives how a passenger behaves: when they travel, how patient they
+-- are and how much crowding they tolerate (commuter, student, tourist, elderly).
+ALTER TABLE passenger ADD COLUMN persona TEXT NOT NULL DEFAULT 'commuter';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE passenger DROP COLUMN persona;
+-- +goose StatementEnd
@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Commuters are the passengers that travel home-work-home every simulated day.
+-- Departures are in seconds since midnight and satisfaction is the baseline
+-- sentiment (0-100) they start every trip with, carried over from previous trips.
+CREATE TABLE commuter (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    persona TEXT NOT NULL DEFAULT 'commuter',
+    home_station_id INTEGER NOT NULL,
+    work_station_id INTEGER NOT NULL,
+    morning_departure INTEGER NOT NULL,
+    evening_departure INTEGER NOT NULL,
+    avoid_line_id INTEGER, -- line they stopped riding after a bad trip
+    satisfaction REAL NOT NULL DEFAULT 100.0,
+    trips INTEGER NOT NULL DEFAULT 0,
+    lost_trips INTEGER NOT NULL DEFAULT 0,
+    bad_days INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY(home_station_id) REFERENCES station(id) ON DELETE CASCADE,
+    FOREIGN KEY(work_station_id) REFERENCES station(id) ON DELETE CASCADE,
+    FOREIGN KEY(avoid_line_id) REFERENCES line(id) ON DELETE SET NULL
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE commuter;
+-- +goose StatementEnd
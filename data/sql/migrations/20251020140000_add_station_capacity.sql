@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Size of the station: how many passengers the platforms hold and how many per
+-- minute the fare gates let in. 0 uses the defaults of the configuration.
+ALTER TABLE station ADD COLUMN platform_capacity INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE station ADD COLUMN gate_throughput REAL NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE station DROP COLUMN gate_throughput;
+ALTER TABLE station DROP COLUMN platform_capacity;
+-- +goose StatementEnd
@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A station complex is an interchange: stations of different lines that passengers
+-- walk between without leaving the system. Walks are in seconds, 0 uses the
+-- defaults of the configuration.
+CREATE TABLE station_complex (
+    id INTEGER PRIMARY KEY,
+    name TEXT NOT NULL,
+    transfer_walk INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+-- Walk between two stations of a complex when it differs from its transfer_walk,
+-- it is the same in both directions.
+CREATE TABLE complex_walk (
+    id INTEGER PRIMARY KEY,
+    from_station_id INTEGER NOT NULL,
+    to_station_id INTEGER NOT NULL,
+    walk INTEGER NOT NULL,
+    FOREIGN KEY(from_station_id) REFERENCES station(id) ON DELETE CASCADE,
+    FOREIGN KEY(to_station_id) REFERENCES station(id) ON DELETE CASCADE
+);
+ALTER TABLE station ADD COLUMN complex_id INTEGER REFERENCES station_complex(id) ON DELETE SET NULL;
+ALTER TABLE station ADD COLUMN entrance_walk INTEGER NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE station DROP COLUMN entrance_walk;
+ALTER TABLE station DROP COLUMN complex_id;
+DROP TABLE complex_walk;
+DROP TABLE station_complex;
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Fare zone of the station, zonal fares charge for the zones crossed.
+ALTER TABLE station ADD COLUMN zone INTEGER NOT NULL DEFAULT 1;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE station DROP COLUMN zone;
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Special events bring a crowd to a station before they start and send it home
+-- after they end. Times are in seconds since midnight, day is the simulated day
+-- starting at 0, a negative day repeats the event every day.
+CREATE TABLE special_event (
+    id INTEGER PRIMARY KEY,
+    name TEXT NOT NULL,
+    station_id INTEGER NOT NULL,
+    day INTEGER NOT NULL DEFAULT 0,
+    start_time INTEGER NOT NULL,
+    end_time INTEGER NOT NULL,
+    attendance INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY(station_id) REFERENCES station(id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE special_event;
+-- +goose StatementEnd
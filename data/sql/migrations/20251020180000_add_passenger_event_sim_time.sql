@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Passenger events are stamped with the simulation time so the timeline of a
+-- journey can be rebuilt: sim_day starts at 0, sim_time is in seconds since midnight.
+ALTER TABLE passenger_event ADD COLUMN sim_day INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE passenger_event ADD COLUMN sim_time INTEGER NOT NULL DEFAULT 0;
+CREATE INDEX idx_passenger_event_sim_day ON passenger_event(sim_day);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX idx_passenger_event_sim_day;
+ALTER TABLE passenger_event DROP COLUMN sim_time;
+ALTER TABLE passenger_event DROP COLUMN sim_day;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Schedules and demand are kept per day type: weekday, saturday, sunday or holiday.
+-- Day types without rows of their own run the ones of their fallback.
+ALTER TABLE schedule ADD COLUMN day_type TEXT NOT NULL DEFAULT 'weekday';
+ALTER TABLE demand ADD COLUMN day_type TEXT NOT NULL DEFAULT 'weekday';
+CREATE INDEX idx_schedule_day_type ON schedule(day_type);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX idx_schedule_day_type;
+ALTER TABLE demand DROP COLUMN day_type;
+ALTER TABLE schedule DROP COLUMN day_type;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Every start of the simulation stores a run. The run and its seed stamp the IDs of
+-- the passengers, so the timelines of two runs never share a passenger ID.
+CREATE TABLE simulation_run (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    seed INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE simulation_run;
+-- +goose StatementEnd
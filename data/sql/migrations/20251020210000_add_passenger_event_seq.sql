@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Order the journey log recorded the event of a run in. Events of the same passenger
+-- may look alike, the sequence tells the stored ones from those still being written.
+ALTER TABLE passenger_event ADD COLUMN seq INTEGER NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE passenger_event DROP COLUMN seq;
+-- +goose StatementEnd
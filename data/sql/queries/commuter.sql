@@ -0,0 +1,19 @@
+-- name: ListCommuters :many
+SELECT * FROM commuter
+ORDER BY id;
+
+-- name: CreateCommuter :exec
+INSERT INTO commuter (id, name, persona, home_station_id, work_station_id, morning_departure, evening_departure)
+VALUES (?, ?, ?, ?, ?, ?, ?);
+
+-- name: UpdateCommuter :exec
+UPDATE commuter
+SET morning_departure = ?,
+    evening_departure = ?,
+    avoid_line_id = ?,
+    satisfaction = ?,
+    trips = ?,
+    lost_trips = ?,
+    bad_days = ?,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = ?;
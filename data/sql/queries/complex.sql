@@ -0,0 +1,7 @@
+-- name: ListStationComplexes :many
+SELECT * FROM station_complex
+ORDER BY id;
+
+-- name: ListComplexWalks :many
+SELECT * FROM complex_walk
+ORDER BY id;
@@ -0,0 +1,10 @@
+-- name: ListDemand :many
+SELECT * FROM demand
+ORDER BY day_type, band_start, origin_id, destination_id;
+
+-- name: CreateDemand :exec
+INSERT INTO demand (band_start, band_end, origin_id, destination_id, per_hour, day_type)
+VALUES (?, ?, ?, ?, ?, ?);
+
+-- name: DeleteAllDemand :exec
+DELETE FROM demand;
@@ -1,5 +1,5 @@
 -- name: GetEdges :many
-SELECT id, fromId, toId
+SELECT id, fromId, toId, directed, platformOffset
 FROM edge;
 
 -- name: GetEdgePoints :many
@@ -13,8 +13,31 @@ INSERT INTO edge (fromId, toId)
 VALUES (?, ?)
 RETURNING id;
 
+-- name: CreateDirectedEdge :one
+INSERT INTO edge (fromId, toId, directed, platformOffset)
+VALUES (?, ?, ?, ?)
+RETURNING id;
+
 -- name: DeleteAllEdges :exec
 DELETE FROM edge;
 
 -- name: DeleteAllEdgePoints :exec
-DELETE FROM edge_point;
\ No newline at end of file
+DELETE FROM edge_point;
+
+-- name: GetEdgesBetween :many
+SELECT id, fromId, toId, directed, platformOffset
+FROM edge
+WHERE fromId = ? AND toId = ?;
+
+-- name: CreateEdgePoint :one
+INSERT INTO edge_point (edgeId, odr, x, y, z)
+VALUES (?, ?, ?, ?, ?)
+RETURNING id;
+
+-- name: DeleteEdge :exec
+DELETE FROM edge
+WHERE id = ?;
+
+-- name: DeleteEdgePoints :exec
+DELETE FROM edge_point
+WHERE edgeId = ?;
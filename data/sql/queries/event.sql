@@ -0,0 +1,8 @@
+-- name: ListSpecialEvents :many
+SELECT * FROM special_event
+ORDER BY day, start_time, id;
+
+-- name: CreateSpecialEvent :one
+INSERT INTO special_event (name, station_id, day, start_time, end_time, attendance)
+VALUES (?, ?, ?, ?, ?, ?)
+RETURNING *;
@@ -102,4 +102,8 @@ WHERE stationId = ? AND lineId = ?;
 DELETE FROM line;
 
 -- name: DeleteAllStationLines :exec
-DELETE FROM station_line;
\ No newline at end of file
+DELETE FROM station_line;
+
+-- name: DeleteStationLinesByLine :exec
+DELETE FROM station_line
+WHERE lineId = ?;
@@ -1,6 +1,6 @@
 -- name: CreatePassenger :one
-INSERT INTO passenger (id, name, current_station_id, destination_station_id, state, sentiment, spawn_time)
-VALUES (?, ?, ?, ?, ?, ?, ?)
+INSERT INTO passenger (id, name, current_station_id, destination_station_id, state, sentiment, spawn_time, persona)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)
 RETURNING *;
 
 -- name: GetPassengerById :one
@@ -50,8 +50,8 @@ SELECT COUNT(*) as count FROM passenger;
 -- Passenger Event queries
 
 -- name: CreatePassengerEvent :one
-INSERT INTO passenger_event (passenger_id, event_type, station_id, train_id, sentiment, metadata)
-VALUES (?, ?, ?, ?, ?, ?)
+INSERT INTO passenger_event (passenger_id, event_type, station_id, train_id, sentiment, metadata, sim_day, sim_time, seq)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
 RETURNING *;
 
 -- name: GetPassengerEvents :many
@@ -67,3 +67,16 @@ LIMIT ?;
 -- name: DeletePassengerEvents :exec
 DELETE FROM passenger_event
 WHERE passenger_id = ?;
+
+-- name: GetPassengerTimeline :many
+SELECT pe.id, pe.passenger_id, pe.event_type, pe.station_id, pe.train_id, pe.sentiment, pe.metadata, pe.sim_day, pe.sim_time, pe.seq,
+    COALESCE(s.name, '') AS station_name, COALESCE(t.name, '') AS train_name
+FROM passenger_event pe
+LEFT JOIN station s ON s.id = pe.station_id
+LEFT JOIN train t ON t.id = pe.train_id
+WHERE pe.passenger_id = ?
+ORDER BY pe.sim_day, pe.sim_time, pe.id;
+
+-- name: DeletePassengerEventsBefore :exec
+DELETE FROM passenger_event
+WHERE sim_day < ?;
@@ -0,0 +1,4 @@
+-- name: CreateSimulationRun :one
+INSERT INTO simulation_run (seed)
+VALUES (?)
+RETURNING *;
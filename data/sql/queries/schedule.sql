@@ -1,6 +1,6 @@
 -- name: CreateScheduleEntry :one
-INSERT INTO schedule (train_id, station_id, scheduled_time, sequence_order)
-VALUES (?, ?, ?, ?)
+INSERT INTO schedule (train_id, station_id, scheduled_time, sequence_order, day_type)
+VALUES (?, ?, ?, ?, ?)
 RETURNING *;
 
 -- name: GetScheduleForTrain :many
@@ -21,7 +21,7 @@ LIMIT 1;
 
 -- name: GetScheduleByTrainAndStation :one
 SELECT * FROM schedule
-WHERE train_id = ? AND station_id = ?
+WHERE train_id = ? AND station_id = ? AND day_type = ?
 LIMIT 1;
 
 -- name: DeleteScheduleForTrain :exec
@@ -29,4 +29,4 @@ DELETE FROM schedule WHERE train_id = ?;
 
 -- name: GetAllSchedules :many
 SELECT * FROM schedule
-ORDER BY train_id, sequence_order;
+ORDER BY day_type, train_id, sequence_order;
@@ -1,5 +1,5 @@
 -- name: ListStations :many
-SELECT id, name, x, y, z, color FROM station 
+SELECT id, name, x, y, z, color, platform_capacity, gate_throughput, complex_id, entrance_walk, zone FROM station 
 ORDER BY id;
 
 -- name: GetStationById :one
@@ -18,6 +18,20 @@ JOIN line ln ON tr.lineId = ln.id
 JOIN make mk ON tr.makeId = mk.id
 JOIN station st ON tr.currentId = st.id;
 
+-- name: ListTrainReferences :many
+SELECT
+	tr.id,
+	tr.name,
+	tr.currentId,
+	tr.makeId,
+	tr.lineId,
+	mk.name as makeName,
+	ln.name as lineName
+FROM train tr
+LEFT JOIN make mk ON tr.makeId = mk.id
+LEFT JOIN line ln ON tr.lineId = ln.id
+ORDER BY tr.id;
+
 -- name: GetTrainById :one
 SELECT id, name, x, y, z FROM train
 WHERE id = ?
@@ -8,7 +8,12 @@ CREATE TABLE station (
     z REAL,
     color VARCHAR(255),
     created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    platform_capacity INTEGER NOT NULL DEFAULT 0,
+    gate_throughput REAL NOT NULL DEFAULT 0,
+    complex_id INTEGER REFERENCES station_complex(id) ON DELETE SET NULL,
+    entrance_walk INTEGER NOT NULL DEFAULT 0,
+    zone INTEGER NOT NULL DEFAULT 1
 );
 CREATE TABLE line (
     id INTEGER PRIMARY KEY,
@@ -56,9 +61,12 @@ CREATE TABLE edge (
     id INTEGER PRIMARY KEY,
     fromId INTEGER NOT NULL,
     toId INTEGER NOT NULL,
+    directed INTEGER NOT NULL DEFAULT 0,
+    platformOffset REAL NOT NULL DEFAULT 0,
     FOREIGN KEY(fromId) REFERENCES station(id),
     FOREIGN KEY(toId) REFERENCES station(id)
 );
+CREATE INDEX idx_edge_from_to ON edge(fromId, toId);
 CREATE TABLE edge_point (
     id INTEGER PRIMARY KEY,
     edgeId INTEGER NOT NULL,
@@ -79,6 +87,7 @@ CREATE TABLE passenger (
     spawn_time TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
     created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    persona TEXT NOT NULL DEFAULT 'commuter',
     FOREIGN KEY(current_station_id) REFERENCES station(id),
     FOREIGN KEY(destination_station_id) REFERENCES station(id),
     FOREIGN KEY(current_train_id) REFERENCES train(id)
@@ -96,6 +105,9 @@ CREATE TABLE passenger_event (
     sentiment REAL,
     metadata TEXT,
     created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    sim_day INTEGER NOT NULL DEFAULT 0,
+    sim_time INTEGER NOT NULL DEFAULT 0,
+    seq INTEGER NOT NULL DEFAULT 0,
     FOREIGN KEY(passenger_id) REFERENCES passenger(id),
     FOREIGN KEY(station_id) REFERENCES station(id),
     FOREIGN KEY(train_id) REFERENCES train(id)
@@ -103,6 +115,7 @@ CREATE TABLE passenger_event (
 CREATE INDEX idx_passenger_event_passenger_id ON passenger_event(passenger_id);
 CREATE INDEX idx_passenger_event_type ON passenger_event(event_type);
 CREATE INDEX idx_passenger_event_created_at ON passenger_event(created_at);
+CREATE INDEX idx_passenger_event_sim_day ON passenger_event(sim_day);
 CREATE TABLE schedule (
     id INTEGER PRIMARY KEY AUTOINCREMENT,
     train_id INTEGER NOT NULL,
@@ -110,6 +123,7 @@ CREATE TABLE schedule (
     scheduled_time INTEGER NOT NULL,
     sequence_order INTEGER NOT NULL,
     created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    day_type TEXT NOT NULL DEFAULT 'weekday',
     FOREIGN KEY(train_id) REFERENCES train(id) ON DELETE CASCADE,
     FOREIGN KEY(station_id) REFERENCES station(id) ON DELETE CASCADE
 );
@@ -117,6 +131,69 @@ CREATE INDEX idx_schedule_train ON schedule(train_id);
 CREATE INDEX idx_schedule_station ON schedule(station_id);
 CREATE INDEX idx_schedule_time ON schedule(scheduled_time);
 CREATE INDEX idx_schedule_train_sequence ON schedule(train_id, sequence_order);
+CREATE INDEX idx_schedule_day_type ON schedule(day_type);
+CREATE TABLE demand (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    band_start INTEGER NOT NULL,
+    band_end INTEGER NOT NULL,
+    origin_id INTEGER NOT NULL,
+    destination_id INTEGER NOT NULL,
+    per_hour REAL NOT NULL,
+    day_type TEXT NOT NULL DEFAULT 'weekday',
+    FOREIGN KEY(origin_id) REFERENCES station(id) ON DELETE CASCADE,
+    FOREIGN KEY(destination_id) REFERENCES station(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_demand_band ON demand(band_start, band_end);
+CREATE TABLE commuter (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    persona TEXT NOT NULL DEFAULT 'commuter',
+    home_station_id INTEGER NOT NULL,
+    work_station_id INTEGER NOT NULL,
+    morning_departure INTEGER NOT NULL,
+    evening_departure INTEGER NOT NULL,
+    avoid_line_id INTEGER,
+    satisfaction REAL NOT NULL DEFAULT 100.0,
+    trips INTEGER NOT NULL DEFAULT 0,
+    lost_trips INTEGER NOT NULL DEFAULT 0,
+    bad_days INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY(home_station_id) REFERENCES station(id) ON DELETE CASCADE,
+    FOREIGN KEY(work_station_id) REFERENCES station(id) ON DELETE CASCADE,
+    FOREIGN KEY(avoid_line_id) REFERENCES line(id) ON DELETE SET NULL
+);
+CREATE TABLE station_complex (
+    id INTEGER PRIMARY KEY,
+    name TEXT NOT NULL,
+    transfer_walk INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE TABLE complex_walk (
+    id INTEGER PRIMARY KEY,
+    from_station_id INTEGER NOT NULL,
+    to_station_id INTEGER NOT NULL,
+    walk INTEGER NOT NULL,
+    FOREIGN KEY(from_station_id) REFERENCES station(id) ON DELETE CASCADE,
+    FOREIGN KEY(to_station_id) REFERENCES station(id) ON DELETE CASCADE
+);
+CREATE TABLE special_event (
+    id INTEGER PRIMARY KEY,
+    name TEXT NOT NULL,
+    station_id INTEGER NOT NULL,
+    day INTEGER NOT NULL DEFAULT 0,
+    start_time INTEGER NOT NULL,
+    end_time INTEGER NOT NULL,
+    attendance INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY(station_id) REFERENCES station(id) ON DELETE CASCADE
+);
+CREATE TABLE simulation_run (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    seed INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
 -- +goose StatementEnd
 
 -- +goose Down
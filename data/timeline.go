@@ -0,0 +1,107 @@
+package data
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/odin-software/metro/control"
+	"github.com/odin-software/metro/internal/baso"
+	"github.com/odin-software/metro/internal/models"
+)
+
+// TimelineClock gives the journey log the simulation time of the events.
+type TimelineClock interface {
+	GetCurrentTimeOfDay() int
+	GetDay() int
+}
+
+// JourneyLog keeps the timelines of the passengers. Events are held in memory and
+// written to the database in batches by Flush, so recording never waits on the disk.
+type JourneyLog struct {
+	clock   TimelineClock
+	db      *baso.Baso
+	pending []models.JourneyEvent
+	writing []models.JourneyEvent // Events being written by Flush
+	pruned  int                   // Last simulated day the old timelines were deleted
+	seq     int64                 // Events recorded so far
+	mutex   sync.Mutex
+}
+
+func NewJourneyLog(clock TimelineClock) *JourneyLog {
+	return &JourneyLog{
+		clock:   clock,
+		db:      baso.NewBaso(),
+		pending: make([]models.JourneyEvent, 0),
+		pruned:  -1,
+	}
+}
+
+// Record stamps the event with the simulation time and its sequence, and queues it
+// for the database.
+func (jl *JourneyLog) Record(event models.JourneyEvent) {
+	event.Day = jl.clock.GetDay()
+	event.SimTime = jl.clock.GetCurrentTimeOfDay()
+
+	jl.mutex.Lock()
+	defer jl.mutex.Unlock()
+	jl.seq++
+	event.Seq = jl.seq
+	jl.pending = append(jl.pending, event)
+}
+
+// Flush writes the queued events to the database, and once a day deletes the
+// timelines older than control.DefaultConfig.TimelineDays.
+func (jl *JourneyLog) Flush() {
+	jl.mutex.Lock()
+	events := jl.pending
+	jl.pending = make([]models.JourneyEvent, 0, len(events))
+	jl.writing = events
+	jl.mutex.Unlock()
+
+	if len(events) > 0 {
+		if err := jl.db.LogPassengerEvents(events); err != nil {
+			control.Log(fmt.Sprintf("Error saving %d passenger events: %v", len(events), err))
+		}
+	}
+	jl.mutex.Lock()
+	jl.writing = nil
+	jl.mutex.Unlock()
+
+	if day := jl.clock.GetDay(); day != jl.pruned && control.DefaultConfig.TimelineDays > 0 {
+		jl.pruned = day
+		if err := jl.db.DeletePassengerEventsBefore(day - control.DefaultConfig.TimelineDays + 1); err != nil {
+			control.Log(fmt.Sprintf("Error deleting old passenger events: %v", err))
+		}
+	}
+}
+
+// PassengerTimeline returns the journey of a passenger so far, the stored events
+// followed by the ones that haven't been written yet.
+func (jl *JourneyLog) PassengerTimeline(passengerID string) (models.Timeline, error) {
+	jl.mutex.Lock()
+	unwritten := make(models.Timeline, 0)
+	for _, events := range [][]models.JourneyEvent{jl.writing, jl.pending} {
+		for _, event := range events {
+			if event.PassengerID == passengerID {
+				unwritten = append(unwritten, event)
+			}
+		}
+	}
+	jl.mutex.Unlock()
+
+	timeline, err := jl.db.GetPassengerTimeline(passengerID)
+	if err != nil {
+		return nil, err
+	}
+	// Events being written may already be stored, skip them.
+	stored := make(map[int64]bool, len(timeline))
+	for _, event := range timeline {
+		stored[event.Seq] = true
+	}
+	for _, event := range unwritten {
+		if !stored[event.Seq] {
+			timeline = append(timeline, event)
+		}
+	}
+	return timeline, nil
+}
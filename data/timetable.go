@@ -0,0 +1,64 @@
+package data
+
+import (
+	"fmt"
+
+	"github.com/odin-software/metro/control"
+	"github.com/odin-software/metro/internal/baso"
+	"github.com/odin-software/metro/internal/models"
+)
+
+// LoadTimetable builds the timetable of every train and day type from the schedules in
+// the database. The direction of each stop is taken from the next stop of the same
+// train on the same day type, so the headways are advertised per platform.
+func LoadTimetable(lines []models.Line) *models.Timetable {
+	timetable := models.NewTimetable()
+	db := baso.NewBaso()
+
+	schedules, err := db.GetAllSchedules()
+	if err != nil {
+		control.Log(fmt.Sprintf("Error loading schedules for the timetable: %v", err))
+		return timetable
+	}
+
+	linesByName := make(map[string]*models.Line, len(lines))
+	for i := range lines {
+		linesByName[lines[i].Name] = &lines[i]
+	}
+	trainLines := make(map[int64]*models.Line)
+	for _, train := range db.ListTrainsFull() {
+		if line, ok := linesByName[train.LineName]; ok {
+			trainLines[train.ID] = line
+		}
+	}
+
+	// Schedules are ordered by day type, train and sequence.
+	for i, sched := range schedules {
+		dayType, err := models.ParseDayType(sched.DayType)
+		if err != nil {
+			control.Log(fmt.Sprintf("Schedule %d skipped: %v", sched.ID, err))
+			continue
+		}
+		platform := models.Platform{}
+		line, ok := trainLines[sched.TrainID]
+		if ok && i+1 < len(schedules) && schedules[i+1].TrainID == sched.TrainID && schedules[i+1].DayType == sched.DayType {
+			from, to := lineIndex(line, sched.StationID), lineIndex(line, schedules[i+1].StationID)
+			if from != -1 && to != -1 && from != to {
+				platform = models.Platform{LineID: line.ID, Forward: to > from}
+			}
+		}
+		timetable.Add(dayType, sched.TrainID, sched.StationID, platform, int(sched.ScheduledTime))
+	}
+	timetable.Build()
+
+	return timetable
+}
+
+func lineIndex(line *models.Line, stationID int64) int {
+	for i, st := range line.Stations {
+		if st.ID == stationID {
+			return i
+		}
+	}
+	return -1
+}
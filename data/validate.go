@@ -0,0 +1,223 @@
+package data
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/odin-software/metro/internal/baso"
+	"github.com/odin-software/metro/internal/dbstore"
+	"github.com/odin-software/metro/internal/models"
+)
+
+// ValidationSeverity tells if an issue prevents the simulation from running.
+type ValidationSeverity string
+
+const (
+	ValidationError   ValidationSeverity = "error"
+	ValidationWarning ValidationSeverity = "warning"
+)
+
+// ValidationIssue is a single problem found in the city data.
+type ValidationIssue struct {
+	Severity ValidationSeverity
+	Check    string
+	Message  string
+}
+
+// ValidationReport holds every issue found while validating the city data.
+type ValidationReport struct {
+	Issues []ValidationIssue
+}
+
+func (r *ValidationReport) addf(severity ValidationSeverity, check string, format string, args ...any) {
+	r.Issues = append(r.Issues, ValidationIssue{
+		Severity: severity,
+		Check:    check,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// Count returns the number of issues with the given severity.
+func (r ValidationReport) Count(severity ValidationSeverity) int {
+	count := 0
+	for _, issue := range r.Issues {
+		if issue.Severity == severity {
+			count++
+		}
+	}
+	return count
+}
+
+// HasErrors returns true if at least one issue is an error.
+func (r ValidationReport) HasErrors() bool {
+	return r.Count(ValidationError) > 0
+}
+
+// String returns a human-readable summary with one issue per line.
+func (r ValidationReport) String() string {
+	var sb strings.Builder
+	for _, issue := range r.Issues {
+		sb.WriteString(fmt.Sprintf("%-7s [%s] %s\n", strings.ToUpper(string(issue.Severity)), issue.Check, issue.Message))
+	}
+	sb.WriteString(fmt.Sprintf("Network validation: %d error(s), %d warning(s)\n",
+		r.Count(ValidationError), r.Count(ValidationWarning)))
+	return sb.String()
+}
+
+// ValidateNetwork checks the city stored in the database for problems that
+// would otherwise only show up at runtime.
+func ValidateNetwork() (ValidationReport, error) {
+	db := baso.NewBaso()
+	stations, err := db.ListStations()
+	if err != nil {
+		return ValidationReport{}, err
+	}
+	edges, err := db.ListEdges()
+	if err != nil {
+		return ValidationReport{}, err
+	}
+	trains, err := db.ListTrainReferences()
+	if err != nil {
+		return ValidationReport{}, err
+	}
+	schedules, err := db.GetAllSchedules()
+	if err != nil {
+		return ValidationReport{}, err
+	}
+	lines := db.ListLinesWithStations()
+
+	return validateNetworkData(stations, edges, lines, trains, schedules), nil
+}
+
+func validateNetworkData(
+	stations []baso.GetStation,
+	edges []dbstore.Edge,
+	lines []baso.LineWithStationData,
+	trains []dbstore.ListTrainReferencesRow,
+	schedules []dbstore.Schedule,
+) ValidationReport {
+	report := ValidationReport{}
+
+	stationsByID := make(map[int64]baso.GetStation, len(stations))
+	for _, st := range stations {
+		stationsByID[st.ID] = st
+	}
+
+	// Edges: both ends must exist. Undirected edges are traversed in both directions.
+	connected := make(map[[2]int64]bool, len(edges)*2)
+	hasEdge := make(map[int64]bool)
+	for _, edge := range edges {
+		_, fromOk := stationsByID[edge.Fromid]
+		_, toOk := stationsByID[edge.Toid]
+		if !fromOk || !toOk {
+			report.addf(ValidationError, "edge", "edge %d references a missing station (%d -> %d)", edge.ID, edge.Fromid, edge.Toid)
+			continue
+		}
+		if edge.Fromid == edge.Toid {
+			report.addf(ValidationError, "edge", "edge %d connects station %d to itself", edge.ID, edge.Fromid)
+			continue
+		}
+		connected[[2]int64{edge.Fromid, edge.Toid}] = true
+		if edge.Directed == 0 {
+			connected[[2]int64{edge.Toid, edge.Fromid}] = true
+		}
+		hasEdge[edge.Fromid] = true
+		hasEdge[edge.Toid] = true
+	}
+
+	// Lines: consecutive stations must be joined in both directions, trains run back and forth.
+	lineStations := make(map[int64]map[int64]bool, len(lines))
+	onAnyLine := make(map[int64]bool)
+	for _, line := range lines {
+		served := make(map[int64]bool, len(line.Stations))
+		for i := range line.Stations {
+			served[line.Stations[i].ID] = true
+			onAnyLine[line.Stations[i].ID] = true
+		}
+		lineStations[line.ID] = served
+
+		if len(line.Stations) < 2 {
+			report.addf(ValidationError, "line", "line %s has %d station(s), at least 2 are needed", line.Name, len(line.Stations))
+			continue
+		}
+		for i := 0; i < len(line.Stations)-1; i++ {
+			a, b := &line.Stations[i], &line.Stations[i+1]
+			if !connected[[2]int64{a.ID, b.ID}] {
+				report.addf(ValidationError, "line", "line %s: no edge from %s (%d) to %s (%d)",
+					line.Name, a.Name, a.ID, b.Name, b.ID)
+			}
+			if !connected[[2]int64{b.ID, a.ID}] {
+				report.addf(ValidationError, "line", "line %s: no edge from %s (%d) to %s (%d)",
+					line.Name, b.Name, b.ID, a.Name, a.ID)
+			}
+		}
+	}
+
+	// Stations: orphans and duplicates.
+	byName := make(map[string]int64)
+	byPosition := make(map[string]int64)
+	for _, st := range stations {
+		if !onAnyLine[st.ID] && !hasEdge[st.ID] {
+			report.addf(ValidationWarning, "station", "station %s (%d) is orphan: no line and no edges", st.Name, st.ID)
+		} else if !onAnyLine[st.ID] {
+			report.addf(ValidationWarning, "station", "station %s (%d) is not served by any line", st.Name, st.ID)
+		}
+
+		if other, ok := byName[st.Name]; ok {
+			report.addf(ValidationWarning, "station", "stations %d and %d share the name %s", other, st.ID, st.Name)
+		} else {
+			byName[st.Name] = st.ID
+		}
+
+		position := fmt.Sprintf("%.3f,%.3f", st.Position.X, st.Position.Y)
+		if other, ok := byPosition[position]; ok {
+			report.addf(ValidationWarning, "station", "stations %d and %d share the coordinates (%s)", other, st.ID, position)
+		} else {
+			byPosition[position] = st.ID
+		}
+	}
+
+	// Trains: make, line and current station must exist and be consistent.
+	trainLines := make(map[int64]int64, len(trains))
+	for _, tr := range trains {
+		if !tr.Makeid.Valid || !tr.Makename.Valid {
+			report.addf(ValidationError, "train", "train %s (%d) references a make that does not exist (%d)", tr.Name, tr.ID, tr.Makeid.Int64)
+		}
+		if !tr.Lineid.Valid || !tr.Linename.Valid {
+			report.addf(ValidationError, "train", "train %s (%d) references a line that does not exist (%d)", tr.Name, tr.ID, tr.Lineid.Int64)
+			continue
+		}
+		trainLines[tr.ID] = tr.Lineid.Int64
+
+		if !tr.Currentid.Valid {
+			report.addf(ValidationError, "train", "train %s (%d) has no current station", tr.Name, tr.ID)
+			continue
+		}
+		if _, ok := stationsByID[tr.Currentid.Int64]; !ok {
+			report.addf(ValidationError, "train", "train %s (%d) is at station %d which does not exist", tr.Name, tr.ID, tr.Currentid.Int64)
+			continue
+		}
+		if !lineStations[tr.Lineid.Int64][tr.Currentid.Int64] {
+			report.addf(ValidationError, "train", "train %s (%d) is at station %d which is not on line %s",
+				tr.Name, tr.ID, tr.Currentid.Int64, tr.Linename.String)
+		}
+	}
+
+	// Schedules: every stop must be served by the train's line, on a known day type.
+	for _, sc := range schedules {
+		if _, err := models.ParseDayType(sc.DayType); err != nil {
+			report.addf(ValidationError, "schedule", "schedule %d: %v", sc.ID, err)
+		}
+		lineID, ok := trainLines[sc.TrainID]
+		if !ok {
+			report.addf(ValidationError, "schedule", "schedule %d references train %d which has no valid line", sc.ID, sc.TrainID)
+			continue
+		}
+		if !lineStations[lineID][sc.StationID] {
+			report.addf(ValidationError, "schedule", "schedule %d: train %d stops at station %d which its line does not serve",
+				sc.ID, sc.TrainID, sc.StationID)
+		}
+	}
+
+	return report
+}
@@ -0,0 +1,189 @@
+package data
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+
+	"github.com/odin-software/metro/internal/baso"
+	"github.com/odin-software/metro/internal/dbstore"
+	"github.com/odin-software/metro/internal/models"
+)
+
+// testNetwork is the city data validated by the tests.
+type testNetwork struct {
+	stations  []baso.GetStation
+	edges     []dbstore.Edge
+	lines     []baso.LineWithStationData
+	trains    []dbstore.ListTrainReferencesRow
+	schedules []dbstore.Schedule
+}
+
+// newTestNetwork returns a valid city: a line from A to C through B, with a train at
+// A stopping at every station.
+func newTestNetwork() testNetwork {
+	stations := []baso.GetStation{
+		{ID: 1, Name: "A", Position: models.NewVector(0, 0)},
+		{ID: 2, Name: "B", Position: models.NewVector(100, 0)},
+		{ID: 3, Name: "C", Position: models.NewVector(200, 0)},
+	}
+	line := baso.LineWithStationData{ID: 1, Name: "L1"}
+	for _, st := range stations {
+		line.Stations = append(line.Stations, models.Station{ID: st.ID, Name: st.Name})
+	}
+	nw := testNetwork{
+		stations: stations,
+		edges: []dbstore.Edge{
+			{ID: 1, Fromid: 1, Toid: 2},
+			{ID: 2, Fromid: 2, Toid: 3},
+		},
+		lines: []baso.LineWithStationData{line},
+		trains: []dbstore.ListTrainReferencesRow{{
+			ID:        1,
+			Name:      "T1",
+			Currentid: sql.NullInt64{Int64: 1, Valid: true},
+			Makeid:    sql.NullInt64{Int64: 1, Valid: true},
+			Lineid:    sql.NullInt64{Int64: 1, Valid: true},
+			Makename:  sql.NullString{String: "M1", Valid: true},
+			Linename:  sql.NullString{String: "L1", Valid: true},
+		}},
+	}
+	for i, st := range stations {
+		nw.schedules = append(nw.schedules, dbstore.Schedule{
+			ID:            int64(i + 1),
+			TrainID:       1,
+			StationID:     st.ID,
+			SequenceOrder: int64(i),
+			DayType:       string(models.DayTypeWeekday),
+		})
+	}
+	return nw
+}
+
+func (nw testNetwork) validate() ValidationReport {
+	return validateNetworkData(nw.stations, nw.edges, nw.lines, nw.trains, nw.schedules)
+}
+
+func TestValidateNetworkValid(t *testing.T) {
+	if report := newTestNetwork().validate(); len(report.Issues) != 0 {
+		t.Fatal("A valid network should have no issues.")
+	}
+}
+
+func TestValidateNetworkBroken(t *testing.T) {
+	cases := []struct {
+		name     string
+		breaks   func(nw *testNetwork)
+		severity ValidationSeverity
+		check    string
+		message  string
+	}{
+		{
+			name: "orphan edge",
+			breaks: func(nw *testNetwork) {
+				nw.edges = append(nw.edges, dbstore.Edge{ID: 3, Fromid: 3, Toid: 9})
+			},
+			severity: ValidationError,
+			check:    "edge",
+			message:  "edge 3 references a missing station",
+		},
+		{
+			name: "edge to itself",
+			breaks: func(nw *testNetwork) {
+				nw.edges = append(nw.edges, dbstore.Edge{ID: 3, Fromid: 2, Toid: 2})
+			},
+			severity: ValidationError,
+			check:    "edge",
+			message:  "edge 3 connects station 2 to itself",
+		},
+		{
+			name: "line with one station",
+			breaks: func(nw *testNetwork) {
+				nw.lines = append(nw.lines, baso.LineWithStationData{
+					ID:       2,
+					Name:     "L2",
+					Stations: []models.Station{{ID: 3, Name: "C"}},
+				})
+			},
+			severity: ValidationError,
+			check:    "line",
+			message:  "line L2 has 1 station(s)",
+		},
+		{
+			name: "line over a directed edge",
+			breaks: func(nw *testNetwork) {
+				nw.edges[1].Directed = 1
+			},
+			severity: ValidationError,
+			check:    "line",
+			message:  "no edge from C (3) to B (2)",
+		},
+		{
+			name: "station without line",
+			breaks: func(nw *testNetwork) {
+				nw.stations = append(nw.stations, baso.GetStation{ID: 4, Name: "D", Position: models.NewVector(300, 0)})
+			},
+			severity: ValidationWarning,
+			check:    "station",
+			message:  "station D (4) is orphan",
+		},
+		{
+			name: "train on an unknown line",
+			breaks: func(nw *testNetwork) {
+				nw.trains[0].Lineid = sql.NullInt64{Int64: 9, Valid: true}
+				nw.trains[0].Linename = sql.NullString{}
+			},
+			severity: ValidationError,
+			check:    "train",
+			message:  "train T1 (1) references a line that does not exist (9)",
+		},
+		{
+			name: "train off its line",
+			breaks: func(nw *testNetwork) {
+				nw.lines[0].Stations = nw.lines[0].Stations[1:]
+			},
+			severity: ValidationError,
+			check:    "train",
+			message:  "train T1 (1) is at station 1 which is not on line L1",
+		},
+		{
+			name: "schedule for a missing station",
+			breaks: func(nw *testNetwork) {
+				nw.schedules[2].StationID = 9
+			},
+			severity: ValidationError,
+			check:    "schedule",
+			message:  "schedule 3: train 1 stops at station 9 which its line does not serve",
+		},
+		{
+			name: "schedule on an unknown day type",
+			breaks: func(nw *testNetwork) {
+				nw.schedules[0].DayType = "monday"
+			},
+			severity: ValidationError,
+			check:    "schedule",
+			message:  "schedule 1:",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			nw := newTestNetwork()
+			c.breaks(&nw)
+			report := nw.validate()
+
+			found := false
+			for _, issue := range report.Issues {
+				if issue.Severity == c.severity && issue.Check == c.check && strings.Contains(issue.Message, c.message) {
+					found = true
+				}
+			}
+			if !found {
+				t.Fatalf("The issue should have been reported, got:\n%s", report)
+			}
+			if report.HasErrors() != (c.severity == ValidationError) {
+				t.Fatal("Only errors should fail the validation.")
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package display
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/odin-software/metro/control"
+	"github.com/odin-software/metro/internal/models"
+)
+
+// handleNetworkEdits edits the network from the map: E marks the station under the
+// cursor, N adds a station at the cursor, M moves the marked station to the cursor,
+// C connects the marked station with the one under the cursor and X disconnects
+// them, Y extends the line ending at the marked station to the one under the
+// cursor. The edits are applied between two ticks, the answer shows in the corner.
+func (g *Game) handleNetworkEdits() {
+	if g.editReply != nil {
+		select {
+		case result := <-g.editReply:
+			g.editStatus = fmt.Sprintf("Done: %s", result.Edit.Kind)
+			if result.Err != nil {
+				g.editStatus = fmt.Sprintf("Rejected %s: %v", result.Edit.Kind, result.Err)
+			}
+			g.editReply = nil
+		default:
+		}
+	}
+	if g.editor == nil {
+		return
+	}
+
+	mx, my := ebiten.CursorPosition()
+	cursor := models.NewVector(float64(mx), float64(my))
+	under := g.stationAt(cursor)
+	if inpututil.IsKeyJustPressed(ebiten.KeyE) {
+		g.markedStation = under
+		return
+	}
+
+	edit := models.NetworkEdit{}
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyN):
+		x, y := g.screenToWorld(cursor.X, cursor.Y)
+		edit.Kind, edit.Position = models.EditAddStation, models.NewVector(x, y)
+		edit.Name = fmt.Sprintf("Station %d", len(g.stations)+1)
+	case inpututil.IsKeyJustPressed(ebiten.KeyM):
+		if g.markedStation == nil {
+			g.editStatus = "Move: mark a station with E first"
+			return
+		}
+		x, y := g.screenToWorld(cursor.X, cursor.Y)
+		edit.Kind, edit.StationID, edit.Position = models.EditMoveStation, g.markedStation.ID, models.NewVector(x, y)
+	case inpututil.IsKeyJustPressed(ebiten.KeyC):
+		edit.Kind = models.EditAddEdge
+	case inpututil.IsKeyJustPressed(ebiten.KeyX):
+		edit.Kind = models.EditDeleteEdge
+	case inpututil.IsKeyJustPressed(ebiten.KeyY):
+		edit.Kind = models.EditReorderLine
+	default:
+		return
+	}
+
+	if edit.Kind == models.EditAddEdge || edit.Kind == models.EditDeleteEdge || edit.Kind == models.EditReorderLine {
+		if g.markedStation == nil || under == nil {
+			g.editStatus = fmt.Sprintf("%s: mark a station with E and point at another", edit.Kind)
+			return
+		}
+	}
+	if edit.Kind == models.EditAddEdge || edit.Kind == models.EditDeleteEdge {
+		edit.StationID, edit.ToStationID = g.markedStation.ID, under.ID
+	}
+	if edit.Kind == models.EditReorderLine {
+		line, ok := g.extendLine(g.markedStation, under)
+		if !ok {
+			g.editStatus = fmt.Sprintf("Extend: no line ends at %s", g.markedStation.Name)
+			return
+		}
+		edit.LineID, edit.StationIDs = line.ID, make([]int64, 0, len(line.Stations))
+		for _, st := range line.Stations {
+			edit.StationIDs = append(edit.StationIDs, st.ID)
+		}
+	}
+	g.editStatus = fmt.Sprintf("Sent %s...", edit.Kind)
+	g.editReply = g.editor.Submit(edit)
+}
+
+// extendLine returns a line ending at a station, with the station to add on that end
+func (g *Game) extendLine(end, added *models.Station) (models.Line, bool) {
+	for _, line := range g.lines {
+		last := len(line.Stations) - 1
+		switch {
+		case last < 0:
+			continue
+		case line.Stations[last] == end:
+			line.Stations = append(line.Stations, added)
+			return line, true
+		case line.Stations[0] == end:
+			line.Stations = append([]*models.Station{added}, line.Stations...)
+			return line, true
+		}
+	}
+	return models.Line{}, false
+}
+
+// stationAt returns the station whose symbol is under a point of the screen, nil
+// when there is none
+func (g *Game) stationAt(point models.Vector) *models.Station {
+	for _, sym := range g.mapSymbols() {
+		screenX, screenY := g.worldToScreen(sym.position.X, sym.position.Y)
+		st := sym.stations[0]
+		if g.isPointInBounds(point, models.NewVector(screenX, screenY), st.FrameWidth, st.FrameHeight) {
+			return st
+		}
+	}
+	return nil
+}
+
+// drawNetworkEdits rings the marked station and writes the answer to the last edit
+// in the bottom-left corner
+func (g *Game) drawNetworkEdits(screen *ebiten.Image) {
+	editColor := color.RGBA{255, 170, 60, 255}
+	if st := g.markedStation; st != nil {
+		position := st.GetPosition()
+		x, y := g.worldToScreen(position.X, position.Y)
+		radius := float64(st.FrameWidth)/2 + 5
+		vector.StrokeCircle(screen, float32(x), float32(y), float32(radius), 2, editColor, true)
+	}
+	if g.editStatus != "" {
+		DrawColoredText(screen, g.editStatus, 10, float32(control.DefaultConfig.DisplayScreenHeight-20), XS_FONT_SIZE, editColor)
+	}
+}
@@ -5,12 +5,17 @@ import (
 	"image"
 	"image/color"
 	"math"
+	"strings"
+	"sync"
 
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
 	"github.com/hajimehoshi/ebiten/v2/vector"
 	"github.com/odin-software/metro/control"
+	"github.com/odin-software/metro/internal/clock"
 	"github.com/odin-software/metro/internal/models"
 	"github.com/odin-software/metro/internal/tenjin"
+	"github.com/odin-software/metro/internal/tenjin/intelligence"
 )
 
 type SceneType int
@@ -21,18 +26,46 @@ const (
 	SceneNewspaper
 )
 
+// trackCellSize is the cell size of the spatial index used for the tracks, in world pixels.
+const trackCellSize = 50.0
+
 type Game struct {
 	trains             []models.Train
 	stations           []*models.Station
 	lines              []models.Line
+	tracks             []models.TrackSegment // Directed track geometry, one per direction
+	editor             *models.NetworkEditor
+	networkVersion     uint64 // Version of the network the stations, lines and tracks come from
 	selectedTrain      *models.Train
 	selectedStation    *models.Station
 	currentScene       SceneType
 	lastMouseClick     bool
 	brain              *tenjin.Tenjin
 	scoreBreakdownOpen bool
-	clock              interface{ GetCurrentTime() string; GetCurrentTimeOfDay() int } // Simulation clock interface
+	clock              SimulationClock                                                  // Simulation clock interface
 	scheduleDB         ScheduleDB                                                       // Schedule database access
+	specialEvents      []*models.SpecialEvent                                           // Events marked on the map while they draw crowds
+	timelines          TimelineSource                                                   // Journey timelines of the passengers
+	selectedPassenger  *models.Passenger                                                // Passenger whose timeline is shown in the station scene
+	timeline           models.Timeline                                                  // Timeline of the selected passenger
+	timelineAge        int                                                              // Frames since the timeline was loaded
+	timelineReply      <-chan loadedTimeline                                            // Timeline being loaded, nil when none is
+	predictions        PredictionSource                                                 // Predicted arrivals for the countdowns
+	controls           SimulationControls                                               // Pause, step and speed of the simulation, nil hides them
+	simulation         Simulation                                                       // Locked while the game reads the simulation, nil when nothing ticks it
+	renderOnChange     bool                                                             // Only draw when checkRedraw saw a change
+	redraw             bool                                                             // The next draw has something new to show
+	seenTicks          int                                                              // Simulation ticks at the last check for changes
+	seenCursor         image.Point                                                      // Cursor position at the last check for changes
+	framesSinceDraw    int                                                              // Updates since the screen was last drawn
+	staticLayer        *ebiten.Image                                                    // Tracks and lines of the map, see drawStaticLayer
+	staticKey          staticLayerKey                                                   // What the static layer was drawn for
+	commands           TrainCommands                                                    // Where the commands of the operator go, nil disables them
+	commandReply       <-chan models.CommandAck                                         // Answer to the last command of the operator, nil once read
+	commandStatus      string                                                           // Last answer to the operator
+	markedStation      *models.Station                                                  // Station the next edit of the network starts from
+	editReply          <-chan models.EditResult                                         // Answer to the last edit of the network, nil once read
+	editStatus         string                                                           // Last answer to an edit of the network
 
 	// Camera controls
 	cameraZoom   float64 // Zoom level (1.0 = normal, 2.0 = 2x zoom)
@@ -40,13 +73,55 @@ type Game struct {
 	cameraOffsetY float64 // Camera pan offset Y
 }
 
+// Simulation is the scheduler ticking the trains, stations and passengers the game
+// shows. The game locks it while it reads them.
+type Simulation interface {
+	sync.Locker
+	Ticks() int
+}
+
+// SimulationClock gives the game the simulated time and date
+type SimulationClock interface {
+	GetCurrentTime() string
+	GetCurrentTimeOfDay() int
+	GetDay() int
+	GetCurrentDate() string
+	GetDayType() models.DayType
+}
+
 // ScheduleDB interface for accessing schedule data
 type ScheduleDB interface {
-	GetScheduleByTrainAndStation(trainID, stationID int64) (Schedule, error)
+	GetScheduleByTrainAndStation(trainID, stationID int64, dayType models.DayType) (Schedule, error)
 	GetScheduleForTrain(trainID int64) ([]Schedule, error)
 	GetScheduleForStation(stationID int64) ([]Schedule, error)
 }
 
+// TimelineSource gives the journey timelines of the passengers
+type TimelineSource interface {
+	PassengerTimeline(passengerID string) (models.Timeline, error)
+}
+
+// PredictionSource gives the predicted arrivals of the trains at a station
+type PredictionSource interface {
+	ForStation(stationID int64) []models.ArrivalPrediction
+}
+
+// SimulationControls pause, step and warp the simulation
+type SimulationControls interface {
+	TogglePause()
+	Paused() bool
+	Step(ticks int) error
+	SetWarp(warp float64) error
+	Warp() float64
+	Faster()
+	Slower()
+}
+
+// TrainCommands sends commands to the trains and answers at the next tick
+type TrainCommands interface {
+	Send(command models.Command) <-chan models.CommandAck
+}
+
 // Schedule represents a scheduled stop
 type Schedule struct {
 	TrainID       int64
@@ -55,13 +130,14 @@ type Schedule struct {
 	SequenceOrder int64
 }
 
-func NewGame(trains []models.Train, stations []*models.Station, lines []models.Line, brain *tenjin.Tenjin, clock interface{ GetCurrentTime() string; GetCurrentTimeOfDay() int }, scheduleDB ScheduleDB) *Game {
+func NewGame(trains []models.Train, stations []*models.Station, lines []models.Line, editor *models.NetworkEditor, brain *tenjin.Tenjin, clock SimulationClock, scheduleDB ScheduleDB) *Game {
 	Init()
 	models.LineInit()
-	return &Game{
+	g := &Game{
 		trains:       trains,
 		stations:     stations,
 		lines:        lines,
+		editor:       editor,
 		currentScene: SceneMap,
 		scheduleDB:   scheduleDB,
 		brain:        brain,
@@ -69,10 +145,54 @@ func NewGame(trains []models.Train, stations []*models.Station, lines []models.L
 		cameraZoom:   1.0, // Start at 1x zoom
 		cameraOffsetX: 0,
 		cameraOffsetY: 0,
+		renderOnChange: control.DefaultConfig.RenderOnChange,
+		redraw:         true,
 	}
+	g.refreshNetwork()
+	return g
+}
+
+// SetSpecialEvents sets the special events marked on the map
+func (g *Game) SetSpecialEvents(events []*models.SpecialEvent) {
+	g.specialEvents = events
+}
+
+// SetTimelines sets where the station scene reads the timelines of the passengers from
+func (g *Game) SetTimelines(timelines TimelineSource) {
+	g.timelines = timelines
+}
+
+// SetPredictions sets where the countdowns of the station scene come from
+func (g *Game) SetPredictions(predictions PredictionSource) {
+	g.predictions = predictions
+}
+
+// SetControls sets the controls of the speed bar and the keyboard shortcuts
+func (g *Game) SetControls(controls SimulationControls) {
+	g.controls = controls
+}
+
+// SetCommands sets where the commands of the operator for the selected train go
+func (g *Game) SetCommands(commands TrainCommands) {
+	g.commands = commands
+}
+
+// SetSimulation sets the scheduler that is locked while the game reads and edits the
+// trains, stations and passengers
+func (g *Game) SetSimulation(simulation Simulation) {
+	g.simulation = simulation
 }
 
 func (g *Game) Update() error {
+	if g.simulation != nil {
+		g.simulation.Lock()
+		defer g.simulation.Unlock()
+	}
+
+	if g.editor != nil && g.editor.Version() != g.networkVersion {
+		g.refreshNetwork()
+	}
+
 	for i := range g.trains {
 		g.trains[i].Update()
 	}
@@ -85,12 +205,104 @@ func (g *Game) Update() error {
 		g.handleCameraControls()
 	}
 
+	// Handle pause, step and speed shortcuts (any scene)
+	g.handleSpeedControls()
+
+	// Handle the commands of the operator for the selected train
+	if g.currentScene == SceneMap {
+		g.handleTrainCommands()
+		g.handleHeadwayControl()
+		g.handleNetworkEdits()
+	}
+
 	// Handle mouse clicks
 	g.handleMouseClick()
 
+	// Keep the timeline of the selected passenger up to date, about once a second
+	if g.selectedPassenger != nil {
+		g.receiveTimeline()
+		g.timelineAge++
+		if g.timelineAge >= ebiten.TPS() {
+			g.loadTimeline()
+		}
+	}
+
+	if g.renderOnChange {
+		g.checkRedraw()
+	}
+
 	return nil
 }
 
+// selectPassenger shows the timeline of the passenger, nil hides it
+func (g *Game) selectPassenger(p *models.Passenger) {
+	g.selectedPassenger = p
+	g.timeline = nil
+	g.timelineReply = nil
+	if p != nil {
+		g.loadTimeline()
+	}
+}
+
+// loadedTimeline is the timeline of a passenger read from the journal
+type loadedTimeline struct {
+	passengerID string
+	timeline    models.Timeline
+	err         error
+}
+
+// loadTimeline reads the timeline of the selected passenger in the background, so the
+// query doesn't hold the simulation up. receiveTimeline picks the answer up.
+func (g *Game) loadTimeline() {
+	g.timelineAge = 0
+	if g.timelines == nil || g.selectedPassenger == nil || g.timelineReply != nil {
+		return
+	}
+	reply := make(chan loadedTimeline, 1)
+	g.timelineReply = reply
+	go func(timelines TimelineSource, passengerID string) {
+		timeline, err := timelines.PassengerTimeline(passengerID)
+		reply <- loadedTimeline{passengerID: passengerID, timeline: timeline, err: err}
+	}(g.timelines, g.selectedPassenger.ID)
+}
+
+// receiveTimeline shows the timeline loaded for the selected passenger, once it is read
+func (g *Game) receiveTimeline() {
+	if g.timelineReply == nil {
+		return
+	}
+	select {
+	case loaded := <-g.timelineReply:
+		g.timelineReply = nil
+		if g.selectedPassenger == nil || loaded.passengerID != g.selectedPassenger.ID {
+			return
+		}
+		if loaded.err != nil {
+			control.Log(fmt.Sprintf("Error loading the timeline of %s: %v", loaded.passengerID, loaded.err))
+			return
+		}
+		g.timeline = loaded.timeline
+	default:
+	}
+}
+
+// refreshNetwork takes a new snapshot of the stations, lines and tracks from the editor
+func (g *Game) refreshNetwork() {
+	if g.editor == nil {
+		return
+	}
+	g.networkVersion = g.editor.Version()
+	g.stations = g.editor.Stations()
+	g.lines = g.editor.Lines()
+
+	index, err := g.editor.SpatialIndex(trackCellSize)
+	if err != nil {
+		control.Log(fmt.Sprintf("Error indexing tracks: %v", err))
+		return
+	}
+	g.tracks = index.Tracks()
+}
+
 // formatTime converts seconds since midnight to HH:MM format
 func (g *Game) formatTime(secondsSinceMidnight int) string {
 	hours := secondsSinceMidnight / 3600
@@ -103,16 +315,16 @@ func (g *Game) handleCameraControls() {
 	// Zoom controls: Mouse wheel or +/- keys
 	_, wheelY := ebiten.Wheel()
 	if wheelY != 0 {
-		zoomDelta := wheelY * 0.1 // 0.1 zoom per wheel notch
+		zoomDelta := wheelY * 0.1 // 0.1 zoom per wheel notch, at any render rate
 		g.cameraZoom += zoomDelta
 	}
 
 	// Keyboard zoom controls
 	if ebiten.IsKeyPressed(ebiten.KeyEqual) || ebiten.IsKeyPressed(ebiten.KeyKPAdd) {
-		g.cameraZoom += 0.02 // Smooth zoom in
+		g.cameraZoom += 0.02 * frameScale() // Smooth zoom in
 	}
 	if ebiten.IsKeyPressed(ebiten.KeyMinus) || ebiten.IsKeyPressed(ebiten.KeyKPSubtract) {
-		g.cameraZoom -= 0.02 // Smooth zoom out
+		g.cameraZoom -= 0.02 * frameScale() // Smooth zoom out
 	}
 
 	// Clamp zoom between 0.5x and 10x
@@ -124,7 +336,7 @@ func (g *Game) handleCameraControls() {
 	}
 
 	// Pan controls: Arrow keys or WASD
-	panSpeed := 5.0 / g.cameraZoom // Pan slower when zoomed in
+	panSpeed := 5.0 * frameScale() / g.cameraZoom // Pan slower when zoomed in
 
 	if ebiten.IsKeyPressed(ebiten.KeyArrowLeft) || ebiten.IsKeyPressed(ebiten.KeyA) {
 		g.cameraOffsetX += panSpeed
@@ -188,7 +400,20 @@ func (g *Game) handleMouseClick() {
 			if g.isPointInBackButton(mousePos) {
 				g.currentScene = SceneMap
 				g.selectedStation = nil
+				g.selectPassenger(nil)
+				return
+			}
+
+			// Tapping a passenger shows their timeline, tapping elsewhere hides it
+			var tapped *models.Passenger
+			passengers, positions, _ := g.stationScenePassengers(g.selectedStation)
+			for i, p := range passengers {
+				if mousePos.Dist(positions[i]) <= 12 {
+					tapped = p
+					break
+				}
 			}
+			g.selectPassenger(tapped)
 		} else if g.currentScene == SceneNewspaper {
 			// In newspaper scene, check for back button
 			if g.isPointInBackButton(mousePos) {
@@ -208,11 +433,23 @@ func (g *Game) handleMouseClick() {
 				return
 			}
 
+			// Check if clicked on the speed bar
+			for _, button := range g.speedButtons() {
+				if button.contains(mousePos) {
+					button.action()
+					g.lastMouseClick = mousePressed
+					return
+				}
+			}
+
 			// Clear previous train selection
 			g.selectedTrain = nil
 
 			// Check if clicked on a train (compare in screen space)
 			for i := range g.trains {
+				if !g.trains[i].InService() {
+					continue
+				}
 				// Convert train position to screen space
 				screenX, screenY := g.worldToScreen(g.trains[i].Position.X, g.trains[i].Position.Y)
 				screenPos := models.NewVector(screenX, screenY)
@@ -225,11 +462,12 @@ func (g *Game) handleMouseClick() {
 
 			// If no train clicked, check stations (switch to station scene)
 			if g.selectedTrain == nil {
-				for _, st := range g.stations {
+				for _, sym := range g.mapSymbols() {
 					// Convert station position to screen space
-					screenX, screenY := g.worldToScreen(st.Position.X, st.Position.Y)
+					screenX, screenY := g.worldToScreen(sym.position.X, sym.position.Y)
 					screenPos := models.NewVector(screenX, screenY)
 
+					st := sym.stations[0]
 					if g.isPointInBounds(mousePos, screenPos, st.FrameWidth, st.FrameHeight) {
 						g.selectedStation = st
 						g.currentScene = SceneStation
@@ -271,6 +509,20 @@ func (g *Game) isPointInBounds(point, objPos models.Vector, width, height int) b
 }
 
 func (g *Game) Draw(screen *ebiten.Image) {
+	// The screen keeps the last frame when nothing changed
+	if g.renderOnChange {
+		if !g.redraw {
+			return
+		}
+		g.redraw = false
+		g.framesSinceDraw = 0
+		screen.Clear()
+	}
+	if g.simulation != nil {
+		g.simulation.Lock()
+		defer g.simulation.Unlock()
+	}
+
 	if g.currentScene == SceneStation {
 		g.drawStationScene(screen)
 	} else if g.currentScene == SceneNewspaper {
@@ -281,18 +533,15 @@ func (g *Game) Draw(screen *ebiten.Image) {
 }
 
 func (g *Game) drawMapScene(screen *ebiten.Image) {
-	// Draw lines first (background layer) - lines draw between stations
-	for _, line := range g.lines {
-		// Lines need to be drawn with transformed endpoints
-		// For now, we'll draw them normally since Line.Draw handles its own rendering
-		// TODO: Implement line drawing with camera transform
-		g.drawLineTransformed(screen, line)
-	}
+	// Draw tracks and lines first (background layer), cached until the camera moves
+	g.drawStaticLayer(screen)
 
-	// Draw stations with camera transform
-	for _, st := range g.stations {
+	// Draw stations with camera transform, the stations of a complex share one symbol
+	symbols := g.mapSymbols()
+	for _, sym := range symbols {
 		// Get screen position
-		screenX, screenY := g.worldToScreen(st.Position.X, st.Position.Y)
+		st := sym.stations[0]
+		screenX, screenY := g.worldToScreen(sym.position.X, sym.position.Y)
 
 		// Calculate current animation frame
 		i := (st.Counter / st.FrameCount) % st.FrameCount
@@ -307,13 +556,20 @@ func (g *Game) drawMapScene(screen *ebiten.Image) {
 
 		screen.DrawImage(frame, op)
 
+		if sym.interchange {
+			g.drawInterchange(screen, sym, screenX, screenY)
+		}
+
 		// Draw waiting passengers
-		g.drawWaitingPassengersTransformed(screen, st)
+		g.drawWaitingPassengersTransformed(screen, sym)
 	}
 
 	// Draw trains with camera transform
 	for i := range g.trains {
 		tr := &g.trains[i]
+		if !tr.InService() {
+			continue
+		}
 		// Get screen position
 		screenX, screenY := g.worldToScreen(tr.Position.X, tr.Position.Y)
 
@@ -332,25 +588,35 @@ func (g *Game) drawMapScene(screen *ebiten.Image) {
 	}
 
 	// Draw text labels in screen space (crisp text regardless of zoom)
-	for _, st := range g.stations {
+	for _, sym := range symbols {
 		// Transform station position to screen space
-		screenX, screenY := g.worldToScreen(st.Position.X, st.Position.Y)
+		st := sym.stations[0]
+		screenX, screenY := g.worldToScreen(sym.position.X, sym.position.Y)
 		screenPos := models.NewVector(screenX, screenY)
 
 		// Draw station name
-		DrawTitle(screen, st.Name, screenPos, XS_FONT_SIZE, st.FrameWidth, st.FrameHeight, TITLE_BOT_SIDE)
+		DrawTitle(screen, sym.name, screenPos, XS_FONT_SIZE, st.FrameWidth, st.FrameHeight, TITLE_BOT_SIDE)
 
 		// Draw passenger count
-		waitingCount := st.GetWaitingPassengersCount()
+		waitingCount := 0
+		for _, member := range sym.stations {
+			waitingCount += member.GetWaitingPassengersCount()
+		}
 		if waitingCount > 0 {
 			countText := fmt.Sprintf("%d", waitingCount)
 			DrawInfo(screen, countText, screenPos, S_FONT_SIZE, st.FrameWidth, st.FrameHeight)
 		}
 	}
 
+	// Draw the special events drawing crowds, above the station labels
+	g.drawSpecialEvents(screen)
+
 	// Draw train labels in screen space
 	for i := range g.trains {
 		tr := &g.trains[i]
+		if !tr.InService() {
+			continue
+		}
 		// Transform train position to screen space
 		screenX, screenY := g.worldToScreen(tr.Position.X, tr.Position.Y)
 		screenPos := models.NewVector(screenX, screenY)
@@ -375,14 +641,26 @@ func (g *Game) drawMapScene(screen *ebiten.Image) {
 	// Draw score overlay (always visible)
 	g.drawScoreOverlay(screen)
 
+	// Draw Tenjin recommendations (bottom-left)
+	g.drawRecommendations(screen)
+
 	// Draw newspaper button (top-right corner)
 	g.drawNewspaperButton(screen)
 
 	// Draw simulation clock (top-center)
 	g.drawSimulationClock(screen)
 
+	// Draw pause, step and speed buttons (below the clock)
+	g.drawSpeedControls(screen)
+
 	// Draw camera controls help (bottom-right)
 	g.drawCameraHelp(screen)
+
+	// Draw the headway regularity of the lines (above the controls help)
+	g.drawRegularity(screen)
+
+	// Draw the marked station and the answer to the last edit of the network
+	g.drawNetworkEdits(screen)
 }
 
 // drawLineTransformed draws a line with camera transform applied
@@ -403,14 +681,39 @@ func (g *Game) drawLineTransformed(screen *ebiten.Image, line models.Line) {
 		st2 := stations[i+1]
 
 		// Transform both endpoints to screen space
-		x1, y1 := g.worldToScreen(st1.Position.X, st1.Position.Y)
-		x2, y2 := g.worldToScreen(st2.Position.X, st2.Position.Y)
+		p1, p2 := st1.GetPosition(), st2.GetPosition()
+		x1, y1 := g.worldToScreen(p1.X, p1.Y)
+		x2, y2 := g.worldToScreen(p2.X, p2.Y)
 
 		// Draw as dashed line (draw short segments with gaps)
 		g.drawDashedLine(screen, x1, y1, x2, y2, lineColor)
 	}
 }
 
+// drawTracksTransformed draws every directed track with camera transform applied.
+// Each direction is shifted to its right in screen space so both directions of a
+// corridor show up as parallel tracks, even when they share the same geometry.
+func (g *Game) drawTracksTransformed(screen *ebiten.Image) {
+	trackColor := color.RGBA{90, 90, 90, 200}
+	const trackSpacing = 3.0 // Screen pixels between the two directions
+
+	for i := range g.tracks {
+		points := g.tracks[i].Line.Points()
+		screenPoints := make([]models.Vector, len(points))
+		for j, p := range points {
+			x, y := g.worldToScreen(p.X, p.Y)
+			screenPoints[j] = models.NewVector(x, y)
+		}
+		screenPoints = models.OffsetPoints(screenPoints, trackSpacing/2)
+
+		for j := 0; j < len(screenPoints)-1; j++ {
+			a, b := screenPoints[j], screenPoints[j+1]
+			vector.StrokeLine(screen, float32(a.X), float32(a.Y), float32(b.X), float32(b.Y),
+				1.0, trackColor, true)
+		}
+	}
+}
+
 // drawDashedLine draws a dashed line between two points
 func (g *Game) drawDashedLine(screen *ebiten.Image, x1, y1, x2, y2 float64, lineColor color.Color) {
 	// Calculate line length
@@ -455,9 +758,106 @@ func (g *Game) drawDashedLine(screen *ebiten.Image, x1, y1, x2, y2 float64, line
 	}
 }
 
+// mapSymbol is what the map draws for a station, or for all the stations of a complex.
+type mapSymbol struct {
+	name        string
+	position    models.Vector
+	stations    []*models.Station
+	interchange bool
+}
+
+// mapSymbols returns one symbol per station, except for the stations of a complex
+// that share a single interchange symbol.
+func (g *Game) mapSymbols() []mapSymbol {
+	symbols := make([]mapSymbol, 0, len(g.stations))
+	complexes := make(map[*models.StationComplex]int)
+	for _, st := range g.stations {
+		if st.Complex == nil {
+			symbols = append(symbols, mapSymbol{name: st.Name, position: st.GetPosition(), stations: []*models.Station{st}})
+			continue
+		}
+		if i, ok := complexes[st.Complex]; ok {
+			symbols[i].stations = append(symbols[i].stations, st)
+			continue
+		}
+		complexes[st.Complex] = len(symbols)
+		symbols = append(symbols, mapSymbol{
+			name:        st.Complex.Name,
+			position:    st.Complex.Position(),
+			stations:    []*models.Station{st},
+			interchange: true,
+		})
+	}
+	return symbols
+}
+
+// drawInterchange rings the symbol of a complex and links it to the stations of
+// the complex that are drawn apart from it
+func (g *Game) drawInterchange(screen *ebiten.Image, sym mapSymbol, screenX, screenY float64) {
+	ringColor := color.RGBA{255, 255, 255, 220}
+	radius := float64(sym.stations[0].FrameWidth)/2 + 2
+	vector.StrokeCircle(screen, float32(screenX), float32(screenY), float32(radius), 2, ringColor, true)
+
+	for _, st := range sym.stations {
+		position := st.GetPosition()
+		x, y := g.worldToScreen(position.X, position.Y)
+		if math.Hypot(x-screenX, y-screenY) <= radius {
+			continue
+		}
+		vector.StrokeLine(screen, float32(screenX), float32(screenY), float32(x), float32(y), 2, ringColor, true)
+		vector.DrawFilledCircle(screen, float32(x), float32(y), 3, ringColor, true)
+	}
+}
+
+// drawSpecialEvents rings the stations of the special events whose crowds are
+// arriving, attending or leaving, with the name of the event and what is going on
+func (g *Game) drawSpecialEvents(screen *ebiten.Image) {
+	if g.clock == nil {
+		return
+	}
+	timeOfDay := g.clock.GetCurrentTimeOfDay()
+	day := g.clock.GetDay()
+	eventColor := color.RGBA{255, 170, 0, 255}
+
+	for _, ev := range g.specialEvents {
+		phase := ev.Phase(control.DefaultConfig.Events, day, timeOfDay)
+		if phase == models.EventPhaseNone {
+			continue
+		}
+
+		// Stations of a complex share the symbol of the complex
+		position := ev.Station.GetPosition()
+		if ev.Station.Complex != nil {
+			position = ev.Station.Complex.Position()
+		}
+		x, y := g.worldToScreen(position.X, position.Y)
+		radius := float32(ev.Station.FrameWidth)/2 + 8
+		vector.StrokeCircle(screen, float32(x), float32(y), radius, 3, eventColor, true)
+
+		label := fmt.Sprintf("%s: %s", ev.Name, eventPhaseLabel(phase))
+		DrawColoredText(screen, label, float32(x)+radius+4, float32(y)-radius-float32(S_FONT_SIZE), S_FONT_SIZE, eventColor)
+	}
+}
+
+func eventPhaseLabel(phase models.EventPhase) string {
+	switch phase {
+	case models.EventPhaseArriving:
+		return "fans arriving"
+	case models.EventPhaseLeaving:
+		return "fans leaving"
+	default:
+		return "underway"
+	}
+}
+
 // drawWaitingPassengersTransformed draws passenger dots with camera transform
-func (g *Game) drawWaitingPassengersTransformed(screen *ebiten.Image, st *models.Station) {
-	passengers := st.GetWaitingPassengers()
+func (g *Game) drawWaitingPassengersTransformed(screen *ebiten.Image, sym mapSymbol) {
+	passengers := make([]*models.Passenger, 0)
+	crowding := 0.0
+	for _, st := range sym.stations {
+		passengers = append(passengers, st.GetWaitingPassengers()...)
+		crowding = math.Max(crowding, st.CrowdingLevel())
+	}
 	if len(passengers) == 0 {
 		return
 	}
@@ -470,7 +870,12 @@ func (g *Game) drawWaitingPassengersTransformed(screen *ebiten.Image, st *models
 
 	// Arrange dots in a circle around the station (constant screen-space radius)
 	radius := 20.0 // Screen pixels
-	stationScreenX, stationScreenY := g.worldToScreen(st.Position.X, st.Position.Y)
+	stationScreenX, stationScreenY := g.worldToScreen(sym.position.X, sym.position.Y)
+
+	// Ring around crowded stations
+	if ringColor := crowdingColor(crowding); ringColor != nil {
+		vector.StrokeCircle(screen, float32(stationScreenX), float32(stationScreenY), float32(radius+5), 2, ringColor, true)
+	}
 
 	for i := 0; i < maxDots; i++ {
 		angle := float64(i) * (2.0 * math.Pi / float64(maxDots))
@@ -497,10 +902,11 @@ func (g *Game) drawWaitingPassengers(screen *ebiten.Image, st *models.Station) {
 
 	// Arrange dots in a circle around the station
 	radius := float32(20.0)
+	stPos := st.GetPosition()
 	for i := 0; i < maxDots; i++ {
 		angle := float64(i) * (2.0 * math.Pi / float64(maxDots))
-		x := st.Position.X + float64(radius)*math.Cos(angle)
-		y := st.Position.Y + float64(radius)*math.Sin(angle)
+		x := stPos.X + float64(radius)*math.Cos(angle)
+		y := stPos.Y + float64(radius)*math.Sin(angle)
 
 		// Draw passenger as a small colored circle
 		passengerColor := getPassengerColor(passengers[i].Sentiment)
@@ -527,6 +933,9 @@ func (g *Game) drawTrainDataPanel(screen *ebiten.Image) {
 	panelY := float32(10)
 	panelW := float32(190)
 	panelH := float32(160) // Increased height for schedule info
+	if g.commands != nil {
+		panelH += 45 // Commands of the operator
+	}
 
 	// Draw panel background
 	vector.DrawFilledRect(screen, panelX, panelY, panelW, panelH, color.RGBA{30, 30, 40, 230}, false)
@@ -548,7 +957,7 @@ func (g *Game) drawTrainDataPanel(screen *ebiten.Image) {
 
 		// Show scheduled arrival time if available
 		if g.scheduleDB != nil && g.clock != nil {
-			schedule, err := g.scheduleDB.GetScheduleByTrainAndStation(tr.ID, tr.Next.ID)
+			schedule, err := g.scheduleDB.GetScheduleByTrainAndStation(tr.ID, tr.Next.ID, g.clock.GetDayType())
 			if err == nil {
 				scheduledTime := g.formatTime(schedule.ScheduledTime)
 				currentTime := g.clock.GetCurrentTimeOfDay()
@@ -564,6 +973,8 @@ func (g *Game) drawTrainDataPanel(screen *ebiten.Image) {
 				DrawDataText(screen, fmt.Sprintf("Sched: %s%s", scheduledTime, etaText), panelX+10, yPos, XS_FONT_SIZE)
 			}
 		}
+	} else if !tr.InService() {
+		DrawDataText(screen, "In the depot", panelX+10, yPos, S_FONT_SIZE)
 	} else if tr.Current != nil {
 		DrawDataText(screen, fmt.Sprintf("At: %s", tr.Current.Name), panelX+10, yPos, S_FONT_SIZE)
 	}
@@ -600,6 +1011,73 @@ func (g *Game) drawTrainDataPanel(screen *ebiten.Image) {
 		barColor = color.RGBA{200, 200, 0, 255}
 	}
 	vector.DrawFilledRect(screen, panelX+10, float32(yPos), barW*float32(capacityPct), barH, barColor, false)
+	yPos += barH + 8
+
+	// Commands of the operator and the answer to the last one
+	if g.commands != nil {
+		DrawDataText(screen, "H hold  K skip  T turn  L limit", panelX+10, yPos, XS_FONT_SIZE)
+		yPos += 12
+		DrawDataText(screen, "O out of / I into service", panelX+10, yPos, XS_FONT_SIZE)
+		yPos += 12
+		if lines := g.wrapText(g.commandStatus, 34); len(lines) > 0 {
+			DrawColoredText(screen, lines[0], panelX+10, yPos, XS_FONT_SIZE, color.RGBA{180, 200, 255, 255})
+		}
+	}
+}
+
+// operatorHold and operatorSpeedCap are the hold and speed cap the operator sends
+const (
+	operatorHold     = 30 // Seconds
+	operatorSpeedCap = 40 // km/h
+)
+
+// handleTrainCommands sends the commands of the operator to the selected train: H
+// holds it at the next station, K skips the next stop, T turns it back at the next
+// stop, L caps its speed or lifts the cap, O takes it out of service and I puts it
+// back. The answer of the train shows in its panel.
+func (g *Game) handleTrainCommands() {
+	if g.commandReply != nil {
+		select {
+		case ack := <-g.commandReply:
+			g.commandStatus = ack.Reason
+			if !ack.Accepted {
+				g.commandStatus = "Rejected: " + ack.Reason
+			}
+			g.commandReply = nil
+		default:
+		}
+	}
+
+	tr := g.selectedTrain
+	if g.commands == nil || tr == nil {
+		return
+	}
+	command := models.Command{TrainID: tr.ID, Source: "operator"}
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyH):
+		command.Kind, command.Seconds = models.CommandHold, operatorHold
+	case inpututil.IsKeyJustPressed(ebiten.KeyK):
+		command.Kind = models.CommandSkipStop
+	case inpututil.IsKeyJustPressed(ebiten.KeyT):
+		if tr.Next == nil {
+			g.commandStatus = "Turn back: wait until the train leaves"
+			return
+		}
+		command.Kind, command.StationID = models.CommandShortTurn, tr.Next.ID
+	case inpututil.IsKeyJustPressed(ebiten.KeyL):
+		command.Kind, command.SpeedKmH = models.CommandSpeedCap, operatorSpeedCap
+		if tr.GetSpeedCapKmH() > 0 {
+			command.SpeedKmH = 0
+		}
+	case inpututil.IsKeyJustPressed(ebiten.KeyO):
+		command.Kind = models.CommandOutOfService
+	case inpututil.IsKeyJustPressed(ebiten.KeyI):
+		command.Kind = models.CommandEnterService
+	default:
+		return
+	}
+	g.commandStatus = fmt.Sprintf("Sent %s...", command.Kind)
+	g.commandReply = g.commands.Send(command)
 }
 
 func (g *Game) drawStationScene(screen *ebiten.Image) {
@@ -623,36 +1101,277 @@ func (g *Game) drawStationScene(screen *ebiten.Image) {
 	stationNameY := float32(30)
 	DrawDataText(screen, st.Name, float32(control.DefaultConfig.DisplayScreenWidth/2-50), stationNameY, L_FONT_SIZE)
 
-	// Draw waiting count
+	// Draw waiting count, against the platform capacity when there is one
 	waitingCount := st.GetWaitingPassengersCount()
-	DrawDataText(screen, fmt.Sprintf("Waiting Passengers: %d", waitingCount), 20, 80, M_FONT_SIZE)
+	if st.PlatformCapacity > 0 {
+		DrawDataText(screen, fmt.Sprintf("Waiting Passengers: %d/%d", waitingCount, st.PlatformCapacity), 20, 80, M_FONT_SIZE)
+		g.drawCrowdingBar(screen, st.CrowdingLevel(), float32(control.DefaultConfig.DisplayScreenWidth-220), 72)
+	} else {
+		DrawDataText(screen, fmt.Sprintf("Waiting Passengers: %d", waitingCount), 20, 80, M_FONT_SIZE)
+	}
+
+	// Draw the queue outside the fare gates
+	gateLabel := fmt.Sprintf("Outside the gates: %d", st.GetEntryQueueCount())
+	if st.GateThroughput > 0 {
+		gateLabel += fmt.Sprintf(" (gates: %.0f/min)", st.GateThroughput)
+	}
+	DrawDataText(screen, gateLabel, 20, 105, S_FONT_SIZE)
+
+	// Draw the queue of each platform
+	platformY := float32(125)
+	for _, pl := range g.platformLabels(st) {
+		DrawDataText(screen, pl, 20, platformY, S_FONT_SIZE)
+		platformY += 20
+	}
+	if walking := st.GetWalkingPassengersCount(); walking > 0 {
+		DrawDataText(screen, fmt.Sprintf("Walking to platforms: %d", walking), 20, platformY, S_FONT_SIZE)
+		platformY += 20
+	}
+
+	// Draw the walks to the other stations of the interchange
+	for _, label := range g.interchangeLabels(st) {
+		DrawDataText(screen, label, 20, platformY, S_FONT_SIZE)
+		platformY += 20
+	}
+
+	// Draw the statistics of the day and the next trains
+	g.drawStationStats(screen, st, platformY+10)
 
 	// Draw passengers as sprites
-	passengers := st.GetWaitingPassengers()
-	if len(passengers) > 0 {
-		// Arrange passengers in rows on the platform
-		spacing := 60.0
-		perRow := 10
-		startX := 100.0
-		startY := float64(floorY) - 60 // Position passengers just above the floor
-
-		for i, p := range passengers {
-			row := i / perRow
-			col := i % perRow
-			x := startX + float64(col)*spacing
-			y := startY + float64(row)*spacing
-
-			// Draw passenger as colored circle (by sentiment)
-			sentimentColor := getPassengerColor(p.Sentiment)
-			vector.DrawFilledCircle(screen, float32(x), float32(y), 8, sentimentColor, true)
-
-			// Draw passenger outline
+	passengers, positions, hidden := g.stationScenePassengers(st)
+	if hidden > 0 {
+		DrawDataText(screen, fmt.Sprintf("+%d more on the platform", hidden), float32(positions[0].X), float32(positions[0].Y-40), S_FONT_SIZE)
+	}
+	for i, p := range passengers {
+		x, y := positions[i].X, positions[i].Y
+
+		// Draw passenger as colored circle (by sentiment)
+		sentimentColor := getPassengerColor(p.Sentiment)
+		vector.DrawFilledCircle(screen, float32(x), float32(y), 8, sentimentColor, true)
+
+		// Draw passenger outline, thicker for the selected passenger
+		if p == g.selectedPassenger {
+			vector.StrokeCircle(screen, float32(x), float32(y), 10, 2, color.RGBA{100, 150, 200, 255}, true)
+		} else {
 			vector.StrokeCircle(screen, float32(x), float32(y), 8, 1, color.White, true)
+		}
+
+		// Draw passenger name and persona below
+		DrawDataText(screen, p.Name, float32(x-20), float32(y+15), XS_FONT_SIZE)
+		DrawDataText(screen, p.Persona.Label, float32(x-20), float32(y+27), XS_FONT_SIZE)
+	}
+
+	// Draw the timeline of the tapped passenger
+	if g.selectedPassenger != nil {
+		g.drawTimelinePanel(screen)
+	}
+}
+
+// drawStationStats draws the dashboard of the station: passengers served today, the
+// queue, the waits and the next train of each platform with its load
+func (g *Game) drawStationStats(screen *ebiten.Image, st *models.Station, y float32) {
+	trains := make([]*models.Train, len(g.trains))
+	for i := range g.trains {
+		trains[i] = &g.trains[i]
+	}
+	stats := st.Stats(g.clock.GetDay(), trains)
+
+	DrawDataText(screen, fmt.Sprintf("Today: %d boarded, %d got off", stats.Boardings, stats.Alightings), 20, y, S_FONT_SIZE)
+	y += 20
+	DrawDataText(screen, fmt.Sprintf("Queue: %d (max %d)", stats.Queue, stats.MaxQueue), 20, y, S_FONT_SIZE)
+	y += 20
+	if stats.Boardings > 0 {
+		DrawDataText(screen, fmt.Sprintf("Wait: %s average, %s for 9 in 10", formatWait(stats.AverageWait), formatWait(stats.Wait90)), 20, y, S_FONT_SIZE)
+		y += 20
+	}
+
+	if len(stats.NextArrivals) == 0 {
+		DrawDataText(screen, "No trains on the way", 20, y, S_FONT_SIZE)
+		return
+	}
+	var predictions []models.ArrivalPrediction
+	if g.predictions != nil {
+		predictions = g.predictions.ForStation(st.ID)
+	}
+	DrawDataText(screen, "Next trains:", 20, y, S_FONT_SIZE)
+	y += 20
+	for _, next := range stats.NextArrivals {
+		where := "At platform"
+		if next.Distance > 0 {
+			where = models.FormatDistance(next.Distance) + " away"
+		}
+		for _, p := range predictions {
+			if p.TrainID == next.TrainID && p.Platform == next.Platform {
+				where = p.Countdown()
+				break
+			}
+		}
+		label := fmt.Sprintf("%s: %s (%s), %d/%d on board", g.platformName(next.Platform), next.TrainName, where, next.Passengers, next.Capacity)
+		textColor := crowdingColor(next.Load())
+		if textColor == nil {
+			textColor = color.White
+		}
+		DrawColoredText(screen, label, 30, y, XS_FONT_SIZE, textColor)
+		y += 15
+	}
+}
+
+// formatWait formats simulation seconds as minutes and seconds
+func formatWait(seconds float64) string {
+	s := int(math.Round(seconds))
+	return fmt.Sprintf("%dm%02ds", s/60, s%60)
+}
+
+// stationScenePassengers returns the waiting passengers drawn on the platform of the
+// station scene with their screen positions, and how many didn't fit
+func (g *Game) stationScenePassengers(st *models.Station) ([]*models.Passenger, []models.Vector, int) {
+	if st == nil {
+		return nil, nil, 0
+	}
+
+	// Arrange passengers in rows on the platform
+	spacing := 60.0
+	perRow := 10
+	maxRows := 2
+	startX := 100.0
+	startY := float64(control.DefaultConfig.DisplayScreenHeight-100) - 60 // Just above the floor
+
+	// Only the rows that fit on the platform are drawn
+	passengers := st.GetWaitingPassengers()
+	hidden := 0
+	if len(passengers) > perRow*maxRows {
+		hidden = len(passengers) - perRow*maxRows
+		passengers = passengers[:perRow*maxRows]
+	}
+
+	positions := make([]models.Vector, len(passengers))
+	for i := range passengers {
+		row := i / perRow
+		col := i % perRow
+		positions[i] = models.NewVector(startX+float64(col)*spacing, startY+float64(row)*spacing)
+	}
+	return passengers, positions, hidden
+}
 
-			// Draw passenger name below
-			DrawDataText(screen, p.Name, float32(x-20), float32(y+15), XS_FONT_SIZE)
+// drawTimelinePanel lists the moments of the journey of the selected passenger
+func (g *Game) drawTimelinePanel(screen *ebiten.Image) {
+	p := g.selectedPassenger
+	lines := g.timeline.Describe()
+	maxLines := 12
+	if len(lines) > maxLines {
+		lines = lines[len(lines)-maxLines:]
+	}
+
+	panelW := float32(320)
+	panelH := float32(50 + 15*maxLines)
+	panelX := float32(control.DefaultConfig.DisplayScreenWidth) - panelW - 10
+	panelY := float32(100)
+	vector.DrawFilledRect(screen, panelX, panelY, panelW, panelH, color.RGBA{30, 30, 40, 230}, false)
+	vector.StrokeRect(screen, panelX, panelY, panelW, panelH, 2, color.RGBA{100, 150, 200, 255}, false)
+
+	yPos := panelY + 15
+	DrawDataText(screen, fmt.Sprintf("%s (%s)", p.Name, p.Persona.Label), panelX+10, yPos, S_FONT_SIZE)
+	yPos += 15
+	status := fmt.Sprintf("Going to %s, %s", p.DestinationStation.Name, p.State)
+	if g.timeline.Finished() {
+		status = fmt.Sprintf("Journey over after %d min", g.timeline.Duration()/60)
+	}
+	DrawColoredText(screen, status, panelX+10, yPos, XS_FONT_SIZE, color.RGBA{150, 200, 255, 255})
+	yPos += 20
+
+	if len(lines) == 0 {
+		DrawDataText(screen, "No journey recorded yet", panelX+10, yPos, XS_FONT_SIZE)
+		return
+	}
+	for _, line := range lines {
+		DrawDataText(screen, line, panelX+10, yPos, XS_FONT_SIZE)
+		yPos += 15
+	}
+}
+
+// drawCrowdingBar draws how full the platforms are, 1 is at capacity
+func (g *Game) drawCrowdingBar(screen *ebiten.Image, level float64, x, y float32) {
+	barW := float32(200)
+	barH := float32(10)
+	vector.DrawFilledRect(screen, x, y, barW, barH, color.RGBA{60, 60, 60, 255}, false)
+
+	barColor := crowdingColor(level)
+	if barColor == nil {
+		barColor = color.RGBA{0, 200, 0, 255}
+	}
+	vector.DrawFilledRect(screen, x, y, barW*float32(math.Min(level, 1)), barH, barColor, false)
+}
+
+// crowdingColor returns the color of a crowding level (1 is at capacity), nil when the station is roomy
+func crowdingColor(level float64) color.Color {
+	if level >= 0.8 {
+		return color.RGBA{200, 0, 0, 255}
+	} else if level >= 0.5 {
+		return color.RGBA{200, 200, 0, 255}
+	}
+	return nil
+}
+
+// platformLabels describes the queue of every platform of the station with passengers waiting
+func (g *Game) platformLabels(st *models.Station) []string {
+	counts := st.GetPlatformCounts()
+	labels := make([]string, 0, len(counts))
+	for _, line := range g.lines {
+		if len(line.Stations) == 0 {
+			continue
+		}
+		for _, forward := range []bool{true, false} {
+			platform := models.Platform{LineID: line.ID, Forward: forward}
+			count, ok := counts[platform]
+			if !ok {
+				continue
+			}
+			labels = append(labels, fmt.Sprintf("%s: %d", g.platformName(platform), count))
+		}
+	}
+	if count, ok := counts[models.Platform{}]; ok {
+		labels = append(labels, fmt.Sprintf("Any train: %d", count))
+	}
+	return labels
+}
+
+// platformName names a platform by its line and the terminal it goes to
+func (g *Game) platformName(platform models.Platform) string {
+	for _, line := range g.lines {
+		if line.ID != platform.LineID || len(line.Stations) == 0 {
+			continue
+		}
+		towards := line.Stations[0]
+		if platform.Forward {
+			towards = line.Stations[len(line.Stations)-1]
+		}
+		return fmt.Sprintf("%s to %s", line.Name, towards.Name)
+	}
+	return "Any train"
+}
+
+// interchangeLabels describes the walk from the station to every other station of its complex
+func (g *Game) interchangeLabels(st *models.Station) []string {
+	if st.Complex == nil {
+		return nil
+	}
+	labels := []string{fmt.Sprintf("Interchange: %s (entrance %s)", st.Complex.Name, st.EntranceWalk)}
+	for _, other := range st.Interchanges() {
+		if other.ID == st.ID {
+			continue
+		}
+		lines := make([]string, 0)
+		for _, line := range g.lines {
+			for _, ls := range line.Stations {
+				if ls.ID == other.ID {
+					lines = append(lines, line.Name)
+					break
+				}
+			}
 		}
+		labels = append(labels, fmt.Sprintf("Walk to %s %s: %s", other.Name, strings.Join(lines, ", "), st.WalkTo(other)))
 	}
+	return labels
 }
 
 func (g *Game) drawBackButton(screen *ebiten.Image) {
@@ -748,6 +1467,59 @@ func (g *Game) drawScoreOverlay(screen *ebiten.Image) {
 	}
 }
 
+// maxRecommendationsShown bounds the recommendations listed on the map
+const maxRecommendationsShown = 4
+
+// drawRecommendations draws the most severe interventions Tenjin recommends in the
+// bottom-left corner, each with the start of its rationale
+func (g *Game) drawRecommendations(screen *ebiten.Image) {
+	if g.brain == nil {
+		return
+	}
+	recommendations := g.brain.GetMetrics().Recommendations
+	if len(recommendations) == 0 {
+		return
+	}
+	shown := min(len(recommendations), maxRecommendationsShown)
+
+	// Panel in bottom-left corner
+	panelW := float32(320)
+	panelH := float32(25 + 26*shown)
+	panelX := float32(10)
+	panelY := float32(control.DefaultConfig.DisplayScreenHeight) - panelH - 10
+
+	vector.DrawFilledRect(screen, panelX, panelY, panelW, panelH, color.RGBA{30, 30, 40, 220}, false)
+	vector.StrokeRect(screen, panelX, panelY, panelW, panelH, 2, g.getSeverityColor(recommendations[0].Severity), false)
+
+	yPos := panelY + 15
+	DrawDataText(screen, fmt.Sprintf("TENJIN RECOMMENDS (%d)", len(recommendations)), panelX+10, yPos, S_FONT_SIZE)
+	yPos += 16
+	for _, r := range recommendations[:shown] {
+		DrawColoredText(screen, r.String(), panelX+10, yPos, S_FONT_SIZE, g.getSeverityColor(r.Severity))
+		yPos += 12
+		if lines := g.wrapText(r.Rationale, 75); len(lines) > 0 {
+			rationale := lines[0]
+			if len(lines) > 1 {
+				rationale += "..."
+			}
+			DrawDataText(screen, rationale, panelX+10, yPos, XS_FONT_SIZE)
+		}
+		yPos += 14
+	}
+}
+
+// getSeverityColor returns the color for the severity of a recommendation
+func (g *Game) getSeverityColor(severity intelligence.Severity) color.RGBA {
+	switch severity {
+	case intelligence.SeverityCritical:
+		return color.RGBA{255, 80, 80, 255} // Red
+	case intelligence.SeverityWarning:
+		return color.RGBA{255, 200, 0, 255} // Amber
+	default:
+		return color.RGBA{150, 200, 255, 255} // Blue
+	}
+}
+
 // getGradeColorRGBA returns the color for a grade
 func (g *Game) getGradeColorRGBA(grade string) color.RGBA {
 	switch grade {
@@ -930,16 +1702,118 @@ func (g *Game) drawSimulationClock(screen *ebiten.Image) {
 	vector.DrawFilledRect(screen, panelX, panelY, panelW, panelH, color.RGBA{30, 30, 40, 230}, false)
 	vector.StrokeRect(screen, panelX, panelY, panelW, panelH, 2, color.RGBA{100, 150, 200, 255}, false)
 
-	// Draw time
+	// Draw date and time
+	date := fmt.Sprintf("%s (%s)", g.clock.GetCurrentDate(), g.clock.GetDayType())
+	DrawDataText(screen, date, panelX+8, panelY+4, XS_FONT_SIZE)
 	currentTime := g.clock.GetCurrentTime()
 	DrawDataText(screen, currentTime, panelX+15, panelY+25, L_FONT_SIZE)
 }
 
+// handleSpeedControls handles the keyboard shortcuts of the simulation controls:
+// Space pauses and resumes, period steps, [ and ] change the speed and the number
+// keys pick a preset
+func (g *Game) handleSpeedControls() {
+	if g.controls == nil {
+		return
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeySpace) {
+		g.controls.TogglePause()
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyPeriod) {
+		g.stepSimulation()
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyBracketRight) {
+		g.controls.Faster()
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyBracketLeft) {
+		g.controls.Slower()
+	}
+	for i, preset := range clock.SpeedPresets {
+		if i < 9 && inpututil.IsKeyJustPressed(ebiten.KeyDigit1+ebiten.Key(i)) {
+			g.controls.SetWarp(preset)
+		}
+	}
+}
+
+// stepSimulation runs control.Config.StepTicks ticks of the paused simulation
+func (g *Game) stepSimulation() {
+	if err := g.controls.Step(control.DefaultConfig.StepTicks); err != nil {
+		control.Log(fmt.Sprintf("Simulation step: %v", err))
+	}
+}
+
+// speedButton is a button of the speed bar
+type speedButton struct {
+	label      string
+	x, y, w, h float64
+	active     bool
+	action     func()
+}
+
+func (b speedButton) contains(point models.Vector) bool {
+	return point.X >= b.x && point.X <= b.x+b.w && point.Y >= b.y && point.Y <= b.y+b.h
+}
+
+// speedButtons lays out the speed bar below the clock: pause or play, step and
+// the speed presets
+func (g *Game) speedButtons() []speedButton {
+	if g.controls == nil {
+		return nil
+	}
+	paused := g.controls.Paused()
+	buttons := []speedButton{
+		{label: "||", w: 30, active: paused, action: g.controls.TogglePause},
+		{label: "Step", w: 40, action: g.stepSimulation},
+	}
+	if paused {
+		buttons[0].label = ">"
+	}
+	warp := g.controls.Warp()
+	for _, preset := range clock.SpeedPresets {
+		buttons = append(buttons, speedButton{
+			label:  fmt.Sprintf("%gx", preset),
+			w:      34,
+			active: preset == warp,
+			action: func() { g.controls.SetWarp(preset) },
+		})
+	}
+
+	width := 0.0
+	for _, b := range buttons {
+		width += b.w + 4
+	}
+	x := float64(control.DefaultConfig.DisplayScreenWidth)/2 - width/2
+	for i := range buttons {
+		buttons[i].x, buttons[i].y, buttons[i].h = x, 55, 22
+		x += buttons[i].w + 4
+	}
+	return buttons
+}
+
+// drawSpeedControls draws the speed bar, highlighting the current speed
+func (g *Game) drawSpeedControls(screen *ebiten.Image) {
+	for _, b := range g.speedButtons() {
+		bg := color.RGBA{30, 30, 40, 230}
+		if b.active {
+			bg = color.RGBA{100, 150, 200, 255}
+		}
+		vector.DrawFilledRect(screen, float32(b.x), float32(b.y), float32(b.w), float32(b.h), bg, false)
+		vector.StrokeRect(screen, float32(b.x), float32(b.y), float32(b.w), float32(b.h), 1, color.RGBA{100, 150, 200, 255}, false)
+		DrawDataText(screen, b.label, float32(b.x)+5, float32(b.y)+15, XS_FONT_SIZE)
+	}
+	if g.controls != nil && g.controls.Paused() {
+		DrawColoredText(screen, "PAUSED", float32(control.DefaultConfig.DisplayScreenWidth/2)-25, 95, S_FONT_SIZE, color.RGBA{255, 200, 0, 255})
+	}
+}
+
+// cameraHelpHeight is the height of the controls help panel
+const cameraHelpHeight = 160
+
 // drawCameraHelp draws the camera control instructions in the bottom-right
 func (g *Game) drawCameraHelp(screen *ebiten.Image) {
 	// Panel in bottom-right corner
 	panelW := float32(180)
-	panelH := float32(90)
+	panelH := float32(cameraHelpHeight)
 	panelX := float32(control.DefaultConfig.DisplayScreenWidth) - panelW - 10
 	panelY := float32(control.DefaultConfig.DisplayScreenHeight) - panelH - 10
 
@@ -952,7 +1826,7 @@ func (g *Game) drawCameraHelp(screen *ebiten.Image) {
 	textY := panelY + 15
 	lineHeight := float32(14)
 
-	DrawDataText(screen, "Camera Controls:", textX, textY, XS_FONT_SIZE)
+	DrawDataText(screen, "Controls:", textX, textY, XS_FONT_SIZE)
 	textY += lineHeight
 	DrawDataText(screen, "Wheel/+/-: Zoom", textX, textY, XS_FONT_SIZE)
 	textY += lineHeight
@@ -960,9 +1834,65 @@ func (g *Game) drawCameraHelp(screen *ebiten.Image) {
 	textY += lineHeight
 	DrawDataText(screen, "R: Reset", textX, textY, XS_FONT_SIZE)
 	textY += lineHeight
+	DrawDataText(screen, "Space: Pause | .: Step", textX, textY, XS_FONT_SIZE)
+	textY += lineHeight
+	DrawDataText(screen, "[ ] or 1-6: Speed", textX, textY, XS_FONT_SIZE)
+	textY += lineHeight
+	DrawDataText(screen, "G: Headway control", textX, textY, XS_FONT_SIZE)
+	textY += lineHeight
+	DrawDataText(screen, "E: Mark | N/M: Add/Move", textX, textY, XS_FONT_SIZE)
+	textY += lineHeight
+	DrawDataText(screen, "C/X: Link/Unlink | Y: Extend", textX, textY, XS_FONT_SIZE)
+	textY += lineHeight
 	DrawDataText(screen, fmt.Sprintf("Zoom: %.1fx", g.cameraZoom), textX, textY, XS_FONT_SIZE)
 }
 
+// handleHeadwayControl switches the headway regularisation of Tenjin on and off with G
+func (g *Game) handleHeadwayControl() {
+	if g.brain != nil && inpututil.IsKeyJustPressed(ebiten.KeyG) {
+		g.brain.SetHeadwayControl(!g.brain.HeadwayControl())
+	}
+}
+
+// drawRegularity draws whether Tenjin regularises the headways, with the coefficient
+// of variation of the headways of every line now and when the control was switched on
+func (g *Game) drawRegularity(screen *ebiten.Image) {
+	if g.brain == nil {
+		return
+	}
+	regularity := g.brain.GetRegularity()
+	if len(regularity) == 0 {
+		return
+	}
+	enabled := g.brain.HeadwayControl()
+
+	// Panel above the controls help in the bottom-right corner
+	panelW := float32(180)
+	panelH := float32(25 + 14*len(regularity))
+	panelX := float32(control.DefaultConfig.DisplayScreenWidth) - panelW - 10
+	panelY := float32(control.DefaultConfig.DisplayScreenHeight) - cameraHelpHeight - panelH - 20
+
+	border := color.RGBA{100, 150, 200, 255}
+	state := "OFF"
+	if enabled {
+		border, state = color.RGBA{80, 200, 120, 255}, "ON"
+	}
+	vector.DrawFilledRect(screen, panelX, panelY, panelW, panelH, color.RGBA{30, 30, 40, 200}, false)
+	vector.StrokeRect(screen, panelX, panelY, panelW, panelH, 1, border, false)
+
+	textX := panelX + 10
+	textY := panelY + 15
+	DrawDataText(screen, "Headway control: "+state, textX, textY, XS_FONT_SIZE)
+	for _, r := range regularity {
+		textY += 14
+		text := fmt.Sprintf("%s CV %.2f", r.LineName, r.Current)
+		if r.Before >= 0 {
+			text += fmt.Sprintf(" (was %.2f)", r.Before)
+		}
+		DrawDataText(screen, text, textX, textY, XS_FONT_SIZE)
+	}
+}
+
 func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
 	return control.DefaultConfig.DisplayScreenWidth, control.DefaultConfig.DisplayScreenHeight
 }
@@ -0,0 +1,74 @@
+package display
+
+import (
+	"image"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// staticLayerKey is what the static layer of the map depends on, it is drawn again
+// when any of it changes.
+type staticLayerKey struct {
+	zoom           float64
+	offsetX        float64
+	offsetY        float64
+	networkVersion uint64
+	size           image.Point
+}
+
+// drawStaticLayer draws the tracks and the lines of the map from an offscreen image,
+// drawn again only when the camera, the network or the screen size change.
+func (g *Game) drawStaticLayer(screen *ebiten.Image) {
+	size := screen.Bounds().Size()
+	key := staticLayerKey{g.cameraZoom, g.cameraOffsetX, g.cameraOffsetY, g.networkVersion, size}
+	if g.staticLayer == nil || key != g.staticKey {
+		if g.staticLayer == nil || g.staticLayer.Bounds().Size() != size {
+			if g.staticLayer != nil {
+				g.staticLayer.Deallocate()
+			}
+			g.staticLayer = ebiten.NewImage(size.X, size.Y)
+		} else {
+			g.staticLayer.Clear()
+		}
+
+		// Tracks first, each direction on its own track, then the lines between stations
+		g.drawTracksTransformed(g.staticLayer)
+		for _, line := range g.lines {
+			g.drawLineTransformed(g.staticLayer, line)
+		}
+		g.staticKey = key
+	}
+	screen.DrawImage(g.staticLayer, nil)
+}
+
+// frameScale is how many 60ths of a second an update stands for, so camera moves
+// keep their speed at any render rate.
+func frameScale() float64 {
+	return 60 / float64(ebiten.TPS())
+}
+
+// checkRedraw marks the screen for drawing when something it shows may have changed:
+// the simulation ticked, there was input or a second went by, for what changes on
+// its own like the newspaper.
+func (g *Game) checkRedraw() {
+	g.framesSinceDraw++
+	ticks := g.seenTicks
+	if g.simulation != nil {
+		ticks = g.simulation.Ticks()
+	}
+	cursor := image.Pt(ebiten.CursorPosition())
+	_, wheelY := ebiten.Wheel()
+
+	g.redraw = g.redraw ||
+		ticks != g.seenTicks ||
+		cursor != g.seenCursor ||
+		wheelY != 0 ||
+		len(inpututil.AppendPressedKeys(nil)) > 0 ||
+		len(inpututil.AppendJustReleasedKeys(nil)) > 0 ||
+		ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) ||
+		inpututil.IsMouseButtonJustReleased(ebiten.MouseButtonLeft) ||
+		g.framesSinceDraw >= ebiten.TPS()
+	g.seenTicks = ticks
+	g.seenCursor = cursor
+}
@@ -3,6 +3,7 @@ package display
 import (
 	"github.com/odin-software/metro/internal/baso"
 	"github.com/odin-software/metro/internal/dbstore"
+	"github.com/odin-software/metro/internal/models"
 )
 
 // BasoScheduleAdapter adapts baso for the display package
@@ -17,9 +18,9 @@ func NewBasoScheduleAdapter() *BasoScheduleAdapter {
 	}
 }
 
-// GetScheduleByTrainAndStation looks up a specific schedule entry
-func (a *BasoScheduleAdapter) GetScheduleByTrainAndStation(trainID, stationID int64) (Schedule, error) {
-	dbSched, err := a.baso.GetScheduleByTrainAndStation(trainID, stationID)
+// GetScheduleByTrainAndStation looks up a specific schedule entry of a day type
+func (a *BasoScheduleAdapter) GetScheduleByTrainAndStation(trainID, stationID int64, dayType models.DayType) (Schedule, error) {
+	dbSched, err := a.baso.GetScheduleByTrainAndStation(trainID, stationID, dayType)
 	if err != nil {
 		return Schedule{}, err
 	}
@@ -6,6 +6,7 @@ toolchain go1.24.2
 
 require (
 	github.com/hajimehoshi/ebiten/v2 v2.8.7
+	github.com/ollama/ollama v0.12.3
 	github.com/pressly/goose/v3 v3.25.0
 )
 
@@ -17,7 +18,6 @@ require (
 	github.com/google/uuid v1.6.0 // indirect
 	github.com/jezek/xgb v1.1.1 // indirect
 	github.com/mfridman/interpolate v0.0.2 // indirect
-	github.com/ollama/ollama v0.12.3 // indirect
 	github.com/sethvargo/go-retry v0.3.0 // indirect
 	go.uber.org/multierr v1.11.0 // indirect
 	golang.org/x/crypto v0.40.0 // indirect
@@ -30,6 +30,10 @@ func (bs *Baso) WipeData() error {
 	defer tx.Rollback()
 	qtx := bs.queries.WithTx(tx)
 
+	err = qtx.DeleteAllDemand(bs.ctx)
+	if err != nil {
+		return err
+	}
 	err = qtx.DeleteAllStationLines(bs.ctx)
 	if err != nil {
 		return err
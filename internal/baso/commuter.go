@@ -0,0 +1,46 @@
+package baso
+
+import (
+	"github.com/odin-software/metro/internal/dbstore"
+)
+
+// ListCommuters returns the whole commuter population.
+func (bs *Baso) ListCommuters() ([]dbstore.Commuter, error) {
+	return bs.queries.ListCommuters(bs.ctx)
+}
+
+// CreateCommuters stores a new commuter population.
+func (bs *Baso) CreateCommuters(rows []dbstore.CreateCommuterParams) error {
+	tx, err := bs.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	qtx := bs.queries.WithTx(tx)
+
+	for _, row := range rows {
+		if err := qtx.CreateCommuter(bs.ctx, row); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// UpdateCommuters saves what the commuters remember from their trips.
+func (bs *Baso) UpdateCommuters(rows []dbstore.UpdateCommuterParams) error {
+	tx, err := bs.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	qtx := bs.queries.WithTx(tx)
+
+	for _, row := range rows {
+		if err := qtx.UpdateCommuter(bs.ctx, row); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
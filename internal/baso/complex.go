@@ -0,0 +1,16 @@
+package baso
+
+import (
+	"github.com/odin-software/metro/internal/dbstore"
+)
+
+// ListStationComplexes returns every interchange of the network.
+func (bs *Baso) ListStationComplexes() ([]dbstore.StationComplex, error) {
+	return bs.queries.ListStationComplexes(bs.ctx)
+}
+
+// ListComplexWalks returns the walks between stations of a complex that differ
+// from its transfer walk.
+func (bs *Baso) ListComplexWalks() ([]dbstore.ComplexWalk, error) {
+	return bs.queries.ListComplexWalks(bs.ctx)
+}
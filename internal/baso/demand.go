@@ -0,0 +1,31 @@
+package baso
+
+import (
+	"github.com/odin-software/metro/internal/dbstore"
+)
+
+// ListDemand returns every OD pair of every time band.
+func (bs *Baso) ListDemand() ([]dbstore.Demand, error) {
+	return bs.queries.ListDemand(bs.ctx)
+}
+
+// ReplaceDemand deletes the current demand and stores the given one.
+func (bs *Baso) ReplaceDemand(rows []dbstore.CreateDemandParams) error {
+	tx, err := bs.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	qtx := bs.queries.WithTx(tx)
+
+	if err := qtx.DeleteAllDemand(bs.ctx); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if err := qtx.CreateDemand(bs.ctx, row); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
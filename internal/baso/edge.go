@@ -2,11 +2,14 @@ package baso
 
 import (
 	"github.com/odin-software/metro/internal/dbstore"
+	"github.com/odin-software/metro/internal/models"
 )
 
 type CreateEdge struct {
-	Fromid int64 `json:"fromId"`
-	Toid   int64 `json:"toId"`
+	Fromid         int64   `json:"fromId"`
+	Toid           int64   `json:"toId"`
+	Directed       bool    `json:"directed"`
+	PlatformOffset float64 `json:"platformOffset"`
 }
 
 func (bs *Baso) ListEdges() ([]dbstore.Edge, error) {
@@ -26,6 +29,23 @@ func (bs *Baso) CreateEdge(fromId, toId int64) error {
 	return err
 }
 
+// CreateDirectedEdge creates an edge with its direction and platform offset.
+// A directed edge only goes from fromId to toId, the other direction needs its own edge.
+func (bs *Baso) CreateDirectedEdge(fromId, toId int64, directed bool, platformOffset float64) error {
+	var dir int64
+	if directed {
+		dir = 1
+	}
+	_, err := bs.queries.CreateDirectedEdge(bs.ctx, dbstore.CreateDirectedEdgeParams{
+		Fromid:         fromId,
+		Toid:           toId,
+		Directed:       dir,
+		Platformoffset: platformOffset,
+	})
+
+	return err
+}
+
 func (bs *Baso) CreateEdges(edgesToCreate []CreateEdge) ([]dbstore.Edge, error) {
 	for _, edge := range edgesToCreate {
 		_, err := bs.GetStationById(edge.Fromid)
@@ -36,7 +56,7 @@ func (bs *Baso) CreateEdges(edgesToCreate []CreateEdge) ([]dbstore.Edge, error)
 		if err != nil {
 			return nil, err
 		}
-		err = bs.CreateEdge(edge.Fromid, edge.Toid)
+		err = bs.CreateDirectedEdge(edge.Fromid, edge.Toid, edge.Directed, edge.PlatformOffset)
 		if err != nil {
 			return nil, err
 		}
@@ -56,3 +76,65 @@ func (bs *Baso) ListEdgePoints(id int64) ([]dbstore.GetEdgePointsRow, error) {
 	}
 	return eps, nil
 }
+
+// CreateEdgeWithPoints creates an undirected edge and the points in between its stations.
+func (bs *Baso) CreateEdgeWithPoints(fromId, toId int64, points []models.Vector) (int64, error) {
+	tx, err := bs.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+	qtx := bs.queries.WithTx(tx)
+
+	edgeId, err := qtx.CreateEdge(bs.ctx, dbstore.CreateEdgeParams{
+		Fromid: fromId,
+		Toid:   toId,
+	})
+	if err != nil {
+		return 0, err
+	}
+	for i, p := range points {
+		_, err := qtx.CreateEdgePoint(bs.ctx, dbstore.CreateEdgePointParams{
+			Edgeid: edgeId,
+			Odr:    int64(i + 1),
+			X:      p.X,
+			Y:      p.Y,
+			Z:      0,
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return edgeId, tx.Commit()
+}
+
+// DeleteEdgesBetween deletes every edge between two stations, in both directions, with their points.
+func (bs *Baso) DeleteEdgesBetween(firstId, secondId int64) error {
+	tx, err := bs.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	qtx := bs.queries.WithTx(tx)
+
+	for _, pair := range [][2]int64{{firstId, secondId}, {secondId, firstId}} {
+		edges, err := qtx.GetEdgesBetween(bs.ctx, dbstore.GetEdgesBetweenParams{
+			Fromid: pair[0],
+			Toid:   pair[1],
+		})
+		if err != nil {
+			return err
+		}
+		for _, edge := range edges {
+			if err := qtx.DeleteEdgePoints(bs.ctx, edge.ID); err != nil {
+				return err
+			}
+			if err := qtx.DeleteEdge(bs.ctx, edge.ID); err != nil {
+				return err
+			}
+		}
+	}
+
+	return tx.Commit()
+}
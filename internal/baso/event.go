@@ -0,0 +1,15 @@
+package baso
+
+import (
+	"github.com/odin-software/metro/internal/dbstore"
+)
+
+// ListSpecialEvents returns the special events sorted by when they take place.
+func (bs *Baso) ListSpecialEvents() ([]dbstore.SpecialEvent, error) {
+	return bs.queries.ListSpecialEvents(bs.ctx)
+}
+
+// CreateSpecialEvent stores a new special event.
+func (bs *Baso) CreateSpecialEvent(params dbstore.CreateSpecialEventParams) (dbstore.SpecialEvent, error) {
+	return bs.queries.CreateSpecialEvent(bs.ctx, params)
+}
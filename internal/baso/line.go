@@ -107,3 +107,30 @@ func (bs *Baso) CreateStationLine(stationId, lineId, odr int64) (int64, error) {
 
 	return stl, err
 }
+
+// ReorderLine replaces the stations of a line, in the given order.
+func (bs *Baso) ReorderLine(lineId int64, stationIds []int64) error {
+	tx, err := bs.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	qtx := bs.queries.WithTx(tx)
+
+	lid := sql.NullInt64{Int64: lineId, Valid: true}
+	if err := qtx.DeleteStationLinesByLine(bs.ctx, lid); err != nil {
+		return err
+	}
+	for i, stationId := range stationIds {
+		_, err := qtx.CreateStationLine(bs.ctx, dbstore.CreateStationLineParams{
+			Stationid: sql.NullInt64{Int64: stationId, Valid: true},
+			Lineid:    lid,
+			Odr:       sql.NullInt64{Int64: int64(i + 1), Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
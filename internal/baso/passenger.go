@@ -20,6 +20,7 @@ func (bs *Baso) SavePassenger(passenger *models.Passenger) error {
 		State:                string(passenger.State),
 		Sentiment:            passenger.Sentiment,
 		SpawnTime:            passenger.WaitStartTime,
+		Persona:              string(passenger.Persona.Kind),
 	})
 
 	return err
@@ -67,25 +68,72 @@ func (bs *Baso) CountActivePassengers() (int64, error) {
 	return bs.queries.CountActivePassengers(bs.ctx)
 }
 
-// CreatePassengerEvent logs an event for a passenger
-func (bs *Baso) CreatePassengerEvent(passengerID, eventType string, stationID *int64, trainID *int64, sentiment float64, metadata string) error {
-	params := dbstore.CreatePassengerEventParams{
-		PassengerID: passengerID,
-		EventType:   eventType,
-		Sentiment:   sql.NullFloat64{Float64: sentiment, Valid: true},
-		Metadata:    sql.NullString{String: metadata, Valid: metadata != ""},
+// LogPassengerEvent stores a moment of the journey of a passenger
+func (bs *Baso) LogPassengerEvent(event models.JourneyEvent) error {
+	_, err := bs.queries.CreatePassengerEvent(bs.ctx, passengerEventParams(event))
+	return err
+}
+
+// LogPassengerEvents stores moments of the journeys of the passengers in a single transaction
+func (bs *Baso) LogPassengerEvents(events []models.JourneyEvent) error {
+	tx, err := bs.db.Begin()
+	if err != nil {
+		return err
 	}
+	defer tx.Rollback()
+	qtx := bs.queries.WithTx(tx)
 
-	if stationID != nil {
-		params.StationID = sql.NullInt64{Int64: *stationID, Valid: true}
+	for _, event := range events {
+		if _, err := qtx.CreatePassengerEvent(bs.ctx, passengerEventParams(event)); err != nil {
+			return err
+		}
 	}
 
-	if trainID != nil {
-		params.TrainID = sql.NullInt64{Int64: *trainID, Valid: true}
+	return tx.Commit()
+}
+
+func passengerEventParams(event models.JourneyEvent) dbstore.CreatePassengerEventParams {
+	return dbstore.CreatePassengerEventParams{
+		PassengerID: event.PassengerID,
+		EventType:   event.Type,
+		StationID:   sql.NullInt64{Int64: event.StationID, Valid: event.StationID != 0},
+		TrainID:     sql.NullInt64{Int64: event.TrainID, Valid: event.TrainID != 0},
+		Sentiment:   sql.NullFloat64{Float64: event.Sentiment, Valid: true},
+		Metadata:    sql.NullString{String: event.Detail, Valid: event.Detail != ""},
+		SimDay:      int64(event.Day),
+		SimTime:     int64(event.SimTime),
+		Seq:         event.Seq,
 	}
+}
 
-	_, err := bs.queries.CreatePassengerEvent(bs.ctx, params)
-	return err
+// GetPassengerTimeline rebuilds the journey of a passenger from its stored events
+func (bs *Baso) GetPassengerTimeline(passengerID string) (models.Timeline, error) {
+	rows, err := bs.queries.GetPassengerTimeline(bs.ctx, passengerID)
+	if err != nil {
+		return nil, err
+	}
+	timeline := make(models.Timeline, 0, len(rows))
+	for _, row := range rows {
+		timeline = append(timeline, models.JourneyEvent{
+			PassengerID: row.PassengerID,
+			Type:        row.EventType,
+			StationID:   row.StationID.Int64,
+			StationName: row.StationName,
+			TrainID:     row.TrainID.Int64,
+			TrainName:   row.TrainName,
+			Sentiment:   row.Sentiment.Float64,
+			Detail:      row.Metadata.String,
+			Day:         int(row.SimDay),
+			SimTime:     int(row.SimTime),
+			Seq:         row.Seq,
+		})
+	}
+	return timeline, nil
+}
+
+// DeletePassengerEventsBefore removes the events of the simulated days before day
+func (bs *Baso) DeletePassengerEventsBefore(day int) error {
+	return bs.queries.DeletePassengerEventsBefore(bs.ctx, int64(day))
 }
 
 // GetPassengerEvents retrieves all events for a passenger
@@ -116,8 +164,8 @@ func (bs *Baso) SyncPassengers(passengers []*models.Passenger) error {
 
 	// Insert current active passengers
 	for _, p := range passengers {
-		if p.State == models.PassengerStateArrived {
-			continue // Skip arrived passengers
+		if p.State == models.PassengerStateArrived || p.State == models.PassengerStateAbandoned {
+			continue // Skip passengers that left the network
 		}
 
 		// Note: current_train_id set to NULL (Train struct doesn't expose ID)
@@ -130,6 +178,7 @@ func (bs *Baso) SyncPassengers(passengers []*models.Passenger) error {
 			State:                string(p.State),
 			Sentiment:            p.Sentiment,
 			SpawnTime:            p.WaitStartTime,
+			Persona:              string(p.Persona.Kind),
 		})
 
 		if err != nil {
@@ -139,49 +188,3 @@ func (bs *Baso) SyncPassengers(passengers []*models.Passenger) error {
 
 	return tx.Commit()
 }
-
-// LogPassengerEvent is a helper to log events from passenger lifecycle
-func (bs *Baso) LogPassengerEvent(event interface{}) error {
-	// Parse event type and extract fields
-	switch e := event.(type) {
-	case map[string]interface{}:
-		eventType, ok := e["Type"].(string)
-		if !ok {
-			return nil // Skip invalid events
-		}
-
-		passengerID, ok := e["PassengerID"].(string)
-		if !ok {
-			passengerID, ok = e["PassengerName"].(string) // Fallback
-			if !ok {
-				return nil
-			}
-		}
-
-		var stationID *int64
-		if sid, ok := e["StationID"].(int64); ok {
-			stationID = &sid
-		}
-
-		var trainID *int64
-		var trainName string
-		if e["TrainName"] != nil {
-			trainName = e["TrainName"].(string)
-		}
-
-		sentiment := 0.0
-		if s, ok := e["Sentiment"].(float64); ok {
-			sentiment = s
-		}
-
-		// Store train name in metadata if available
-		metadata := ""
-		if trainName != "" {
-			metadata = trainName
-		}
-
-		return bs.CreatePassengerEvent(passengerID, eventType, stationID, trainID, sentiment, metadata)
-	}
-
-	return nil
-}
@@ -0,0 +1,10 @@
+package baso
+
+import (
+	"github.com/odin-software/metro/internal/dbstore"
+)
+
+// CreateSimulationRun stores a new run of the simulation with its seed.
+func (bs *Baso) CreateSimulationRun(seed int64) (dbstore.SimulationRun, error) {
+	return bs.queries.CreateSimulationRun(bs.ctx, seed)
+}
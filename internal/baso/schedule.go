@@ -1,7 +1,11 @@
 package baso
 
 import (
+	"database/sql"
+	"errors"
+
 	"github.com/odin-software/metro/internal/dbstore"
+	"github.com/odin-software/metro/internal/models"
 )
 
 // GetScheduleForTrain retrieves all scheduled stops for a train, ordered by sequence
@@ -22,15 +26,24 @@ func (bs *Baso) GetNextScheduledStop(trainID int64, currentSequence int64) (dbst
 	})
 }
 
-// GetScheduleByTrainAndStation finds a specific schedule entry
-func (bs *Baso) GetScheduleByTrainAndStation(trainID, stationID int64) (dbstore.Schedule, error) {
-	return bs.queries.GetScheduleByTrainAndStation(bs.ctx, dbstore.GetScheduleByTrainAndStationParams{
-		TrainID:   trainID,
-		StationID: stationID,
-	})
+// GetScheduleByTrainAndStation finds a specific schedule entry of a day type, or of
+// the closest of its fallbacks when the day type has none
+func (bs *Baso) GetScheduleByTrainAndStation(trainID, stationID int64, dayType models.DayType) (dbstore.Schedule, error) {
+	for {
+		schedule, err := bs.queries.GetScheduleByTrainAndStation(bs.ctx, dbstore.GetScheduleByTrainAndStationParams{
+			TrainID:   trainID,
+			StationID: stationID,
+			DayType:   string(dayType),
+		})
+		fallback, ok := dayType.Fallback()
+		if !errors.Is(err, sql.ErrNoRows) || !ok {
+			return schedule, err
+		}
+		dayType = fallback
+	}
 }
 
-// GetAllSchedules retrieves all schedules, ordered by train and sequence
+// GetAllSchedules retrieves all schedules, ordered by day type, train and sequence
 func (bs *Baso) GetAllSchedules() ([]dbstore.Schedule, error) {
 	return bs.queries.GetAllSchedules(bs.ctx)
 }
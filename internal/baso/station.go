@@ -8,10 +8,15 @@ import (
 )
 
 type GetStation struct {
-	ID       int64         `json:"id"`
-	Name     string        `json:"name"`
-	Color    string        `json:"color"`
-	Position models.Vector `json:"position"`
+	ID               int64         `json:"id"`
+	Name             string        `json:"name"`
+	Color            string        `json:"color"`
+	Position         models.Vector `json:"position"`
+	PlatformCapacity int           `json:"platformCapacity"` // 0 uses the default
+	GateThroughput   float64       `json:"gateThroughput"`   // Passengers per minute, 0 uses the default
+	ComplexID        int64         `json:"complexId"`        // 0 when the station is not part of a complex
+	EntranceWalk     int           `json:"entranceWalk"`     // Seconds, 0 uses the default
+	Zone             int           `json:"zone"`             // Fare zone
 }
 
 type CreateStation struct {
@@ -30,10 +35,15 @@ func (bs *Baso) ListStations() ([]GetStation, error) {
 	result := make([]GetStation, 0)
 	for _, station := range stations {
 		result = append(result, GetStation{
-			ID:       station.ID,
-			Name:     station.Name,
-			Color:    station.Color.String,
-			Position: models.NewVector(station.X.Float64, station.Y.Float64),
+			ID:               station.ID,
+			Name:             station.Name,
+			Color:            station.Color.String,
+			Position:         models.NewVector(station.X.Float64, station.Y.Float64),
+			PlatformCapacity: int(station.PlatformCapacity),
+			GateThroughput:   station.GateThroughput,
+			ComplexID:        station.ComplexID.Int64,
+			EntranceWalk:     int(station.EntranceWalk),
+			Zone:             int(station.Zone),
 		})
 	}
 	return result, nil
@@ -121,3 +131,26 @@ func (bs *Baso) CreateStations(sts []CreateStation) ([]GetStation, error) {
 
 	return newStations, nil
 }
+
+// AddStation creates a station and returns its ID.
+func (bs *Baso) AddStation(name, color string, x, y float64) (int64, error) {
+	return bs.queries.CreateStation(bs.ctx, dbstore.CreateStationParams{
+		Name:  name,
+		X:     sql.NullFloat64{Float64: x, Valid: true},
+		Y:     sql.NullFloat64{Float64: y, Valid: true},
+		Z:     sql.NullFloat64{Float64: 0, Valid: true},
+		Color: sql.NullString{String: color, Valid: true},
+	})
+}
+
+// MoveStation updates the position of a station.
+func (bs *Baso) MoveStation(id int64, name string, x, y float64) error {
+	_, err := bs.queries.UpdateStation(bs.ctx, dbstore.UpdateStationParams{
+		Name: name,
+		X:    sql.NullFloat64{Float64: x, Valid: true},
+		Y:    sql.NullFloat64{Float64: y, Valid: true},
+		Z:    sql.NullFloat64{Float64: 0, Valid: true},
+		ID:   id,
+	})
+	return err
+}
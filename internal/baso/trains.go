@@ -86,3 +86,9 @@ func (bs *Baso) MoveTrainToLine(trainId, lineId int64) error {
 	}
 	return nil
 }
+
+// ListTrainReferences lists every train with the make and line it points to.
+// Unlike ListTrainsFull, trains with missing references are included.
+func (bs *Baso) ListTrainReferences() ([]dbstore.ListTrainReferencesRow, error) {
+	return bs.queries.ListTrainReferences(bs.ctx)
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/odin-software/metro/control"
+	"github.com/odin-software/metro/internal/models"
 )
 
 // SimulationClock tracks the current time in the simulation
@@ -13,6 +14,7 @@ type SimulationClock struct {
 	startTime       time.Time     // When simulation started (real time)
 	simulationStart int           // Seconds since midnight when sim starts (e.g., 8:00 AM = 28800)
 	elapsedSeconds  float64       // Elapsed simulation time in seconds
+	calendar        *models.Calendar // Date and day type of each simulated day
 	mutex           sync.RWMutex
 }
 
@@ -24,10 +26,17 @@ func NewSimulationClock() *SimulationClock {
 	// Convert start time to seconds since midnight
 	simulationStart := startHour*3600 + startMin*60
 
+	calendar, err := models.ParseCalendar(control.DefaultConfig.Calendar)
+	if err != nil {
+		control.Log(fmt.Sprintf("Error in the calendar, starting today without holidays: %v", err))
+		calendar = models.NewCalendar(time.Now(), nil)
+	}
+
 	return &SimulationClock{
 		startTime:       time.Now(),
 		simulationStart: simulationStart,
 		elapsedSeconds:  0,
+		calendar:        calendar,
 	}
 }
 
@@ -54,6 +63,35 @@ func (c *SimulationClock) GetCurrentTimeOfDay() int {
 	return currentSeconds % 86400
 }
 
+// GetDay returns the number of simulated days since the first midnight, starting at 0
+func (c *SimulationClock) GetDay() int {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	currentSeconds := c.simulationStart + int(c.elapsedSeconds)
+	return currentSeconds / 86400
+}
+
+// GetDate returns the date of the current simulated day
+func (c *SimulationClock) GetDate() time.Time {
+	return c.calendar.Date(c.GetDay())
+}
+
+// GetDayType returns the service the current simulated day runs
+func (c *SimulationClock) GetDayType() models.DayType {
+	return c.calendar.DayType(c.GetDay())
+}
+
+// GetCalendar returns the calendar of the simulation
+func (c *SimulationClock) GetCalendar() *models.Calendar {
+	return c.calendar
+}
+
+// GetCurrentDate returns the current simulated date as a formatted string, like "Mon 3 Mar 2025"
+func (c *SimulationClock) GetCurrentDate() string {
+	return models.FormatDate(c.GetDate())
+}
+
 // GetCurrentTime returns the current simulation time as a formatted string (HH:MM:SS)
 func (c *SimulationClock) GetCurrentTime() string {
 	seconds := c.GetCurrentTimeOfDay()
@@ -67,6 +105,14 @@ func (c *SimulationClock) GetElapsedSeconds() float64 {
 	return c.elapsedSeconds
 }
 
+// Now returns the simulation time as a time.Time: the real time the simulation
+// started plus the elapsed simulation time. It stands still while paused.
+func (c *SimulationClock) Now() time.Time {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.startTime.Add(time.Duration(c.elapsedSeconds * float64(time.Second)))
+}
+
 // FormatSecondsAsTime converts seconds since midnight to HH:MM:SS format
 func FormatSecondsAsTime(seconds int) string {
 	hours := (seconds / 3600) % 24
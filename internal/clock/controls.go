@@ -0,0 +1,125 @@
+package clock
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/odin-software/metro/control"
+)
+
+// SpeedPresets are the time warps the simulation can run at, 1 is the configured
+// control.Config.SimulationSpeed.
+var SpeedPresets = []float64{0.5, 1, 2, 5, 10, 60}
+
+// LoopTicker is the ticker of the simulation loop, a *sematick.Ticker.
+type LoopTicker interface {
+	Pause()
+	Resume()
+	Paused() bool
+	Step(n int)
+	SetInterval(interval time.Duration)
+	Count() int
+}
+
+// Controls pause, step and warp the simulation. They drive the loop ticker, so every
+// subsystem that moves with the ticks, trains, stations, passengers and the clock,
+// stops, steps and speeds up together.
+type Controls struct {
+	ticker LoopTicker
+	warp   float64
+	mutex  sync.Mutex
+}
+
+func NewControls(ticker LoopTicker) *Controls {
+	return &Controls{ticker: ticker, warp: 1}
+}
+
+// Pause stops the simulation.
+func (c *Controls) Pause() {
+	c.ticker.Pause()
+	control.Log("Simulation paused")
+}
+
+// Resume runs the simulation again.
+func (c *Controls) Resume() {
+	c.ticker.Resume()
+	control.Log("Simulation resumed")
+}
+
+// TogglePause pauses a running simulation and resumes a paused one.
+func (c *Controls) TogglePause() {
+	if c.Paused() {
+		c.Resume()
+	} else {
+		c.Pause()
+	}
+}
+
+// Paused returns true when the simulation is not running, steps aside.
+func (c *Controls) Paused() bool {
+	return c.ticker.Paused()
+}
+
+// Step runs the given ticks of a paused simulation, at the current warp.
+// It returns an error when the simulation is running.
+func (c *Controls) Step(ticks int) error {
+	if !c.Paused() {
+		return fmt.Errorf("pause the simulation before stepping")
+	}
+	if ticks <= 0 {
+		return fmt.Errorf("invalid number of ticks %d", ticks)
+	}
+	c.ticker.Step(ticks)
+	return nil
+}
+
+// SetWarp runs the simulation at one of the SpeedPresets.
+func (c *Controls) SetWarp(warp float64) error {
+	valid := false
+	for _, preset := range SpeedPresets {
+		valid = valid || preset == warp
+	}
+	if !valid {
+		return fmt.Errorf("unknown speed %gx, use one of %v", warp, SpeedPresets)
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.warp = warp
+	c.ticker.SetInterval(time.Duration(float64(control.DefaultConfig.LoopDuration) / warp))
+	control.Log(fmt.Sprintf("Simulation speed set to %gx", warp))
+	return nil
+}
+
+// Warp returns the current speed preset.
+func (c *Controls) Warp() float64 {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.warp
+}
+
+// Faster moves to the next speed preset, if there is one.
+func (c *Controls) Faster() {
+	for _, preset := range SpeedPresets {
+		if preset > c.Warp() {
+			c.SetWarp(preset)
+			return
+		}
+	}
+}
+
+// Slower moves to the previous speed preset, if there is one.
+func (c *Controls) Slower() {
+	for i := len(SpeedPresets) - 1; i >= 0; i-- {
+		if SpeedPresets[i] < c.Warp() {
+			c.SetWarp(SpeedPresets[i])
+			return
+		}
+	}
+}
+
+// Ticks returns how many ticks the simulation has run.
+func (c *Controls) Ticks() int {
+	return c.ticker.Count()
+}
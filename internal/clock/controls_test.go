@@ -0,0 +1,86 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/odin-software/metro/control"
+)
+
+// fakeTicker records what the controls ask of the loop ticker.
+type fakeTicker struct {
+	paused   bool
+	steps    int
+	interval time.Duration
+	count    int
+}
+
+func (ft *fakeTicker) Pause()                             { ft.paused = true }
+func (ft *fakeTicker) Resume()                            { ft.paused, ft.steps = false, 0 }
+func (ft *fakeTicker) Paused() bool                       { return ft.paused }
+func (ft *fakeTicker) Step(n int)                         { ft.steps += n }
+func (ft *fakeTicker) SetInterval(interval time.Duration) { ft.interval = interval }
+func (ft *fakeTicker) Count() int                         { return ft.count }
+
+func newTestControls(t *testing.T) (*Controls, *fakeTicker) {
+	control.DefaultConfig.LogsDirectory = t.TempDir() + "/"
+	control.InitLogger()
+	ticker := &fakeTicker{interval: control.DefaultConfig.LoopDuration}
+	return NewControls(ticker), ticker
+}
+
+func TestControlsSpeedPresets(t *testing.T) {
+	c, ticker := newTestControls(t)
+
+	if c.Warp() != 1 || ticker.interval != control.DefaultConfig.LoopDuration {
+		t.Fatal("The controls should start at the configured speed.")
+	}
+	if err := c.SetWarp(3); err == nil || c.Warp() != 1 {
+		t.Fatal("A speed that isn't a preset should be rejected.")
+	}
+	if err := c.SetWarp(10); err != nil || ticker.interval != control.DefaultConfig.LoopDuration/10 {
+		t.Fatal("A faster preset should tick more often.")
+	}
+	if err := c.SetWarp(0.5); err != nil || ticker.interval != control.DefaultConfig.LoopDuration*2 {
+		t.Fatal("A slower preset should tick less often.")
+	}
+
+	c.Slower()
+	if c.Warp() != SpeedPresets[0] {
+		t.Fatal("The slowest preset should stay the slowest.")
+	}
+	for range SpeedPresets {
+		c.Faster()
+	}
+	if c.Warp() != SpeedPresets[len(SpeedPresets)-1] {
+		t.Fatal("Going faster should stop at the fastest preset.")
+	}
+	c.Slower()
+	if c.Warp() != SpeedPresets[len(SpeedPresets)-2] {
+		t.Fatal("Going slower should move to the previous preset.")
+	}
+}
+
+func TestControlsStep(t *testing.T) {
+	c, ticker := newTestControls(t)
+
+	if err := c.Step(1); err == nil || ticker.steps != 0 {
+		t.Fatal("A running simulation should not be stepped.")
+	}
+	c.Pause()
+	if err := c.Step(0); err == nil || ticker.steps != 0 {
+		t.Fatal("Stepping no ticks should be rejected.")
+	}
+	if err := c.Step(2); err != nil || ticker.steps != 2 || !c.Paused() {
+		t.Fatal("A paused simulation should be stepped and stay paused.")
+	}
+	ticker.count = 2
+	if c.Ticks() != 2 {
+		t.Fatal("The ticks should be counted by the loop ticker.")
+	}
+
+	c.TogglePause()
+	if c.Paused() {
+		t.Fatal("Toggling a paused simulation should resume it.")
+	}
+}
@@ -9,6 +9,31 @@ import (
 	"context"
 )
 
+const createDirectedEdge = `-- name: CreateDirectedEdge :one
+INSERT INTO edge (fromId, toId, directed, platformOffset)
+VALUES (?, ?, ?, ?)
+RETURNING id
+`
+
+type CreateDirectedEdgeParams struct {
+	Fromid         int64
+	Toid           int64
+	Directed       int64
+	Platformoffset float64
+}
+
+func (q *Queries) CreateDirectedEdge(ctx context.Context, arg CreateDirectedEdgeParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, createDirectedEdge,
+		arg.Fromid,
+		arg.Toid,
+		arg.Directed,
+		arg.Platformoffset,
+	)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
 const createEdge = `-- name: CreateEdge :one
 INSERT INTO edge (fromId, toId) 
 VALUES (?, ?)
@@ -27,6 +52,33 @@ func (q *Queries) CreateEdge(ctx context.Context, arg CreateEdgeParams) (int64,
 	return id, err
 }
 
+const createEdgePoint = `-- name: CreateEdgePoint :one
+INSERT INTO edge_point (edgeId, odr, x, y, z)
+VALUES (?, ?, ?, ?, ?)
+RETURNING id
+`
+
+type CreateEdgePointParams struct {
+	Edgeid int64
+	Odr    int64
+	X      float64
+	Y      float64
+	Z      float64
+}
+
+func (q *Queries) CreateEdgePoint(ctx context.Context, arg CreateEdgePointParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, createEdgePoint,
+		arg.Edgeid,
+		arg.Odr,
+		arg.X,
+		arg.Y,
+		arg.Z,
+	)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
 const deleteAllEdgePoints = `-- name: DeleteAllEdgePoints :exec
 DELETE FROM edge_point
 `
@@ -45,6 +97,26 @@ func (q *Queries) DeleteAllEdges(ctx context.Context) error {
 	return err
 }
 
+const deleteEdge = `-- name: DeleteEdge :exec
+DELETE FROM edge
+WHERE id = ?
+`
+
+func (q *Queries) DeleteEdge(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteEdge, id)
+	return err
+}
+
+const deleteEdgePoints = `-- name: DeleteEdgePoints :exec
+DELETE FROM edge_point
+WHERE edgeId = ?
+`
+
+func (q *Queries) DeleteEdgePoints(ctx context.Context, edgeid int64) error {
+	_, err := q.db.ExecContext(ctx, deleteEdgePoints, edgeid)
+	return err
+}
+
 const getEdgePoints = `-- name: GetEdgePoints :many
 SELECT id, edgeId, X, Y, Z, odr
 FROM edge_point
@@ -92,7 +164,7 @@ func (q *Queries) GetEdgePoints(ctx context.Context, edgeid int64) ([]GetEdgePoi
 }
 
 const getEdges = `-- name: GetEdges :many
-SELECT id, fromId, toId
+SELECT id, fromId, toId, directed, platformOffset
 FROM edge
 `
 
@@ -105,7 +177,53 @@ func (q *Queries) GetEdges(ctx context.Context) ([]Edge, error) {
 	var items []Edge
 	for rows.Next() {
 		var i Edge
-		if err := rows.Scan(&i.ID, &i.Fromid, &i.Toid); err != nil {
+		if err := rows.Scan(
+			&i.ID,
+			&i.Fromid,
+			&i.Toid,
+			&i.Directed,
+			&i.Platformoffset,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getEdgesBetween = `-- name: GetEdgesBetween :many
+SELECT id, fromId, toId, directed, platformOffset
+FROM edge
+WHERE fromId = ? AND toId = ?
+`
+
+type GetEdgesBetweenParams struct {
+	Fromid int64
+	Toid   int64
+}
+
+func (q *Queries) GetEdgesBetween(ctx context.Context, arg GetEdgesBetweenParams) ([]Edge, error) {
+	rows, err := q.db.QueryContext(ctx, getEdgesBetween, arg.Fromid, arg.Toid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Edge
+	for rows.Next() {
+		var i Edge
+		if err := rows.Scan(
+			&i.ID,
+			&i.Fromid,
+			&i.Toid,
+			&i.Directed,
+			&i.Platformoffset,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
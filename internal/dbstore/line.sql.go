@@ -93,6 +93,16 @@ func (q *Queries) DeleteLine(ctx context.Context, id int64) error {
 	return err
 }
 
+const deleteStationLinesByLine = `-- name: DeleteStationLinesByLine :exec
+DELETE FROM station_line
+WHERE lineId = ?
+`
+
+func (q *Queries) DeleteStationLinesByLine(ctx context.Context, lineid sql.NullInt64) error {
+	_, err := q.db.ExecContext(ctx, deleteStationLinesByLine, lineid)
+	return err
+}
+
 const getLineById = `-- name: GetLineById :one
 SELECT id, name, color FROM line
 WHERE id = ?
@@ -9,10 +9,46 @@ import (
 	"time"
 )
 
+type Commuter struct {
+	ID               string
+	Name             string
+	Persona          string
+	HomeStationID    int64
+	WorkStationID    int64
+	MorningDeparture int64
+	EveningDeparture int64
+	AvoidLineID      sql.NullInt64
+	Satisfaction     float64
+	Trips            int64
+	LostTrips        int64
+	BadDays          int64
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+type ComplexWalk struct {
+	ID            int64
+	FromStationID int64
+	ToStationID   int64
+	Walk          int64
+}
+
+type Demand struct {
+	ID            int64
+	BandStart     int64
+	BandEnd       int64
+	OriginID      int64
+	DestinationID int64
+	PerHour       float64
+	DayType       string
+}
+
 type Edge struct {
-	ID     int64
-	Fromid int64
-	Toid   int64
+	ID             int64
+	Fromid         int64
+	Toid           int64
+	Directed       int64
+	Platformoffset float64
 }
 
 type EdgePoint struct {
@@ -54,6 +90,7 @@ type Passenger struct {
 	SpawnTime            time.Time
 	CreatedAt            time.Time
 	UpdatedAt            time.Time
+	Persona              string
 }
 
 type PassengerEvent struct {
@@ -65,6 +102,9 @@ type PassengerEvent struct {
 	Sentiment   sql.NullFloat64
 	Metadata    sql.NullString
 	CreatedAt   time.Time
+	SimDay      int64
+	SimTime     int64
+	Seq         int64
 }
 
 type Schedule struct {
@@ -74,17 +114,48 @@ type Schedule struct {
 	ScheduledTime int64
 	SequenceOrder int64
 	CreatedAt     time.Time
+	DayType       string
 }
 
-type Station struct {
+type SimulationRun struct {
 	ID        int64
-	Name      string
-	X         sql.NullFloat64
-	Y         sql.NullFloat64
-	Z         sql.NullFloat64
-	Color     sql.NullString
+	Seed      int64
 	CreatedAt time.Time
-	UpdatedAt time.Time
+}
+
+type SpecialEvent struct {
+	ID         int64
+	Name       string
+	StationID  int64
+	Day        int64
+	StartTime  int64
+	EndTime    int64
+	Attendance int64
+	CreatedAt  time.Time
+}
+
+type Station struct {
+	ID               int64
+	Name             string
+	X                sql.NullFloat64
+	Y                sql.NullFloat64
+	Z                sql.NullFloat64
+	Color            sql.NullString
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	PlatformCapacity int64
+	GateThroughput   float64
+	ComplexID        sql.NullInt64
+	EntranceWalk     int64
+	Zone             int64
+}
+
+type StationComplex struct {
+	ID           int64
+	Name         string
+	TransferWalk int64
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
 }
 
 type StationLine struct {
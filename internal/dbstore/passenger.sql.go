@@ -23,9 +23,9 @@ func (q *Queries) CountActivePassengers(ctx context.Context) (int64, error) {
 }
 
 const createPassenger = `-- name: CreatePassenger :one
-INSERT INTO passenger (id, name, current_station_id, destination_station_id, state, sentiment, spawn_time)
-VALUES (?, ?, ?, ?, ?, ?, ?)
-RETURNING id, name, current_station_id, destination_station_id, current_train_id, state, sentiment, spawn_time, created_at, updated_at
+INSERT INTO passenger (id, name, current_station_id, destination_station_id, state, sentiment, spawn_time, persona)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id, name, current_station_id, destination_station_id, current_train_id, state, sentiment, spawn_time, created_at, updated_at, persona
 `
 
 type CreatePassengerParams struct {
@@ -36,6 +36,7 @@ type CreatePassengerParams struct {
 	State                string
 	Sentiment            float64
 	SpawnTime            time.Time
+	Persona              string
 }
 
 func (q *Queries) CreatePassenger(ctx context.Context, arg CreatePassengerParams) (Passenger, error) {
@@ -47,6 +48,7 @@ func (q *Queries) CreatePassenger(ctx context.Context, arg CreatePassengerParams
 		arg.State,
 		arg.Sentiment,
 		arg.SpawnTime,
+		arg.Persona,
 	)
 	var i Passenger
 	err := row.Scan(
@@ -60,15 +62,16 @@ func (q *Queries) CreatePassenger(ctx context.Context, arg CreatePassengerParams
 		&i.SpawnTime,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Persona,
 	)
 	return i, err
 }
 
 const createPassengerEvent = `-- name: CreatePassengerEvent :one
 
-INSERT INTO passenger_event (passenger_id, event_type, station_id, train_id, sentiment, metadata)
-VALUES (?, ?, ?, ?, ?, ?)
-RETURNING id, passenger_id, event_type, station_id, train_id, sentiment, metadata, created_at
+INSERT INTO passenger_event (passenger_id, event_type, station_id, train_id, sentiment, metadata, sim_day, sim_time, seq)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id, passenger_id, event_type, station_id, train_id, sentiment, metadata, created_at, sim_day, sim_time, seq
 `
 
 type CreatePassengerEventParams struct {
@@ -78,6 +81,9 @@ type CreatePassengerEventParams struct {
 	TrainID     sql.NullInt64
 	Sentiment   sql.NullFloat64
 	Metadata    sql.NullString
+	SimDay      int64
+	SimTime     int64
+	Seq         int64
 }
 
 // Passenger Event queries
@@ -89,6 +95,9 @@ func (q *Queries) CreatePassengerEvent(ctx context.Context, arg CreatePassengerE
 		arg.TrainID,
 		arg.Sentiment,
 		arg.Metadata,
+		arg.SimDay,
+		arg.SimTime,
+		arg.Seq,
 	)
 	var i PassengerEvent
 	err := row.Scan(
@@ -100,6 +109,9 @@ func (q *Queries) CreatePassengerEvent(ctx context.Context, arg CreatePassengerE
 		&i.Sentiment,
 		&i.Metadata,
 		&i.CreatedAt,
+		&i.SimDay,
+		&i.SimTime,
+		&i.Seq,
 	)
 	return i, err
 }
@@ -133,8 +145,18 @@ func (q *Queries) DeletePassengerEvents(ctx context.Context, passengerID string)
 	return err
 }
 
+const deletePassengerEventsBefore = `-- name: DeletePassengerEventsBefore :exec
+DELETE FROM passenger_event
+WHERE sim_day < ?
+`
+
+func (q *Queries) DeletePassengerEventsBefore(ctx context.Context, simDay int64) error {
+	_, err := q.db.ExecContext(ctx, deletePassengerEventsBefore, simDay)
+	return err
+}
+
 const getAllActivePassengers = `-- name: GetAllActivePassengers :many
-SELECT id, name, current_station_id, destination_station_id, current_train_id, state, sentiment, spawn_time, created_at, updated_at FROM passenger
+SELECT id, name, current_station_id, destination_station_id, current_train_id, state, sentiment, spawn_time, created_at, updated_at, persona FROM passenger
 ORDER BY spawn_time DESC
 `
 
@@ -158,6 +180,7 @@ func (q *Queries) GetAllActivePassengers(ctx context.Context) ([]Passenger, erro
 			&i.SpawnTime,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Persona,
 		); err != nil {
 			return nil, err
 		}
@@ -173,7 +196,7 @@ func (q *Queries) GetAllActivePassengers(ctx context.Context) ([]Passenger, erro
 }
 
 const getPassengerById = `-- name: GetPassengerById :one
-SELECT id, name, current_station_id, destination_station_id, current_train_id, state, sentiment, spawn_time, created_at, updated_at FROM passenger
+SELECT id, name, current_station_id, destination_station_id, current_train_id, state, sentiment, spawn_time, created_at, updated_at, persona FROM passenger
 WHERE id = ?
 LIMIT 1
 `
@@ -192,12 +215,13 @@ func (q *Queries) GetPassengerById(ctx context.Context, id string) (Passenger, e
 		&i.SpawnTime,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Persona,
 	)
 	return i, err
 }
 
 const getPassengerEvents = `-- name: GetPassengerEvents :many
-SELECT id, passenger_id, event_type, station_id, train_id, sentiment, metadata, created_at FROM passenger_event
+SELECT id, passenger_id, event_type, station_id, train_id, sentiment, metadata, created_at, sim_day, sim_time, seq FROM passenger_event
 WHERE passenger_id = ?
 ORDER BY created_at ASC
 `
@@ -220,6 +244,70 @@ func (q *Queries) GetPassengerEvents(ctx context.Context, passengerID string) ([
 			&i.Sentiment,
 			&i.Metadata,
 			&i.CreatedAt,
+			&i.SimDay,
+			&i.SimTime,
+			&i.Seq,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPassengerTimeline = `-- name: GetPassengerTimeline :many
+SELECT pe.id, pe.passenger_id, pe.event_type, pe.station_id, pe.train_id, pe.sentiment, pe.metadata, pe.sim_day, pe.sim_time, pe.seq,
+    COALESCE(s.name, '') AS station_name, COALESCE(t.name, '') AS train_name
+FROM passenger_event pe
+LEFT JOIN station s ON s.id = pe.station_id
+LEFT JOIN train t ON t.id = pe.train_id
+WHERE pe.passenger_id = ?
+ORDER BY pe.sim_day, pe.sim_time, pe.id
+`
+
+type GetPassengerTimelineRow struct {
+	ID          int64
+	PassengerID string
+	EventType   string
+	StationID   sql.NullInt64
+	TrainID     sql.NullInt64
+	Sentiment   sql.NullFloat64
+	Metadata    sql.NullString
+	SimDay      int64
+	SimTime     int64
+	Seq         int64
+	StationName string
+	TrainName   string
+}
+
+func (q *Queries) GetPassengerTimeline(ctx context.Context, passengerID string) ([]GetPassengerTimelineRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPassengerTimeline, passengerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPassengerTimelineRow
+	for rows.Next() {
+		var i GetPassengerTimelineRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.PassengerID,
+			&i.EventType,
+			&i.StationID,
+			&i.TrainID,
+			&i.Sentiment,
+			&i.Metadata,
+			&i.SimDay,
+			&i.SimTime,
+			&i.Seq,
+			&i.StationName,
+			&i.TrainName,
 		); err != nil {
 			return nil, err
 		}
@@ -235,7 +323,7 @@ func (q *Queries) GetPassengerEvents(ctx context.Context, passengerID string) ([
 }
 
 const getPassengersByStation = `-- name: GetPassengersByStation :many
-SELECT id, name, current_station_id, destination_station_id, current_train_id, state, sentiment, spawn_time, created_at, updated_at FROM passenger
+SELECT id, name, current_station_id, destination_station_id, current_train_id, state, sentiment, spawn_time, created_at, updated_at, persona FROM passenger
 WHERE current_station_id = ? AND state = 'waiting'
 ORDER BY spawn_time ASC
 `
@@ -260,6 +348,7 @@ func (q *Queries) GetPassengersByStation(ctx context.Context, currentStationID i
 			&i.SpawnTime,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Persona,
 		); err != nil {
 			return nil, err
 		}
@@ -275,7 +364,7 @@ func (q *Queries) GetPassengersByStation(ctx context.Context, currentStationID i
 }
 
 const getPassengersByTrain = `-- name: GetPassengersByTrain :many
-SELECT id, name, current_station_id, destination_station_id, current_train_id, state, sentiment, spawn_time, created_at, updated_at FROM passenger
+SELECT id, name, current_station_id, destination_station_id, current_train_id, state, sentiment, spawn_time, created_at, updated_at, persona FROM passenger
 WHERE current_train_id = ? AND state = 'riding'
 ORDER BY spawn_time ASC
 `
@@ -300,6 +389,7 @@ func (q *Queries) GetPassengersByTrain(ctx context.Context, currentTrainID sql.N
 			&i.SpawnTime,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Persona,
 		); err != nil {
 			return nil, err
 		}
@@ -315,7 +405,7 @@ func (q *Queries) GetPassengersByTrain(ctx context.Context, currentTrainID sql.N
 }
 
 const getRecentEvents = `-- name: GetRecentEvents :many
-SELECT id, passenger_id, event_type, station_id, train_id, sentiment, metadata, created_at FROM passenger_event
+SELECT id, passenger_id, event_type, station_id, train_id, sentiment, metadata, created_at, sim_day, sim_time, seq FROM passenger_event
 ORDER BY created_at DESC
 LIMIT ?
 `
@@ -338,6 +428,9 @@ func (q *Queries) GetRecentEvents(ctx context.Context, limit int64) ([]Passenger
 			&i.Sentiment,
 			&i.Metadata,
 			&i.CreatedAt,
+			&i.SimDay,
+			&i.SimTime,
+			&i.Seq,
 		); err != nil {
 			return nil, err
 		}
@@ -10,9 +10,9 @@ import (
 )
 
 const createScheduleEntry = `-- name: CreateScheduleEntry :one
-INSERT INTO schedule (train_id, station_id, scheduled_time, sequence_order)
-VALUES (?, ?, ?, ?)
-RETURNING id, train_id, station_id, scheduled_time, sequence_order, created_at
+INSERT INTO schedule (train_id, station_id, scheduled_time, sequence_order, day_type)
+VALUES (?, ?, ?, ?, ?)
+RETURNING id, train_id, station_id, scheduled_time, sequence_order, created_at, day_type
 `
 
 type CreateScheduleEntryParams struct {
@@ -20,6 +20,7 @@ type CreateScheduleEntryParams struct {
 	StationID     int64
 	ScheduledTime int64
 	SequenceOrder int64
+	DayType       string
 }
 
 func (q *Queries) CreateScheduleEntry(ctx context.Context, arg CreateScheduleEntryParams) (Schedule, error) {
@@ -28,6 +29,7 @@ func (q *Queries) CreateScheduleEntry(ctx context.Context, arg CreateScheduleEnt
 		arg.StationID,
 		arg.ScheduledTime,
 		arg.SequenceOrder,
+		arg.DayType,
 	)
 	var i Schedule
 	err := row.Scan(
@@ -37,6 +39,7 @@ func (q *Queries) CreateScheduleEntry(ctx context.Context, arg CreateScheduleEnt
 		&i.ScheduledTime,
 		&i.SequenceOrder,
 		&i.CreatedAt,
+		&i.DayType,
 	)
 	return i, err
 }
@@ -51,8 +54,8 @@ func (q *Queries) DeleteScheduleForTrain(ctx context.Context, trainID int64) err
 }
 
 const getAllSchedules = `-- name: GetAllSchedules :many
-SELECT id, train_id, station_id, scheduled_time, sequence_order, created_at FROM schedule
-ORDER BY train_id, sequence_order
+SELECT id, train_id, station_id, scheduled_time, sequence_order, created_at, day_type FROM schedule
+ORDER BY day_type, train_id, sequence_order
 `
 
 func (q *Queries) GetAllSchedules(ctx context.Context) ([]Schedule, error) {
@@ -71,6 +74,7 @@ func (q *Queries) GetAllSchedules(ctx context.Context) ([]Schedule, error) {
 			&i.ScheduledTime,
 			&i.SequenceOrder,
 			&i.CreatedAt,
+			&i.DayType,
 		); err != nil {
 			return nil, err
 		}
@@ -86,7 +90,7 @@ func (q *Queries) GetAllSchedules(ctx context.Context) ([]Schedule, error) {
 }
 
 const getNextScheduledStop = `-- name: GetNextScheduledStop :one
-SELECT id, train_id, station_id, scheduled_time, sequence_order, created_at FROM schedule
+SELECT id, train_id, station_id, scheduled_time, sequence_order, created_at, day_type FROM schedule
 WHERE train_id = ? AND sequence_order > ?
 ORDER BY sequence_order ASC
 LIMIT 1
@@ -107,23 +111,25 @@ func (q *Queries) GetNextScheduledStop(ctx context.Context, arg GetNextScheduled
 		&i.ScheduledTime,
 		&i.SequenceOrder,
 		&i.CreatedAt,
+		&i.DayType,
 	)
 	return i, err
 }
 
 const getScheduleByTrainAndStation = `-- name: GetScheduleByTrainAndStation :one
-SELECT id, train_id, station_id, scheduled_time, sequence_order, created_at FROM schedule
-WHERE train_id = ? AND station_id = ?
+SELECT id, train_id, station_id, scheduled_time, sequence_order, created_at, day_type FROM schedule
+WHERE train_id = ? AND station_id = ? AND day_type = ?
 LIMIT 1
 `
 
 type GetScheduleByTrainAndStationParams struct {
 	TrainID   int64
 	StationID int64
+	DayType   string
 }
 
 func (q *Queries) GetScheduleByTrainAndStation(ctx context.Context, arg GetScheduleByTrainAndStationParams) (Schedule, error) {
-	row := q.db.QueryRowContext(ctx, getScheduleByTrainAndStation, arg.TrainID, arg.StationID)
+	row := q.db.QueryRowContext(ctx, getScheduleByTrainAndStation, arg.TrainID, arg.StationID, arg.DayType)
 	var i Schedule
 	err := row.Scan(
 		&i.ID,
@@ -132,12 +138,13 @@ func (q *Queries) GetScheduleByTrainAndStation(ctx context.Context, arg GetSched
 		&i.ScheduledTime,
 		&i.SequenceOrder,
 		&i.CreatedAt,
+		&i.DayType,
 	)
 	return i, err
 }
 
 const getScheduleForStation = `-- name: GetScheduleForStation :many
-SELECT id, train_id, station_id, scheduled_time, sequence_order, created_at FROM schedule
+SELECT id, train_id, station_id, scheduled_time, sequence_order, created_at, day_type FROM schedule
 WHERE station_id = ?
 ORDER BY scheduled_time ASC
 `
@@ -158,6 +165,7 @@ func (q *Queries) GetScheduleForStation(ctx context.Context, stationID int64) ([
 			&i.ScheduledTime,
 			&i.SequenceOrder,
 			&i.CreatedAt,
+			&i.DayType,
 		); err != nil {
 			return nil, err
 		}
@@ -173,7 +181,7 @@ func (q *Queries) GetScheduleForStation(ctx context.Context, stationID int64) ([
 }
 
 const getScheduleForTrain = `-- name: GetScheduleForTrain :many
-SELECT id, train_id, station_id, scheduled_time, sequence_order, created_at FROM schedule
+SELECT id, train_id, station_id, scheduled_time, sequence_order, created_at, day_type FROM schedule
 WHERE train_id = ?
 ORDER BY sequence_order ASC
 `
@@ -194,6 +202,7 @@ func (q *Queries) GetScheduleForTrain(ctx context.Context, trainID int64) ([]Sch
 			&i.ScheduledTime,
 			&i.SequenceOrder,
 			&i.CreatedAt,
+			&i.DayType,
 		); err != nil {
 			return nil, err
 		}
@@ -144,17 +144,22 @@ func (q *Queries) GetStationLines(ctx context.Context, stationid sql.NullInt64)
 }
 
 const listStations = `-- name: ListStations :many
-SELECT id, name, x, y, z, color FROM station 
+SELECT id, name, x, y, z, color, platform_capacity, gate_throughput, complex_id, entrance_walk, zone FROM station 
 ORDER BY id
 `
 
 type ListStationsRow struct {
-	ID    int64
-	Name  string
-	X     sql.NullFloat64
-	Y     sql.NullFloat64
-	Z     sql.NullFloat64
-	Color sql.NullString
+	ID               int64
+	Name             string
+	X                sql.NullFloat64
+	Y                sql.NullFloat64
+	Z                sql.NullFloat64
+	Color            sql.NullString
+	PlatformCapacity int64
+	GateThroughput   float64
+	ComplexID        sql.NullInt64
+	EntranceWalk     int64
+	Zone             int64
 }
 
 func (q *Queries) ListStations(ctx context.Context) ([]ListStationsRow, error) {
@@ -173,6 +178,11 @@ func (q *Queries) ListStations(ctx context.Context) ([]ListStationsRow, error) {
 			&i.Y,
 			&i.Z,
 			&i.Color,
+			&i.PlatformCapacity,
+			&i.GateThroughput,
+			&i.ComplexID,
+			&i.EntranceWalk,
+			&i.Zone,
 		); err != nil {
 			return nil, err
 		}
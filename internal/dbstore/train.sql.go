@@ -270,6 +270,62 @@ func (q *Queries) GetTrainNext(ctx context.Context, id int64) (sql.NullInt64, er
 	return nextid, err
 }
 
+const listTrainReferences = `-- name: ListTrainReferences :many
+SELECT
+	tr.id,
+	tr.name,
+	tr.currentId,
+	tr.makeId,
+	tr.lineId,
+	mk.name as makeName,
+	ln.name as lineName
+FROM train tr
+LEFT JOIN make mk ON tr.makeId = mk.id
+LEFT JOIN line ln ON tr.lineId = ln.id
+ORDER BY tr.id
+`
+
+type ListTrainReferencesRow struct {
+	ID        int64
+	Name      string
+	Currentid sql.NullInt64
+	Makeid    sql.NullInt64
+	Lineid    sql.NullInt64
+	Makename  sql.NullString
+	Linename  sql.NullString
+}
+
+func (q *Queries) ListTrainReferences(ctx context.Context) ([]ListTrainReferencesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listTrainReferences)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListTrainReferencesRow
+	for rows.Next() {
+		var i ListTrainReferencesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Currentid,
+			&i.Makeid,
+			&i.Lineid,
+			&i.Makename,
+			&i.Linename,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const setTrainNext = `-- name: SetTrainNext :exec
 UPDATE train
 SET nextId = ?
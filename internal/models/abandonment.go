@@ -0,0 +1,49 @@
+package models
+
+import "github.com/odin-software/metro/control"
+
+// AbandonReason is why a passenger left the station without travelling.
+type AbandonReason string
+
+const (
+	AbandonBalk   AbandonReason = "balk"   // The queue was too long when they arrived
+	AbandonRenege AbandonReason = "renege" // They ran out of patience while waiting
+)
+
+// Description returns a human readable explanation of the reason.
+func (r AbandonReason) Description() string {
+	switch r {
+	case AbandonBalk:
+		return "the queue was too long"
+	case AbandonRenege:
+		return "ran out of patience"
+	}
+	return string(r)
+}
+
+// Balks returns true if the persona leaves instead of joining a platform queue of the given length.
+func (pe Persona) Balks(config control.AbandonmentConfig, queueLength int) bool {
+	if config.BalkQueueLength <= 0 {
+		return false
+	}
+	return float64(queueLength) >= float64(config.BalkQueueLength)*pe.patience()
+}
+
+// Reneges returns true if the persona gives up after waiting, in simulation seconds,
+// with the given sentiment. Nobody keeps waiting once their sentiment is gone.
+func (pe Persona) Reneges(config control.AbandonmentConfig, wait, sentiment float64) bool {
+	if sentiment <= 0 {
+		return true
+	}
+	if config.MaxWait <= 0 {
+		return false
+	}
+	return wait >= config.MaxWait.Seconds()*pe.patience()
+}
+
+func (pe Persona) patience() float64 {
+	if pe.Patience <= 0 {
+		return 1
+	}
+	return pe.Patience
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/odin-software/metro/control"
+)
+
+func TestPersonaBalks(t *testing.T) {
+	config := control.AbandonmentConfig{BalkQueueLength: 40}
+	commuter := PersonaByKind(PersonaCommuter)
+	elderly := PersonaByKind(PersonaElderly)
+
+	if commuter.Balks(config, 10) {
+		t.Fatal("Short queues should not make passengers leave.")
+	}
+	if !commuter.Balks(config, 40) {
+		t.Fatal("Impatient passengers should leave long queues.")
+	}
+	if elderly.Balks(config, 40) {
+		t.Fatal("Patient passengers should put up with longer queues.")
+	}
+	if commuter.Balks(control.AbandonmentConfig{}, 1000) {
+		t.Fatal("Balking should be disabled without a queue length.")
+	}
+}
+
+func TestPersonaReneges(t *testing.T) {
+	config := control.AbandonmentConfig{MaxWait: 30 * time.Minute}
+	commuter := PersonaByKind(PersonaCommuter)
+
+	if commuter.Reneges(config, 600, 80) {
+		t.Fatal("Passengers should keep waiting while they have patience.")
+	}
+	if !commuter.Reneges(config, 1800, 80) {
+		t.Fatal("Passengers should give up after waiting too long.")
+	}
+	if !commuter.Reneges(control.AbandonmentConfig{}, 0, 0) {
+		t.Fatal("Passengers should give up when their sentiment is gone.")
+	}
+}
@@ -0,0 +1,116 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/odin-software/metro/control"
+)
+
+// DayType is the kind of service a simulated day runs. Timetables and demand are
+// kept per day type.
+type DayType string
+
+const (
+	DayTypeWeekday  DayType = "weekday"
+	DayTypeSaturday DayType = "saturday"
+	DayTypeSunday   DayType = "sunday"
+	DayTypeHoliday  DayType = "holiday"
+)
+
+// DayTypes lists every day type, weekdays first.
+var DayTypes = []DayType{DayTypeWeekday, DayTypeSaturday, DayTypeSunday, DayTypeHoliday}
+
+// ParseDayType reads a day type, an empty value is a weekday.
+func ParseDayType(value string) (DayType, error) {
+	if value == "" {
+		return DayTypeWeekday, nil
+	}
+	for _, dt := range DayTypes {
+		if DayType(value) == dt {
+			return dt, nil
+		}
+	}
+	return "", fmt.Errorf("invalid day type %q", value)
+}
+
+// Fallback returns the day type whose service runs when this one has none of its
+// own: holidays run the Sunday service, Sundays the Saturday one and Saturdays the
+// weekday one. Weekdays have no fallback.
+func (dt DayType) Fallback() (DayType, bool) {
+	switch dt {
+	case DayTypeHoliday:
+		return DayTypeSunday, true
+	case DayTypeSunday:
+		return DayTypeSaturday, true
+	case DayTypeSaturday:
+		return DayTypeWeekday, true
+	}
+	return "", false
+}
+
+// Calendar gives the date and the day type of each simulated day.
+type Calendar struct {
+	start    time.Time       // Date of day 0, at midnight UTC
+	holidays map[string]bool // Holidays by date, as 2006-01-02
+}
+
+const dateLayout = "2006-01-02"
+
+func NewCalendar(start time.Time, holidays []time.Time) *Calendar {
+	c := &Calendar{
+		start:    time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC),
+		holidays: make(map[string]bool, len(holidays)),
+	}
+	for _, holiday := range holidays {
+		c.holidays[holiday.Format(dateLayout)] = true
+	}
+	return c
+}
+
+// ParseCalendar builds the calendar from the config. The first simulated day is
+// today when the start date is empty.
+func ParseCalendar(config control.CalendarConfig) (*Calendar, error) {
+	start := time.Now()
+	if config.StartDate != "" {
+		date, err := time.Parse(dateLayout, config.StartDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid start date %q", config.StartDate)
+		}
+		start = date
+	}
+	holidays := make([]time.Time, 0, len(config.Holidays))
+	for _, value := range config.Holidays {
+		date, err := time.Parse(dateLayout, value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid holiday %q", value)
+		}
+		holidays = append(holidays, date)
+	}
+	return NewCalendar(start, holidays), nil
+}
+
+// Date returns the date of a simulated day, day 0 is the start date.
+func (c *Calendar) Date(day int) time.Time {
+	return c.start.AddDate(0, 0, day)
+}
+
+// DayType returns the service a simulated day runs.
+func (c *Calendar) DayType(day int) DayType {
+	date := c.Date(day)
+	if c.holidays[date.Format(dateLayout)] {
+		return DayTypeHoliday
+	}
+	switch date.Weekday() {
+	case time.Saturday:
+		return DayTypeSaturday
+	case time.Sunday:
+		return DayTypeSunday
+	}
+	return DayTypeWeekday
+}
+
+// FormatDate describes a date for the boards, like "Mon 3 Mar 2025".
+func FormatDate(date time.Time) string {
+	return date.Format("Mon 2 Jan 2006")
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/odin-software/metro/control"
+)
+
+func TestCalendarDayTypes(t *testing.T) {
+	calendar, err := ParseCalendar(control.CalendarConfig{StartDate: "2025-03-03", Holidays: []string{"2025-03-05"}})
+	if err != nil {
+		t.Fatal("The calendar should parse its dates.")
+	}
+
+	expected := []DayType{
+		DayTypeWeekday, DayTypeWeekday, DayTypeHoliday, DayTypeWeekday, DayTypeWeekday,
+		DayTypeSaturday, DayTypeSunday, DayTypeWeekday,
+	}
+	for day, dayType := range expected {
+		if calendar.DayType(day) != dayType {
+			t.Fatal("Every day of the week should run its own service.")
+		}
+	}
+	if date := calendar.Date(6); date.Weekday() != time.Sunday || FormatDate(date) != "Sun 9 Mar 2025" {
+		t.Fatal("The days should follow the start date.")
+	}
+	if calendar.Date(29).Month() != time.April {
+		t.Fatal("The days should go across months.")
+	}
+
+	if _, err := ParseCalendar(control.CalendarConfig{StartDate: "03/03/2025"}); err == nil {
+		t.Fatal("Invalid dates should be rejected.")
+	}
+	if dayType, err := ParseDayType(""); err != nil || dayType != DayTypeWeekday {
+		t.Fatal("An empty day type should be a weekday.")
+	}
+	if _, err := ParseDayType("friday"); err == nil {
+		t.Fatal("Unknown day types should be rejected.")
+	}
+}
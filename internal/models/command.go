@@ -0,0 +1,427 @@
+package models
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/odin-software/metro/control"
+)
+
+// CommandKind is what a command asks a train to do.
+type CommandKind string
+
+const (
+	CommandHold         CommandKind = "hold"           // Hold at the next station for Seconds
+	CommandSkipStop     CommandKind = "skip_stop"      // Pass the next station without stopping
+	CommandSpeedCap     CommandKind = "speed_cap"      // Run no faster than SpeedKmH, 0 lifts the cap
+	CommandShortTurn    CommandKind = "short_turn"     // Turn back at StationID instead of the terminal
+	CommandOutOfService CommandKind = "out_of_service" // Let everyone off at the next station and go to the depot
+	CommandEnterService CommandKind = "enter_service"  // Leave the depot at StationID, or where the train left service
+)
+
+// Command is an instruction for a train, from Tenjin, the operator or an external agent.
+type Command struct {
+	ID        int64 // Set by the dispatcher
+	TrainID   int64
+	Kind      CommandKind
+	Seconds   int     // Length of a hold
+	SpeedKmH  float64 // Speed cap
+	StationID int64   // Station of a short-turn or of the entry in service. For a hold or a skip, the station expected, 0 for any
+	Source    string  // Who sent it, like "tenjin" or "operator"
+}
+
+// CommandAck is the answer of a train to a command.
+type CommandAck struct {
+	CommandID int64
+	TrainID   int64
+	Kind      CommandKind
+	Accepted  bool
+	Reason    string // What the train does, or why it rejected the command
+	Time      int    // Simulation seconds since the first midnight
+}
+
+// commandRequest is a command on its way to a train, with where the answer goes and
+// the trains it is checked against.
+type commandRequest struct {
+	command Command
+	reply   chan CommandAck
+	fleet   []*Train
+}
+
+// Dispatcher sends commands to the trains. It is the single way Tenjin, the operator
+// and external agents act on them: a train takes its commands at its next tick,
+// checks them against its state and the other trains, and answers every one.
+type Dispatcher struct {
+	trains []*Train
+	nextID atomic.Int64
+}
+
+func NewDispatcher(trains []*Train) *Dispatcher {
+	return &Dispatcher{trains: trains}
+}
+
+// Send sends a command to its train. The answer comes on the returned channel at the
+// next tick of the simulation, or right away when the command can't be delivered.
+func (d *Dispatcher) Send(command Command) <-chan CommandAck {
+	command.ID = d.nextID.Add(1)
+	reply := make(chan CommandAck, 1)
+	for _, tr := range d.trains {
+		if tr.ID == command.TrainID {
+			tr.send(commandRequest{command: command, reply: reply, fleet: d.trains})
+			return reply
+		}
+	}
+	reply <- CommandAck{
+		CommandID: command.ID,
+		TrainID:   command.TrainID,
+		Kind:      command.Kind,
+		Reason:    fmt.Sprintf("no train %d", command.TrainID),
+	}
+	return reply
+}
+
+// send queues a command for the next tick of the train, it is rejected when the
+// train already has too many waiting.
+func (tr *Train) send(request commandRequest) {
+	select {
+	case tr.commands <- request:
+	default:
+		tr.acknowledge(request, "", fmt.Errorf("%s has too many commands waiting", tr.Name))
+	}
+}
+
+// applyCommands carries out the commands sent since the last tick, in order.
+func (tr *Train) applyCommands() {
+	for {
+		select {
+		case request := <-tr.commands:
+			reason, err := tr.execute(request)
+			tr.acknowledge(request, reason, err)
+		default:
+			return
+		}
+	}
+}
+
+// acknowledge answers a command, logs it and emits it to Tenjin.
+func (tr *Train) acknowledge(request commandRequest, reason string, err error) {
+	command := request.command
+	ack := CommandAck{
+		CommandID: command.ID,
+		TrainID:   tr.ID,
+		Kind:      command.Kind,
+		Accepted:  err == nil,
+		Reason:    reason,
+		Time:      tr.simNow(),
+	}
+	verb := "accepted"
+	if err != nil {
+		ack.Reason = err.Error()
+		verb = "rejected"
+	}
+	request.reply <- ack
+
+	control.Log(fmt.Sprintf("%s %s %s from %s: %s", tr.Name, verb, command.Kind, command.Source, ack.Reason))
+	tr.emitCommandEvent(command, ack)
+}
+
+// execute checks a command against the state of the train and carries it out. It
+// returns what the train does, or why the command was rejected.
+func (tr *Train) execute(request commandRequest) (string, error) {
+	command := request.command
+	if tr.inDepot && command.Kind != CommandEnterService {
+		return "", fmt.Errorf("%s is in the depot", tr.Name)
+	}
+	if tr.Current == nil {
+		return "", fmt.Errorf("%s has no station", tr.Name)
+	}
+	switch command.Kind {
+	case CommandHold:
+		return tr.hold(command)
+	case CommandSkipStop:
+		return tr.skip(command)
+	case CommandSpeedCap:
+		return tr.capSpeed(command)
+	case CommandShortTurn:
+		return tr.shortTurn(command)
+	case CommandOutOfService:
+		return tr.leaveService()
+	case CommandEnterService:
+		return tr.enterService(command, request.fleet)
+	}
+	return "", fmt.Errorf("unknown command %q", command.Kind)
+}
+
+// hold keeps the train at the station it is standing at, or at the next one, for
+// longer than the dwell.
+func (tr *Train) hold(command Command) (string, error) {
+	limit := control.DefaultConfig.Commands.MaxHold
+	if command.Seconds <= 0 || time.Duration(command.Seconds)*time.Second > limit {
+		return "", fmt.Errorf("holds last from 1 s to %.0f s", limit.Seconds())
+	}
+	station := tr.Next
+	if station == nil {
+		station = tr.Current
+	}
+	if err := tr.expect(command, station); err != nil {
+		return "", err
+	}
+
+	ticks := simTicks(command.Seconds)
+	if tr.Next == nil {
+		tr.waitCounter += ticks
+		return fmt.Sprintf("holding at %s for %d s", station.Name, command.Seconds), nil
+	}
+	tr.holdTicks = ticks
+	return fmt.Sprintf("will hold at %s for %d s", station.Name, command.Seconds), nil
+}
+
+// skip passes the next stop without stopping. Terminals can't be skipped, nor stops
+// where passengers on board get off.
+func (tr *Train) skip(command Command) (string, error) {
+	station := tr.Next
+	if station == nil {
+		station = tr.departureStop()
+	}
+	if station == nil {
+		return "", fmt.Errorf("%s has no next stop", tr.Name)
+	}
+	if err := tr.expect(command, station); err != nil {
+		return "", err
+	}
+
+	stations := tr.destinations.Stations
+	if index := stationIndex(stations, station.ID); index <= 0 || index == len(stations)-1 {
+		return "", fmt.Errorf("%s is a terminal", station.Name)
+	}
+	if station.ID == tr.turnAt {
+		return "", fmt.Errorf("%s turns back at %s", tr.Name, station.Name)
+	}
+	if tr.leaving {
+		return "", fmt.Errorf("%s leaves service at %s", tr.Name, station.Name)
+	}
+	alighting := 0
+	for _, p := range tr.GetPassengers() {
+		if p.AlightsAt(station.ID) {
+			alighting++
+		}
+	}
+	if alighting > 0 {
+		return "", fmt.Errorf("%d passengers on board get off at %s", alighting, station.Name)
+	}
+
+	tr.skipStop = station.ID
+	return fmt.Sprintf("will pass %s without stopping", station.Name), nil
+}
+
+// capSpeed limits the speed of the train until the cap is lifted.
+func (tr *Train) capSpeed(command Command) (string, error) {
+	if command.SpeedKmH == 0 {
+		tr.speedCap = 0
+		return "speed cap lifted", nil
+	}
+	minimum := control.DefaultConfig.Commands.MinSpeed
+	if command.SpeedKmH < minimum {
+		return "", fmt.Errorf("speed caps start at %.0f km/h", minimum)
+	}
+	if top := PixelSpeedToKmPerHour(tr.model.TopSpeed); command.SpeedKmH >= top {
+		return "", fmt.Errorf("%s runs at %.0f km/h at most", tr.Name, top)
+	}
+	tr.speedCap = KmPerHourToPixelSpeed(command.SpeedKmH)
+	return fmt.Sprintf("running at %.0f km/h at most", command.SpeedKmH), nil
+}
+
+// shortTurn turns the train back at a station ahead instead of at the terminal. The
+// passengers going further get off there and continue with the next train.
+func (tr *Train) shortTurn(command Command) (string, error) {
+	stations := tr.destinations.Stations
+	turn := stationIndex(stations, command.StationID)
+	if turn == -1 {
+		return "", fmt.Errorf("station %d isn't on %s", command.StationID, tr.destinations.Name)
+	}
+	station := stations[turn]
+	if turn == 0 || turn == len(stations)-1 {
+		return "", fmt.Errorf("%s is a terminal", station.Name)
+	}
+	if tr.leaving {
+		return "", fmt.Errorf("%s is going to the depot", tr.Name)
+	}
+	if tr.turnAt != 0 {
+		return "", fmt.Errorf("%s already turns back at station %d", tr.Name, tr.turnAt)
+	}
+	if station.ID == tr.skipStop {
+		return "", fmt.Errorf("%s skips %s", tr.Name, station.Name)
+	}
+
+	// The station must be ahead: the next one or further on, or past the one the
+	// train is standing at
+	var ahead bool
+	if tr.Next != nil {
+		next := stationIndex(stations, tr.Next.ID)
+		ahead = next != -1 && (tr.forward && turn >= next || !tr.forward && turn <= next)
+	} else {
+		current := stationIndex(stations, tr.Current.ID)
+		ahead = current != -1 && (tr.departsForward(current) && turn > current || !tr.departsForward(current) && turn < current)
+	}
+	if !ahead {
+		return "", fmt.Errorf("%s isn't ahead of %s", station.Name, tr.Name)
+	}
+
+	tr.turnAt = station.ID
+	return fmt.Sprintf("will turn back at %s", station.Name), nil
+}
+
+// leaveService takes the train to the depot, from the station it is standing at or
+// after the next one.
+func (tr *Train) leaveService() (string, error) {
+	if tr.leaving {
+		return "", fmt.Errorf("%s is already going to the depot", tr.Name)
+	}
+	if tr.Next == nil {
+		station := tr.Current.Name
+		tr.toDepot()
+		return fmt.Sprintf("went to the depot from %s", station), nil
+	}
+	tr.leaving = true
+	return fmt.Sprintf("going to the depot after %s", tr.Next.Name), nil
+}
+
+// toDepot sets everyone down at the current station and takes the train out of
+// service, with nothing left of the previous commands.
+func (tr *Train) toDepot() {
+	tr.setDown()
+	tr.inDepot, tr.leaving = true, false
+	tr.holdTicks, tr.skipStop, tr.turnAt, tr.speedCap = 0, 0, 0, 0
+	tr.waitCounter = 0
+	tr.routeMutex.Lock()
+	tr.predictions = nil
+	tr.routeMutex.Unlock()
+	control.Log(fmt.Sprintf("%s went to the depot from %s", tr.Name, tr.Current.Name))
+}
+
+// enterService puts the train back in service at a station of its line, when no
+// other train of the line is standing there or about to arrive.
+func (tr *Train) enterService(command Command, fleet []*Train) (string, error) {
+	if !tr.inDepot {
+		return "", fmt.Errorf("%s is in service", tr.Name)
+	}
+	stationID := command.StationID
+	if stationID == 0 {
+		stationID = tr.Current.ID
+	}
+	stations := tr.destinations.Stations
+	index := stationIndex(stations, stationID)
+	if index == -1 {
+		return "", fmt.Errorf("station %d isn't on %s", stationID, tr.destinations.Name)
+	}
+	station := stations[index]
+
+	separation := MetersToPixels(control.DefaultConfig.Commands.Separation)
+	for _, other := range fleet {
+		if other == tr || other.inDepot || other.Current == nil || other.GetLine().ID != tr.destinations.ID {
+			continue
+		}
+		if other.Next == nil && other.Current.ID == station.ID {
+			return "", fmt.Errorf("%s is standing at %s", other.Name, station.Name)
+		}
+		if other.Next != nil && other.Next.ID == station.ID && other.remainingDistance() < separation {
+			return "", fmt.Errorf("%s is arriving at %s", other.Name, station.Name)
+		}
+	}
+
+	tr.inDepot = false
+	tr.Current, tr.Next = station, nil
+	tr.Position = station.GetPosition()
+	tr.velocity.Scale(0)
+	tr.q = Queue[Vector]{}
+	tr.waitCounter = tr.waitTicks
+	tr.handlePassengerBoarding()
+	tr.refreshPredictions()
+	return fmt.Sprintf("entering service at %s", station.Name), nil
+}
+
+// expect checks the command is for the station the train is due at, when it names one.
+func (tr *Train) expect(command Command, station *Station) error {
+	if command.StationID != 0 && command.StationID != station.ID {
+		return fmt.Errorf("%s is due at %s first", tr.Name, station.Name)
+	}
+	return nil
+}
+
+// departureStop returns the station the train goes to when it leaves the one it is
+// standing at, nil when it isn't on its line.
+func (tr *Train) departureStop() *Station {
+	stations := tr.destinations.Stations
+	index := stationIndex(stations, tr.Current.ID)
+	if index == -1 || len(stations) < 2 {
+		return nil
+	}
+	return stations[stepFrom(index, tr.departsForward(index))]
+}
+
+// setDown lets off the passengers still on board at the current station, to continue
+// with the next train.
+func (tr *Train) setDown() {
+	day := tr.simDay()
+	for _, p := range tr.GetPassengers() {
+		p.continueFrom(tr.Current)
+		tr.letOff(p, day)
+	}
+}
+
+// InService returns false while the train is in the depot.
+func (tr *Train) InService() bool {
+	return !tr.inDepot
+}
+
+// GetSpeedCapKmH returns the speed cap of the train in km/h, 0 when it has none.
+func (tr *Train) GetSpeedCapKmH() float64 {
+	if tr.speedCap == 0 {
+		return 0
+	}
+	return PixelSpeedToKmPerHour(tr.speedCap)
+}
+
+// GetTopSpeedKmH returns the top speed of the make of the train in km/h, without its
+// speed cap.
+func (tr *Train) GetTopSpeedKmH() float64 {
+	return PixelSpeedToKmPerHour(tr.model.TopSpeed)
+}
+
+// simTicks returns the ticks of the given simulation seconds.
+func simTicks(seconds int) int {
+	tickSeconds := control.DefaultConfig.LoopDuration.Seconds() * control.DefaultConfig.SimulationSpeed
+	return int(float64(seconds)/tickSeconds + 0.5)
+}
+
+// emitCommandEvent sends the answer of the train to a command to Tenjin
+func (tr *Train) emitCommandEvent(command Command, ack CommandAck) {
+	if tr.eventChannel == nil || !control.DefaultConfig.TenjinEnabled {
+		return
+	}
+
+	event := struct {
+		Type      string
+		TrainID   int64
+		Train     string
+		CommandID int64
+		Kind      string
+		Source    string
+		Accepted  bool
+		Reason    string
+		Time      time.Time
+	}{
+		Type:      "train_command",
+		TrainID:   tr.ID,
+		Train:     tr.Name,
+		CommandID: command.ID,
+		Kind:      string(command.Kind),
+		Source:    command.Source,
+		Accepted:  ack.Accepted,
+		Reason:    ack.Reason,
+		Time:      Now(),
+	}
+
+	emit(tr.eventChannel, event)
+}
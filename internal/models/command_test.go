@@ -0,0 +1,167 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/odin-software/metro/control"
+)
+
+// logToTemp sends the logs of the trains answering commands to a temporary directory
+func logToTemp(t *testing.T) {
+	control.DefaultConfig.LogsDirectory = t.TempDir() + "/"
+	control.InitLogger()
+}
+
+// newCommandLine returns a line of four stations 100 pixels apart, on a network
+func newCommandLine() (Line, *Network[*Station]) {
+	stations := make([]*Station, 4)
+	central := NewNetwork(func(st *Station) string { return st.Name })
+	for i := range stations {
+		stations[i] = &Station{ID: int64(i + 1), Name: string(rune('A' + i))}
+		stations[i].SetPosition(NewVector(float64(i*100), 0))
+	}
+	central.InsertVertices(stations)
+	for i := 1; i < len(stations); i++ {
+		central.InsertEdge(stations[i-1], stations[i], nil)
+	}
+	return Line{ID: 1, Name: "L1", Stations: stations}, central
+}
+
+// newCommandTrain returns a train of the line on its way from A to B
+func newCommandTrain(id int64, line Line, central *Network[*Station]) *Train {
+	return &Train{
+		ID:           id,
+		Name:         "T" + string(rune('0'+id)),
+		model:        Make{AccMag: 0.1, TopSpeed: 2},
+		Current:      line.Stations[0],
+		Next:         line.Stations[1],
+		forward:      true,
+		destinations: line,
+		central:      central,
+		Capacity:     10,
+		commands:     make(chan commandRequest, 4),
+	}
+}
+
+// command sends a command and runs a tick of the train
+func command(d *Dispatcher, tr *Train, cmd Command) CommandAck {
+	cmd.TrainID = tr.ID
+	reply := d.Send(cmd)
+	tr.applyCommands()
+	return <-reply
+}
+
+func TestCommandsAreValidated(t *testing.T) {
+	logToTemp(t)
+	line, central := newCommandLine()
+	tr := newCommandTrain(1, line, central)
+	d := NewDispatcher([]*Train{tr})
+
+	if ack := <-d.Send(Command{TrainID: 9, Kind: CommandHold, Seconds: 30}); ack.Accepted {
+		t.Fatal("A command for a train that doesn't exist should be rejected.")
+	}
+	reply := d.Send(Command{TrainID: tr.ID, Kind: CommandHold, Seconds: 30})
+	select {
+	case <-reply:
+		t.Fatal("A command should be answered at the next tick of the train.")
+	default:
+	}
+	tr.applyCommands()
+	if ack := <-reply; !ack.Accepted || tr.holdTicks == 0 {
+		t.Fatal("A hold at the next station should be accepted.")
+	}
+	if command(d, tr, Command{Kind: CommandHold, Seconds: 3600}).Accepted {
+		t.Fatal("A hold longer than the limit should be rejected.")
+	}
+	if command(d, tr, Command{Kind: CommandHold, Seconds: 30, StationID: 3}).Accepted {
+		t.Fatal("A hold at a station the train isn't due at should be rejected.")
+	}
+
+	// Skips
+	p := &Passenger{ID: "1", DestinationStation: line.Stations[1]}
+	tr.AddPassenger(p)
+	if command(d, tr, Command{Kind: CommandSkipStop}).Accepted {
+		t.Fatal("A stop where passengers get off shouldn't be skipped.")
+	}
+	tr.RemovePassenger(p)
+	if ack := command(d, tr, Command{Kind: CommandSkipStop}); !ack.Accepted || tr.skipStop != 2 {
+		t.Fatal("The next stop should be skipped.")
+	}
+	tr.skipStop = 0
+
+	// Short-turns
+	if command(d, tr, Command{Kind: CommandShortTurn, StationID: 4}).Accepted {
+		t.Fatal("A train shouldn't short-turn at a terminal, it turns there anyway.")
+	}
+	if command(d, tr, Command{Kind: CommandShortTurn, StationID: 1}).Accepted {
+		t.Fatal("A train shouldn't short-turn at a station behind it.")
+	}
+	if ack := command(d, tr, Command{Kind: CommandShortTurn, StationID: 3}); !ack.Accepted {
+		t.Fatal("A train should short-turn at a station ahead.")
+	}
+	if tr.ServesLeg(&Leg{LineID: 1, From: line.Stations[0], To: line.Stations[3]}) {
+		t.Fatal("A train turning back shouldn't take passengers past the station.")
+	}
+
+	// Speed caps
+	top := PixelSpeedToKmPerHour(tr.model.TopSpeed)
+	if command(d, tr, Command{Kind: CommandSpeedCap, SpeedKmH: 1}).Accepted {
+		t.Fatal("A speed cap under the minimum should be rejected.")
+	}
+	if ack := command(d, tr, Command{Kind: CommandSpeedCap, SpeedKmH: top / 2}); !ack.Accepted || tr.topSpeed() >= tr.model.TopSpeed {
+		t.Fatal("A speed cap should lower the top speed of the train.")
+	}
+	if ack := command(d, tr, Command{Kind: CommandSpeedCap}); !ack.Accepted || tr.topSpeed() != tr.model.TopSpeed {
+		t.Fatal("A speed cap of 0 should lift the cap.")
+	}
+}
+
+func TestCommandsTooManyWaiting(t *testing.T) {
+	logToTemp(t)
+	line, central := newCommandLine()
+	tr := newCommandTrain(1, line, central)
+	tr.commands = make(chan commandRequest, 1)
+	d := NewDispatcher([]*Train{tr})
+
+	d.Send(Command{TrainID: tr.ID, Kind: CommandHold, Seconds: 30})
+	if ack := <-d.Send(Command{TrainID: tr.ID, Kind: CommandHold, Seconds: 30}); ack.Accepted {
+		t.Fatal("Commands beyond the buffer should be rejected right away.")
+	}
+}
+
+func TestCommandsDepot(t *testing.T) {
+	logToTemp(t)
+	line, central := newCommandLine()
+	tr := newCommandTrain(1, line, central)
+	other := newCommandTrain(2, line, central)
+	d := NewDispatcher([]*Train{tr, other})
+
+	// Standing at B with a passenger going to D
+	b, d4 := line.Stations[1], line.Stations[3]
+	tr.Current, tr.Next = b, nil
+	p := &Passenger{ID: "1", DestinationStation: d4, Itinerary: Itinerary{Legs: []Leg{{LineID: 1, From: line.Stations[0], To: d4, Forward: true}}}}
+	tr.AddPassenger(p)
+	p.CurrentTrain, p.State = tr, PassengerStateRiding
+
+	if ack := command(d, tr, Command{Kind: CommandOutOfService}); !ack.Accepted || tr.InService() {
+		t.Fatal("A train standing at a station should go to the depot.")
+	}
+	if tr.GetPassengerCount() != 0 || len(b.GetPlatformQueue(Platform{LineID: 1, Forward: true})) != 1 || p.CurrentLeg().From != b {
+		t.Fatal("The passengers should continue from the station with the next train.")
+	}
+	if command(d, tr, Command{Kind: CommandHold, Seconds: 30}).Accepted {
+		t.Fatal("A train in the depot should only take the command to enter service.")
+	}
+
+	// Another train standing at C
+	other.Current, other.Next = line.Stations[2], nil
+	if command(d, tr, Command{Kind: CommandEnterService, StationID: 3}).Accepted {
+		t.Fatal("A train shouldn't enter service where another train is standing.")
+	}
+	if ack := command(d, tr, Command{Kind: CommandEnterService}); !ack.Accepted || !tr.InService() || tr.Current != b {
+		t.Fatal("A train should enter service where it left.")
+	}
+	if tr.GetPassengerCount() != 1 {
+		t.Fatal("A train entering service should board the passengers waiting.")
+	}
+}
@@ -0,0 +1,149 @@
+package models
+
+import (
+	"math"
+	"sync"
+
+	"github.com/odin-software/metro/control"
+)
+
+// Commute is one of the two daily trips of a commuter.
+type Commute int
+
+const (
+	CommuteToWork Commute = iota // Morning trip from home to work
+	CommuteToHome                // Evening trip from work to home
+)
+
+// CommuterHistory is what a commuter remembers from previous days, it is persisted.
+type CommuterHistory struct {
+	MorningDeparture int     // Seconds since midnight when they leave home
+	EveningDeparture int     // Seconds since midnight when they leave work
+	AvoidLineID      int64   // Line they stopped riding after a bad trip, 0 rides every line
+	Satisfaction     float64 // Baseline sentiment (0-100) every trip starts with
+	Trips            int
+	LostTrips        int // Trips abandoned before boarding
+	BadDays          int // Days with at least one bad trip
+}
+
+// CommuteResult is how a trip of a commuter went.
+type CommuteResult struct {
+	Commute   Commute
+	Day       int            // Simulated day the trip started
+	Sentiment float64        // Sentiment at the end of the trip
+	Complaint SentimentCause // What cost the most sentiment, empty if nothing did
+	LineID    int64          // Line of the last leg ridden or waited for, 0 if unknown
+	Abandoned bool
+}
+
+// Commuter is a persistent individual that travels home-work-home every simulated day
+// and adapts their habits after bad trips.
+type Commuter struct {
+	ID      string
+	Name    string
+	Persona Persona
+	Home    *Station
+	Work    *Station
+
+	history    CommuterHistory
+	lastBadDay int
+	mutex      sync.Mutex
+}
+
+func NewCommuter(id, name string, persona Persona, home, work *Station, history CommuterHistory) *Commuter {
+	return &Commuter{
+		ID:         id,
+		Name:       name,
+		Persona:    persona,
+		Home:       home,
+		Work:       work,
+		history:    history,
+		lastBadDay: -1,
+	}
+}
+
+// History returns a copy of what the commuter remembers.
+func (c *Commuter) History() CommuterHistory {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.history
+}
+
+// Trip returns the origin and destination of a commute.
+func (c *Commuter) Trip(commute Commute) (from, to *Station) {
+	if commute == CommuteToHome {
+		return c.Work, c.Home
+	}
+	return c.Home, c.Work
+}
+
+// DueTrips returns the commutes that depart after prev and until now, both in seconds
+// since midnight. The window wraps around midnight when now is before prev.
+func (c *Commuter) DueTrips(prev, now int) []Commute {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	due := make([]Commute, 0, 2)
+	if departsBetween(c.history.MorningDeparture, prev, now) {
+		due = append(due, CommuteToWork)
+	}
+	if departsBetween(c.history.EveningDeparture, prev, now) {
+		due = append(due, CommuteToHome)
+	}
+	return due
+}
+
+func departsBetween(departure, prev, now int) bool {
+	if now >= prev {
+		return departure > prev && departure <= now
+	}
+	return departure > prev || departure <= now
+}
+
+// ClearAvoidedLine makes the commuter ride every line again, used when the network
+// can't take them around the line they avoid.
+func (c *Commuter) ClearAvoidedLine() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.history.AvoidLineID = 0
+}
+
+// RecordTrip updates the baseline satisfaction with the result of a trip. After a bad
+// trip the commuter leaves earlier if the problem was waiting or crowding, otherwise
+// they avoid the line they were on. It returns true if the trip was bad.
+func (c *Commuter) RecordTrip(config control.CommuterConfig, result CommuteResult) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	sentiment := result.Sentiment
+	if result.Abandoned {
+		sentiment = 0
+		c.history.LostTrips++
+	}
+	c.history.Trips++
+	memory := math.Max(0, math.Min(1, config.Memory))
+	c.history.Satisfaction = (1-memory)*c.history.Satisfaction + memory*sentiment
+
+	if sentiment >= config.BadTrip {
+		return false
+	}
+	if result.Day != c.lastBadDay {
+		c.lastBadDay = result.Day
+		c.history.BadDays++
+	}
+
+	switch result.Complaint {
+	case CauseWait, CauseDeniedBoarding, CauseCrowding:
+		shift := int(config.DepartureShift.Seconds())
+		if result.Commute == CommuteToHome {
+			c.history.EveningDeparture = (c.history.EveningDeparture - shift + 86400) % 86400
+		} else {
+			c.history.MorningDeparture = (c.history.MorningDeparture - shift + 86400) % 86400
+		}
+	default:
+		if result.LineID != 0 {
+			c.history.AvoidLineID = result.LineID
+		}
+	}
+	return true
+}
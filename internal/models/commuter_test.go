@@ -0,0 +1,54 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/odin-software/metro/control"
+)
+
+func TestCommuterDueTrips(t *testing.T) {
+	c := NewCommuter("C-001", "María", PersonaByKind(PersonaCommuter), nil, nil, CommuterHistory{
+		MorningDeparture: 7 * 3600,
+		EveningDeparture: 17 * 3600,
+	})
+
+	if due := c.DueTrips(7*3600-5, 7*3600); len(due) != 1 || due[0] != CommuteToWork {
+		t.Fatal("The morning trip should be due when the clock passes the departure.")
+	}
+	if len(c.DueTrips(7*3600, 7*3600+5)) != 0 {
+		t.Fatal("A trip should only be due once.")
+	}
+	if due := c.DueTrips(86000, 7*3600); len(due) != 1 || due[0] != CommuteToWork {
+		t.Fatal("Windows should wrap around midnight.")
+	}
+}
+
+func TestCommuterRecordTrip(t *testing.T) {
+	config := control.CommuterConfig{Memory: 0.5, BadTrip: 50, DepartureShift: 15 * time.Minute}
+	c := NewCommuter("C-001", "María", PersonaByKind(PersonaCommuter), nil, nil, CommuterHistory{
+		MorningDeparture: 7 * 3600,
+		EveningDeparture: 17 * 3600,
+		Satisfaction:     100,
+	})
+
+	if c.RecordTrip(config, CommuteResult{Commute: CommuteToWork, Day: 0, Sentiment: 80}) {
+		t.Fatal("A good trip should not be bad.")
+	}
+	if c.History().Satisfaction != 90 {
+		t.Fatal("The satisfaction should carry over from previous trips.")
+	}
+
+	c.RecordTrip(config, CommuteResult{Commute: CommuteToWork, Day: 1, Sentiment: 20, Complaint: CauseWait})
+	c.RecordTrip(config, CommuteResult{Commute: CommuteToHome, Day: 1, Abandoned: true, Complaint: CauseTransfer, LineID: 2})
+	history := c.History()
+	if history.MorningDeparture != 7*3600-900 || history.EveningDeparture != 17*3600 {
+		t.Fatal("Waiting too long should make the commuter leave earlier.")
+	}
+	if history.AvoidLineID != 2 {
+		t.Fatal("Other complaints should make the commuter avoid the line.")
+	}
+	if history.BadDays != 1 || history.LostTrips != 1 || history.Trips != 3 {
+		t.Fatal("Bad trips on the same day should count as one bad day.")
+	}
+}
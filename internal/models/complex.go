@@ -0,0 +1,118 @@
+package models
+
+import (
+	"sync"
+	"time"
+)
+
+// StationComplex is an interchange: stations of different lines that passengers
+// walk between without leaving the system.
+type StationComplex struct {
+	ID           int64
+	Name         string
+	Stations     []*Station
+	TransferWalk time.Duration // Walk between two of its stations, unless set for the pair
+	walks        map[[2]int64]time.Duration
+	mutex        sync.RWMutex
+}
+
+func NewStationComplex(id int64, name string, transferWalk time.Duration) *StationComplex {
+	return &StationComplex{
+		ID:           id,
+		Name:         name,
+		Stations:     make([]*Station, 0),
+		TransferWalk: transferWalk,
+		walks:        make(map[[2]int64]time.Duration),
+	}
+}
+
+// Add makes the station part of the complex.
+func (c *StationComplex) Add(st *Station) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.Stations = append(c.Stations, st)
+	st.Complex = c
+}
+
+// SetWalk sets the walk between two stations of the complex, in both directions.
+func (c *StationComplex) SetWalk(fromID, toID int64, walk time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.walks[walkKey(fromID, toID)] = walk
+}
+
+// Walk returns how long it takes to walk between two stations of the complex.
+func (c *StationComplex) Walk(fromID, toID int64) time.Duration {
+	if fromID == toID {
+		return 0
+	}
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	if walk, ok := c.walks[walkKey(fromID, toID)]; ok {
+		return walk
+	}
+	return c.TransferWalk
+}
+
+// Position returns the centre of the stations of the complex.
+func (c *StationComplex) Position() Vector {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	if len(c.Stations) == 0 {
+		return Vector{}
+	}
+	centre := Vector{}
+	for _, st := range c.Stations {
+		position := st.GetPosition()
+		centre.X += position.X
+		centre.Y += position.Y
+	}
+	centre.X /= float64(len(c.Stations))
+	centre.Y /= float64(len(c.Stations))
+	return centre
+}
+
+// GetWaitingPassengersCount returns the passengers waiting on every station of the complex.
+func (c *StationComplex) GetWaitingPassengersCount() int {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	count := 0
+	for _, st := range c.Stations {
+		count += st.GetWaitingPassengersCount()
+	}
+	return count
+}
+
+func walkKey(fromID, toID int64) [2]int64 {
+	if fromID > toID {
+		fromID, toID = toID, fromID
+	}
+	return [2]int64{fromID, toID}
+}
+
+// Interchanges returns the stations a passenger at st can walk to without leaving
+// the system, st included.
+func (st *Station) Interchanges() []*Station {
+	if st.Complex == nil {
+		return []*Station{st}
+	}
+	st.Complex.mutex.RLock()
+	defer st.Complex.mutex.RUnlock()
+	return append([]*Station(nil), st.Complex.Stations...)
+}
+
+// SamePlace returns true if both are the same station or stations of the same complex.
+func (st *Station) SamePlace(other *Station) bool {
+	if st == nil || other == nil {
+		return false
+	}
+	return st.ID == other.ID || (st.Complex != nil && st.Complex == other.Complex)
+}
+
+// WalkTo returns how long it takes to walk to another station of the same place.
+func (st *Station) WalkTo(other *Station) time.Duration {
+	if st.Complex == nil || st.ID == other.ID {
+		return 0
+	}
+	return st.Complex.Walk(st.ID, other.ID)
+}
@@ -0,0 +1,169 @@
+package models
+
+import (
+	"math"
+	"sort"
+)
+
+// StationStats is a snapshot of the live statistics of a station, shown on its dashboard.
+type StationStats struct {
+	Day          int             // Simulated day the counts are for
+	Boardings    int             // Passengers who boarded a train today
+	Alightings   int             // Passengers who got off a train today
+	Queue        int             // Passengers on the platforms and outside the gates
+	MaxQueue     int             // Longest queue today
+	AverageWait  float64         // Simulation seconds the passengers who boarded today waited
+	Wait90       float64         // 90th percentile of those waits
+	NextArrivals []IncomingTrain // Next train of each platform, by line and direction
+}
+
+// IncomingTrain is the next train leaving from a platform of the station, on its way
+// there or already stopped at it.
+type IncomingTrain struct {
+	TrainID    int64
+	TrainName  string
+	Platform   Platform // Platform the train leaves the station from
+	Distance   float64  // Pixels left along the track, 0 when at the platform
+	Passengers int
+	Capacity   int
+}
+
+// Load returns how full the train is, 1 is at capacity.
+func (it IncomingTrain) Load() float64 {
+	if it.Capacity <= 0 {
+		return 0
+	}
+	return float64(it.Passengers) / float64(it.Capacity)
+}
+
+// stationStats are the counts of a station for the current simulated day.
+type stationStats struct {
+	day        int
+	boardings  int
+	alightings int
+	queue      int // Last queue seen
+	maxQueue   int
+	waits      waitHistogram // Simulation seconds the boarding passengers waited
+}
+
+const (
+	waitBucket  = 10.0 // Simulation seconds of wait each bucket of the histogram spans
+	waitBuckets = 180  // Buckets of the histogram, longer waits share the last one
+)
+
+// waitHistogram counts the waits in buckets, so a busy day takes no more room than a
+// quiet one.
+type waitHistogram struct {
+	count   int
+	total   float64
+	longest float64
+	buckets [waitBuckets]int
+}
+
+func (h *waitHistogram) add(wait float64) {
+	bucket := int(math.Ceil(wait/waitBucket)) - 1
+	h.buckets[max(0, min(bucket, waitBuckets-1))]++
+	h.count++
+	h.total += wait
+	h.longest = max(h.longest, wait)
+}
+
+func (h *waitHistogram) average() float64 {
+	if h.count == 0 {
+		return 0
+	}
+	return h.total / float64(h.count)
+}
+
+// percentile returns the nearest-rank percentile of the waits, p between 0 and 1,
+// rounded up to the end of its bucket.
+func (h *waitHistogram) percentile(p float64) float64 {
+	if h.count == 0 {
+		return 0
+	}
+	rank := max(1, int(math.Ceil(p*float64(h.count))))
+	seen := 0
+	for i, n := range h.buckets[:waitBuckets-1] {
+		seen += n
+		if seen >= rank {
+			return min(float64(i+1)*waitBucket, h.longest)
+		}
+	}
+	return h.longest
+}
+
+// rollStats starts the counts of a new day, the stats lock must be held
+func (st *Station) rollStats(day int) {
+	if day == st.stats.day {
+		return
+	}
+	st.stats = stationStats{day: day, queue: st.stats.queue, maxQueue: st.stats.queue}
+}
+
+// recordBoarding counts a passenger boarding a train after waiting the given simulation seconds.
+func (st *Station) recordBoarding(day int, wait float64) {
+	st.statsMutex.Lock()
+	defer st.statsMutex.Unlock()
+	st.rollStats(day)
+	st.stats.boardings++
+	st.stats.waits.add(wait)
+}
+
+// recordAlighting counts a passenger getting off a train.
+func (st *Station) recordAlighting(day int) {
+	st.statsMutex.Lock()
+	defer st.statsMutex.Unlock()
+	st.rollStats(day)
+	st.stats.alightings++
+}
+
+// recordQueue keeps track of the longest queue of the day.
+func (st *Station) recordQueue(queue int) {
+	st.statsMutex.Lock()
+	defer st.statsMutex.Unlock()
+	st.stats.queue = queue
+	st.stats.maxQueue = max(st.stats.maxQueue, queue)
+}
+
+// Stats returns the statistics of the station on the given simulated day, with the
+// next of the trains that leave from each of its platforms.
+func (st *Station) Stats(day int, trains []*Train) StationStats {
+	queue := st.GetWaitingPassengersCount() + st.GetEntryQueueCount()
+
+	st.statsMutex.Lock()
+	st.stats.queue = queue
+	st.rollStats(day)
+	stats := StationStats{
+		Day:         day,
+		Boardings:   st.stats.boardings,
+		Alightings:  st.stats.alightings,
+		Queue:       queue,
+		MaxQueue:    max(st.stats.maxQueue, queue),
+		AverageWait: st.stats.waits.average(),
+		Wait90:      st.stats.waits.percentile(0.9),
+	}
+	st.statsMutex.Unlock()
+
+	next := make(map[Platform]IncomingTrain)
+	for _, tr := range trains {
+		incoming, ok := tr.incomingTo(st)
+		if !ok {
+			continue
+		}
+		if current, ok := next[incoming.Platform]; !ok || incoming.Distance < current.Distance {
+			next[incoming.Platform] = incoming
+		}
+	}
+	stats.NextArrivals = make([]IncomingTrain, 0, len(next))
+	for _, incoming := range next {
+		stats.NextArrivals = append(stats.NextArrivals, incoming)
+	}
+	sort.Slice(stats.NextArrivals, func(i, j int) bool {
+		a, b := stats.NextArrivals[i].Platform, stats.NextArrivals[j].Platform
+		if a.LineID != b.LineID {
+			return a.LineID < b.LineID
+		}
+		return a.Forward && !b.Forward
+	})
+	return stats
+}
@@ -0,0 +1,232 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// ODPair is an origin-destination pair of stations.
+type ODPair struct {
+	Origin      int64
+	Destination int64
+}
+
+// DemandBand holds the demand between stations during part of the day.
+type DemandBand struct {
+	Start int                // Seconds since midnight, included
+	End   int                // Seconds since midnight, excluded
+	Rates map[ODPair]float64 // Passengers per hour
+}
+
+// DemandMatrix is a set of origin-destination matrices, one per time band.
+type DemandMatrix struct {
+	bands []*DemandBand // Sorted by start, never overlapping
+}
+
+func NewDemandMatrix() *DemandMatrix {
+	return &DemandMatrix{
+		bands: make([]*DemandBand, 0),
+	}
+}
+
+// Add sets the demand between two stations for the band [start, end).
+// Bands are matched by their exact start and end, a band cannot overlap another one.
+func (dm *DemandMatrix) Add(start, end int, origin, destination int64, perHour float64) error {
+	if start < 0 || end > 86400 || start >= end {
+		return fmt.Errorf("invalid time band: %d-%d", start, end)
+	}
+	if origin == destination {
+		return errors.New("the origin and the destination are the same station")
+	}
+	if perHour < 0 {
+		return fmt.Errorf("invalid demand: %f", perHour)
+	}
+
+	var band *DemandBand
+	for _, b := range dm.bands {
+		if b.Start == start && b.End == end {
+			band = b
+			break
+		}
+		if start < b.End && b.Start < end {
+			return fmt.Errorf("time band %d-%d overlaps %d-%d", start, end, b.Start, b.End)
+		}
+	}
+	if band == nil {
+		band = &DemandBand{Start: start, End: end, Rates: make(map[ODPair]float64)}
+		dm.bands = append(dm.bands, band)
+		sort.Slice(dm.bands, func(i, j int) bool { return dm.bands[i].Start < dm.bands[j].Start })
+	}
+	band.Rates[ODPair{origin, destination}] = perHour
+
+	return nil
+}
+
+// Bands returns the time bands sorted by start.
+func (dm *DemandMatrix) Bands() []*DemandBand {
+	return dm.bands
+}
+
+// IsEmpty returns true if there is no demand at all.
+func (dm *DemandMatrix) IsEmpty() bool {
+	return dm == nil || len(dm.bands) == 0
+}
+
+// BandAt returns the band that contains the time of day, or nil when there is none.
+func (dm *DemandMatrix) BandAt(timeOfDay int) *DemandBand {
+	for _, b := range dm.bands {
+		if timeOfDay >= b.Start && timeOfDay < b.End {
+			return b
+		}
+	}
+	return nil
+}
+
+// RateAt returns the passengers per hour between two stations at the time of day.
+func (dm *DemandMatrix) RateAt(timeOfDay int, origin, destination int64) float64 {
+	band := dm.BandAt(timeOfDay)
+	if band == nil {
+		return 0
+	}
+	return band.Rates[ODPair{origin, destination}]
+}
+
+// Arrivals samples how many passengers appear for each OD pair during an interval
+// of the given length (in seconds) starting at the time of day. Arrivals follow a
+// Poisson process per OD pair with the rate of the band.
+func (dm *DemandMatrix) Arrivals(rng *rand.Rand, timeOfDay int, seconds float64) map[ODPair]int {
+	arrivals := make(map[ODPair]int)
+	band := dm.BandAt(timeOfDay)
+	if band == nil || seconds <= 0 {
+		return arrivals
+	}
+
+	// Iterate in a fixed order so a seeded generator gives the same result.
+	pairs := make([]ODPair, 0, len(band.Rates))
+	for pair := range band.Rates {
+		pairs = append(pairs, pair)
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].Origin != pairs[j].Origin {
+			return pairs[i].Origin < pairs[j].Origin
+		}
+		return pairs[i].Destination < pairs[j].Destination
+	})
+
+	for _, pair := range pairs {
+		n := PoissonSample(rng, band.Rates[pair]*seconds/3600)
+		if n > 0 {
+			arrivals[pair] = n
+		}
+	}
+	return arrivals
+}
+
+// DemandProfile holds a demand matrix per day type. Day types without demand of their
+// own use the demand of their fallback, scaled down by their share of the demand.
+type DemandProfile struct {
+	matrices map[DayType]*DemandMatrix
+	shares   map[DayType]float64 // Demand of each day type as a share of the weekday one
+}
+
+// NewDemandProfile creates an empty profile, the shares are keyed by day type and
+// are 1 when not given.
+func NewDemandProfile(shares map[string]float64) *DemandProfile {
+	dp := &DemandProfile{
+		matrices: make(map[DayType]*DemandMatrix),
+		shares:   make(map[DayType]float64, len(shares)),
+	}
+	for dayType, share := range shares {
+		dp.shares[DayType(dayType)] = share
+	}
+	return dp
+}
+
+// Add sets the demand between two stations for the band [start, end) of a day type.
+func (dp *DemandProfile) Add(dayType DayType, start, end int, origin, destination int64, perHour float64) error {
+	matrix, ok := dp.matrices[dayType]
+	if !ok {
+		matrix = NewDemandMatrix()
+		dp.matrices[dayType] = matrix
+	}
+	return matrix.Add(start, end, origin, destination, perHour)
+}
+
+// IsEmpty returns true if there is no demand on any day type.
+func (dp *DemandProfile) IsEmpty() bool {
+	if dp == nil {
+		return true
+	}
+	for _, matrix := range dp.matrices {
+		if !matrix.IsEmpty() {
+			return false
+		}
+	}
+	return true
+}
+
+// Bands returns the time bands of every day type.
+func (dp *DemandProfile) Bands() []*DemandBand {
+	bands := make([]*DemandBand, 0)
+	for _, dayType := range DayTypes {
+		if matrix, ok := dp.matrices[dayType]; ok {
+			bands = append(bands, matrix.Bands()...)
+		}
+	}
+	return bands
+}
+
+// Matrix returns the demand a day type runs and how much of it: its own demand in
+// full, or the demand of its closest fallback scaled by the ratio of their shares.
+// It returns nil when neither the day type nor its fallbacks have demand.
+func (dp *DemandProfile) Matrix(dayType DayType) (*DemandMatrix, float64) {
+	for source, ok := dayType, true; ok; source, ok = source.Fallback() {
+		if matrix := dp.matrices[source]; !matrix.IsEmpty() {
+			return matrix, dp.share(dayType) / dp.share(source)
+		}
+	}
+	return nil, 0
+}
+
+func (dp *DemandProfile) share(dayType DayType) float64 {
+	if share, ok := dp.shares[dayType]; ok && share > 0 && dayType != DayTypeWeekday {
+		return share
+	}
+	return 1
+}
+
+// Arrivals samples the passengers that appear during an interval of a day of the
+// given type, see DemandMatrix.Arrivals. Scaling the rates is the same as scaling
+// the length of the interval.
+func (dp *DemandProfile) Arrivals(rng *rand.Rand, dayType DayType, timeOfDay int, seconds float64) map[ODPair]int {
+	matrix, scale := dp.Matrix(dayType)
+	if matrix == nil {
+		return make(map[ODPair]int)
+	}
+	return matrix.Arrivals(rng, timeOfDay, seconds*scale)
+}
+
+// PoissonSample draws a value from a Poisson distribution with the given mean.
+// Large means use a normal approximation.
+func PoissonSample(rng *rand.Rand, mean float64) int {
+	if mean <= 0 {
+		return 0
+	}
+	if mean > 30 {
+		n := math.Round(mean + math.Sqrt(mean)*rng.NormFloat64())
+		return int(math.Max(0, n))
+	}
+
+	// Knuth's algorithm.
+	limit := math.Exp(-mean)
+	k := 0
+	p := rng.Float64()
+	for p > limit {
+		k++
+		p *= rng.Float64()
+	}
+	return k
+}
@@ -0,0 +1,102 @@
+package models
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestDemandMatrixBands(t *testing.T) {
+	dm := NewDemandMatrix()
+	dm.Add(7*3600, 9*3600, 1, 2, 120)
+	dm.Add(0, 7*3600, 1, 2, 10)
+
+	if len(dm.Bands()) != 2 || dm.Bands()[0].Start != 0 {
+		t.Fatal("The bands should be sorted by start.")
+	}
+	if dm.RateAt(8*3600, 1, 2) != 120 {
+		t.Fatal("The rate of the morning band is incorrect.")
+	}
+	if dm.RateAt(8*3600, 2, 1) != 0 {
+		t.Fatal("The demand should be directional.")
+	}
+	if dm.RateAt(10*3600, 1, 2) != 0 {
+		t.Fatal("There should be no demand outside of the bands.")
+	}
+}
+
+func TestDemandMatrixRejectsInvalidBands(t *testing.T) {
+	dm := NewDemandMatrix()
+	dm.Add(7*3600, 9*3600, 1, 2, 120)
+
+	if err := dm.Add(8*3600, 10*3600, 1, 3, 10); err == nil {
+		t.Fatal("Overlapping bands should be rejected.")
+	}
+	if err := dm.Add(9*3600, 8*3600, 1, 3, 10); err == nil {
+		t.Fatal("Bands that end before they start should be rejected.")
+	}
+	if err := dm.Add(9*3600, 10*3600, 1, 1, 10); err == nil {
+		t.Fatal("Demand to the same station should be rejected.")
+	}
+}
+
+func TestDemandArrivalsFollowRate(t *testing.T) {
+	dm := NewDemandMatrix()
+	dm.Add(0, 86400, 1, 2, 360)
+	rng := rand.New(rand.NewSource(1))
+
+	// 100 hours of 10 second intervals at 360 passengers/hour.
+	total := 0
+	for i := 0; i < 36000; i++ {
+		total += dm.Arrivals(rng, 3600, 10)[ODPair{1, 2}]
+	}
+	if math.Abs(float64(total)-36000) > 1000 {
+		t.Fatal("The arrivals do not follow the rate of the band.")
+	}
+}
+
+func TestPoissonSampleLargeMean(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	total := 0
+	for i := 0; i < 1000; i++ {
+		total += PoissonSample(rng, 100)
+	}
+	if math.Abs(float64(total)/1000-100) > 2 {
+		t.Fatal("The mean of the samples is incorrect.")
+	}
+	if PoissonSample(rng, 0) != 0 {
+		t.Fatal("A zero mean should give no arrivals.")
+	}
+}
+
+func TestDemandProfileDayTypes(t *testing.T) {
+	dp := NewDemandProfile(map[string]float64{"saturday": 0.5, "sunday": 0.25})
+	dp.Add(DayTypeWeekday, 0, 86400, 1, 2, 360)
+	dp.Add(DayTypeSunday, 0, 86400, 1, 2, 60)
+
+	if matrix, scale := dp.Matrix(DayTypeWeekday); matrix == nil || scale != 1 {
+		t.Fatal("Weekdays should run their own demand in full.")
+	}
+	if matrix, scale := dp.Matrix(DayTypeSaturday); matrix == nil || matrix.RateAt(0, 1, 2) != 360 || scale != 0.5 {
+		t.Fatal("Saturdays without demand should run a share of the weekday demand.")
+	}
+	if matrix, scale := dp.Matrix(DayTypeSunday); matrix == nil || matrix.RateAt(0, 1, 2) != 60 || scale != 1 {
+		t.Fatal("Sundays should run their own demand in full.")
+	}
+	if matrix, scale := dp.Matrix(DayTypeHoliday); matrix == nil || matrix.RateAt(0, 1, 2) != 60 || scale != 4 {
+		t.Fatal("Holidays should run the Sunday demand, scaled by their share of it.")
+	}
+
+	// 100 hours of 10 second Saturday intervals at half of 360 passengers/hour.
+	rng := rand.New(rand.NewSource(1))
+	total := 0
+	for i := 0; i < 36000; i++ {
+		total += dp.Arrivals(rng, DayTypeSaturday, 3600, 10)[ODPair{1, 2}]
+	}
+	if math.Abs(float64(total)-18000) > 700 {
+		t.Fatal("The arrivals should follow the share of the day type.")
+	}
+	if !NewDemandProfile(nil).IsEmpty() || dp.IsEmpty() {
+		t.Fatal("A profile is empty when no day type has demand.")
+	}
+}
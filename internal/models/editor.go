@@ -0,0 +1,379 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// NetworkStore persists the edits made through a NetworkEditor.
+type NetworkStore interface {
+	AddStation(name string, position Vector) (int64, error)
+	MoveStation(id int64, name string, position Vector) error
+	AddEdge(fromID, toID int64, points []Vector) error
+	DeleteEdge(fromID, toID int64) error
+	ReorderLine(lineID int64, stationIDs []int64) error
+}
+
+// EditKind is what an edit of the network does.
+type EditKind string
+
+const (
+	EditAddStation  EditKind = "add_station"  // Add a station named Name at Position
+	EditMoveStation EditKind = "move_station" // Move StationID to Position
+	EditAddEdge     EditKind = "add_edge"     // Connect StationID and ToStationID through Points
+	EditDeleteEdge  EditKind = "delete_edge"  // Disconnect StationID and ToStationID
+	EditReorderLine EditKind = "reorder_line" // Run LineID through StationIDs, in order
+)
+
+// NetworkEdit is an edit of the network, from the operator or Tenjin.
+type NetworkEdit struct {
+	Kind        EditKind
+	Name        string
+	Position    Vector
+	StationID   int64
+	ToStationID int64
+	Points      []Vector // Positions between the stations of an edge
+	LineID      int64
+	StationIDs  []int64
+}
+
+// EditResult is the answer to an edit.
+type EditResult struct {
+	Edit    NetworkEdit
+	Station *Station // Station added, nil for the other edits
+	Err     error    // Why the edit was rejected, nil when it was applied
+}
+
+// editRequest is an edit waiting for the next tick, with where the answer goes.
+type editRequest struct {
+	edit  NetworkEdit
+	reply chan EditResult
+}
+
+// editBuffer is how many edits can wait for the next tick, the rest are rejected.
+const editBuffer = 16
+
+// NetworkEditor applies edits to the city while the simulation runs.
+// Edits are submitted from anywhere and applied between two ticks, by the scheduler,
+// so the trains and stations never change under the phase ticking them. Every edit
+// is validated, persisted and then applied in memory as a whole, so readers never
+// see a half-applied change.
+type NetworkEditor struct {
+	mu       sync.RWMutex
+	central  *Network[*Station]
+	stations []*Station
+	lines    []Line
+	trains   []*Train
+	store    NetworkStore
+	edits    chan editRequest // Edits waiting for the next tick
+	version  uint64           // Incremented after every edit
+}
+
+func NewNetworkEditor(
+	central *Network[*Station],
+	stations []*Station,
+	lines []Line,
+	trains []*Train,
+	store NetworkStore,
+) *NetworkEditor {
+	ls := make([]Line, len(lines))
+	for i, line := range lines {
+		ls[i] = copyLine(line)
+	}
+	return &NetworkEditor{
+		central:  central,
+		stations: append([]*Station(nil), stations...),
+		lines:    ls,
+		trains:   trains,
+		store:    store,
+		edits:    make(chan editRequest, editBuffer),
+	}
+}
+
+// Submit queues an edit of the network. The answer comes on the returned channel
+// once the edit is applied, between two ticks, or right away when too many edits
+// are waiting.
+func (ed *NetworkEditor) Submit(edit NetworkEdit) <-chan EditResult {
+	reply := make(chan EditResult, 1)
+	select {
+	case ed.edits <- editRequest{edit: edit, reply: reply}:
+	default:
+		reply <- EditResult{Edit: edit, Err: errors.New("too many edits waiting")}
+	}
+	return reply
+}
+
+// ApplyEdits applies the edits submitted since the last call, in order, and answers
+// them. It must be called between two ticks, it returns true when the network changed.
+func (ed *NetworkEditor) ApplyEdits() bool {
+	changed := false
+	for {
+		select {
+		case request := <-ed.edits:
+			result := ed.apply(request.edit)
+			changed = changed || result.Err == nil
+			request.reply <- result
+		default:
+			return changed
+		}
+	}
+}
+
+// apply applies a single edit.
+func (ed *NetworkEditor) apply(edit NetworkEdit) EditResult {
+	result := EditResult{Edit: edit}
+	switch edit.Kind {
+	case EditAddStation:
+		result.Station, result.Err = ed.addStation(edit.Name, edit.Position)
+	case EditMoveStation:
+		result.Err = ed.moveStation(edit.StationID, edit.Position)
+	case EditAddEdge:
+		result.Err = ed.addEdge(edit.StationID, edit.ToStationID, edit.Points)
+	case EditDeleteEdge:
+		result.Err = ed.deleteEdge(edit.StationID, edit.ToStationID)
+	case EditReorderLine:
+		result.Err = ed.reorderLine(edit.LineID, edit.StationIDs)
+	default:
+		result.Err = fmt.Errorf("unknown edit %q", edit.Kind)
+	}
+	return result
+}
+
+// Version returns a number that changes every time the network is edited.
+func (ed *NetworkEditor) Version() uint64 {
+	ed.mu.RLock()
+	defer ed.mu.RUnlock()
+	return ed.version
+}
+
+// Network returns the network being edited.
+func (ed *NetworkEditor) Network() *Network[*Station] {
+	return ed.central
+}
+
+// Stations returns a copy of the list of stations.
+func (ed *NetworkEditor) Stations() []*Station {
+	ed.mu.RLock()
+	defer ed.mu.RUnlock()
+	return append([]*Station(nil), ed.stations...)
+}
+
+// Lines returns a copy of the lines, their station lists can be kept as is.
+func (ed *NetworkEditor) Lines() []Line {
+	ed.mu.RLock()
+	defer ed.mu.RUnlock()
+	ls := make([]Line, len(ed.lines))
+	for i, line := range ed.lines {
+		ls[i] = copyLine(line)
+	}
+	return ls
+}
+
+// SpatialIndex builds an index of the current stations and tracks.
+func (ed *NetworkEditor) SpatialIndex(cellSize float64) (*SpatialIndex, error) {
+	ed.mu.RLock()
+	defer ed.mu.RUnlock()
+	return NewSpatialIndex(ed.central, ed.stations, cellSize)
+}
+
+// addStation creates a new station, it is not connected to anything until edges are added.
+func (ed *NetworkEditor) addStation(name string, position Vector) (*Station, error) {
+	ed.mu.Lock()
+	defer ed.mu.Unlock()
+
+	if name == "" {
+		return nil, errors.New("the station needs a name")
+	}
+	id, err := ed.store.AddStation(name, position)
+	if err != nil {
+		return nil, err
+	}
+
+	st := NewStation(id, name, position)
+	if err := ed.central.InsertVertex(st); err != nil {
+		return nil, err
+	}
+	ed.stations = append(ed.stations, st)
+	ed.version++
+
+	return st, nil
+}
+
+// moveStation changes the position of a station. Trains already travelling towards
+// it finish their current route and pick up the new position on their next departure.
+func (ed *NetworkEditor) moveStation(id int64, position Vector) error {
+	ed.mu.Lock()
+	defer ed.mu.Unlock()
+
+	st, err := ed.station(id)
+	if err != nil {
+		return err
+	}
+	if err := ed.store.MoveStation(id, st.Name, position); err != nil {
+		return err
+	}
+
+	st.SetPosition(position)
+	ed.version++
+
+	return nil
+}
+
+// addEdge connects two stations in both directions, the points are positions in between.
+func (ed *NetworkEditor) addEdge(fromID, toID int64, points []Vector) error {
+	ed.mu.Lock()
+	defer ed.mu.Unlock()
+
+	if fromID == toID {
+		return errors.New("a station cannot be connected to itself")
+	}
+	from, err := ed.station(fromID)
+	if err != nil {
+		return err
+	}
+	to, err := ed.station(toID)
+	if err != nil {
+		return err
+	}
+	fromKey, toKey := ed.central.KeyOf(from), ed.central.KeyOf(to)
+	if _, err := ed.central.AreConnectedByKey(fromKey, toKey); err == nil {
+		return fmt.Errorf("stations %d and %d are already connected", fromID, toID)
+	}
+
+	if err := ed.store.AddEdge(fromID, toID, points); err != nil {
+		return err
+	}
+	if err := ed.central.InsertEdgeByKey(fromKey, toKey, append([]Vector(nil), points...)); err != nil {
+		return err
+	}
+	ed.version++
+
+	return nil
+}
+
+// deleteEdge removes both directions of the edge between two stations.
+// Edges used by a line cannot be deleted, the line has to be reordered first.
+func (ed *NetworkEditor) deleteEdge(fromID, toID int64) error {
+	ed.mu.Lock()
+	defer ed.mu.Unlock()
+
+	from, err := ed.station(fromID)
+	if err != nil {
+		return err
+	}
+	to, err := ed.station(toID)
+	if err != nil {
+		return err
+	}
+	fromKey, toKey := ed.central.KeyOf(from), ed.central.KeyOf(to)
+	if _, err := ed.central.AreConnectedByKey(fromKey, toKey); err != nil {
+		return err
+	}
+	for _, line := range ed.lines {
+		for i := 0; i < len(line.Stations)-1; i++ {
+			a, b := line.Stations[i].ID, line.Stations[i+1].ID
+			if (a == fromID && b == toID) || (a == toID && b == fromID) {
+				return fmt.Errorf("the edge between %d and %d is used by line %s", fromID, toID, line.Name)
+			}
+		}
+	}
+
+	if err := ed.store.DeleteEdge(fromID, toID); err != nil {
+		return err
+	}
+	if err := ed.central.DeleteEdgeByKey(fromKey, toKey); err != nil {
+		return err
+	}
+	ed.version++
+
+	return nil
+}
+
+// reorderLine replaces the ordered list of stations of a line.
+// Consecutive stations must be connected in both directions, and the stations the
+// trains of the line are at or heading to must stay on it. Trains on the line
+// switch to the new order when they leave their next station.
+func (ed *NetworkEditor) reorderLine(lineID int64, stationIDs []int64) error {
+	ed.mu.Lock()
+	defer ed.mu.Unlock()
+
+	idx := -1
+	for i := range ed.lines {
+		if ed.lines[i].ID == lineID {
+			idx = i
+			break
+		}
+	}
+	if idx == -1 {
+		return fmt.Errorf("line %d does not exist", lineID)
+	}
+	if len(stationIDs) < 2 {
+		return errors.New("a line needs at least 2 stations")
+	}
+
+	stations := make([]*Station, 0, len(stationIDs))
+	seen := make(map[int64]bool, len(stationIDs))
+	for _, id := range stationIDs {
+		if seen[id] {
+			return fmt.Errorf("station %d appears more than once", id)
+		}
+		seen[id] = true
+		st, err := ed.station(id)
+		if err != nil {
+			return err
+		}
+		stations = append(stations, st)
+	}
+	for i := 0; i < len(stations)-1; i++ {
+		a, b := ed.central.KeyOf(stations[i]), ed.central.KeyOf(stations[i+1])
+		if _, err := ed.central.AreConnectedByKey(a, b); err != nil {
+			return fmt.Errorf("stations %d and %d: %w", stations[i].ID, stations[i+1].ID, err)
+		}
+		if _, err := ed.central.AreConnectedByKey(b, a); err != nil {
+			return fmt.Errorf("stations %d and %d: %w", stations[i+1].ID, stations[i].ID, err)
+		}
+	}
+
+	for _, tr := range ed.trains {
+		if tr.GetLine().ID != lineID {
+			continue
+		}
+		for _, st := range []*Station{tr.Current, tr.Next} {
+			if st != nil && !seen[st.ID] {
+				return fmt.Errorf("station %d is served by train %s", st.ID, tr.Name)
+			}
+		}
+	}
+
+	if err := ed.store.ReorderLine(lineID, stationIDs); err != nil {
+		return err
+	}
+
+	// The new slice is never modified, so copies handed out before stay valid.
+	ed.lines[idx] = Line{ID: lineID, Name: ed.lines[idx].Name, Stations: stations}
+	for _, tr := range ed.trains {
+		tr.ReplaceLine(copyLine(ed.lines[idx]))
+	}
+	ed.version++
+
+	return nil
+}
+
+// station must be called with the lock held.
+func (ed *NetworkEditor) station(id int64) (*Station, error) {
+	for _, st := range ed.stations {
+		if st.ID == id {
+			return st, nil
+		}
+	}
+	return nil, fmt.Errorf("station %d does not exist", id)
+}
+
+func copyLine(line Line) Line {
+	return Line{
+		ID:       line.ID,
+		Name:     line.Name,
+		Stations: append([]*Station(nil), line.Stations...),
+	}
+}
@@ -0,0 +1,231 @@
+package models
+
+import (
+	"errors"
+	"testing"
+)
+
+// fakeNetworkStore records the edits persisted, or fails them all.
+type fakeNetworkStore struct {
+	fail   bool
+	nextID int64
+	edits  []string
+}
+
+func (s *fakeNetworkStore) persist(edit string) error {
+	if s.fail {
+		return errors.New("store unavailable")
+	}
+	s.edits = append(s.edits, edit)
+	return nil
+}
+
+func (s *fakeNetworkStore) AddStation(name string, position Vector) (int64, error) {
+	s.nextID++
+	return s.nextID, s.persist("add station")
+}
+
+func (s *fakeNetworkStore) MoveStation(id int64, name string, position Vector) error {
+	return s.persist("move station")
+}
+
+func (s *fakeNetworkStore) AddEdge(fromID, toID int64, points []Vector) error {
+	return s.persist("add edge")
+}
+
+func (s *fakeNetworkStore) DeleteEdge(fromID, toID int64) error {
+	return s.persist("delete edge")
+}
+
+func (s *fakeNetworkStore) ReorderLine(lineID int64, stationIDs []int64) error {
+	return s.persist("reorder line")
+}
+
+// newTestEditor returns an editor of the line A-B-C-D, with a train leaving A for B.
+func newTestEditor() (*NetworkEditor, *fakeNetworkStore, *Train) {
+	line, central := newCommandLine()
+	tr := newCommandTrain(1, line, central)
+	store := &fakeNetworkStore{nextID: 10}
+	return NewNetworkEditor(central, line.Stations, []Line{line}, []*Train{tr}, store), store, tr
+}
+
+func TestEditorAddStation(t *testing.T) {
+	ed, store, _ := newTestEditor()
+
+	if _, err := ed.addStation("", NewVector(0, 100)); err == nil || len(store.edits) != 0 {
+		t.Fatal("A station without a name should be rejected before it is persisted.")
+	}
+	store.fail = true
+	if _, err := ed.addStation("E", NewVector(0, 100)); err == nil || len(ed.Stations()) != 4 || ed.Version() != 0 {
+		t.Fatal("A station that couldn't be persisted should not be added.")
+	}
+	store.fail = false
+
+	st, err := ed.addStation("F", NewVector(0, 100))
+	if err != nil || st.ID != 12 || len(ed.Stations()) != 5 || ed.Version() != 1 {
+		t.Fatal("The station should have been added with the ID it was persisted with.")
+	}
+	if _, err := ed.Network().GetVertexFromKey("F"); err != nil {
+		t.Fatal("The station should be in the network.")
+	}
+}
+
+func TestEditorMoveStation(t *testing.T) {
+	ed, store, _ := newTestEditor()
+
+	if err := ed.moveStation(9, NewVector(0, 100)); err == nil {
+		t.Fatal("A station that doesn't exist should not be moved.")
+	}
+	store.fail = true
+	if err := ed.moveStation(2, NewVector(100, 50)); err == nil || ed.Stations()[1].GetPosition().Y != 0 {
+		t.Fatal("A move that couldn't be persisted should not be applied.")
+	}
+	store.fail = false
+
+	if err := ed.moveStation(2, NewVector(100, 50)); err != nil || ed.Stations()[1].GetPosition().Y != 50 || ed.Version() != 1 {
+		t.Fatal("The station should have been moved.")
+	}
+}
+
+func TestEditorAddEdge(t *testing.T) {
+	ed, store, _ := newTestEditor()
+
+	if err := ed.addEdge(1, 1, nil); err == nil {
+		t.Fatal("A station should not be connected to itself.")
+	}
+	if err := ed.addEdge(1, 9, nil); err == nil {
+		t.Fatal("A station that doesn't exist should not be connected.")
+	}
+	if err := ed.addEdge(1, 2, nil); err == nil {
+		t.Fatal("Stations already connected should not be connected again.")
+	}
+	if len(store.edits) != 0 {
+		t.Fatal("Rejected edges should not be persisted.")
+	}
+
+	points := []Vector{NewVector(150, 100)}
+	if err := ed.addEdge(1, 4, points); err != nil || ed.Version() != 1 {
+		t.Fatal("The edge should have been added.")
+	}
+	points[0] = NewVector(0, 0)
+	back, err := ed.Network().AreConnectedByKey("D", "A")
+	if err != nil || len(back) != 1 || back[0].Y != 100 {
+		t.Fatal("The edge should connect both directions with its own copy of the points.")
+	}
+}
+
+func TestEditorDeleteEdge(t *testing.T) {
+	ed, store, _ := newTestEditor()
+	ed.addEdge(1, 3, nil)
+
+	if err := ed.deleteEdge(2, 4); err == nil {
+		t.Fatal("Stations that aren't connected have no edge to delete.")
+	}
+	if err := ed.deleteEdge(2, 3); err == nil {
+		t.Fatal("An edge used by a line should not be deleted.")
+	}
+	store.fail = true
+	if err := ed.deleteEdge(1, 3); err == nil {
+		t.Fatal("An edge that couldn't be deleted from the store should stay.")
+	}
+	store.fail = false
+
+	if err := ed.deleteEdge(3, 1); err != nil || ed.Version() != 2 {
+		t.Fatal("The edge should have been deleted.")
+	}
+	if _, err := ed.Network().AreConnectedByKey("A", "C"); err == nil {
+		t.Fatal("Both directions of the edge should have been deleted.")
+	}
+}
+
+func TestEditorReorderLine(t *testing.T) {
+	ed, store, tr := newTestEditor()
+
+	if err := ed.reorderLine(9, []int64{1, 2}); err == nil {
+		t.Fatal("A line that doesn't exist should not be reordered.")
+	}
+	if err := ed.reorderLine(1, []int64{1}); err == nil {
+		t.Fatal("A line needs at least two stations.")
+	}
+	if err := ed.reorderLine(1, []int64{1, 2, 1}); err == nil {
+		t.Fatal("A station should not appear twice on a line.")
+	}
+	if err := ed.reorderLine(1, []int64{1, 3}); err == nil {
+		t.Fatal("Stations that aren't connected should not follow each other.")
+	}
+	if len(store.edits) != 0 || tr.pendingLine != nil {
+		t.Fatal("Rejected orders should not be persisted or given to the trains.")
+	}
+
+	if err := ed.reorderLine(1, []int64{1, 2, 3}); err != nil || len(ed.Lines()[0].Stations) != 3 || ed.Version() != 1 {
+		t.Fatal("The line should have been shortened.")
+	}
+	if tr.pendingLine == nil || len(tr.pendingLine.Stations) != 3 || len(tr.GetLine().Stations) != 4 {
+		t.Fatal("The train should switch to the new order when it leaves its next station.")
+	}
+}
+
+func TestEditorReorderLineKeepsServedStations(t *testing.T) {
+	ed, store, tr := newTestEditor()
+	ed.addEdge(1, 3, nil)
+
+	// The train left A and is heading to B
+	if err := ed.reorderLine(1, []int64{1, 3, 4}); err == nil {
+		t.Fatal("The station a train is heading to should stay on the line.")
+	}
+	if err := ed.reorderLine(1, []int64{2, 3, 4}); err == nil {
+		t.Fatal("The station a train is at should stay on the line.")
+	}
+	if len(store.edits) != 1 || tr.pendingLine != nil {
+		t.Fatal("Rejected orders should not be persisted or given to the trains.")
+	}
+
+	// At B, the train can leave for C once A is gone
+	tr.Current, tr.Next = tr.destinations.Stations[1], nil
+	if err := ed.reorderLine(1, []int64{2, 3, 4}); err != nil {
+		t.Fatal("A station no train is at or heading to can be taken off the line.")
+	}
+	tr.applyPendingLine()
+	if next := tr.getNextFromDestinations(); next.ID != 3 {
+		t.Fatal("The train should carry on along the new order.")
+	}
+}
+
+func TestEditorSubmit(t *testing.T) {
+	ed, store, _ := newTestEditor()
+
+	added := ed.Submit(NetworkEdit{Kind: EditAddStation, Name: "E", Position: NewVector(300, 100)})
+	connected := ed.Submit(NetworkEdit{Kind: EditAddEdge, StationID: 4, ToStationID: 11})
+	rejected := ed.Submit(NetworkEdit{Kind: EditDeleteEdge, StationID: 1, ToStationID: 2})
+	select {
+	case <-added:
+		t.Fatal("An edit should wait for the next tick.")
+	default:
+	}
+	if len(ed.Stations()) != 4 || len(store.edits) != 0 {
+		t.Fatal("Nothing should change before the edits are applied.")
+	}
+
+	if !ed.ApplyEdits() {
+		t.Fatal("Applying the edits should change the network.")
+	}
+	if result := <-added; result.Err != nil || result.Station == nil || result.Station.ID != 11 {
+		t.Fatal("The station added should be in the answer.")
+	}
+	if result := <-connected; result.Err != nil {
+		t.Fatal("An edit should see the edits applied before it.")
+	}
+	if result := <-rejected; result.Err == nil {
+		t.Fatal("An edge used by a line should not be deleted.")
+	}
+	if ed.ApplyEdits() {
+		t.Fatal("Nothing should change without edits.")
+	}
+
+	for i := 0; i < editBuffer; i++ {
+		ed.Submit(NetworkEdit{Kind: EditMoveStation, StationID: 1})
+	}
+	if result := <-ed.Submit(NetworkEdit{Kind: EditMoveStation, StationID: 1}); result.Err == nil {
+		t.Fatal("Edits beyond the buffer should be rejected right away.")
+	}
+}
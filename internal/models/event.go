@@ -0,0 +1,120 @@
+package models
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/odin-software/metro/control"
+)
+
+// EventPhase is where a special event is at a moment of the day
+type EventPhase string
+
+const (
+	EventPhaseNone     EventPhase = ""         // Not today, or too early or late to matter
+	EventPhaseArriving EventPhase = "arriving" // Attendees are travelling to the event
+	EventPhaseUnderway EventPhase = "underway" // Between the start and the end
+	EventPhaseLeaving  EventPhase = "leaving"  // The crowd is going home
+)
+
+// SpecialEvent is something happening next to a station, like a game at a stadium.
+// Attendees arrive by metro before the start and leave all at once after the end,
+// on top of the usual demand. Surges don't cross midnight.
+type SpecialEvent struct {
+	ID         int64
+	Name       string
+	Station    *Station
+	Day        int // Simulated day of the event, negative repeats it every day
+	Start      int // Seconds since midnight
+	End        int // Seconds since midnight
+	Attendance int
+}
+
+func NewSpecialEvent(id int64, name string, station *Station, day, start, end, attendance int) (*SpecialEvent, error) {
+	if station == nil {
+		return nil, fmt.Errorf("event %s has no station", name)
+	}
+	if start < 0 || end > 86400 || start >= end {
+		return nil, fmt.Errorf("invalid event time: %d-%d", start, end)
+	}
+	if attendance < 0 {
+		return nil, fmt.Errorf("invalid attendance: %d", attendance)
+	}
+	return &SpecialEvent{
+		ID:         id,
+		Name:       name,
+		Station:    station,
+		Day:        day,
+		Start:      start,
+		End:        end,
+		Attendance: attendance,
+	}, nil
+}
+
+// OnDay returns true if the event takes place on the simulated day.
+func (ev *SpecialEvent) OnDay(day int) bool {
+	return ev.Day < 0 || ev.Day == day
+}
+
+// Riders returns how many attendees travel by metro, each way.
+func (ev *SpecialEvent) Riders(config control.EventConfig) float64 {
+	return float64(ev.Attendance) * config.MetroShare
+}
+
+// Phase returns where the event is at the time of the day.
+func (ev *SpecialEvent) Phase(config control.EventConfig, day, timeOfDay int) EventPhase {
+	if !ev.OnDay(day) {
+		return EventPhaseNone
+	}
+	switch {
+	case timeOfDay >= ev.Start-int(config.ArrivalLead.Seconds()) && timeOfDay < ev.Start:
+		return EventPhaseArriving
+	case timeOfDay >= ev.Start && timeOfDay < ev.End:
+		return EventPhaseUnderway
+	case timeOfDay >= ev.End && timeOfDay < ev.End+int(config.Dispersal.Seconds()):
+		return EventPhaseLeaving
+	}
+	return EventPhaseNone
+}
+
+// ArrivalRate returns the passengers per hour travelling to the event at the time of
+// the day. It grows steadily during the lead so most attendees arrive close to the start.
+func (ev *SpecialEvent) ArrivalRate(config control.EventConfig, timeOfDay int) float64 {
+	lead := config.ArrivalLead.Seconds()
+	if lead <= 0 {
+		return 0
+	}
+	before := float64(ev.Start - timeOfDay)
+	if before <= 0 || before > lead {
+		return 0
+	}
+	// Triangular profile with the riders as its area, peaking at the start.
+	peak := 2 * ev.Riders(config) / lead * 3600
+	return peak * (1 - before/lead)
+}
+
+// DepartureRate returns the passengers per hour leaving the event at the time of the
+// day. The crowd rushes out at the end and thins out during the dispersal.
+func (ev *SpecialEvent) DepartureRate(config control.EventConfig, timeOfDay int) float64 {
+	dispersal := config.Dispersal.Seconds()
+	if dispersal <= 0 {
+		return 0
+	}
+	after := float64(timeOfDay - ev.End)
+	if after < 0 || after >= dispersal {
+		return 0
+	}
+	peak := 2 * ev.Riders(config) / dispersal * 3600
+	return peak * (1 - after/dispersal)
+}
+
+// Surge samples how many passengers travel to and from the event during an interval
+// of the given length (in seconds) starting at the time of the day.
+func (ev *SpecialEvent) Surge(rng *rand.Rand, config control.EventConfig, day, timeOfDay int, seconds float64) (arriving, leaving int) {
+	if !ev.OnDay(day) || seconds <= 0 {
+		return 0, 0
+	}
+	arriving = PoissonSample(rng, ev.ArrivalRate(config, timeOfDay)*seconds/3600)
+	leaving = PoissonSample(rng, ev.DepartureRate(config, timeOfDay)*seconds/3600)
+	return arriving, leaving
+}
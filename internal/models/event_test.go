@@ -0,0 +1,57 @@
+package models
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/odin-software/metro/control"
+)
+
+func TestSpecialEventSurges(t *testing.T) {
+	stadium := &Station{ID: 1, Name: "Stadium"}
+	config := control.EventConfig{MetroShare: 0.5, ArrivalLead: time.Hour, Dispersal: 30 * time.Minute}
+	game, err := NewSpecialEvent(1, "Game", stadium, 2, 19*3600, 22*3600, 10000)
+	if err != nil {
+		t.Fatal("The event should be valid.")
+	}
+	if _, err := NewSpecialEvent(2, "Backwards", stadium, 0, 22*3600, 19*3600, 100); err == nil {
+		t.Fatal("An event should not end before it starts.")
+	}
+
+	if game.ArrivalRate(config, 17*3600) != 0 || game.DepartureRate(config, 21*3600) != 0 {
+		t.Fatal("There should be no surge outside the lead and the dispersal.")
+	}
+	if game.ArrivalRate(config, 18*3600+60) >= game.ArrivalRate(config, 19*3600-60) {
+		t.Fatal("Arrivals should build up towards the start.")
+	}
+	if game.DepartureRate(config, 22*3600) <= game.DepartureRate(config, 22*3600+20*60) {
+		t.Fatal("Departures should peak at the end.")
+	}
+
+	// Every rider arrives during the lead and leaves during the dispersal.
+	arriving, leaving := 0.0, 0.0
+	for s := 18 * 3600; s < 23*3600; s++ {
+		arriving += game.ArrivalRate(config, s) / 3600
+		leaving += game.DepartureRate(config, s) / 3600
+	}
+	if math.Abs(arriving-5000) > 5 || math.Abs(leaving-5000) > 5 {
+		t.Fatal("The surges should carry the share of the attendance that travels by metro.")
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	if a, l := game.Surge(rng, config, 1, 19*3600-60, 60); a != 0 || l != 0 {
+		t.Fatal("There should be no surge on other days.")
+	}
+	if a, _ := game.Surge(rng, config, 2, 19*3600-60, 60); a == 0 {
+		t.Fatal("Attendees should arrive before the start.")
+	}
+
+	if game.Phase(config, 2, 18*3600+30*60) != EventPhaseArriving ||
+		game.Phase(config, 2, 20*3600) != EventPhaseUnderway ||
+		game.Phase(config, 2, 22*3600+10*60) != EventPhaseLeaving ||
+		game.Phase(config, 3, 20*3600) != EventPhaseNone {
+		t.Fatal("The phase should follow the lead, the event and the dispersal.")
+	}
+}
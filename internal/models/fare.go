@@ -0,0 +1,83 @@
+package models
+
+import (
+	"math"
+
+	"github.com/odin-software/metro/control"
+)
+
+// Fare models, see control.FareConfig.Model
+const (
+	FareFlat     = "flat"     // Everyone pays the base fare
+	FareDistance = "distance" // The base fare and the km travelled
+	FareZonal    = "zonal"    // The base fare and the zones crossed
+)
+
+// Gates where fares are charged
+const (
+	GateEntry = "entry"
+	GateExit  = "exit"
+)
+
+// FareModel charges passengers at the gates. The base fare is paid when entering,
+// transfers beyond the discount and the distance or zones travelled when leaving.
+type FareModel struct {
+	Config control.FareConfig
+	clock  ClockInterface // Simulation time of the charges, for revenue per hour
+}
+
+func NewFareModel(config control.FareConfig, clock ClockInterface) *FareModel {
+	return &FareModel{Config: config, clock: clock}
+}
+
+// EntryFare returns what the persona pays when going through the gates to travel.
+func (fm *FareModel) EntryFare(persona Persona) float64 {
+	return fm.Config.Base * fm.concession(persona)
+}
+
+// ExitFare returns what the persona pays when leaving at exit after entering at
+// entry and travelling the itinerary.
+func (fm *FareModel) ExitFare(persona Persona, entry, exit *Station, itinerary Itinerary) float64 {
+	discount := math.Max(0, math.Min(1, fm.Config.TransferDiscount))
+	fare := float64(itinerary.Transfers()) * fm.Config.Base * (1 - discount)
+
+	switch fm.Config.Model {
+	case FareDistance:
+		fare += fm.Config.PerKm * journeyKm(entry, exit, itinerary)
+	case FareZonal:
+		if entry != nil && exit != nil {
+			fare += fm.Config.PerZone * math.Abs(float64(exit.Zone-entry.Zone))
+		}
+	}
+	return fare * fm.concession(persona)
+}
+
+func (fm *FareModel) concession(persona Persona) float64 {
+	if share, ok := fm.Config.Concessions[string(persona.Kind)]; ok {
+		return math.Max(0, share)
+	}
+	return 1
+}
+
+// timeOfDay returns the simulation time in seconds since midnight, 0 without a clock.
+func (fm *FareModel) timeOfDay() int {
+	if fm.clock == nil {
+		return 0
+	}
+	return fm.clock.GetCurrentTimeOfDay()
+}
+
+// journeyKm measures the journey leg by leg in a straight line, or from entry to
+// exit without an itinerary.
+func journeyKm(entry, exit *Station, itinerary Itinerary) float64 {
+	pixels := 0.0
+	if len(itinerary.Legs) == 0 && entry != nil && exit != nil {
+		from := entry.GetPosition()
+		pixels = from.Dist(exit.GetPosition())
+	}
+	for _, leg := range itinerary.Legs {
+		from := leg.From.GetPosition()
+		pixels += from.Dist(leg.To.GetPosition())
+	}
+	return PixelsToKilometers(pixels)
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"math"
+	"testing"
+
+	"github.com/odin-software/metro/control"
+)
+
+func TestFareModels(t *testing.T) {
+	a := &Station{ID: 1, Name: "A", Zone: 1}
+	b := &Station{ID: 2, Name: "B", Position: NewVector(30, 40), Zone: 2}
+	c := &Station{ID: 3, Name: "C", Position: NewVector(30, 0), Zone: 3}
+	direct := Itinerary{Legs: []Leg{{LineID: 1, From: a, To: b}}}
+	transfer := Itinerary{Legs: []Leg{{LineID: 1, From: a, To: b}, {LineID: 2, From: b, To: c}}}
+	commuter := PersonaByKind(PersonaCommuter)
+	config := control.FareConfig{Model: FareFlat, Base: 20, PerKm: 2, PerZone: 5, TransferDiscount: 1}
+
+	flat := NewFareModel(config, nil)
+	if flat.EntryFare(commuter) != 20 || flat.ExitFare(commuter, a, c, transfer) != 0 {
+		t.Fatal("A flat fare should be the base fare, with free transfers.")
+	}
+
+	config.TransferDiscount = 0.5
+	if NewFareModel(config, nil).ExitFare(commuter, a, c, transfer) != 10 {
+		t.Fatal("Transfers should pay the base fare less the discount.")
+	}
+
+	config.Model = FareDistance
+	km := PixelsToKilometers(50)
+	if fare := NewFareModel(config, nil).ExitFare(commuter, a, b, direct); math.Abs(fare-2*km) > 1e-9 {
+		t.Fatal("A distance fare should charge the km travelled when leaving.")
+	}
+
+	config.Model = FareZonal
+	if NewFareModel(config, nil).ExitFare(commuter, a, c, direct) != 10 {
+		t.Fatal("A zonal fare should charge the zones crossed when leaving.")
+	}
+
+	config.Concessions = map[string]float64{string(PersonaStudent): 0.5}
+	zonal := NewFareModel(config, nil)
+	student := PersonaByKind(PersonaStudent)
+	if zonal.EntryFare(student) != 10 || zonal.ExitFare(student, a, c, direct) != 5 {
+		t.Fatal("Concessions should pay their share of every fare.")
+	}
+	if zonal.EntryFare(commuter) != 20 {
+		t.Fatal("Personas without a concession should pay the full fare.")
+	}
+}
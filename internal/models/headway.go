@@ -0,0 +1,172 @@
+package models
+
+import (
+	"math"
+	"sort"
+
+	"github.com/odin-software/metro/control"
+)
+
+// LinePosition is where a train is on the round trip of its line, measured in time:
+// the running and dwell time of its make from leaving the first station, forward to
+// the last one and back. Holds still to be served put the train further behind.
+type LinePosition struct {
+	TrainID   int64
+	TrainName string
+	LineID    int64
+	StationID int64   // Station the train is at or heading to
+	Forward   bool    // Direction the train leaves the station or travels in
+	Standing  bool    // At the station
+	Offset    float64 // Seconds into the round trip
+	RoundTrip float64 // Seconds of a round trip
+}
+
+// Phase returns the share (0-1) of the round trip behind the train.
+func (p LinePosition) Phase() float64 {
+	if p.RoundTrip <= 0 {
+		return 0
+	}
+	phase := math.Mod(p.Offset/p.RoundTrip, 1)
+	if phase < 0 {
+		phase++
+	}
+	return phase
+}
+
+// GetLinePosition returns where the train is on the round trip of its line, false in
+// the depot or when it isn't at a station of its line.
+func (tr *Train) GetLinePosition() (LinePosition, bool) {
+	stations := tr.destinations.Stations
+	count := len(stations)
+	if tr.inDepot || tr.Current == nil || count < 2 {
+		return LinePosition{}, false
+	}
+	index := stationIndex(stations, tr.Current.ID)
+	if index == -1 {
+		return LinePosition{}, false
+	}
+
+	// Start of every leg of the round trip, a leg is the dwell at its first stop and
+	// the travel to the next one
+	legs := 2 * (count - 1)
+	starts := make([]int, legs+1)
+	for leg := range legs {
+		from, to := roundTripLeg(leg, count)
+		ticks, err := tr.legTravelTicks(tr.model, stations[from], stations[to])
+		if err != nil {
+			return LinePosition{}, false
+		}
+		starts[leg+1] = starts[leg] + tr.waitTicks + ticks
+	}
+
+	position := LinePosition{
+		TrainID:   tr.ID,
+		TrainName: tr.Name,
+		LineID:    tr.destinations.ID,
+		Standing:  tr.Next == nil,
+	}
+	ticks := 0
+	if tr.Next == nil {
+		position.StationID, position.Forward = tr.Current.ID, tr.departsForward(index)
+		ticks = starts[roundTripLegFrom(index, position.Forward, count)] + tr.waitTicks - tr.waitCounter
+	} else {
+		to := stationIndex(stations, tr.Next.ID)
+		if to == -1 {
+			return LinePosition{}, false
+		}
+		position.StationID, position.Forward = tr.Next.ID, to > index
+		leg := roundTripLegFrom(index, position.Forward, count)
+		start, _, _ := tr.route.PositionAt(0)
+		remaining := travelTicks(tr.model, start, tr.Position, tr.velocity.Magnitude(), tr.q.items)
+		ticks = starts[leg+1] - min(remaining, starts[leg+1]-starts[leg]-tr.waitTicks)
+	}
+	ticks -= tr.holdTicks
+
+	tickSeconds := control.DefaultConfig.LoopDuration.Seconds() * control.DefaultConfig.SimulationSpeed
+	position.Offset = float64(ticks) * tickSeconds
+	position.RoundTrip = float64(starts[legs]) * tickSeconds
+	return position, true
+}
+
+// roundTripLeg returns the stops at both ends of a leg of the round trip of a line of
+// count stations: the forward legs first, then the backward ones.
+func roundTripLeg(leg, count int) (int, int) {
+	if leg < count-1 {
+		return leg, leg + 1
+	}
+	from := 2*(count-1) - leg
+	return from, from - 1
+}
+
+// roundTripLegFrom returns the leg of the round trip leaving the stop at the given
+// index in the given direction.
+func roundTripLegFrom(index int, forward bool, count int) int {
+	if forward {
+		return index
+	}
+	return 2*(count-1) - index
+}
+
+// Headway is the spacing of a train with the trains around it on its line.
+type Headway struct {
+	Position LinePosition
+	Forward  float64 // Seconds behind the train ahead
+	Backward float64 // Seconds ahead of the train behind
+}
+
+// LineHeadways orders the trains of a line around its round trip, in the order they
+// run, and returns their headways. Trains of different makes are compared by the
+// share of their round trip they covered, over the average round trip. A single
+// train is a round trip away from itself.
+func LineHeadways(positions []LinePosition) []Headway {
+	if len(positions) == 0 {
+		return nil
+	}
+	headways := make([]Headway, len(positions))
+	roundTrip := 0.0
+	for i, p := range positions {
+		headways[i].Position = p
+		roundTrip += p.RoundTrip
+	}
+	roundTrip /= float64(len(positions))
+	sort.SliceStable(headways, func(i, j int) bool {
+		a, b := headways[i].Position, headways[j].Position
+		if a.Phase() != b.Phase() {
+			return a.Phase() < b.Phase()
+		}
+		return a.TrainID < b.TrainID
+	})
+
+	for i := range headways {
+		ahead := headways[(i+1)%len(headways)].Position.Phase()
+		gap := ahead - headways[i].Position.Phase()
+		if gap < 0 || gap == 0 && len(headways) == 1 {
+			gap++
+		}
+		headways[i].Forward = gap * roundTrip
+		headways[(i+1)%len(headways)].Backward = headways[i].Forward
+	}
+	return headways
+}
+
+// CoefficientOfVariation returns the standard deviation of the values over their
+// mean, 0 for fewer than two values or a mean of 0. Even headways have a
+// coefficient of 0, bunched ones get closer to 1 and above.
+func CoefficientOfVariation(values []float64) float64 {
+	if len(values) < 2 {
+		return 0
+	}
+	mean := 0.0
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+	if mean == 0 {
+		return 0
+	}
+	variance := 0.0
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	return math.Sqrt(variance/float64(len(values))) / mean
+}
@@ -0,0 +1,78 @@
+package models
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLineHeadways(t *testing.T) {
+	positions := []LinePosition{
+		{TrainID: 1, Offset: 100, RoundTrip: 1000},
+		{TrainID: 2, Offset: 900, RoundTrip: 1000},
+		{TrainID: 3, Offset: 1150, RoundTrip: 1000}, // Past the end, 150 into the next round trip
+	}
+	headways := LineHeadways(positions)
+	if len(headways) != 3 {
+		t.Fatal("Every train should have its headways.")
+	}
+	if headways[0].Position.TrainID != 1 || headways[1].Position.TrainID != 3 || headways[2].Position.TrainID != 2 {
+		t.Fatal("Trains should be ordered around the round trip.")
+	}
+	if math.Abs(headways[0].Forward-50) > 1e-9 || math.Abs(headways[2].Forward-200) > 1e-9 {
+		t.Fatal("The forward headway should be the time to the train ahead, across the end of the round trip.")
+	}
+	if math.Abs(headways[0].Backward-200) > 1e-9 || math.Abs(headways[1].Backward-50) > 1e-9 {
+		t.Fatal("The backward headway should be the forward headway of the train behind.")
+	}
+
+	single := LineHeadways([]LinePosition{{TrainID: 1, Offset: 300, RoundTrip: 1000}})
+	if single[0].Forward != 1000 || single[0].Backward != 1000 {
+		t.Fatal("A single train should be a round trip away from itself.")
+	}
+}
+
+func TestCoefficientOfVariation(t *testing.T) {
+	if CoefficientOfVariation([]float64{300, 300, 300}) != 0 {
+		t.Fatal("Even headways should have a coefficient of 0.")
+	}
+	if cv := CoefficientOfVariation([]float64{0, 600}); math.Abs(cv-1) > 1e-9 {
+		t.Fatal("Paired trains should have a coefficient of 1.")
+	}
+	if CoefficientOfVariation([]float64{300}) != 0 {
+		t.Fatal("A single headway has no variation.")
+	}
+}
+
+func TestLinePosition(t *testing.T) {
+	line, central := newCommandLine()
+	tr := newCommandTrain(1, line, central)
+	tr.waitTicks = 60
+	tr.Current, tr.Next = line.Stations[1], nil
+
+	standing, ok := tr.GetLinePosition()
+	if !ok || !standing.Standing || standing.StationID != 2 || !standing.Forward {
+		t.Fatal("A train at a station should be there, leaving forward.")
+	}
+	if standing.RoundTrip <= 0 || standing.Offset <= 0 || standing.Offset >= standing.RoundTrip/2 {
+		t.Fatal("A train at the second of four stations going forward should be in the first half of the round trip.")
+	}
+
+	tr.waitCounter = 60
+	tr.holdTicks = 120
+	held, _ := tr.GetLinePosition()
+	if held.Offset >= standing.Offset {
+		t.Fatal("A train still dwelling or held should be behind one ready to leave.")
+	}
+
+	tr.waitCounter, tr.holdTicks = 0, 0
+	tr.Current, tr.forward = line.Stations[2], false
+	back, _ := tr.GetLinePosition()
+	if back.Forward || back.Offset <= back.RoundTrip/2 {
+		t.Fatal("A train going back should be in the second half of the round trip.")
+	}
+
+	tr.inDepot = true
+	if _, ok := tr.GetLinePosition(); ok {
+		t.Fatal("A train in the depot isn't on the line.")
+	}
+}
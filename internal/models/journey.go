@@ -0,0 +1,344 @@
+package models
+
+import (
+	"container/heap"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/odin-software/metro/control"
+)
+
+// TransferPenalty is the cost of changing lines, measured in stops. A journey with
+// one transfer is only chosen over a direct one if it saves more stops than this.
+// Walks between the stations of a complex are added on top.
+const TransferPenalty = 4
+
+// Leg is the part of a journey made on a single line, without changing trains.
+type Leg struct {
+	LineID   int64
+	LineName string
+	From     *Station
+	To       *Station
+	Forward  bool     // True when travelling in the order of the stations of the line
+	Towards  *Station // Last station of the line in the direction of travel
+	Stops    int
+	Walk     time.Duration // Walk to the platform before boarding, from the gates or the previous leg
+}
+
+// Itinerary is the planned journey of a passenger, a sequence of legs where the
+// last station of each leg is the first one of the next.
+type Itinerary struct {
+	Legs []Leg
+}
+
+// Transfers returns the number of times the passenger changes lines.
+func (it Itinerary) Transfers() int {
+	if len(it.Legs) == 0 {
+		return 0
+	}
+	return len(it.Legs) - 1
+}
+
+// Stops returns the number of stops of the whole journey.
+func (it Itinerary) Stops() int {
+	stops := 0
+	for _, leg := range it.Legs {
+		stops += leg.Stops
+	}
+	return stops
+}
+
+// Walk returns the time spent walking to platforms during the whole journey.
+func (it Itinerary) Walk() time.Duration {
+	walk := time.Duration(0)
+	for _, leg := range it.Legs {
+		walk += leg.Walk
+	}
+	return walk
+}
+
+// Duration estimates the journey time from the gates to the destination platform,
+// riding control.Config.Walking.StopTime per stop, without waits.
+func (it Itinerary) Duration() time.Duration {
+	return time.Duration(it.Stops())*control.DefaultConfig.Walking.StopTime + it.Walk()
+}
+
+func (it Itinerary) String() string {
+	parts := make([]string, 0, len(it.Legs))
+	for _, leg := range it.Legs {
+		parts = append(parts, fmt.Sprintf("%s %s → %s", leg.LineName, leg.From.Name, leg.To.Name))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// JourneyPlanner finds itineraries between stations over the lines of the network.
+// Lines are run in both directions, like the trains do. Passengers can walk between
+// the stations of a complex to change lines.
+type JourneyPlanner struct {
+	lines    []Line
+	stations map[int64]*Station
+	// Index of each station in each line, by line position in lines.
+	index []map[int64]int
+	// Lines serving each station, by line position in lines.
+	serving map[int64][]int
+}
+
+func NewJourneyPlanner(lines []Line) *JourneyPlanner {
+	jp := &JourneyPlanner{
+		lines:    make([]Line, len(lines)),
+		stations: make(map[int64]*Station),
+		index:    make([]map[int64]int, len(lines)),
+		serving:  make(map[int64][]int),
+	}
+	for i, line := range lines {
+		jp.lines[i] = copyLine(line)
+		jp.index[i] = make(map[int64]int, len(line.Stations))
+		for j, st := range line.Stations {
+			jp.index[i][st.ID] = j
+			jp.serving[st.ID] = append(jp.serving[st.ID], i)
+			jp.stations[st.ID] = st
+		}
+	}
+	return jp
+}
+
+// Reachable returns true if a journey exists between the two stations.
+func (jp *JourneyPlanner) Reachable(originID, destinationID int64) bool {
+	_, err := jp.Plan(originID, destinationID)
+	return err == nil
+}
+
+// Destinations returns every station that can be reached from the origin, other
+// than the stations of its complex.
+func (jp *JourneyPlanner) Destinations(originID int64) []*Station {
+	reached := jp.search(originID, -1, -1)
+	origin := jp.stations[originID]
+	seen := make(map[int64]bool)
+	destinations := make([]*Station, 0)
+	for i, line := range jp.lines {
+		for j, st := range line.Stations {
+			if st.SamePlace(origin) || seen[st.ID] {
+				continue
+			}
+			if _, ok := reached[planNode{i, j}]; ok {
+				seen[st.ID] = true
+				destinations = append(destinations, st)
+			}
+		}
+	}
+	return destinations
+}
+
+// Plan returns the itinerary between two stations with the lowest cost, where every
+// stop costs 1, every transfer costs TransferPenalty and walks cost the stops that
+// could be ridden meanwhile. The journey may start or end on any station of the
+// complex of the origin or the destination.
+func (jp *JourneyPlanner) Plan(originID, destinationID int64) (Itinerary, error) {
+	return jp.plan(originID, destinationID, -1)
+}
+
+// PlanAvoiding returns the cheapest itinerary that doesn't ride the given line.
+func (jp *JourneyPlanner) PlanAvoiding(originID, destinationID, avoidLineID int64) (Itinerary, error) {
+	skip := -1
+	for i, line := range jp.lines {
+		if line.ID == avoidLineID {
+			skip = i
+		}
+	}
+	return jp.plan(originID, destinationID, skip)
+}
+
+func (jp *JourneyPlanner) plan(originID, destinationID int64, skip int) (Itinerary, error) {
+	origin, destination := jp.stations[originID], jp.stations[destinationID]
+	if originID == destinationID || (origin != nil && origin.SamePlace(destination)) {
+		return Itinerary{}, fmt.Errorf("the origin and the destination are the same station")
+	}
+	if origin == nil {
+		return Itinerary{}, fmt.Errorf("station %d is not served by any line", originID)
+	}
+
+	reached := jp.search(originID, destinationID, skip)
+	var end *planNode
+	best := 0.0
+	if destination != nil {
+		for _, st := range destination.Interchanges() {
+			for _, li := range jp.serving[st.ID] {
+				node := planNode{li, jp.index[li][st.ID]}
+				if state, ok := reached[node]; ok && (end == nil || state.cost < best) {
+					end = &node
+					best = state.cost
+				}
+			}
+		}
+	}
+	if end == nil {
+		return Itinerary{}, fmt.Errorf("station %d cannot be reached from station %d", destinationID, originID)
+	}
+
+	// Walk back from the destination and cut a leg at every transfer.
+	path := []planNode{*end}
+	for node := *end; reached[node].hasPrev; {
+		node = reached[node].prev
+		path = append(path, node)
+	}
+	legs := make([]Leg, 0)
+	for i := len(path) - 1; i >= 0; {
+		start := path[i]
+		j := i
+		for j > 0 && path[j-1].line == start.line {
+			j--
+		}
+		if j != i {
+			legs = append(legs, jp.leg(start, path[j]))
+		}
+		i = j - 1
+	}
+	for i := range legs {
+		if i == 0 {
+			legs[i].Walk = legs[i].From.EntranceWalk
+		} else {
+			legs[i].Walk = legs[i-1].To.WalkTo(legs[i].From)
+		}
+	}
+
+	return Itinerary{Legs: legs}, nil
+}
+
+func (jp *JourneyPlanner) leg(from, to planNode) Leg {
+	line := jp.lines[from.line]
+	forward := to.stop > from.stop
+	towards := line.Stations[0]
+	if forward {
+		towards = line.Stations[len(line.Stations)-1]
+	}
+	stops := to.stop - from.stop
+	if !forward {
+		stops = -stops
+	}
+	return Leg{
+		LineID:   line.ID,
+		LineName: line.Name,
+		From:     line.Stations[from.stop],
+		To:       line.Stations[to.stop],
+		Forward:  forward,
+		Towards:  towards,
+		Stops:    stops,
+	}
+}
+
+// planNode is a station of a line: riding moves along the line, transferring
+// moves to the same station or another station of its complex on another line.
+type planNode struct {
+	line, stop int
+}
+
+type planState struct {
+	cost    float64
+	prev    planNode
+	hasPrev bool
+}
+
+// search runs Dijkstra from every line serving the complex of the origin and stops
+// once the destination is settled, a negative destination explores the whole
+// network. The line at index skip is never ridden, -1 rides every line.
+func (jp *JourneyPlanner) search(originID, destinationID int64, skip int) map[planNode]planState {
+	settled := make(map[planNode]planState)
+	origin, destination := jp.stations[originID], jp.stations[destinationID]
+	if origin == nil {
+		return settled
+	}
+	queue := &planQueue{}
+	for _, st := range origin.Interchanges() {
+		for _, li := range jp.serving[st.ID] {
+			if li == skip {
+				continue
+			}
+			heap.Push(queue, planItem{
+				node: planNode{li, jp.index[li][st.ID]},
+				cost: walkCost(st.EntranceWalk),
+			})
+		}
+	}
+
+	for queue.Len() > 0 {
+		item := heap.Pop(queue).(planItem)
+		if _, ok := settled[item.node]; ok {
+			continue
+		}
+		settled[item.node] = planState{cost: item.cost, prev: item.prev, hasPrev: item.hasPrev}
+
+		stations := jp.lines[item.node.line].Stations
+		station := stations[item.node.stop]
+		if station.SamePlace(destination) {
+			break
+		}
+
+		for _, next := range []int{item.node.stop - 1, item.node.stop + 1} {
+			if next < 0 || next >= len(stations) {
+				continue
+			}
+			heap.Push(queue, planItem{
+				node:    planNode{item.node.line, next},
+				cost:    item.cost + 1,
+				prev:    item.node,
+				hasPrev: true,
+			})
+		}
+		// Transfers are not allowed at the origin, the passenger picks the line there.
+		if station.SamePlace(origin) {
+			continue
+		}
+		for _, st := range station.Interchanges() {
+			for _, li := range jp.serving[st.ID] {
+				if li == item.node.line || li == skip {
+					continue
+				}
+				heap.Push(queue, planItem{
+					node:    planNode{li, jp.index[li][st.ID]},
+					cost:    item.cost + TransferPenalty + walkCost(station.WalkTo(st)),
+					prev:    item.node,
+					hasPrev: true,
+				})
+			}
+		}
+	}
+	return settled
+}
+
+// walkCost converts a walk to the stops that could be ridden in the same time.
+func walkCost(walk time.Duration) float64 {
+	stopTime := control.DefaultConfig.Walking.StopTime
+	if stopTime <= 0 {
+		return 0
+	}
+	return walk.Seconds() / stopTime.Seconds()
+}
+
+type planItem struct {
+	node    planNode
+	cost    float64
+	prev    planNode
+	hasPrev bool
+}
+
+type planQueue []planItem
+
+func (q planQueue) Len() int { return len(q) }
+func (q planQueue) Less(i, j int) bool {
+	if q[i].cost != q[j].cost {
+		return q[i].cost < q[j].cost
+	}
+	if q[i].node.line != q[j].node.line {
+		return q[i].node.line < q[j].node.line
+	}
+	return q[i].node.stop < q[j].node.stop
+}
+func (q planQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+func (q *planQueue) Push(x any)   { *q = append(*q, x.(planItem)) }
+func (q *planQueue) Pop() any {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
@@ -0,0 +1,113 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func journeyStations(n int) []*Station {
+	stations := make([]*Station, n)
+	for i := range stations {
+		stations[i] = &Station{ID: int64(i + 1), Name: string(rune('A' + i))}
+	}
+	return stations
+}
+
+func TestJourneyPlannerTransfer(t *testing.T) {
+	st := journeyStations(7)
+	jp := NewJourneyPlanner([]Line{
+		{ID: 1, Name: "L1", Stations: []*Station{st[0], st[1], st[2], st[3]}},
+		{ID: 2, Name: "L2", Stations: []*Station{st[4], st[2], st[5]}},
+		{ID: 3, Name: "L3", Stations: []*Station{st[6]}},
+	})
+
+	it, err := jp.Plan(1, 5)
+	if err != nil {
+		t.Fatal("There should be a journey from A to E.")
+	}
+	if len(it.Legs) != 2 || it.Transfers() != 1 {
+		t.Fatal("The journey should have a transfer.")
+	}
+	if it.Legs[0].LineID != 1 || !it.Legs[0].Forward || it.Legs[0].To.ID != 3 {
+		t.Fatal("The first leg should go forward on L1 until C.")
+	}
+	if it.Legs[1].LineID != 2 || it.Legs[1].Forward || it.Legs[1].From.ID != 3 || it.Legs[1].Towards.ID != 5 {
+		t.Fatal("The second leg should go backward on L2 from C towards E.")
+	}
+	if it.Stops() != 3 {
+		t.Fatal("The journey should have 3 stops.")
+	}
+
+	if _, err := jp.Plan(1, 7); err == nil {
+		t.Fatal("G is not reachable from A.")
+	}
+	if len(jp.Destinations(4)) != 5 {
+		t.Fatal("Every station but G should be reachable from D.")
+	}
+}
+
+func TestJourneyPlannerPrefersDirect(t *testing.T) {
+	st := journeyStations(6)
+	jp := NewJourneyPlanner([]Line{
+		{ID: 1, Name: "L1", Stations: []*Station{st[0], st[1], st[2], st[3], st[4]}},
+		{ID: 2, Name: "L2", Stations: []*Station{st[0], st[5], st[4]}},
+		{ID: 3, Name: "L3", Stations: []*Station{st[5], st[3]}},
+	})
+
+	// L1 has 4 stops, L2 to F and L3 to D has 2 but a transfer.
+	it, err := jp.Plan(1, 4)
+	if err != nil || len(it.Legs) != 1 || it.Legs[0].LineID != 1 {
+		t.Fatal("A direct journey should be preferred over a short transfer.")
+	}
+	it, _ = jp.Plan(1, 5)
+	if len(it.Legs) != 1 || it.Legs[0].LineID != 2 {
+		t.Fatal("The line with the fewest stops should be chosen.")
+	}
+
+	it, err = jp.PlanAvoiding(1, 5, 2)
+	if err != nil || len(it.Legs) != 1 || it.Legs[0].LineID != 1 {
+		t.Fatal("Avoiding L2 should ride L1.")
+	}
+	it, err = jp.PlanAvoiding(1, 6, 2)
+	if err != nil || len(it.Legs) != 2 || it.Legs[1].LineID != 3 {
+		t.Fatal("Avoiding L2 should transfer from L1 to L3 to reach F.")
+	}
+}
+
+func TestJourneyPlannerComplex(t *testing.T) {
+	st := journeyStations(14)
+	interchange := NewStationComplex(1, "BE", 3*time.Minute)
+	interchange.Add(st[1])
+	interchange.Add(st[4])
+	jp := NewJourneyPlanner([]Line{
+		{ID: 1, Name: "L1", Stations: []*Station{st[0], st[1], st[2]}},
+		{ID: 2, Name: "L2", Stations: []*Station{st[3], st[4], st[5]}},
+		{ID: 3, Name: "L3", Stations: append(append([]*Station{st[0]}, st[6:]...), st[5])},
+	})
+
+	// L1 to B, walk to E and L2 to F costs 2 stops, a transfer and a walk of 1.5 stops,
+	// L3 has 9 stops.
+	it, err := jp.Plan(1, 6)
+	if err != nil || len(it.Legs) != 2 || it.Legs[0].To.ID != 2 || it.Legs[1].From.ID != 5 {
+		t.Fatal("The journey should transfer walking from B to E.")
+	}
+	if it.Legs[1].Walk != 3*time.Minute || it.Walk() != 3*time.Minute {
+		t.Fatal("The transfer should include the walk between the stations of the complex.")
+	}
+
+	interchange.SetWalk(5, 2, 10*time.Minute)
+	if interchange.Walk(2, 5) != 10*time.Minute {
+		t.Fatal("A walk set between two stations should apply in both directions.")
+	}
+	it, _ = jp.Plan(1, 6)
+	if len(it.Legs) != 1 || it.Legs[0].LineID != 3 {
+		t.Fatal("A long walk should make the direct journey the better one.")
+	}
+
+	if it, err = jp.Plan(1, 5); err != nil || len(it.Legs) != 1 || it.Legs[0].To.ID != 2 {
+		t.Fatal("A journey to E should end at B, on the same complex.")
+	}
+	if _, err := jp.Plan(2, 5); err == nil {
+		t.Fatal("B and E are the same place.")
+	}
+}
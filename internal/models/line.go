@@ -28,9 +28,11 @@ var (
 
 func (ln Line) Draw(screen *ebiten.Image) {
 	var path vector.Path
-	path.MoveTo(float32(ln.Stations[0].Position.X), float32(ln.Stations[0].Position.Y))
+	start := ln.Stations[0].GetPosition()
+	path.MoveTo(float32(start.X), float32(start.Y))
 	for _, st := range ln.Stations {
-		path.LineTo(float32(st.Position.X), float32(st.Position.Y))
+		position := st.GetPosition()
+		path.LineTo(float32(position.X), float32(position.Y))
 	}
 
 	// Draw the main line in white.
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// nameLocale holds the names used to build full names for a locale.
+type nameLocale struct {
+	given    []string
+	surnames []string
+	// Two surnames (paternal and maternal) are common in Spanish speaking countries.
+	doubleSurname bool
+}
+
+var nameLocales = map[string]nameLocale{
+	"es_DO": {
+		given: []string{
+			"Juan", "José", "Luis", "Carlos", "Miguel", "Rafael", "Pedro", "Ramón", "Manuel", "Francisco",
+			"Yunior", "Wilkin", "Alexander", "Starlin", "Ángel", "Félix", "Víctor", "Héctor", "Julio", "Anderson",
+			"María", "Ana", "Rosa", "Carmen", "Yolanda", "Altagracia", "Mercedes", "Juana", "Marisol", "Yokasta",
+			"Yamilet", "Dahiana", "Leidy", "Patria", "Minerva", "Esperanza", "Milagros", "Nathalie", "Paola", "Katherine",
+		},
+		surnames: []string{
+			"Rodríguez", "Pérez", "Martínez", "García", "Sánchez", "Reyes", "Ramírez", "Santos", "Peña", "Jiménez",
+			"Díaz", "Hernández", "Núñez", "Cruz", "Mejía", "Rosario", "De la Cruz", "Batista", "Castillo", "Guzmán",
+			"Vásquez", "Tavárez", "Almonte", "Féliz", "Polanco", "Ureña", "Paulino", "Encarnación", "Mateo", "Familia",
+		},
+		doubleSurname: true,
+	},
+	"en_US": {
+		given: []string{
+			"James", "John", "Robert", "Michael", "William", "David", "Daniel", "Matthew", "Anthony", "Joshua",
+			"Mary", "Patricia", "Jennifer", "Linda", "Elizabeth", "Susan", "Jessica", "Sarah", "Karen", "Emily",
+		},
+		surnames: []string{
+			"Smith", "Johnson", "Williams", "Brown", "Jones", "Miller", "Davis", "Wilson", "Anderson", "Taylor",
+			"Thomas", "Moore", "Martin", "Jackson", "Thompson", "White", "Harris", "Clark", "Lewis", "Walker",
+		},
+	},
+}
+
+// DefaultNameLocale is used when a generator is created for an unknown locale.
+const DefaultNameLocale = "en_US"
+
+// NameGenerator builds random full names for a locale.
+type NameGenerator struct {
+	locale nameLocale
+	rng    *rand.Rand
+}
+
+// NewNameGenerator creates a generator for the locale (e.g. "es_DO"), unknown locales use DefaultNameLocale.
+func NewNameGenerator(locale string, rng *rand.Rand) *NameGenerator {
+	names, ok := nameLocales[locale]
+	if !ok {
+		names = nameLocales[DefaultNameLocale]
+	}
+	return &NameGenerator{locale: names, rng: rng}
+}
+
+// Name returns a random full name.
+func (ng *NameGenerator) Name() string {
+	given := ng.locale.given[ng.rng.Intn(len(ng.locale.given))]
+	surname := ng.locale.surnames[ng.rng.Intn(len(ng.locale.surnames))]
+	if !ng.locale.doubleSurname {
+		return fmt.Sprintf("%s %s", given, surname)
+	}
+	second := ng.locale.surnames[ng.rng.Intn(len(ng.locale.surnames))]
+	return fmt.Sprintf("%s %s %s", given, surname, second)
+}
@@ -3,18 +3,30 @@ package models
 import (
 	"errors"
 	"slices"
+	"sync"
 )
 
+// Network is a graph of vertices joined by edges with geometry.
+// It is safe for concurrent use: reads can happen in parallel and every edit is atomic.
 type Network[T any] struct {
+	mu           sync.RWMutex
 	vertices     map[string]T
 	edges        map[string]map[string][]Vector
+	directed     map[edgeKey]bool    // Directions with their own geometry
+	offsets      map[edgeKey]float64 // Lateral offset of the track, to the right of the direction
 	hashFunction func(T) string
 }
 
-func NewNetwork[T any](hashF func(T) string) Network[T] {
-	return Network[T]{
+type edgeKey struct {
+	from, to string
+}
+
+func NewNetwork[T any](hashF func(T) string) *Network[T] {
+	return &Network[T]{
 		vertices:     make(map[string]T),
 		edges:        make(map[string]map[string][]Vector),
+		directed:     make(map[edgeKey]bool),
+		offsets:      make(map[edgeKey]float64),
 		hashFunction: hashF,
 	}
 }
@@ -23,6 +35,13 @@ func NewNetwork[T any](hashF func(T) string) Network[T] {
 // If the vertex already exists, it returns an error, otherwise it returns nil.
 // It also creates an empty map for the edges of the new vertex.
 func (gr *Network[T]) InsertVertex(vertex T) error {
+	gr.mu.Lock()
+	defer gr.mu.Unlock()
+
+	return gr.insertVertex(vertex)
+}
+
+func (gr *Network[T]) insertVertex(vertex T) error {
 	key := gr.hashFunction(vertex)
 	if _, ok := gr.vertices[key]; ok {
 		return errors.New("this vertex already exists in the graph")
@@ -34,38 +53,125 @@ func (gr *Network[T]) InsertVertex(vertex T) error {
 }
 
 // InsertVertices works like InsertVertex, but for a slice of vertices.
-func (gr *Network[T]) InsertVertices(vertices []*T) error {
+// Either every vertex is inserted or none of them.
+func (gr *Network[T]) InsertVertices(vertices []T) error {
+	gr.mu.Lock()
+	defer gr.mu.Unlock()
+
+	inserted := make([]string, 0, len(vertices))
 	for _, v := range vertices {
-		err := gr.InsertVertex(*v)
+		err := gr.insertVertex(v)
 		if err != nil {
+			for _, key := range inserted {
+				delete(gr.vertices, key)
+				delete(gr.edges, key)
+			}
 			return err
 		}
+		inserted = append(inserted, gr.hashFunction(v))
 	}
 	return nil
 }
 
 // Inserts an edge between two vertices, the points are positions in between the vertices.
+// The opposite direction reuses the same points reversed, unless it was already
+// inserted with its own geometry through InsertDirectedEdgeByKey.
 func (gr *Network[T]) InsertEdge(firstVertex T, secondVertex T, points []Vector) error {
-	firstKey := gr.hashFunction(firstVertex)
-	secondKey := gr.hashFunction(secondVertex)
+	return gr.InsertEdgeByKey(gr.hashFunction(firstVertex), gr.hashFunction(secondVertex), points)
+}
+
+// InsertEdgeByKey works like InsertEdge, but takes the keys of the vertices.
+func (gr *Network[T]) InsertEdgeByKey(firstKey string, secondKey string, points []Vector) error {
+	gr.mu.Lock()
+	defer gr.mu.Unlock()
 
 	if _, ok := gr.edges[firstKey]; !ok {
 		return errors.New("the first vertex does not exists in the graph")
 	}
 	if _, ok := gr.edges[secondKey]; !ok {
+		return errors.New("the second vertex does not exists in the graph")
+	}
+
+	if !gr.directed[edgeKey{firstKey, secondKey}] {
+		gr.edges[firstKey][secondKey] = points
+	}
+	if !gr.directed[edgeKey{secondKey, firstKey}] {
+		gr.edges[secondKey][firstKey] = reversed(points)
+	}
+
+	return nil
+}
+
+// InsertDirectedEdgeByKey inserts an edge that only goes from the first key to the second one.
+// Its geometry is independent from the opposite direction and is never replaced by the
+// reversal of an edge inserted with InsertEdge.
+func (gr *Network[T]) InsertDirectedEdgeByKey(firstKey string, secondKey string, points []Vector) error {
+	gr.mu.Lock()
+	defer gr.mu.Unlock()
+
+	if _, ok := gr.edges[firstKey]; !ok {
 		return errors.New("the first vertex does not exists in the graph")
 	}
+	if _, ok := gr.edges[secondKey]; !ok {
+		return errors.New("the second vertex does not exists in the graph")
+	}
 
 	gr.edges[firstKey][secondKey] = points
+	gr.directed[edgeKey{firstKey, secondKey}] = true
+
+	return nil
+}
+
+// SetEdgeOffsetByKey sets the lateral offset of the track going from the first key to
+// the second one. Positive values move the track to the right of the travel direction.
+func (gr *Network[T]) SetEdgeOffsetByKey(firstKey string, secondKey string, offset float64) error {
+	gr.mu.Lock()
+	defer gr.mu.Unlock()
+
+	if _, err := gr.connected(firstKey, secondKey); err != nil {
+		return err
+	}
+	if offset == 0 {
+		delete(gr.offsets, edgeKey{firstKey, secondKey})
+		return nil
+	}
+	gr.offsets[edgeKey{firstKey, secondKey}] = offset
+
+	return nil
+}
+
+// EdgeOffsetByKey returns the lateral offset of the track going from the first key to the second one.
+func (gr *Network[T]) EdgeOffsetByKey(firstKey string, secondKey string) float64 {
+	gr.mu.RLock()
+	defer gr.mu.RUnlock()
+
+	return gr.offsets[edgeKey{firstKey, secondKey}]
+}
+
+// IsDirectedByKey returns true if the edge going from the first key to the second one has its own geometry.
+func (gr *Network[T]) IsDirectedByKey(firstKey string, secondKey string) bool {
+	gr.mu.RLock()
+	defer gr.mu.RUnlock()
+
+	return gr.directed[edgeKey{firstKey, secondKey}]
+}
+
+func reversed(points []Vector) []Vector {
 	rev := make([]Vector, len(points))
 	copy(rev, points)
 	slices.Reverse(rev)
-	gr.edges[secondKey][firstKey] = rev
+	return rev
+}
 
-	return nil
+// KeyOf returns the key used to store a vertex in the graph.
+func (gr *Network[T]) KeyOf(vertex T) string {
+	return gr.hashFunction(vertex)
 }
 
 func (gr *Network[T]) GetVertexFromKey(key string) (T, error) {
+	gr.mu.RLock()
+	defer gr.mu.RUnlock()
+
 	if _, ok := gr.vertices[key]; !ok {
 		return *new(T), errors.New("this vertex doesnt exists in the graph")
 	}
@@ -73,6 +179,9 @@ func (gr *Network[T]) GetVertexFromKey(key string) (T, error) {
 }
 
 func (gr *Network[T]) GetVertexFromValue(value T) (T, error) {
+	gr.mu.RLock()
+	defer gr.mu.RUnlock()
+
 	key := gr.hashFunction(value)
 	vertex, ok := gr.vertices[key]
 	if !ok {
@@ -83,6 +192,9 @@ func (gr *Network[T]) GetVertexFromValue(value T) (T, error) {
 }
 
 func (gr *Network[T]) GetVertices() []T {
+	gr.mu.RLock()
+	defer gr.mu.RUnlock()
+
 	values := make([]T, 0, len(gr.vertices))
 	for _, v := range gr.vertices {
 		values = append(values, v)
@@ -91,6 +203,9 @@ func (gr *Network[T]) GetVertices() []T {
 }
 
 func (gr *Network[T]) UpdateVertex(vertex T) error {
+	gr.mu.Lock()
+	defer gr.mu.Unlock()
+
 	key := gr.hashFunction(vertex)
 
 	if _, ok := gr.vertices[key]; !ok {
@@ -102,6 +217,9 @@ func (gr *Network[T]) UpdateVertex(vertex T) error {
 }
 
 func (gr *Network[T]) DeleteVertex(vertex T) error {
+	gr.mu.Lock()
+	defer gr.mu.Unlock()
+
 	key := gr.hashFunction(vertex)
 	if _, ok := gr.vertices[key]; !ok {
 		return errors.New("this vertex does not exists")
@@ -114,40 +232,101 @@ func (gr *Network[T]) DeleteVertex(vertex T) error {
 	for _, v := range gr.edges {
 		delete(v, key)
 	}
+	for ek := range gr.directed {
+		if ek.from == key || ek.to == key {
+			delete(gr.directed, ek)
+		}
+	}
+	for ek := range gr.offsets {
+		if ek.from == key || ek.to == key {
+			delete(gr.offsets, ek)
+		}
+	}
 
 	return nil
 }
 
+// GetEdges returns a copy of the edges that start at the vertex.
 func (gr *Network[T]) GetEdges(vertex T) (map[string][]Vector, error) {
+	gr.mu.RLock()
+	defer gr.mu.RUnlock()
+
 	key := gr.hashFunction(vertex)
 	v, ok := gr.edges[key]
 	if !ok {
 		return nil, errors.New("this vertex does not exists in the graph")
 	}
-	return v, nil
+	edges := make(map[string][]Vector, len(v))
+	for k, points := range v {
+		edges[k] = points
+	}
+	return edges, nil
 }
 
 func (gr *Network[T]) AreConnected(firstVertex T, secondVertex T) (std []Vector, e error) {
-	firstKey := gr.hashFunction(firstVertex)
-	secondKey := gr.hashFunction(secondVertex)
+	return gr.AreConnectedByKey(gr.hashFunction(firstVertex), gr.hashFunction(secondVertex))
+}
+
+// AreConnectedByKey works like AreConnected, but takes the keys of the vertices.
+func (gr *Network[T]) AreConnectedByKey(firstKey string, secondKey string) ([]Vector, error) {
+	gr.mu.RLock()
+	defer gr.mu.RUnlock()
+
+	return gr.connected(firstKey, secondKey)
+}
+
+// EdgeByKey returns the points and the lateral offset of the edge going from the first key
+// to the second one, read at the same time so they are consistent with each other.
+func (gr *Network[T]) EdgeByKey(firstKey string, secondKey string) ([]Vector, float64, error) {
+	gr.mu.RLock()
+	defer gr.mu.RUnlock()
+
+	points, err := gr.connected(firstKey, secondKey)
+	if err != nil {
+		return nil, 0, err
+	}
+	return points, gr.offsets[edgeKey{firstKey, secondKey}], nil
+}
 
+// connected must be called with the lock held.
+func (gr *Network[T]) connected(firstKey string, secondKey string) ([]Vector, error) {
 	firstMap, ok := gr.edges[firstKey]
 	if !ok {
-		return std, errors.New("the first vertex does not exists")
+		return nil, errors.New("the first vertex does not exists")
 	}
 	if _, ok := gr.edges[secondKey]; !ok {
-		return std, errors.New("the second vertex does not exists")
+		return nil, errors.New("the second vertex does not exists")
 	}
 
 	connections, ok := firstMap[secondKey]
 	if !ok {
-		return std, errors.New("these vertices are not connected")
+		return nil, errors.New("these vertices are not connected")
 	}
 
 	return connections, nil
 }
 
+// GetNeighborKeys returns the keys of every vertex reachable from the given one through a single edge.
+func (gr *Network[T]) GetNeighborKeys(key string) ([]string, error) {
+	gr.mu.RLock()
+	defer gr.mu.RUnlock()
+
+	v, ok := gr.edges[key]
+	if !ok {
+		return nil, errors.New("this vertex does not exists in the graph")
+	}
+	keys := make([]string, 0, len(v))
+	for k := range v {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys, nil
+}
+
 func (gr *Network[T]) UpdateEdgeValue(firstVertex T, secondVertex T, points []Vector) error {
+	gr.mu.Lock()
+	defer gr.mu.Unlock()
+
 	firstKey := gr.hashFunction(firstVertex)
 	secondKey := gr.hashFunction(secondVertex)
 
@@ -164,20 +343,22 @@ func (gr *Network[T]) UpdateEdgeValue(firstVertex T, secondVertex T, points []Ve
 	if !ok {
 		return errors.New("these vertices are not connected")
 	}
-	_, ok = secondMap[firstKey]
-	if !ok {
-		return errors.New("these vertices are not connected")
-	}
-
 	firstMap[secondKey] = points
-	secondMap[firstKey] = points
+	if !gr.directed[edgeKey{firstKey, secondKey}] && !gr.directed[edgeKey{secondKey, firstKey}] {
+		secondMap[firstKey] = reversed(points)
+	}
 
 	return nil
 }
 
 func (gr *Network[T]) DeleteEdge(firstVertex T, secondVertex T) error {
-	firstKey := gr.hashFunction(firstVertex)
-	secondKey := gr.hashFunction(secondVertex)
+	return gr.DeleteEdgeByKey(gr.hashFunction(firstVertex), gr.hashFunction(secondVertex))
+}
+
+// DeleteEdgeByKey removes both directions of the edge between the two keys.
+func (gr *Network[T]) DeleteEdgeByKey(firstKey string, secondKey string) error {
+	gr.mu.Lock()
+	defer gr.mu.Unlock()
 
 	firstMap, ok := gr.edges[firstKey]
 	if !ok {
@@ -192,7 +373,11 @@ func (gr *Network[T]) DeleteEdge(firstVertex T, secondVertex T) error {
 		return errors.New("these vertices are not connected")
 	}
 	delete(firstMap, secondKey)
-	delete(secondMap, secondKey)
+	delete(secondMap, firstKey)
+	for _, ek := range []edgeKey{{firstKey, secondKey}, {secondKey, firstKey}} {
+		delete(gr.directed, ek)
+		delete(gr.offsets, ek)
+	}
 
 	return nil
 }
@@ -0,0 +1,83 @@
+package models
+
+import (
+	"fmt"
+	"testing"
+)
+
+func newTestNetwork() *Network[Vector] {
+	nw := NewNetwork(func(v Vector) string {
+		return fmt.Sprintf("%.0f,%.0f", v.X, v.Y)
+	})
+	nw.InsertVertex(NewVector(0, 0))
+	nw.InsertVertex(NewVector(10, 0))
+	return nw
+}
+
+func TestInsertEdgeReversesGeometry(t *testing.T) {
+	nw := newTestNetwork()
+	nw.InsertEdge(NewVector(0, 0), NewVector(10, 0), []Vector{NewVector(3, 1), NewVector(7, 1)})
+
+	rev, err := nw.AreConnectedByKey("10,0", "0,0")
+	if err != nil {
+		t.Fatal("The opposite direction should exist.")
+	}
+	if rev[0].X != 7 || rev[1].X != 3 {
+		t.Fatal("The opposite direction should reuse the reversed points.")
+	}
+}
+
+func TestInsertDirectedEdge(t *testing.T) {
+	nw := newTestNetwork()
+	nw.InsertDirectedEdgeByKey("10,0", "0,0", []Vector{NewVector(5, -1)})
+
+	if _, err := nw.AreConnectedByKey("0,0", "10,0"); err == nil {
+		t.Fatal("A directed edge should not create the opposite direction.")
+	}
+
+	// The undirected edge should not replace the directed geometry.
+	nw.InsertEdge(NewVector(0, 0), NewVector(10, 0), []Vector{NewVector(5, 1)})
+	rev, _ := nw.AreConnectedByKey("10,0", "0,0")
+	if len(rev) != 1 || rev[0].Y != -1 {
+		t.Fatal("The directed geometry was replaced.")
+	}
+	if !nw.IsDirectedByKey("10,0", "0,0") || nw.IsDirectedByKey("0,0", "10,0") {
+		t.Fatal("Only the directed edge should be marked as directed.")
+	}
+}
+
+func TestDeleteEdgeRemovesBothDirections(t *testing.T) {
+	nw := newTestNetwork()
+	nw.InsertEdge(NewVector(0, 0), NewVector(10, 0), nil)
+	nw.SetEdgeOffsetByKey("0,0", "10,0", 2)
+	nw.DeleteEdge(NewVector(0, 0), NewVector(10, 0))
+
+	if _, err := nw.AreConnectedByKey("10,0", "0,0"); err == nil {
+		t.Fatal("The opposite direction should have been deleted.")
+	}
+	if nw.EdgeOffsetByKey("0,0", "10,0") != 0 {
+		t.Fatal("The offset should have been deleted.")
+	}
+}
+
+func TestConcurrentReadsAndEdits(t *testing.T) {
+	nw := newTestNetwork()
+	done := make(chan bool)
+
+	go func() {
+		for i := 0; i < 1000; i++ {
+			nw.InsertEdge(NewVector(0, 0), NewVector(10, 0), []Vector{NewVector(5, float64(i))})
+			nw.DeleteEdge(NewVector(0, 0), NewVector(10, 0))
+		}
+		done <- true
+	}()
+	for i := 0; i < 1000; i++ {
+		nw.AreConnectedByKey("0,0", "10,0")
+		nw.GetNeighborKeys("10,0")
+	}
+	<-done
+
+	if _, err := nw.AreConnectedByKey("0,0", "10,0"); err == nil {
+		t.Fatal("The edge should have been deleted.")
+	}
+}
@@ -2,35 +2,56 @@ package models
 
 import (
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/odin-software/metro/control"
 )
 
 // PassengerState represents the current state of a passenger
 type PassengerState string
 
 const (
+	PassengerStateEntering     PassengerState = "entering"     // Queueing outside the fare gates
+	PassengerStateWalking      PassengerState = "walking"      // Walking to the platform of their next train
 	PassengerStateWaiting      PassengerState = "waiting"      // Waiting at station
 	PassengerStateBoarding     PassengerState = "boarding"     // In process of boarding train
 	PassengerStateRiding       PassengerState = "riding"       // On the train
 	PassengerStateDisembarking PassengerState = "disembarking" // In process of leaving train
 	PassengerStateArrived      PassengerState = "arrived"      // Reached destination
+	PassengerStateAbandoned    PassengerState = "abandoned"    // Left the station without travelling
 )
 
 // Passenger represents a person using the transit system
 type Passenger struct {
 	ID                 string
 	Name               string
+	Persona            Persona
 	Position           Vector
 	CurrentStation     *Station
 	DestinationStation *Station
-	CurrentTrain       *Train  // nil if not on a train
-	Sentiment          float64 // 0-100, higher is better
+	CurrentTrain       *Train                     // nil if not on a train
+	Itinerary          Itinerary                  // Planned legs, empty if the passenger boards any train
+	leg                int                        // Index of the current leg of the itinerary
+	platform           Platform                   // Platform the passenger is queued on
+	DeniedBoardings    int                        // Times left behind because a train was full
+	Sentiment          float64                    // 0-100, higher is better
+	SentimentModel     *SentimentModel            // Model of how the passenger feels, the default one when nil
+	SentimentImpact    map[SentimentCause]float64 // Total change of sentiment by cause
+	LastSentimentCause SentimentCause             // Cause of the last change of sentiment
+	Commuter           *Commuter                  // Recurring commuter making this trip, nil for one-off passengers
+	Event              *SpecialEvent              // Special event the passenger travels to or from, nil otherwise
+	Fares              *FareModel                 // Charges the passenger at the gates, nil travels for free
+	FarePaid           float64                    // Total paid for the journey so far
+	entry              *Station                   // Station where the passenger went through the gates
+	journal            JourneyRecorder            // Keeps the timeline of the journey, nil keeps none
+	commute            Commute                    // Which daily trip of the commuter this is
+	commuteDay         int                        // Simulated day the commute started
 	State              PassengerState
 	WaitStartTime      time.Time          // When they started waiting
 	JourneyStartTime   time.Time          // When they spawned/started journey
-	lastSentimentDrop  time.Time          // Last time sentiment was decreased
+	lastSentimentDrop  time.Time          // Last time sentiment was evaluated
 	eventChannel       chan<- interface{} // Channel to send events to Tenjin
 	Drawing                               // For future visualization
 }
@@ -39,6 +60,7 @@ type Passenger struct {
 func NewPassenger(
 	id string,
 	name string,
+	persona Persona,
 	currentStation *Station,
 	destinationStation *Station,
 	eventChannel chan<- interface{},
@@ -46,15 +68,17 @@ func NewPassenger(
 	p := &Passenger{
 		ID:                 id,
 		Name:               name,
-		Position:           currentStation.Position, // Start at station position
+		Persona:            persona,
+		Position:           currentStation.GetPosition(), // Start at station position
 		CurrentStation:     currentStation,
 		DestinationStation: destinationStation,
 		CurrentTrain:       nil,
 		Sentiment:          100.0, // Start with perfect satisfaction
+		SentimentImpact:    make(map[SentimentCause]float64),
 		State:              PassengerStateWaiting,
-		WaitStartTime:      time.Now(),
+		WaitStartTime:      Now(),
 		JourneyStartTime:   time.Time{}, // Will be set when boarding
-		lastSentimentDrop:  time.Now(),
+		lastSentimentDrop:  Now(),
 		eventChannel:       eventChannel,
 	}
 
@@ -64,54 +88,257 @@ func NewPassenger(
 	return p
 }
 
-// UpdateSentiment adjusts passenger sentiment based on waiting/riding conditions
+// UpdateSentiment evaluates the sentiment model for the current situation of the
+// passenger, once every interval of the model
 func (p *Passenger) UpdateSentiment(deltaTime time.Duration) {
-	// Only update sentiment every 5 seconds to avoid rapid drops
-	if time.Since(p.lastSentimentDrop) < 5*time.Second {
+	model := p.sentimentModel()
+	if Now().Sub(p.lastSentimentDrop) < model.Config.Interval {
 		return
 	}
+	p.lastSentimentDrop = Now()
 
+	var changes []SentimentChange
+	wait := 0.0
 	switch p.State {
 	case PassengerStateWaiting:
-		// Lose 2 points every 5 seconds of waiting
-		waitTime := time.Since(p.WaitStartTime)
-		if waitTime >= 5*time.Second {
-			p.Sentiment -= 2.0
-			if p.Sentiment < 0 {
-				p.Sentiment = 0
-			}
-			p.lastSentimentDrop = time.Now()
-
-			// Emit frustration event when sentiment drops below 50
-			if p.Sentiment < 50 {
-				p.emitFrustrationEvent()
-			}
-		}
+		// Waits are compared with the headway, which is in simulation time
+		wait = p.waitSeconds()
+		headway := model.Headway(p.platform, p.CurrentStation.ID)
+		changes = model.Waiting(wait, headway, p.CurrentStation.CrowdingLevel(), p.Persona)
+
+	case PassengerStateEntering:
+		wait = p.waitSeconds()
+		changes = model.Entering(p.Persona)
 
 	case PassengerStateRiding:
-		// Minor sentiment decrease for long journeys
-		journeyTime := time.Since(p.JourneyStartTime)
-		if journeyTime > 15*time.Second {
-			p.Sentiment -= 0.5
-			if p.Sentiment < 0 {
-				p.Sentiment = 0
-			}
-			p.lastSentimentDrop = time.Now()
-
-			// Extra penalty if train is crowded
-			if p.CurrentTrain != nil && p.CurrentTrain.IsCrowded() {
-				p.Sentiment -= 1.0
-			}
+		if p.CurrentTrain == nil {
+			return
+		}
+		occupancy := p.CurrentTrain.GetCapacityPercentage() / 100
+		seated := p.CurrentTrain.HasSeat(p)
+		delay := float64(p.CurrentTrain.GetDelay())
+		changes = model.Riding(occupancy, seated, delay, p.Persona)
+	}
+
+	for _, change := range changes {
+		p.changeSentiment(change)
+	}
+
+	// Waiting passengers give up once their patience runs out
+	waiting := p.State == PassengerStateWaiting || p.State == PassengerStateEntering
+	if waiting && p.Persona.Reneges(control.DefaultConfig.Abandonment, wait, p.Sentiment) {
+		p.Abandon(AbandonRenege)
+	}
+}
+
+// changeSentiment applies a change of sentiment and records its cause
+func (p *Passenger) changeSentiment(change SentimentChange) {
+	before := p.Sentiment
+	p.Sentiment = math.Max(0, math.Min(100, p.Sentiment+change.Delta))
+	delta := p.Sentiment - before
+	if delta == 0 {
+		return
+	}
+
+	if p.SentimentImpact == nil {
+		p.SentimentImpact = make(map[SentimentCause]float64)
+	}
+	p.SentimentImpact[change.Cause] += delta
+	p.LastSentimentCause = change.Cause
+	p.emitSentimentEvent(change.Cause, delta)
+
+	// Emit frustration event when sentiment drops below 50
+	if delta < 0 && p.Sentiment < 50 {
+		p.emitFrustrationEvent()
+	}
+}
+
+// MainComplaint returns the cause that cost the passenger the most sentiment,
+// and false when nothing made them unhappy
+func (p *Passenger) MainComplaint() (SentimentCause, bool) {
+	var worst SentimentCause
+	lowest := 0.0
+	for cause, impact := range p.SentimentImpact {
+		if impact < lowest {
+			worst = cause
+			lowest = impact
 		}
 	}
+	return worst, lowest < 0
+}
+
+func (p *Passenger) sentimentModel() *SentimentModel {
+	if p.SentimentModel == nil {
+		return defaultSentimentModel
+	}
+	return p.SentimentModel
+}
+
+// CurrentLeg returns the leg the passenger is waiting for or riding, nil without itinerary.
+func (p *Passenger) CurrentLeg() *Leg {
+	if p.leg >= len(p.Itinerary.Legs) {
+		return nil
+	}
+	return &p.Itinerary.Legs[p.leg]
+}
+
+// nextPlatform returns the platform where the current leg starts.
+func (p *Passenger) nextPlatform() Platform {
+	leg := p.CurrentLeg()
+	if leg == nil {
+		return Platform{}
+	}
+	return Platform{LineID: leg.LineID, Forward: leg.Forward}
+}
+
+// AlightsAt returns true if the passenger leaves the train at the station, either
+// because it is the destination or because the next leg starts there.
+func (p *Passenger) AlightsAt(stationID int64) bool {
+	if leg := p.CurrentLeg(); leg != nil {
+		return leg.To.ID == stationID
+	}
+	return p.DestinationStation.ID == stationID
+}
+
+// platformWalk returns the station where the passenger boards their next train and
+// how long the walk there from st takes, from the fare gates or from another
+// platform. Passengers without an itinerary board at st.
+func (p *Passenger) platformWalk(st *Station, fromGates bool) (*Station, time.Duration) {
+	to := st
+	if leg := p.CurrentLeg(); leg != nil && st.SamePlace(leg.From) {
+		to = leg.From
+	}
+	if fromGates {
+		return to, to.EntranceWalk
+	}
+	return to, st.WalkTo(to)
+}
+
+// waitSeconds returns the simulation seconds the passenger has been waiting
+func (p *Passenger) waitSeconds() float64 {
+	if p.WaitStartTime.IsZero() {
+		return 0
+	}
+	return Now().Sub(p.WaitStartTime).Seconds()
 }
 
 // StartWaiting sets passenger to waiting state
 func (p *Passenger) StartWaiting() {
 	p.State = PassengerStateWaiting
-	p.WaitStartTime = time.Now()
-	p.lastSentimentDrop = time.Now()
+	p.WaitStartTime = Now()
+	p.lastSentimentDrop = Now()
 	p.emitWaitEvent()
+	p.record(JourneyWait, p.CurrentStation, nil, "")
+}
+
+// Balks returns true if the passenger would rather leave than queue at the station,
+// counting the platform of the complex where their first train leaves from
+func (p *Passenger) Balks(station *Station) bool {
+	platform, _ := p.platformWalk(station, true)
+	queue := len(platform.GetPlatformQueue(p.nextPlatform())) + station.GetEntryQueueCount()
+	return p.Persona.Balks(control.DefaultConfig.Abandonment, queue)
+}
+
+// Abandon makes the passenger leave the station without travelling, it is lost ridership
+func (p *Passenger) Abandon(reason AbandonReason) {
+	if p.CurrentStation != nil {
+		p.CurrentStation.RemovePassenger(p)
+	}
+	p.State = PassengerStateAbandoned
+	p.emitAbandonEvent(reason)
+	p.record(JourneyAbandon, p.CurrentStation, nil, reason.Description())
+	p.endCommute(true)
+	p.endEventTrip(true)
+}
+
+// StartCommute makes the passenger a trip of a commuter, who brings the satisfaction
+// of previous days with them
+func (p *Passenger) StartCommute(commuter *Commuter, commute Commute, day int) {
+	p.Commuter = commuter
+	p.commute = commute
+	p.commuteDay = day
+	p.Sentiment = commuter.History().Satisfaction
+}
+
+// endCommute lets the commuter remember how the trip went
+func (p *Passenger) endCommute(abandoned bool) {
+	if p.Commuter == nil {
+		return
+	}
+
+	complaint, _ := p.MainComplaint()
+	lineID := int64(0)
+	if leg := p.CurrentLeg(); leg != nil {
+		lineID = leg.LineID
+	}
+	p.Commuter.RecordTrip(control.DefaultConfig.Commuters, CommuteResult{
+		Commute:   p.commute,
+		Day:       p.commuteDay,
+		Sentiment: p.Sentiment,
+		Complaint: complaint,
+		LineID:    lineID,
+		Abandoned: abandoned,
+	})
+	p.emitCommuteEvent(abandoned)
+}
+
+// endEventTrip reports how the trip to or from a special event went
+func (p *Passenger) endEventTrip(abandoned bool) {
+	if p.Event != nil {
+		p.emitEventTripEvent(abandoned)
+	}
+}
+
+// StartTimeline keeps the timeline of the journey in the recorder from now on,
+// starting at the station where the passenger is
+func (p *Passenger) StartTimeline(recorder JourneyRecorder) {
+	p.journal = recorder
+	p.record(JourneySpawn, p.CurrentStation, nil, p.DestinationStation.Name)
+}
+
+// record adds a moment of the journey to the timeline of the passenger
+func (p *Passenger) record(eventType string, st *Station, train *Train, detail string) {
+	if p.journal == nil {
+		return
+	}
+	event := JourneyEvent{
+		PassengerID: p.ID,
+		Type:        eventType,
+		Sentiment:   p.Sentiment,
+		Detail:      detail,
+	}
+	if st != nil {
+		event.StationID = st.ID
+		event.StationName = st.Name
+	}
+	if train != nil {
+		event.TrainID = train.ID
+		event.TrainName = train.Name
+	}
+	p.journal.Record(event)
+}
+
+// payEntry charges the entry fare when the passenger goes through the gates of st
+func (p *Passenger) payEntry(st *Station) {
+	p.entry = st
+	if p.Fares != nil {
+		p.pay(st, GateEntry, p.Fares.EntryFare(p.Persona))
+	}
+}
+
+// payExit charges the rest of the fare when the passenger leaves at st
+func (p *Passenger) payExit(st *Station) {
+	if p.Fares != nil {
+		p.pay(st, GateExit, p.Fares.ExitFare(p.Persona, p.entry, st, p.Itinerary))
+	}
+}
+
+func (p *Passenger) pay(st *Station, gate string, amount float64) {
+	if amount <= 0 {
+		return
+	}
+	p.FarePaid += amount
+	p.emitFareEvent(st, gate, amount)
 }
 
 // BoardTrain puts passenger on a train
@@ -120,34 +347,63 @@ func (p *Passenger) BoardTrain(train *Train) {
 	p.CurrentTrain = train
 	p.Position = train.Position
 	p.State = PassengerStateRiding
-	p.JourneyStartTime = time.Now()  // Start tracking journey time
-	p.WaitStartTime = time.Time{}    // Clear wait timer
-	p.lastSentimentDrop = time.Now() // Reset sentiment drop timer
+	p.JourneyStartTime = Now()    // Start tracking journey time
+	p.WaitStartTime = time.Time{} // Clear wait timer
+	p.lastSentimentDrop = Now()   // Reset sentiment drop timer
 	p.emitBoardEvent()
+	p.record(JourneyBoard, p.CurrentStation, train, "")
+}
+
+// DenyBoarding is called when the passenger is left behind because the train is full
+func (p *Passenger) DenyBoarding(train *Train) {
+	p.DeniedBoardings++
+	p.changeSentiment(p.sentimentModel().DeniedBoarding())
+	p.emitDeniedBoardingEvent(train)
+	p.record(JourneyDeniedBoarding, p.CurrentStation, train, "")
 }
 
 // DisembarkTrain removes passenger from train
 func (p *Passenger) DisembarkTrain(station *Station) {
+	train := p.CurrentTrain
 	p.State = PassengerStateDisembarking
 	p.CurrentTrain = nil
 	p.CurrentStation = station
-	p.Position = station.Position
+	p.Position = station.GetPosition()
 
 	// Always emit disembark event when leaving a train
 	p.emitDisembarkEvent()
+	p.record(JourneyDisembark, station, train, "")
 
-	// Check if arrived at destination
-	if station.ID == p.DestinationStation.ID {
+	// Check if arrived at destination, any station of its complex will do
+	if station.SamePlace(p.DestinationStation) {
+		p.payExit(station)
 		p.State = PassengerStateArrived
 		p.emitArriveEvent()
+		p.record(JourneyArrive, station, nil, "")
 		p.JourneyStartTime = time.Time{} // Clear journey timer
+		p.endCommute(false)
+		p.endEventTrip(false)
 	} else {
-		// Transfer - start waiting again
+		// Transfer - start waiting for the next leg
+		if leg := p.CurrentLeg(); leg != nil && leg.To.ID == station.ID {
+			p.leg++
+			p.changeSentiment(p.sentimentModel().Transfer())
+			p.record(JourneyTransfer, station, nil, "")
+		}
 		p.State = PassengerStateWaiting
-		p.WaitStartTime = time.Now()
+		p.WaitStartTime = Now()
 		p.JourneyStartTime = time.Time{} // Reset for next leg
-		p.lastSentimentDrop = time.Now() // Reset sentiment drop timer
+		p.lastSentimentDrop = Now()      // Reset sentiment drop timer
 		p.emitWaitEvent()
+		p.record(JourneyWait, station, nil, "")
+	}
+}
+
+// continueFrom is called when the train sets the passenger down before the end of
+// the current leg, the leg goes on from the station with the next train.
+func (p *Passenger) continueFrom(station *Station) {
+	if leg := p.CurrentLeg(); leg != nil && leg.To.ID != station.ID {
+		leg.From = station
 	}
 }
 
@@ -169,9 +425,10 @@ func (p *Passenger) GetSentimentCategory() string {
 
 // String returns a string representation of the passenger
 func (p *Passenger) String() string {
-	return fmt.Sprintf("%s (%s) at %s → %s [%s, %.0f%%]",
+	return fmt.Sprintf("%s (%s, %s) at %s → %s [%s, %.0f%%]",
 		p.Name,
 		p.ID,
+		p.Persona.Label,
 		p.CurrentStation.Name,
 		p.DestinationStation.Name,
 		p.State,
@@ -190,6 +447,7 @@ func (p *Passenger) emitSpawnEvent() {
 		Type            string
 		PassengerID     string
 		PassengerName   string
+		Persona         string
 		StationID       int64
 		StationName     string
 		DestinationID   int64
@@ -199,18 +457,15 @@ func (p *Passenger) emitSpawnEvent() {
 		Type:            "passenger_spawn",
 		PassengerID:     p.ID,
 		PassengerName:   p.Name,
+		Persona:         string(p.Persona.Kind),
 		StationID:       p.CurrentStation.ID,
 		StationName:     p.CurrentStation.Name,
 		DestinationID:   p.DestinationStation.ID,
 		DestinationName: p.DestinationStation.Name,
-		Time:            time.Now(),
+		Time:            Now(),
 	}
 
-	select {
-	case p.eventChannel <- event:
-	default:
-		// Channel full, skip event
-	}
+	emit(p.eventChannel, event)
 }
 
 func (p *Passenger) emitWaitEvent() {
@@ -233,16 +488,12 @@ func (p *Passenger) emitWaitEvent() {
 		PassengerName: p.Name,
 		StationID:     p.CurrentStation.ID,
 		StationName:   p.CurrentStation.Name,
-		WaitDuration:  time.Since(p.WaitStartTime),
+		WaitDuration:  Now().Sub(p.WaitStartTime),
 		Sentiment:     p.Sentiment,
-		Time:          time.Now(),
+		Time:          Now(),
 	}
 
-	select {
-	case p.eventChannel <- event:
-	default:
-		// Channel full, skip event
-	}
+	emit(p.eventChannel, event)
 }
 
 func (p *Passenger) emitBoardEvent() {
@@ -267,14 +518,40 @@ func (p *Passenger) emitBoardEvent() {
 		StationID:     p.CurrentStation.ID,
 		StationName:   p.CurrentStation.Name,
 		Sentiment:     p.Sentiment,
-		Time:          time.Now(),
+		Time:          Now(),
 	}
 
-	select {
-	case p.eventChannel <- event:
-	default:
-		// Channel full, skip event
+	emit(p.eventChannel, event)
+}
+
+func (p *Passenger) emitDeniedBoardingEvent(train *Train) {
+	if p.eventChannel == nil {
+		return
 	}
+
+	event := struct {
+		Type          string
+		PassengerID   string
+		PassengerName string
+		TrainName     string
+		StationID     int64
+		StationName   string
+		DeniedCount   int
+		Sentiment     float64
+		Time          time.Time
+	}{
+		Type:          "passenger_denied_boarding",
+		PassengerID:   p.ID,
+		PassengerName: p.Name,
+		TrainName:     train.Name,
+		StationID:     p.CurrentStation.ID,
+		StationName:   p.CurrentStation.Name,
+		DeniedCount:   p.DeniedBoardings,
+		Sentiment:     p.Sentiment,
+		Time:          Now(),
+	}
+
+	emit(p.eventChannel, event)
 }
 
 func (p *Passenger) emitDisembarkEvent() {
@@ -297,14 +574,10 @@ func (p *Passenger) emitDisembarkEvent() {
 		StationID:     p.CurrentStation.ID,
 		StationName:   p.CurrentStation.Name,
 		Sentiment:     p.Sentiment,
-		Time:          time.Now(),
+		Time:          Now(),
 	}
 
-	select {
-	case p.eventChannel <- event:
-	default:
-		// Channel full, skip event
-	}
+	emit(p.eventChannel, event)
 }
 
 func (p *Passenger) emitArriveEvent() {
@@ -312,7 +585,7 @@ func (p *Passenger) emitArriveEvent() {
 		return
 	}
 
-	journeyDuration := time.Since(p.JourneyStartTime)
+	journeyDuration := Now().Sub(p.JourneyStartTime)
 
 	event := struct {
 		Type            string
@@ -331,14 +604,177 @@ func (p *Passenger) emitArriveEvent() {
 		DestinationName: p.DestinationStation.Name,
 		JourneyDuration: journeyDuration,
 		Sentiment:       p.Sentiment,
-		Time:            time.Now(),
+		Time:            Now(),
 	}
 
-	select {
-	case p.eventChannel <- event:
-	default:
-		// Channel full, skip event
+	emit(p.eventChannel, event)
+}
+
+func (p *Passenger) emitAbandonEvent(reason AbandonReason) {
+	if p.eventChannel == nil {
+		return
 	}
+
+	event := struct {
+		Type          string
+		PassengerID   string
+		PassengerName string
+		StationID     int64
+		StationName   string
+		Reason        string
+		WaitDuration  time.Duration
+		Sentiment     float64
+		Time          time.Time
+	}{
+		Type:          "passenger_abandon",
+		PassengerID:   p.ID,
+		PassengerName: p.Name,
+		StationID:     p.CurrentStation.ID,
+		StationName:   p.CurrentStation.Name,
+		Reason:        string(reason),
+		WaitDuration:  Now().Sub(p.WaitStartTime),
+		Sentiment:     p.Sentiment,
+		Time:          Now(),
+	}
+
+	emit(p.eventChannel, event)
+}
+
+func (p *Passenger) emitCommuteEvent(abandoned bool) {
+	if p.eventChannel == nil {
+		return
+	}
+
+	history := p.Commuter.History()
+	event := struct {
+		Type          string
+		CommuterID    string
+		CommuterName  string
+		HomeStation   string
+		WorkStation   string
+		TripSentiment float64
+		Satisfaction  float64
+		Abandoned     bool
+		Trips         int
+		BadDays       int
+		Time          time.Time
+	}{
+		Type:          "commuter_trip",
+		CommuterID:    p.Commuter.ID,
+		CommuterName:  p.Commuter.Name,
+		HomeStation:   p.Commuter.Home.Name,
+		WorkStation:   p.Commuter.Work.Name,
+		TripSentiment: p.Sentiment,
+		Satisfaction:  history.Satisfaction,
+		Abandoned:     abandoned,
+		Trips:         history.Trips,
+		BadDays:       history.BadDays,
+		Time:          Now(),
+	}
+
+	emit(p.eventChannel, event)
+}
+
+func (p *Passenger) emitEventTripEvent(abandoned bool) {
+	if p.eventChannel == nil {
+		return
+	}
+
+	event := struct {
+		Type            string
+		EventID         int64
+		EventName       string
+		StationID       int64
+		StationName     string
+		Attendance      int
+		PassengerID     string
+		Arriving        bool // Travelling to the event, false when going home
+		Abandoned       bool
+		DeniedBoardings int
+		Sentiment       float64
+		Time            time.Time
+	}{
+		Type:            "special_event_trip",
+		EventID:         p.Event.ID,
+		EventName:       p.Event.Name,
+		StationID:       p.Event.Station.ID,
+		StationName:     p.Event.Station.Name,
+		Attendance:      p.Event.Attendance,
+		PassengerID:     p.ID,
+		Arriving:        p.DestinationStation.SamePlace(p.Event.Station),
+		Abandoned:       abandoned,
+		DeniedBoardings: p.DeniedBoardings,
+		Sentiment:       p.Sentiment,
+		Time:            Now(),
+	}
+
+	emit(p.eventChannel, event)
+}
+
+func (p *Passenger) emitFareEvent(st *Station, gate string, amount float64) {
+	if p.eventChannel == nil {
+		return
+	}
+
+	// The fare is shared between the lines of the journey
+	lines := make([]string, 0, len(p.Itinerary.Legs))
+	for _, leg := range p.Itinerary.Legs {
+		lines = append(lines, leg.LineName)
+	}
+
+	event := struct {
+		Type          string
+		PassengerID   string
+		PassengerName string
+		Persona       string
+		StationID     int64
+		StationName   string
+		Gate          string
+		Amount        float64
+		Lines         []string
+		SimTime       int
+		Time          time.Time
+	}{
+		Type:          "passenger_fare",
+		PassengerID:   p.ID,
+		PassengerName: p.Name,
+		Persona:       string(p.Persona.Kind),
+		StationID:     st.ID,
+		StationName:   st.Name,
+		Gate:          gate,
+		Amount:        amount,
+		Lines:         lines,
+		SimTime:       p.Fares.timeOfDay(),
+		Time:          Now(),
+	}
+
+	emit(p.eventChannel, event)
+}
+
+func (p *Passenger) emitSentimentEvent(cause SentimentCause, delta float64) {
+	if p.eventChannel == nil {
+		return
+	}
+
+	event := struct {
+		Type          string
+		PassengerID   string
+		PassengerName string
+		Cause         string
+		Delta         float64
+		Sentiment     float64
+		Time          time.Time
+	}{
+		Type:          "passenger_sentiment",
+		PassengerID:   p.ID,
+		PassengerName: p.Name,
+		Cause:         string(cause),
+		Delta:         delta,
+		Sentiment:     p.Sentiment,
+		Time:          Now(),
+	}
+
+	emit(p.eventChannel, event)
 }
 
 func (p *Passenger) emitFrustrationEvent() {
@@ -360,23 +796,22 @@ func (p *Passenger) emitFrustrationEvent() {
 		PassengerName: p.Name,
 		Sentiment:     p.Sentiment,
 		Category:      p.GetSentimentCategory(),
-		Reason:        fmt.Sprintf("Waiting for %.0f seconds", time.Since(p.WaitStartTime).Seconds()),
-		Time:          time.Now(),
+		Reason:        p.LastSentimentCause.Description(),
+		Time:          Now(),
 	}
 
-	select {
-	case p.eventChannel <- event:
-	default:
-		// Channel full, skip event
-	}
+	emit(p.eventChannel, event)
+}
+
+// Tick is the passenger phase of a tick: the sentiment and patience of the passenger.
+func (p *Passenger) Tick() {
+	p.UpdateSentiment(control.DefaultConfig.LoopDuration)
 }
 
 // Display methods (for future visualization)
 
 func (p *Passenger) Update() {
 	p.Drawing.Counter++
-	// Update sentiment based on time
-	p.UpdateSentiment(time.Second / 60) // Called every tick
 }
 
 func (p *Passenger) Draw(screen *ebiten.Image) {
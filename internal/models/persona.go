@@ -0,0 +1,142 @@
+package models
+
+import "math/rand"
+
+// PersonaKind identifies a type of passenger, it is what gets persisted.
+type PersonaKind string
+
+const (
+	PersonaCommuter PersonaKind = "commuter"
+	PersonaStudent  PersonaKind = "student"
+	PersonaTourist  PersonaKind = "tourist"
+	PersonaElderly  PersonaKind = "elderly"
+)
+
+// PersonaWeight is how likely a persona is to spawn during a time band,
+// relative to the other personas.
+type PersonaWeight struct {
+	Start  int // Seconds since midnight, included
+	End    int // Seconds since midnight, excluded
+	Weight float64
+}
+
+// Persona describes how a type of passenger behaves.
+type Persona struct {
+	Kind              PersonaKind
+	Label             string
+	Weights           []PersonaWeight // Spawn weight per time band
+	DefaultWeight     float64         // Spawn weight outside of the bands
+	Patience          float64         // Multiplier of how long they wait before losing sentiment, 1 is average
+	CrowdingTolerance float64         // Train occupancy (0-1) above which the ride becomes uncomfortable
+	WalkingSpeed      float64         // Meters per second
+}
+
+// WeightAt returns the spawn weight of the persona at the time of day.
+func (pe Persona) WeightAt(timeOfDay int) float64 {
+	for _, w := range pe.Weights {
+		if timeOfDay >= w.Start && timeOfDay < w.End {
+			return w.Weight
+		}
+	}
+	return pe.DefaultWeight
+}
+
+// Impatience returns how much more than average the persona suffers from waiting.
+func (pe Persona) Impatience() float64 {
+	return 1 / pe.patience()
+}
+
+// IsCrowded returns true if the occupancy (0-1) of a train is above what the persona tolerates.
+func (pe Persona) IsCrowded(occupancy float64) bool {
+	tolerance := pe.CrowdingTolerance
+	if tolerance <= 0 {
+		tolerance = 0.8
+	}
+	return occupancy > tolerance
+}
+
+const secondsPerHour = 3600
+
+// DefaultPersonas are the personas used by the simulation.
+var DefaultPersonas = []Persona{
+	{
+		Kind:  PersonaCommuter,
+		Label: "Commuter",
+		Weights: []PersonaWeight{
+			{6 * secondsPerHour, 10 * secondsPerHour, 6},
+			{16 * secondsPerHour, 20 * secondsPerHour, 6},
+		},
+		DefaultWeight:     1.5,
+		Patience:          0.7,
+		CrowdingTolerance: 0.9,
+		WalkingSpeed:      1.5,
+	},
+	{
+		Kind:  PersonaStudent,
+		Label: "Student",
+		Weights: []PersonaWeight{
+			{6 * secondsPerHour, 8 * secondsPerHour, 3},
+			{12 * secondsPerHour, 18 * secondsPerHour, 3},
+			{22 * secondsPerHour, 24 * secondsPerHour, 0.5},
+		},
+		DefaultWeight:     1,
+		Patience:          1,
+		CrowdingTolerance: 0.95,
+		WalkingSpeed:      1.4,
+	},
+	{
+		Kind:  PersonaTourist,
+		Label: "Tourist",
+		Weights: []PersonaWeight{
+			{0, 8 * secondsPerHour, 0.1},
+			{10 * secondsPerHour, 19 * secondsPerHour, 2},
+		},
+		DefaultWeight:     0.5,
+		Patience:          1.3,
+		CrowdingTolerance: 0.7,
+		WalkingSpeed:      1.1,
+	},
+	{
+		Kind:  PersonaElderly,
+		Label: "Elderly",
+		Weights: []PersonaWeight{
+			{0, 7 * secondsPerHour, 0.1},
+			{9 * secondsPerHour, 16 * secondsPerHour, 2},
+			{19 * secondsPerHour, 24 * secondsPerHour, 0.2},
+		},
+		DefaultWeight:     0.4,
+		Patience:          1.5,
+		CrowdingTolerance: 0.6,
+		WalkingSpeed:      0.9,
+	},
+}
+
+// PersonaByKind returns the default persona of the kind, commuters for unknown kinds.
+func PersonaByKind(kind PersonaKind) Persona {
+	for _, pe := range DefaultPersonas {
+		if pe.Kind == kind {
+			return pe
+		}
+	}
+	return DefaultPersonas[0]
+}
+
+// PickPersona picks a persona at random following their spawn weights at the time of day.
+func PickPersona(rng *rand.Rand, personas []Persona, timeOfDay int) Persona {
+	total := 0.0
+	for _, pe := range personas {
+		total += pe.WeightAt(timeOfDay)
+	}
+	if total <= 0 {
+		return personas[rng.Intn(len(personas))]
+	}
+
+	r := rng.Float64() * total
+	for _, pe := range personas {
+		r -= pe.WeightAt(timeOfDay)
+		if r < 0 {
+			return pe
+		}
+	}
+	return personas[len(personas)-1]
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestPickPersonaFollowsWeights(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	personas := []Persona{
+		{Kind: PersonaCommuter, Weights: []PersonaWeight{{0, 3600, 3}}, DefaultWeight: 0},
+		{Kind: PersonaTourist, DefaultWeight: 1},
+	}
+
+	commuters := 0
+	for i := 0; i < 4000; i++ {
+		if PickPersona(rng, personas, 1800).Kind == PersonaCommuter {
+			commuters++
+		}
+	}
+	if commuters < 2800 || commuters > 3200 {
+		t.Fatal("Commuters should be 3 times more likely than tourists in the band.")
+	}
+	for i := 0; i < 100; i++ {
+		if PickPersona(rng, personas, 7200).Kind == PersonaCommuter {
+			t.Fatal("Commuters should not spawn outside of their band.")
+		}
+	}
+}
+
+func TestPersonaBehaviour(t *testing.T) {
+	if PersonaByKind(PersonaElderly).Impatience() >= PersonaByKind(PersonaCommuter).Impatience() {
+		t.Fatal("Elderly passengers should be more patient than commuters.")
+	}
+	if !PersonaByKind(PersonaElderly).IsCrowded(0.7) || PersonaByKind(PersonaCommuter).IsCrowded(0.7) {
+		t.Fatal("Elderly passengers should tolerate less crowding than commuters.")
+	}
+	if PersonaByKind("unknown").Kind != PersonaCommuter {
+		t.Fatal("Unknown personas should fall back to commuters.")
+	}
+}
+
+func TestNameGenerator(t *testing.T) {
+	ng := NewNameGenerator("es_DO", rand.New(rand.NewSource(1)))
+	if len(strings.Fields(ng.Name())) < 3 {
+		t.Fatal("Dominican names should have two surnames.")
+	}
+	ng = NewNameGenerator("xx", rand.New(rand.NewSource(1)))
+	if len(strings.Fields(ng.Name())) != 2 {
+		t.Fatal("Unknown locales should use the default one.")
+	}
+}
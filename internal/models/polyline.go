@@ -0,0 +1,191 @@
+package models
+
+import (
+	"errors"
+	"math"
+)
+
+// Polyline is an ordered list of points with precomputed cumulative lengths,
+// used to answer distance queries along a track.
+type Polyline struct {
+	points     []Vector
+	cumulative []float64 // cumulative[i] is the distance from points[0] to points[i]
+}
+
+// NewPolyline builds a polyline from a list of points.
+// Consecutive duplicated points are dropped since they add no length.
+func NewPolyline(points []Vector) Polyline {
+	pl := Polyline{
+		points:     make([]Vector, 0, len(points)),
+		cumulative: make([]float64, 0, len(points)),
+	}
+	for _, p := range points {
+		n := len(pl.points)
+		if n == 0 {
+			pl.points = append(pl.points, p)
+			pl.cumulative = append(pl.cumulative, 0)
+			continue
+		}
+		d := pl.points[n-1].Dist(p)
+		if d == 0 {
+			continue
+		}
+		pl.points = append(pl.points, p)
+		pl.cumulative = append(pl.cumulative, pl.cumulative[n-1]+d)
+	}
+	return pl
+}
+
+// Points returns a copy of the points of the polyline.
+func (pl Polyline) Points() []Vector {
+	points := make([]Vector, len(pl.points))
+	copy(points, pl.points)
+	return points
+}
+
+// Length returns the total length of the polyline.
+func (pl Polyline) Length() float64 {
+	if len(pl.cumulative) == 0 {
+		return 0
+	}
+	return pl.cumulative[len(pl.cumulative)-1]
+}
+
+// segmentAt returns the index of the segment that contains the distance s.
+// The distance must already be clamped to [0, Length()].
+func (pl Polyline) segmentAt(s float64) int {
+	// Binary search for the first cumulative value greater than s.
+	lo, hi := 1, len(pl.cumulative)-1
+	for lo < hi {
+		mid := (lo + hi) / 2
+		if pl.cumulative[mid] < s {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	return lo - 1
+}
+
+// PositionAt returns the point located at distance s from the start of the
+// polyline and the heading (in radians) of the segment it lies on.
+// Distances outside of the polyline are clamped to its ends.
+func (pl Polyline) PositionAt(s float64) (Vector, float64, error) {
+	switch len(pl.points) {
+	case 0:
+		return Vector{}, 0, errors.New("the polyline has no points")
+	case 1:
+		return pl.points[0], 0, nil
+	}
+
+	s = math.Max(0, math.Min(s, pl.Length()))
+	i := pl.segmentAt(s)
+	a, b := pl.points[i], pl.points[i+1]
+	segment := b.SoftSub(a)
+	t := (s - pl.cumulative[i]) / (pl.cumulative[i+1] - pl.cumulative[i])
+
+	position := a.SoftAdd(segment.SoftScale(t))
+	return position, segment.Angle(), nil
+}
+
+// Projection is the result of projecting a point onto a polyline.
+type Projection struct {
+	Point    Vector  // Closest point on the polyline
+	Along    float64 // Distance from the start of the polyline to Point
+	Distance float64 // Distance from the queried point to Point
+	Heading  float64 // Heading (in radians) of the segment that contains Point
+}
+
+// Project returns the closest point of the polyline to p.
+func (pl Polyline) Project(p Vector) (Projection, error) {
+	switch len(pl.points) {
+	case 0:
+		return Projection{}, errors.New("the polyline has no points")
+	case 1:
+		return Projection{Point: pl.points[0], Distance: pl.points[0].Dist(p)}, nil
+	}
+
+	best := Projection{Distance: math.Inf(1)}
+	for i := 0; i < len(pl.points)-1; i++ {
+		point, t := closestOnSegment(pl.points[i], pl.points[i+1], p)
+		d := point.Dist(p)
+		if d < best.Distance {
+			segment := pl.points[i+1].SoftSub(pl.points[i])
+			best = Projection{
+				Point:    point,
+				Along:    pl.cumulative[i] + t*(pl.cumulative[i+1]-pl.cumulative[i]),
+				Distance: d,
+				Heading:  segment.Angle(),
+			}
+		}
+	}
+	return best, nil
+}
+
+// Bounds returns the top-left and bottom-right corners of the polyline's bounding box.
+func (pl Polyline) Bounds() (Vector, Vector) {
+	if len(pl.points) == 0 {
+		return Vector{}, Vector{}
+	}
+	min, max := pl.points[0], pl.points[0]
+	for _, p := range pl.points[1:] {
+		min.X = math.Min(min.X, p.X)
+		min.Y = math.Min(min.Y, p.Y)
+		max.X = math.Max(max.X, p.X)
+		max.Y = math.Max(max.Y, p.Y)
+	}
+	return min, max
+}
+
+// closestOnSegment returns the closest point to p on the segment ab and its
+// position along the segment as a fraction between 0 and 1.
+func closestOnSegment(a, b, p Vector) (Vector, float64) {
+	ab := b.SoftSub(a)
+	lengthSq := ab.X*ab.X + ab.Y*ab.Y
+	if lengthSq == 0 {
+		return a, 0
+	}
+	ap := p.SoftSub(a)
+	t := (ap.X*ab.X + ap.Y*ab.Y) / lengthSq
+	t = math.Max(0, math.Min(1, t))
+	return a.SoftAdd(ab.SoftScale(t)), t
+}
+
+// OffsetPoints returns the points shifted laterally by offset, to the right of the
+// travel direction for positive values. Corners are mitered, with the miter length
+// capped to twice the offset to avoid spikes on sharp turns.
+func OffsetPoints(points []Vector, offset float64) []Vector {
+	pts := NewPolyline(points).points
+	if offset == 0 || len(pts) < 2 {
+		return pts
+	}
+
+	normals := make([]Vector, len(pts)-1)
+	for i := range normals {
+		d := pts[i+1].SoftSub(pts[i])
+		d.Normalize()
+		normals[i] = NewVector(-d.Y, d.X)
+	}
+
+	result := make([]Vector, len(pts))
+	for i, p := range pts {
+		var n Vector
+		switch i {
+		case 0:
+			n = normals[0]
+		case len(pts) - 1:
+			n = normals[len(normals)-1]
+		default:
+			n = normals[i-1].SoftAdd(normals[i])
+			if n.Magnitude() == 0 {
+				// The track turns back on itself.
+				n = normals[i]
+			}
+			n.Normalize()
+			cos := n.X*normals[i].X + n.Y*normals[i].Y
+			n.Scale(1 / math.Max(cos, 0.5))
+		}
+		result[i] = p.SoftAdd(n.SoftScale(offset))
+	}
+	return result
+}
@@ -0,0 +1,132 @@
+package models
+
+import (
+	"math"
+	"testing"
+)
+
+func newLPolyline() Polyline {
+	// (0,0) -> (10,0) -> (10,5)
+	return NewPolyline([]Vector{
+		NewVector(0, 0),
+		NewVector(10, 0),
+		NewVector(10, 0),
+		NewVector(10, 5),
+	})
+}
+
+func TestPolylineLength(t *testing.T) {
+	pl := newLPolyline()
+
+	if RoundFloat(pl.Length(), 2) != 15.0 {
+		t.Fatal("The polyline length was calculated incorrectly.")
+	}
+	if len(pl.Points()) != 3 {
+		t.Fatal("Duplicated points should have been dropped.")
+	}
+}
+
+func TestPolylineEmpty(t *testing.T) {
+	pl := NewPolyline(nil)
+
+	if pl.Length() != 0 {
+		t.Fatal("An empty polyline should have no length.")
+	}
+	if _, _, err := pl.PositionAt(1); err == nil {
+		t.Fatal("An empty polyline should not have positions.")
+	}
+	if _, err := pl.Project(NewVector(1, 1)); err == nil {
+		t.Fatal("An empty polyline should not have projections.")
+	}
+}
+
+func TestPolylinePositionAt(t *testing.T) {
+	pl := newLPolyline()
+
+	pos, heading, err := pl.PositionAt(4)
+	if err != nil {
+		t.Fatal("The position should exist.")
+	}
+	if RoundFloat(pos.X, 2) != 4.0 || RoundFloat(pos.Y, 2) != 0.0 {
+		t.Fatal("The position on the first segment is incorrect.")
+	}
+	if RoundFloat(heading, 2) != 0.0 {
+		t.Fatal("The heading on the first segment is incorrect.")
+	}
+
+	pos, heading, _ = pl.PositionAt(12)
+	if RoundFloat(pos.X, 2) != 10.0 || RoundFloat(pos.Y, 2) != 2.0 {
+		t.Fatal("The position on the second segment is incorrect.")
+	}
+	if RoundFloat(heading, 2) != RoundFloat(math.Pi/2, 2) {
+		t.Fatal("The heading on the second segment is incorrect.")
+	}
+}
+
+func TestPolylinePositionAtClamps(t *testing.T) {
+	pl := newLPolyline()
+
+	pos, _, _ := pl.PositionAt(-3)
+	if pos.X != 0 || pos.Y != 0 {
+		t.Fatal("Negative distances should clamp to the start.")
+	}
+	pos, _, _ = pl.PositionAt(100)
+	if pos.X != 10 || pos.Y != 5 {
+		t.Fatal("Distances past the end should clamp to the end.")
+	}
+}
+
+func TestPolylineProject(t *testing.T) {
+	pl := newLPolyline()
+
+	proj, err := pl.Project(NewVector(3, 2))
+	if err != nil {
+		t.Fatal("The projection should exist.")
+	}
+	if RoundFloat(proj.Point.X, 2) != 3.0 || RoundFloat(proj.Point.Y, 2) != 0.0 {
+		t.Fatal("The projected point is incorrect.")
+	}
+	if RoundFloat(proj.Along, 2) != 3.0 {
+		t.Fatal("The distance along the polyline is incorrect.")
+	}
+	if RoundFloat(proj.Distance, 2) != 2.0 {
+		t.Fatal("The distance to the polyline is incorrect.")
+	}
+
+	proj, _ = pl.Project(NewVector(13, 4))
+	if RoundFloat(proj.Along, 2) != 14.0 {
+		t.Fatal("The projection on the second segment is incorrect.")
+	}
+	if RoundFloat(proj.Distance, 2) != 3.0 {
+		t.Fatal("The distance to the second segment is incorrect.")
+	}
+}
+
+func TestPolylineBounds(t *testing.T) {
+	pl := newLPolyline()
+	min, max := pl.Bounds()
+
+	if min.X != 0 || min.Y != 0 || max.X != 10 || max.Y != 5 {
+		t.Fatal("The bounds of the polyline are incorrect.")
+	}
+}
+
+func TestOffsetPoints(t *testing.T) {
+	// Heading east, the right side is +Y on screen.
+	pts := OffsetPoints([]Vector{NewVector(0, 0), NewVector(10, 0)}, 2)
+	if pts[0].X != 0 || RoundFloat(pts[0].Y, 2) != 2.0 || RoundFloat(pts[1].Y, 2) != 2.0 {
+		t.Fatal("The points were not offset to the right of the direction.")
+	}
+
+	// The reversed direction should end up on the other side.
+	rev := OffsetPoints([]Vector{NewVector(10, 0), NewVector(0, 0)}, 2)
+	if RoundFloat(rev[0].Y, 2) != -2.0 {
+		t.Fatal("The reversed points were not offset to their right.")
+	}
+
+	// Corners keep the same distance to both segments.
+	corner := OffsetPoints(newLPolyline().Points(), 1)
+	if RoundFloat(corner[1].X, 2) != 9.0 || RoundFloat(corner[1].Y, 2) != 1.0 {
+		t.Fatal("The corner was not mitered correctly.")
+	}
+}
@@ -0,0 +1,252 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+)
+
+// ArrivalPrediction is when a train is expected at a station, made by the train from
+// where it is, how fast it goes, its make and the stops in between.
+type ArrivalPrediction struct {
+	TrainID     int64
+	TrainName   string
+	StationID   int64
+	StationName string
+	Platform    Platform // Platform the train leaves the station from
+	ETA         float64  // Simulation seconds until the arrival
+	Issued      int      // Simulation seconds since the first midnight when it was made
+	AtPlatform  bool     // The train is already stopped at the station
+}
+
+// Arrival returns the expected arrival in simulation seconds since the first midnight.
+func (ap ArrivalPrediction) Arrival() int {
+	return ap.Issued + int(math.Round(ap.ETA))
+}
+
+// Countdown describes the prediction for the boards, like "Due" or "3 min".
+func (ap ArrivalPrediction) Countdown() string {
+	if ap.AtPlatform {
+		return "At platform"
+	}
+	if ap.ETA < 30 {
+		return "Due"
+	}
+	return fmt.Sprintf("%d min", int(math.Round(ap.ETA/60)))
+}
+
+// TrainArrival is the last arrival of a train at a station.
+type TrainArrival struct {
+	StationID int64
+	Platform  Platform // Platform the train leaves the station from
+	Time      int      // Simulation seconds since the first midnight
+}
+
+// travelTicks returns how many ticks a train of the given make takes to go through
+// the waypoints of a route from its position and speed. It follows the movement of
+// Train.Move on a straight line: the train speeds up towards each waypoint, eases
+// off in the slowing zone and stops on it.
+func travelTicks(model Make, routeStart, position Vector, speed float64, waypoints []Vector) int {
+	ticks := 0
+	for _, waypoint := range waypoints {
+		distance := position.Dist(waypoint)
+		where := routeStart.Dist(waypoint) / 4
+		covered := 0.0
+		for distance-covered > 1 && ticks < maxTravelTicks {
+			acc := model.AccMag
+			if remaining := distance - covered; remaining < where {
+				acc = Map(remaining, 0, where, 0, model.AccMag)
+			}
+			speed = math.Min(speed+acc, model.TopSpeed)
+			if speed <= 0 {
+				return maxTravelTicks
+			}
+			covered += speed
+			ticks++
+		}
+		position = waypoint
+		speed = 0
+	}
+	return ticks
+}
+
+// maxTravelTicks bounds the travel of a train that doesn't move, a simulated day at 60 ticks a second
+const maxTravelTicks = 86400 * 60
+
+// PredictionHorizons are the bounds, in simulation seconds before the arrival, of
+// the horizons the accuracy of the predictions is tracked for.
+var PredictionHorizons = []float64{120, 300, 600}
+
+// PredictionAccuracy is how far the predictions made at a horizon were from the
+// actual arrivals.
+type PredictionAccuracy struct {
+	Horizon      string  // Like "2-5 min"
+	Count        int     // Arrivals compared
+	MeanError    float64 // Average seconds the trains arrived after the prediction, negative when before
+	MeanAbsError float64 // Average seconds between prediction and arrival
+}
+
+// horizonLabel names the horizon at the given index of PredictionHorizons.
+func horizonLabel(i int) string {
+	if i == len(PredictionHorizons) {
+		return fmt.Sprintf("%.0f+ min", PredictionHorizons[i-1]/60)
+	}
+	from := 0.0
+	if i > 0 {
+		from = PredictionHorizons[i-1]
+	}
+	return fmt.Sprintf("%.0f-%.0f min", from/60, PredictionHorizons[i]/60)
+}
+
+// horizonOf returns the index of the horizon of a prediction made the given seconds ahead.
+func horizonOf(eta float64) int {
+	return sort.SearchFloat64s(PredictionHorizons, eta)
+}
+
+type predictionKey struct {
+	trainID   int64
+	stationID int64
+	platform  Platform
+}
+
+// pendingArrival keeps the first prediction made at each horizon for an arrival
+// that hasn't happened yet.
+type pendingArrival struct {
+	predicted map[int]int // Predicted arrival by horizon
+	latest    int         // Latest predicted arrival, to forget arrivals that never happen
+}
+
+type predictionErrors struct {
+	count    int
+	sum      float64
+	absolute float64
+}
+
+// ArrivalPredictor gathers the predictions of the trains, for the countdown boards
+// of the stations and for Tenjin, and compares them with the actual arrivals.
+type ArrivalPredictor struct {
+	trains      []*Train
+	stations    map[int64][]ArrivalPrediction // Predictions by station, soonest first
+	pending     map[predictionKey]*pendingArrival
+	lastArrival map[int64]TrainArrival // Last arrival of each train already compared
+	errors      []predictionErrors     // By horizon
+	mutex       sync.RWMutex
+}
+
+func NewArrivalPredictor(trains []*Train) *ArrivalPredictor {
+	return &ArrivalPredictor{
+		trains:      trains,
+		stations:    make(map[int64][]ArrivalPrediction),
+		pending:     make(map[predictionKey]*pendingArrival),
+		lastArrival: make(map[int64]TrainArrival),
+		errors:      make([]predictionErrors, len(PredictionHorizons)+1),
+	}
+}
+
+// Refresh collects the latest predictions of the trains and compares the arrivals
+// since the last refresh with what was predicted.
+func (ap *ArrivalPredictor) Refresh() {
+	arrivals := make(map[int64]TrainArrival, len(ap.trains))
+	predictions := make([]ArrivalPrediction, 0)
+	for _, tr := range ap.trains {
+		if arrival, ok := tr.LastArrival(); ok {
+			arrivals[tr.ID] = arrival
+		}
+		predictions = append(predictions, tr.GetPredictions()...)
+	}
+	ap.update(arrivals, predictions)
+}
+
+// update compares the arrivals with the pending predictions and keeps the new predictions.
+func (ap *ArrivalPredictor) update(arrivals map[int64]TrainArrival, predictions []ArrivalPrediction) {
+	ap.mutex.Lock()
+	defer ap.mutex.Unlock()
+
+	now := 0
+	for trainID, arrival := range arrivals {
+		now = max(now, arrival.Time)
+		if ap.lastArrival[trainID] == arrival {
+			continue
+		}
+		ap.lastArrival[trainID] = arrival
+		key := predictionKey{trainID, arrival.StationID, arrival.Platform}
+		if pending, ok := ap.pending[key]; ok {
+			for horizon, predicted := range pending.predicted {
+				diff := float64(arrival.Time - predicted)
+				ap.errors[horizon].count++
+				ap.errors[horizon].sum += diff
+				ap.errors[horizon].absolute += math.Abs(diff)
+			}
+			delete(ap.pending, key)
+		}
+	}
+
+	stations := make(map[int64][]ArrivalPrediction)
+	for _, p := range predictions {
+		now = max(now, p.Issued)
+		stations[p.StationID] = append(stations[p.StationID], p)
+		// Predictions made before the last arrival of the train are already settled
+		if last, ok := ap.lastArrival[p.TrainID]; p.AtPlatform || ok && p.Issued < last.Time {
+			continue
+		}
+		key := predictionKey{p.TrainID, p.StationID, p.Platform}
+		pending, ok := ap.pending[key]
+		if !ok {
+			pending = &pendingArrival{predicted: make(map[int]int)}
+			ap.pending[key] = pending
+		}
+		horizon := horizonOf(p.ETA)
+		if _, ok := pending.predicted[horizon]; !ok {
+			pending.predicted[horizon] = p.Arrival()
+		}
+		pending.latest = p.Arrival()
+	}
+	for _, list := range stations {
+		sort.Slice(list, func(i, j int) bool { return list[i].Arrival() < list[j].Arrival() })
+	}
+	ap.stations = stations
+
+	// Arrivals an hour overdue are not going to happen, the train was moved.
+	for key, pending := range ap.pending {
+		if now-pending.latest > 3600 {
+			delete(ap.pending, key)
+		}
+	}
+}
+
+// ForStation returns the predicted arrivals at a station, soonest first.
+func (ap *ArrivalPredictor) ForStation(stationID int64) []ArrivalPrediction {
+	ap.mutex.RLock()
+	defer ap.mutex.RUnlock()
+	return append([]ArrivalPrediction(nil), ap.stations[stationID]...)
+}
+
+// NextArrival returns the soonest predicted arrival at a platform of a station.
+func (ap *ArrivalPredictor) NextArrival(stationID int64, platform Platform) (ArrivalPrediction, bool) {
+	ap.mutex.RLock()
+	defer ap.mutex.RUnlock()
+	for _, p := range ap.stations[stationID] {
+		if p.Platform == platform {
+			return p, true
+		}
+	}
+	return ArrivalPrediction{}, false
+}
+
+// Accuracy returns how the predictions did against the actual arrivals, by horizon.
+func (ap *ArrivalPredictor) Accuracy() []PredictionAccuracy {
+	ap.mutex.RLock()
+	defer ap.mutex.RUnlock()
+
+	accuracy := make([]PredictionAccuracy, len(ap.errors))
+	for i, e := range ap.errors {
+		accuracy[i].Horizon = horizonLabel(i)
+		accuracy[i].Count = e.count
+		if e.count > 0 {
+			accuracy[i].MeanError = e.sum / float64(e.count)
+			accuracy[i].MeanAbsError = e.absolute / float64(e.count)
+		}
+	}
+	return accuracy
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"testing"
+)
+
+func TestTravelTicks(t *testing.T) {
+	model := Make{Name: "Test", AccMag: 0.1, TopSpeed: 2}
+	start, end := NewVector(0, 0), NewVector(200, 0)
+
+	fromStop := travelTicks(model, start, start, 0, []Vector{end})
+	if fromStop <= 100 {
+		t.Fatal("A train starting from a stop should take longer than at top speed.")
+	}
+	if rolling := travelTicks(model, start, start, 2, []Vector{end}); rolling >= fromStop {
+		t.Fatal("A train already at speed should get there sooner.")
+	}
+	if halfway := travelTicks(model, start, NewVector(100, 0), 2, []Vector{end}); halfway >= fromStop/2+10 {
+		t.Fatal("A train halfway should take about half the time.")
+	}
+	if stops := travelTicks(model, start, start, 0, []Vector{NewVector(100, 0), end}); stops <= fromStop {
+		t.Fatal("Stopping on a waypoint should take longer than going straight.")
+	}
+}
+
+func TestArrivalPredictorAccuracy(t *testing.T) {
+	forward := Platform{LineID: 1, Forward: true}
+	predictor := NewArrivalPredictor(nil)
+
+	// Predicted 10 minutes ahead, then 1 minute ahead, arriving 30 seconds late
+	predictor.update(nil, []ArrivalPrediction{{TrainID: 1, StationID: 2, Platform: forward, ETA: 600, Issued: 1000}})
+	predictor.update(nil, []ArrivalPrediction{{TrainID: 1, StationID: 2, Platform: forward, ETA: 60, Issued: 1540}})
+	if next, ok := predictor.NextArrival(2, forward); !ok || next.Countdown() != "1 min" {
+		t.Fatal("The station should have the latest prediction of the train.")
+	}
+	arrival := map[int64]TrainArrival{1: {StationID: 2, Platform: forward, Time: 1630}}
+	predictor.update(arrival, []ArrivalPrediction{{TrainID: 1, StationID: 2, Platform: forward, Issued: 1630, AtPlatform: true}})
+
+	accuracy := predictor.Accuracy()
+	if len(accuracy) != len(PredictionHorizons)+1 || accuracy[0].Horizon != "0-2 min" || accuracy[3].Horizon != "10+ min" {
+		t.Fatal("The accuracy should be tracked for every horizon.")
+	}
+	if accuracy[0].Count != 1 || accuracy[0].MeanError != 30 || accuracy[2].Count != 1 || accuracy[2].MeanAbsError != 30 {
+		t.Fatal("The predictions should be compared with the actual arrival.")
+	}
+
+	// The same arrival is only compared once, and the train at the platform isn't pending
+	predictor.update(arrival, nil)
+	if predictor.Accuracy()[0].Count != 1 || len(predictor.pending) != 0 {
+		t.Fatal("An arrival should only be compared once.")
+	}
+}
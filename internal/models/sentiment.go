@@ -0,0 +1,165 @@
+package models
+
+import (
+	"math"
+
+	"github.com/odin-software/metro/control"
+)
+
+// SentimentCause is the reason why the sentiment of a passenger changed.
+type SentimentCause string
+
+const (
+	CauseWait           SentimentCause = "wait"
+	CauseCrowding       SentimentCause = "crowding"
+	CauseNoSeat         SentimentCause = "no_seat"
+	CauseTransfer       SentimentCause = "transfer"
+	CauseDelay          SentimentCause = "delay"
+	CauseDeniedBoarding SentimentCause = "denied_boarding"
+	CauseGateQueue      SentimentCause = "gate_queue"
+	CauseRecovery       SentimentCause = "recovery"
+)
+
+// Description returns a human readable explanation of the cause.
+func (c SentimentCause) Description() string {
+	switch c {
+	case CauseWait:
+		return "waiting longer than the advertised headway"
+	case CauseCrowding:
+		return "crowded trains and platforms"
+	case CauseNoSeat:
+		return "no seats available"
+	case CauseTransfer:
+		return "having to transfer"
+	case CauseDelay:
+		return "trains behind schedule"
+	case CauseDeniedBoarding:
+		return "being left behind by full trains"
+	case CauseGateQueue:
+		return "queueing outside the fare gates"
+	case CauseRecovery:
+		return "good service"
+	default:
+		return string(c)
+	}
+}
+
+// SentimentChange is a change of sentiment and what caused it.
+type SentimentChange struct {
+	Cause SentimentCause
+	Delta float64
+}
+
+// SentimentModel turns what passengers go through into changes of sentiment,
+// weighing every factor with the configured weights.
+type SentimentModel struct {
+	Config    control.SentimentConfig
+	Timetable *Timetable     // Advertised headways, the default headway is used when nil
+	DayType   func() DayType // Day type of the current simulated day, weekdays when nil
+}
+
+func NewSentimentModel(config control.SentimentConfig, timetable *Timetable) *SentimentModel {
+	return &SentimentModel{
+		Config:    config,
+		Timetable: timetable,
+	}
+}
+
+// defaultSentimentModel is used by passengers created without a model.
+var defaultSentimentModel = NewSentimentModel(control.DefaultConfig.Sentiment, nil)
+
+// Headway returns the advertised headway in seconds of a platform at a station.
+func (sm *SentimentModel) Headway(platform Platform, stationID int64) float64 {
+	dayType := DayTypeWeekday
+	if sm.DayType != nil {
+		dayType = sm.DayType()
+	}
+	if headway := sm.Timetable.Headway(dayType, platform, stationID); headway > 0 {
+		return headway
+	}
+	return sm.Config.DefaultHeadway.Seconds()
+}
+
+// Waiting evaluates a passenger waiting on a platform with the given crowding level
+// (1 is at capacity). Waiting up to half the headway is expected, longer waits cost
+// sentiment in proportion to the headway and the patience of the persona. Short waits
+// on a roomy platform let the passenger recover.
+func (sm *SentimentModel) Waiting(wait, headway, crowding float64, persona Persona) []SentimentChange {
+	if headway <= 0 {
+		headway = sm.Config.DefaultHeadway.Seconds()
+	}
+	changes := make([]SentimentChange, 0, 2)
+
+	if ratio := wait / headway; ratio > 0.5 {
+		// A passenger waiting several headways is already as unhappy as they get per interval.
+		excess := math.Min(ratio, 3) - 0.5
+		changes = append(changes, SentimentChange{CauseWait, -sm.Config.Wait * excess * persona.Impatience()})
+	}
+	if change, ok := sm.crowding(crowding, persona); ok {
+		changes = append(changes, change)
+	}
+
+	if len(changes) == 0 {
+		return sm.recovery()
+	}
+	return changes
+}
+
+// Entering evaluates a passenger queueing outside the fare gates.
+func (sm *SentimentModel) Entering(persona Persona) []SentimentChange {
+	return []SentimentChange{{CauseGateQueue, -sm.Config.GateQueue * persona.Impatience()}}
+}
+
+// Riding evaluates a passenger on a train with the given occupancy (0-1), whether they
+// have a seat and the delay of the train in seconds.
+func (sm *SentimentModel) Riding(occupancy float64, seated bool, delay float64, persona Persona) []SentimentChange {
+	changes := make([]SentimentChange, 0, 3)
+
+	if change, ok := sm.crowding(occupancy, persona); ok {
+		changes = append(changes, change)
+	}
+	if !seated {
+		changes = append(changes, SentimentChange{CauseNoSeat, -sm.Config.NoSeat})
+	}
+	// Delays under two minutes go unnoticed, the penalty stops growing after ten.
+	if delay > 120 {
+		minutes := math.Min(delay, 600) / 60
+		changes = append(changes, SentimentChange{CauseDelay, -sm.Config.Delay * minutes})
+	}
+
+	if len(changes) == 0 {
+		return sm.recovery()
+	}
+	return changes
+}
+
+// crowding is the change of sentiment for a train or platform with the given occupancy
+// (0-1), false if the persona tolerates it.
+func (sm *SentimentModel) crowding(occupancy float64, persona Persona) (SentimentChange, bool) {
+	if !persona.IsCrowded(occupancy) {
+		return SentimentChange{}, false
+	}
+	tolerance := persona.CrowdingTolerance
+	if tolerance <= 0 || tolerance >= 1 {
+		tolerance = 0.8
+	}
+	level := math.Min(1, (occupancy-tolerance)/(1-tolerance))
+	return SentimentChange{CauseCrowding, -sm.Config.Crowding * level}, true
+}
+
+// Transfer is the change of sentiment every time a passenger changes lines.
+func (sm *SentimentModel) Transfer() SentimentChange {
+	return SentimentChange{CauseTransfer, -sm.Config.Transfer}
+}
+
+// DeniedBoarding is the change of sentiment every time a full train leaves a passenger behind.
+func (sm *SentimentModel) DeniedBoarding() SentimentChange {
+	return SentimentChange{CauseDeniedBoarding, -sm.Config.DeniedBoarding}
+}
+
+func (sm *SentimentModel) recovery() []SentimentChange {
+	if sm.Config.Recovery <= 0 {
+		return nil
+	}
+	return []SentimentChange{{CauseRecovery, sm.Config.Recovery}}
+}
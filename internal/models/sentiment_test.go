@@ -0,0 +1,84 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/odin-software/metro/control"
+)
+
+func TestSentimentWaiting(t *testing.T) {
+	sm := NewSentimentModel(control.DefaultConfig.Sentiment, nil)
+	commuter := PersonaByKind(PersonaCommuter)
+
+	changes := sm.Waiting(60, 300, 0, commuter)
+	if len(changes) != 1 || changes[0].Cause != CauseRecovery {
+		t.Fatal("Waiting less than half the headway should let passengers recover.")
+	}
+
+	short := sm.Waiting(300, 300, 0, commuter)
+	long := sm.Waiting(900, 300, 0, commuter)
+	if len(short) != 1 || short[0].Cause != CauseWait || short[0].Delta >= 0 {
+		t.Fatal("Waiting a full headway should cost sentiment.")
+	}
+	if long[0].Delta >= short[0].Delta {
+		t.Fatal("Longer waits should cost more sentiment.")
+	}
+	if capped := sm.Waiting(9000, 300, 0, commuter); capped[0].Delta != sm.Waiting(900, 300, 0, commuter)[0].Delta {
+		t.Fatal("The wait penalty should stop growing after three headways.")
+	}
+
+	elderly := sm.Waiting(900, 300, 0, PersonaByKind(PersonaElderly))
+	if elderly[0].Delta <= long[0].Delta {
+		t.Fatal("Patient personas should lose less sentiment waiting.")
+	}
+
+	crowded := sm.Waiting(60, 300, 1, commuter)
+	if len(crowded) != 1 || crowded[0].Cause != CauseCrowding {
+		t.Fatal("A full platform should cost sentiment even on a short wait.")
+	}
+	if gate := sm.Entering(commuter); len(gate) != 1 || gate[0].Cause != CauseGateQueue || gate[0].Delta >= 0 {
+		t.Fatal("Queueing outside the gates should cost sentiment.")
+	}
+}
+
+func TestSentimentRiding(t *testing.T) {
+	sm := NewSentimentModel(control.DefaultConfig.Sentiment, nil)
+	commuter := PersonaByKind(PersonaCommuter)
+
+	changes := sm.Riding(0.3, true, 0, commuter)
+	if len(changes) != 1 || changes[0].Cause != CauseRecovery {
+		t.Fatal("A seated passenger on an empty train on time should recover.")
+	}
+
+	causes := make(map[SentimentCause]bool)
+	for _, c := range sm.Riding(1, false, 300, commuter) {
+		if c.Delta >= 0 {
+			t.Fatal("Riding problems should cost sentiment.")
+		}
+		causes[c.Cause] = true
+	}
+	if !causes[CauseCrowding] || !causes[CauseNoSeat] || !causes[CauseDelay] {
+		t.Fatal("Every riding problem should be recorded with its cause.")
+	}
+
+	if changes := sm.Riding(0.3, true, 60, commuter); changes[0].Cause != CauseRecovery {
+		t.Fatal("Small delays should go unnoticed.")
+	}
+}
+
+func TestSentimentHeadway(t *testing.T) {
+	tt := NewTimetable()
+	platform := Platform{LineID: 1, Forward: true}
+	for _, scheduled := range []int{100, 400, 700} {
+		tt.Add(DayTypeWeekday, 1, 10, platform, scheduled)
+	}
+	tt.Build()
+
+	sm := NewSentimentModel(control.DefaultConfig.Sentiment, tt)
+	if sm.Headway(platform, 10) != 300 {
+		t.Fatal("The headway should come from the timetable.")
+	}
+	if sm.Headway(Platform{LineID: 2}, 10) != control.DefaultConfig.Sentiment.DefaultHeadway.Seconds() {
+		t.Fatal("Unknown platforms should use the default headway.")
+	}
+}
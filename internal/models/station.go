@@ -2,18 +2,42 @@ package models
 
 import (
 	"image"
+	"math"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/odin-software/metro/control"
 	"github.com/odin-software/metro/internal/assets"
 )
 
+// Platform is one direction of a line at a station. Passengers without an
+// itinerary wait on the zero Platform and take any train.
+type Platform struct {
+	LineID  int64
+	Forward bool
+}
+
 type Station struct {
-	ID                int64        `json:"id"`
-	Name              string       `json:"name"`
-	Position          Vector       `json:"position"`
-	WaitingPassengers []*Passenger // Passengers waiting at this station
-	passengerMutex    sync.RWMutex // Thread safety for passenger operations
+	ID                int64                     `json:"id"`
+	Name              string                    `json:"name"`
+	Position          Vector                    `json:"position"`
+	WaitingPassengers []*Passenger              // Passengers waiting at this station, in order of arrival
+	platforms         map[Platform][]*Passenger // Queue of each platform, in order of arrival
+	PlatformCapacity  int                       // Passengers the platforms hold, 0 is unlimited
+	GateThroughput    float64                   // Passengers per minute the fare gates let in, 0 is unlimited
+	entryQueue        []*Passenger              // Passengers outside the gates, in order of arrival
+	gateCredit        float64                   // Passengers the gates can let in right now, fractions included
+	Complex           *StationComplex           // Interchange the station is part of, nil when it stands alone
+	EntranceWalk      time.Duration             // Walk from the fare gates to the platforms
+	Zone              int                       // Fare zone, see FareZonal
+	walkers           []walker                  // Passengers walking from here to a platform
+	passengerMutex    sync.RWMutex              // Thread safety for passenger operations
+	incoming          atomic.Int64              // Passengers walking to the platforms, from here or another station of the complex
+	positionMutex     sync.RWMutex              // Thread safety for moving the station at runtime
+	stats             stationStats              // Counts of the day, see Stats
+	statsMutex        sync.Mutex                // Thread safety for the counts
 	Drawing
 }
 
@@ -24,6 +48,12 @@ func NewStation(id int64, name string, location Vector) *Station {
 		Name:              name,
 		Position:          location,
 		WaitingPassengers: make([]*Passenger, 0),
+		platforms:         make(map[Platform][]*Passenger),
+		PlatformCapacity:  control.DefaultConfig.PlatformCapacity,
+		GateThroughput:    control.DefaultConfig.GateThroughput,
+		entryQueue:        make([]*Passenger, 0),
+		EntranceWalk:      control.DefaultConfig.Walking.Entrance,
+		Zone:              1,
 		Drawing: Drawing{
 			Counter:     0,
 			FrameWidth:  frameWidth,
@@ -34,32 +64,282 @@ func NewStation(id int64, name string, location Vector) *Station {
 	}
 }
 
+// GetPosition returns the position of the station (thread-safe)
+func (st *Station) GetPosition() Vector {
+	st.positionMutex.RLock()
+	defer st.positionMutex.RUnlock()
+	return st.Position
+}
+
+// SetPosition moves the station (thread-safe)
+func (st *Station) SetPosition(position Vector) {
+	st.positionMutex.Lock()
+	defer st.positionMutex.Unlock()
+	st.Position = position
+}
+
 // Passenger management methods
 
-// AddPassenger adds a passenger to the station's waiting queue
+// Enter puts a passenger arriving from the street at the back of the queue outside
+// the fare gates, Tick lets them onto the platforms.
+func (st *Station) Enter(passenger *Passenger) {
+	st.passengerMutex.Lock()
+	defer st.passengerMutex.Unlock()
+
+	passenger.CurrentStation = st
+	passenger.Position = st.GetPosition()
+	passenger.State = PassengerStateEntering
+	st.entryQueue = append(st.entryQueue, passenger)
+	st.recordQueue(len(st.WaitingPassengers) + len(st.entryQueue))
+}
+
+// Tick is the station phase of a tick: the fare gates and the walks to the platforms.
+func (st *Station) Tick() {
+	seconds := control.DefaultConfig.LoopDuration.Seconds() * control.DefaultConfig.SimulationSpeed
+	st.processGates(seconds)
+	st.processWalks(seconds)
+}
+
+// processGates lets passengers through the fare gates for the given simulation seconds,
+// as long as there is room on the platforms they go to. Passengers pay their entry
+// fare and the ones with a walk to their platform start walking.
+func (st *Station) processGates(seconds float64) {
+	st.passengerMutex.Lock()
+	passed := make([]*Passenger, 0)
+	admitted := make([]*Passenger, 0)
+	if st.GateThroughput > 0 {
+		st.gateCredit += st.GateThroughput / 60 * seconds
+	}
+	full := false
+	for len(st.entryQueue) > 0 && (st.GateThroughput <= 0 || st.gateCredit >= 1) {
+		passenger := st.entryQueue[0]
+		to, walk := passenger.platformWalk(st, true)
+		if full = !st.hasRoom(to); full {
+			break
+		}
+		st.entryQueue = st.entryQueue[1:]
+		if st.GateThroughput > 0 {
+			st.gateCredit--
+		}
+		passed = append(passed, passenger)
+		if to != st || walk > 0 {
+			st.startWalk(passenger, to, walk)
+			continue
+		}
+		st.addPassenger(passenger)
+		admitted = append(admitted, passenger)
+	}
+	// Idle gates don't save up capacity for later
+	if len(st.entryQueue) == 0 || full {
+		st.gateCredit = math.Max(0, math.Min(st.gateCredit, 1))
+	}
+	st.passengerMutex.Unlock()
+
+	for _, passenger := range passed {
+		passenger.payEntry(st)
+	}
+	for _, passenger := range admitted {
+		passenger.StartWaiting()
+	}
+}
+
+// walker is a passenger walking to the platforms of a station of the complex.
+type walker struct {
+	passenger *Passenger
+	to        *Station
+	remaining float64 // Simulation seconds left
+}
+
+// Transfer takes a passenger who got off a train to the platform of their next
+// leg, walking there when it is on another station of the complex.
+func (st *Station) Transfer(passenger *Passenger) {
+	to, walk := passenger.platformWalk(st, false)
+	if to == st && walk <= 0 {
+		st.AddPassenger(passenger)
+		return
+	}
+	st.passengerMutex.Lock()
+	defer st.passengerMutex.Unlock()
+	st.startWalk(passenger, to, walk)
+}
+
+// startWalk sends a passenger walking to the platforms of a station, the lock must be held.
+// The passenger counts against the capacity of those platforms from now on.
+func (st *Station) startWalk(passenger *Passenger, to *Station, walk time.Duration) {
+	passenger.CurrentStation = st
+	passenger.Position = st.GetPosition()
+	passenger.State = PassengerStateWalking
+	st.walkers = append(st.walkers, walker{passenger: passenger, to: to, remaining: walk.Seconds()})
+	to.incoming.Add(1)
+}
+
+// endWalk puts a passenger who walked to the platforms in the queue of their platform
+func (st *Station) endWalk(passenger *Passenger) {
+	st.passengerMutex.Lock()
+	defer st.passengerMutex.Unlock()
+	st.incoming.Add(-1)
+	st.addPassenger(passenger)
+}
+
+// processWalks moves walking passengers for the given simulation seconds, the ones
+// that get to their platform start waiting there.
+func (st *Station) processWalks(seconds float64) {
+	st.passengerMutex.Lock()
+	arrived := make([]walker, 0)
+	walking := make([]walker, 0, len(st.walkers))
+	for _, w := range st.walkers {
+		w.remaining -= seconds
+		if w.remaining <= 0 {
+			arrived = append(arrived, w)
+		} else {
+			walking = append(walking, w)
+		}
+	}
+	st.walkers = walking
+	st.passengerMutex.Unlock()
+
+	for _, w := range arrived {
+		w.to.endWalk(w.passenger)
+		w.passenger.StartWaiting()
+	}
+}
+
+// platformsFull returns true when no one else fits on the platforms, counting the
+// passengers walking there. The lock must be held.
+func (st *Station) platformsFull() bool {
+	return st.PlatformCapacity > 0 && len(st.WaitingPassengers)+int(st.incoming.Load()) >= st.PlatformCapacity
+}
+
+// hasRoom returns true when a passenger fits on the platforms of to, the station
+// itself or another one of its complex. The lock of the station must be held, the
+// stations are ticked one at a time so the lock of the other one is taken after it.
+func (st *Station) hasRoom(to *Station) bool {
+	if to != st {
+		to.passengerMutex.RLock()
+		defer to.passengerMutex.RUnlock()
+	}
+	return !to.platformsFull()
+}
+
+// AddPassenger adds a passenger to the back of the queue of its platform, skipping
+// the gates. Transferring passengers are already inside the station.
 func (st *Station) AddPassenger(passenger *Passenger) {
 	st.passengerMutex.Lock()
 	defer st.passengerMutex.Unlock()
+	st.addPassenger(passenger)
+}
 
-	st.WaitingPassengers = append(st.WaitingPassengers, passenger)
+func (st *Station) addPassenger(passenger *Passenger) {
 	passenger.CurrentStation = st
-	passenger.Position = st.Position
+	passenger.Position = st.GetPosition()
+	passenger.platform = passenger.nextPlatform()
+
+	st.WaitingPassengers = append(st.WaitingPassengers, passenger)
+	if st.platforms == nil {
+		st.platforms = make(map[Platform][]*Passenger)
+	}
+	st.platforms[passenger.platform] = append(st.platforms[passenger.platform], passenger)
+	st.recordQueue(len(st.WaitingPassengers) + len(st.entryQueue))
 }
 
-// RemovePassenger removes a passenger from the station's waiting queue
+// RemovePassenger removes a passenger from the station, keeping the order of the queues
 func (st *Station) RemovePassenger(passenger *Passenger) bool {
 	st.passengerMutex.Lock()
 	defer st.passengerMutex.Unlock()
 
-	for i, p := range st.WaitingPassengers {
-		if p.ID == passenger.ID {
-			// Remove passenger by swapping with last and truncating
-			st.WaitingPassengers[i] = st.WaitingPassengers[len(st.WaitingPassengers)-1]
-			st.WaitingPassengers = st.WaitingPassengers[:len(st.WaitingPassengers)-1]
+	if queue, ok := removeFromQueue(st.entryQueue, passenger); ok {
+		st.entryQueue = queue
+		return true
+	}
+	for i, w := range st.walkers {
+		if w.passenger.ID == passenger.ID {
+			st.walkers = append(st.walkers[:i:i], st.walkers[i+1:]...)
+			w.to.incoming.Add(-1)
 			return true
 		}
 	}
-	return false
+	queue, ok := removeFromQueue(st.WaitingPassengers, passenger)
+	if !ok {
+		return false
+	}
+	st.WaitingPassengers = queue
+	if queue, ok := removeFromQueue(st.platforms[passenger.platform], passenger); ok {
+		st.platforms[passenger.platform] = queue
+	}
+	return true
+}
+
+// removeFromQueue returns a new slice, so slices handed out before are never modified.
+func removeFromQueue(queue []*Passenger, passenger *Passenger) ([]*Passenger, bool) {
+	for i, p := range queue {
+		if p.ID == passenger.ID {
+			rest := make([]*Passenger, 0, len(queue)-1)
+			rest = append(rest, queue[:i]...)
+			return append(rest, queue[i+1:]...), true
+		}
+	}
+	return queue, false
+}
+
+// GetPlatformQueue returns a copy of the queue of a platform, first in line first
+func (st *Station) GetPlatformQueue(platform Platform) []*Passenger {
+	st.passengerMutex.RLock()
+	defer st.passengerMutex.RUnlock()
+
+	passengers := make([]*Passenger, len(st.platforms[platform]))
+	copy(passengers, st.platforms[platform])
+	return passengers
+}
+
+// GetPlatformCounts returns the number of passengers waiting on each platform
+func (st *Station) GetPlatformCounts() map[Platform]int {
+	st.passengerMutex.RLock()
+	defer st.passengerMutex.RUnlock()
+
+	counts := make(map[Platform]int, len(st.platforms))
+	for platform, queue := range st.platforms {
+		if len(queue) > 0 {
+			counts[platform] = len(queue)
+		}
+	}
+	return counts
+}
+
+// GetEntryQueue returns a copy of the queue outside the fare gates, first in line first
+func (st *Station) GetEntryQueue() []*Passenger {
+	st.passengerMutex.RLock()
+	defer st.passengerMutex.RUnlock()
+
+	passengers := make([]*Passenger, len(st.entryQueue))
+	copy(passengers, st.entryQueue)
+	return passengers
+}
+
+// GetEntryQueueCount returns the number of passengers outside the fare gates
+func (st *Station) GetEntryQueueCount() int {
+	st.passengerMutex.RLock()
+	defer st.passengerMutex.RUnlock()
+	return len(st.entryQueue)
+}
+
+// GetWalkingPassengersCount returns the number of passengers walking from this
+// station to a platform
+func (st *Station) GetWalkingPassengersCount() int {
+	st.passengerMutex.RLock()
+	defer st.passengerMutex.RUnlock()
+	return len(st.walkers)
+}
+
+// CrowdingLevel returns how full the platforms are, 1 is at capacity. Stations
+// without a capacity are never crowded.
+func (st *Station) CrowdingLevel() float64 {
+	st.passengerMutex.RLock()
+	defer st.passengerMutex.RUnlock()
+
+	if st.PlatformCapacity <= 0 {
+		return 0
+	}
+	return float64(len(st.WaitingPassengers)) / float64(st.PlatformCapacity)
 }
 
 // GetWaitingPassengersCount returns the number of passengers waiting
@@ -95,25 +375,35 @@ func (st *Station) GetPassengersForDestination(destinationID int64) []*Passenger
 
 // Drawing methods
 
-func (st *Station) Update() {
-	st.Drawing.Counter++
-
-	// Update all waiting passengers
+// TickPassengers is the passenger phase of a tick for the passengers waiting at the
+// station, inside and outside the gates.
+func (st *Station) TickPassengers() {
 	st.passengerMutex.RLock()
 	passengers := st.WaitingPassengers
+	entering := st.entryQueue
 	st.passengerMutex.RUnlock()
 
 	for _, passenger := range passengers {
 		if passenger.State == PassengerStateWaiting {
-			passenger.Update()
+			passenger.Tick()
 		}
 	}
+	for _, passenger := range entering {
+		if passenger.State == PassengerStateEntering {
+			passenger.Tick()
+		}
+	}
+}
+
+func (st *Station) Update() {
+	st.Drawing.Counter++
 }
 
 func (st *Station) Draw(screen *ebiten.Image) {
 	op := &ebiten.DrawImageOptions{}
 	op.GeoM.Translate(-float64(st.FrameWidth)/2, -float64(st.FrameHeight)/2)
-	op.GeoM.Translate(st.Position.X, st.Position.Y)
+	position := st.GetPosition()
+	op.GeoM.Translate(position.X, position.Y)
 	i := (st.Counter / st.FrameCount) % st.FrameCount
 	sx, sy := 0+i*st.FrameWidth, 0
 	screen.DrawImage(st.Sprite.SubImage(image.Rect(sx, sy, sx+st.FrameWidth, sy+st.FrameHeight)).(*ebiten.Image), op)
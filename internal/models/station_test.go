@@ -0,0 +1,214 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStationQueuesKeepOrder(t *testing.T) {
+	a, b := &Station{ID: 1, Name: "A"}, &Station{ID: 2, Name: "B"}
+	forward := Itinerary{Legs: []Leg{{LineID: 1, From: a, To: b, Forward: true}}}
+	backward := Itinerary{Legs: []Leg{{LineID: 1, From: a, To: b, Forward: false}}}
+
+	passengers := make([]*Passenger, 5)
+	for i := range passengers {
+		passengers[i] = &Passenger{ID: string(rune('1' + i)), DestinationStation: b, Itinerary: forward}
+	}
+	passengers[2].Itinerary = backward
+	for _, p := range passengers {
+		a.AddPassenger(p)
+	}
+
+	a.RemovePassenger(passengers[1])
+	queue := a.GetPlatformQueue(Platform{LineID: 1, Forward: true})
+	if len(queue) != 3 || queue[0] != passengers[0] || queue[1] != passengers[3] || queue[2] != passengers[4] {
+		t.Fatal("The platform queue should keep the order of arrival.")
+	}
+	if len(a.GetPlatformQueue(Platform{LineID: 1, Forward: false})) != 1 {
+		t.Fatal("Passengers going the other way should be on the other platform.")
+	}
+	waiting := a.GetWaitingPassengers()
+	if len(waiting) != 4 || waiting[1] != passengers[2] {
+		t.Fatal("The waiting passengers should keep the order of arrival.")
+	}
+}
+
+func TestStationGates(t *testing.T) {
+	a, b := &Station{ID: 1, Name: "A", PlatformCapacity: 3, GateThroughput: 60}, &Station{ID: 2, Name: "B"}
+	passengers := make([]*Passenger, 5)
+	for i := range passengers {
+		passengers[i] = &Passenger{ID: string(rune('1' + i)), DestinationStation: b}
+		a.Enter(passengers[i])
+	}
+
+	a.processGates(0.5)
+	if a.GetEntryQueueCount() != 5 {
+		t.Fatal("The gates should not let anyone in before a full passenger of throughput.")
+	}
+	a.processGates(1.5)
+	if a.GetEntryQueueCount() != 3 || a.GetWaitingPassengersCount() != 2 {
+		t.Fatal("The gates should let one passenger per second in at 60 per minute.")
+	}
+	if passengers[0].State != PassengerStateWaiting || passengers[2].State != PassengerStateEntering {
+		t.Fatal("Passengers should wait on the platform once past the gates.")
+	}
+	a.processGates(60)
+	if a.GetEntryQueueCount() != 2 || a.CrowdingLevel() != 1 {
+		t.Fatal("The gates should stop letting passengers in when the platform is full.")
+	}
+
+	a.RemovePassenger(passengers[4])
+	if queue := a.GetEntryQueue(); len(queue) != 1 || queue[0] != passengers[3] {
+		t.Fatal("Passengers leaving the entry queue should be removed from it.")
+	}
+}
+
+func TestStationTransferWalk(t *testing.T) {
+	a, b, c := &Station{ID: 1, Name: "A"}, &Station{ID: 2, Name: "B"}, &Station{ID: 3, Name: "C"}
+	interchange := NewStationComplex(1, "AB", 3*time.Minute)
+	interchange.Add(a)
+	interchange.Add(b)
+	b.EntranceWalk = time.Minute
+	itinerary := Itinerary{Legs: []Leg{
+		{LineID: 1, From: c, To: a, Forward: true},
+		{LineID: 2, From: b, To: c, Forward: true},
+	}}
+
+	transferring := &Passenger{ID: "1", DestinationStation: c, Itinerary: itinerary, leg: 1}
+	a.Transfer(transferring)
+	if transferring.State != PassengerStateWalking || a.GetWalkingPassengersCount() != 1 {
+		t.Fatal("A passenger changing to a line of another station of the complex should walk there.")
+	}
+	a.processWalks(120)
+	if b.GetWaitingPassengersCount() != 0 {
+		t.Fatal("The passenger should still be walking before the walk is over.")
+	}
+	a.processWalks(60)
+	if a.GetWalkingPassengersCount() != 0 || b.GetWaitingPassengersCount() != 1 || transferring.CurrentStation != b {
+		t.Fatal("The passenger should wait on the platform of B after the walk.")
+	}
+	if len(b.GetPlatformQueue(Platform{LineID: 2, Forward: true})) != 1 || transferring.State != PassengerStateWaiting {
+		t.Fatal("The passenger should queue for the next leg.")
+	}
+
+	entering := &Passenger{ID: "2", DestinationStation: c, Itinerary: Itinerary{Legs: itinerary.Legs[1:]}}
+	a.Enter(entering)
+	a.processGates(1)
+	if entering.State != PassengerStateWalking {
+		t.Fatal("Passengers should walk from the gates to the platform of their first train.")
+	}
+	a.processWalks(60)
+	if b.GetWaitingPassengersCount() != 2 {
+		t.Fatal("The walk from the gates should take the entrance walk of B.")
+	}
+}
+
+func TestStationGatesCountWalkers(t *testing.T) {
+	a, b := &Station{ID: 1, Name: "A", PlatformCapacity: 3, EntranceWalk: time.Minute}, &Station{ID: 2, Name: "B"}
+	passengers := make([]*Passenger, 5)
+	for i := range passengers {
+		passengers[i] = &Passenger{ID: string(rune('1' + i)), DestinationStation: b}
+		a.Enter(passengers[i])
+	}
+
+	a.processGates(1)
+	a.processGates(1)
+	if a.GetWalkingPassengersCount() != 3 || a.GetEntryQueueCount() != 2 {
+		t.Fatal("Passengers walking to the platforms should count against their capacity.")
+	}
+	a.processWalks(60)
+	a.processGates(1)
+	if a.GetWaitingPassengersCount() != 3 || a.GetEntryQueueCount() != 2 || a.CrowdingLevel() != 1 {
+		t.Fatal("The platforms should stay at capacity after the walk.")
+	}
+
+	a.RemovePassenger(passengers[0])
+	a.processGates(1)
+	if a.GetWalkingPassengersCount() != 1 || a.GetEntryQueueCount() != 1 {
+		t.Fatal("The gates should let a passenger in when there is room again.")
+	}
+	a.RemovePassenger(passengers[3])
+	a.processGates(1)
+	if a.GetWalkingPassengersCount() != 1 || a.GetEntryQueueCount() != 0 {
+		t.Fatal("A passenger leaving the walk should make room on the platforms.")
+	}
+}
+
+func TestStationGatesComplexCapacity(t *testing.T) {
+	a, b, c := &Station{ID: 1, Name: "A"}, &Station{ID: 2, Name: "B", PlatformCapacity: 2}, &Station{ID: 3, Name: "C"}
+	interchange := NewStationComplex(1, "AB", 3*time.Minute)
+	interchange.Add(a)
+	interchange.Add(b)
+	itinerary := Itinerary{Legs: []Leg{{LineID: 2, From: b, To: c, Forward: true}}}
+
+	transferring := &Passenger{ID: "1", DestinationStation: c, Itinerary: itinerary}
+	a.Transfer(transferring)
+	entering := make([]*Passenger, 3)
+	for i := range entering {
+		entering[i] = &Passenger{ID: string(rune('2' + i)), DestinationStation: c, Itinerary: itinerary}
+		a.Enter(entering[i])
+	}
+	a.processGates(1)
+	if a.GetWalkingPassengersCount() != 2 || a.GetEntryQueueCount() != 2 {
+		t.Fatal("The gates should check the capacity of the platforms of the complex the passengers go to.")
+	}
+
+	a.processWalks(180)
+	a.processGates(1)
+	if b.GetWaitingPassengersCount() != 2 || a.GetEntryQueueCount() != 2 {
+		t.Fatal("The platforms of the complex should stay at capacity after the walks.")
+	}
+}
+
+func TestStationStats(t *testing.T) {
+	a, b := &Station{ID: 1, Name: "A"}, &Station{ID: 2, Name: "B"}
+	for i := 1; i <= 10; i++ {
+		a.recordBoarding(3, float64(i*10))
+	}
+	a.recordAlighting(3)
+	passengers := make([]*Passenger, 4)
+	for i := range passengers {
+		passengers[i] = &Passenger{ID: string(rune('1' + i)), DestinationStation: b}
+		a.AddPassenger(passengers[i])
+	}
+	a.RemovePassenger(passengers[0])
+
+	stats := a.Stats(3, nil)
+	if stats.Boardings != 10 || stats.Alightings != 1 {
+		t.Fatal("The stats should count the passengers served today.")
+	}
+	if stats.Queue != 3 || stats.MaxQueue != 4 {
+		t.Fatal("The stats should keep the current and the longest queue.")
+	}
+	if stats.AverageWait != 55 || stats.Wait90 != 90 {
+		t.Fatal("The stats should have the average and the 90th percentile of the waits.")
+	}
+
+	stats = a.Stats(4, nil)
+	if stats.Boardings != 0 || stats.Alightings != 0 || stats.AverageWait != 0 || stats.MaxQueue != 3 {
+		t.Fatal("The stats should start over on a new day.")
+	}
+}
+
+func TestWaitHistogram(t *testing.T) {
+	var h waitHistogram
+	if h.average() != 0 || h.percentile(0.9) != 0 {
+		t.Fatal("Without waits the average and the percentile should be 0.")
+	}
+	for i := 0; i < 9; i++ {
+		h.add(12)
+	}
+	if h.percentile(0.9) != 12 {
+		t.Fatal("A percentile should not be past the longest wait.")
+	}
+	h.add(5000)
+	if h.average() != (9*12+5000)/10.0 {
+		t.Fatal("The average should be exact.")
+	}
+	if h.percentile(0.9) != 20 {
+		t.Fatal("A percentile should be rounded up to the end of its bucket.")
+	}
+	if h.percentile(1) != 5000 {
+		t.Fatal("Waits longer than the histogram should give the longest wait.")
+	}
+}
@@ -0,0 +1,103 @@
+package models
+
+import (
+	"fmt"
+)
+
+// Journey event types, the moments of a journey kept in the timeline of a passenger
+const (
+	JourneySpawn          = "spawn"           // Appeared at the station
+	JourneyWait           = "wait"            // Started waiting on a platform
+	JourneyBoard          = "board"           // Boarded a train
+	JourneyDeniedBoarding = "denied_boarding" // Left behind by a full train
+	JourneyDisembark      = "disembark"       // Got off a train
+	JourneyTransfer       = "transfer"        // Changed to the next leg of the itinerary
+	JourneyArrive         = "arrive"          // Reached the destination
+	JourneyAbandon        = "abandon"         // Left the station without travelling
+)
+
+// JourneyEvent is a moment of the journey of a passenger.
+type JourneyEvent struct {
+	PassengerID string
+	Type        string
+	StationID   int64 // 0 when not at a station
+	StationName string
+	TrainID     int64 // 0 when there is no train involved
+	TrainName   string
+	Sentiment   float64
+	Detail      string // Reason of an abandon, destination of a spawn
+	Day         int    // Simulated day, set by the recorder
+	SimTime     int    // Seconds since midnight, set by the recorder
+	Seq         int64  // Order the event was recorded in, set by the recorder
+}
+
+// JourneyRecorder keeps the timelines of the passengers. Recorders stamp the events
+// with the simulation time and must be safe for concurrent use.
+type JourneyRecorder interface {
+	Record(event JourneyEvent)
+}
+
+// Timeline is the journey of a passenger in the order it happened.
+type Timeline []JourneyEvent
+
+// Duration returns the simulation seconds between the first and the last event.
+func (t Timeline) Duration() int {
+	if len(t) < 2 {
+		return 0
+	}
+	first, last := t[0], t[len(t)-1]
+	return (last.Day-first.Day)*86400 + last.SimTime - first.SimTime
+}
+
+// Finished returns true if the passenger arrived or gave up.
+func (t Timeline) Finished() bool {
+	if len(t) == 0 {
+		return false
+	}
+	last := t[len(t)-1].Type
+	return last == JourneyArrive || last == JourneyAbandon
+}
+
+// Contains returns true if the timeline has the event.
+func (t Timeline) Contains(event JourneyEvent) bool {
+	for _, ev := range t {
+		if ev == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Describe returns a line for each event, like "08:15:02 Boarded Train 1 at Central".
+func (t Timeline) Describe() []string {
+	lines := make([]string, 0, len(t))
+	for _, ev := range t {
+		lines = append(lines, fmt.Sprintf("%02d:%02d:%02d %s", ev.SimTime/3600, (ev.SimTime%3600)/60, ev.SimTime%60, ev.describe()))
+	}
+	return lines
+}
+
+func (ev JourneyEvent) describe() string {
+	switch ev.Type {
+	case JourneySpawn:
+		if ev.Detail != "" {
+			return fmt.Sprintf("Entered %s going to %s", ev.StationName, ev.Detail)
+		}
+		return fmt.Sprintf("Entered %s", ev.StationName)
+	case JourneyWait:
+		return fmt.Sprintf("Waiting at %s", ev.StationName)
+	case JourneyBoard:
+		return fmt.Sprintf("Boarded %s at %s", ev.TrainName, ev.StationName)
+	case JourneyDeniedBoarding:
+		return fmt.Sprintf("Left behind by %s at %s", ev.TrainName, ev.StationName)
+	case JourneyDisembark:
+		return fmt.Sprintf("Got off %s at %s", ev.TrainName, ev.StationName)
+	case JourneyTransfer:
+		return fmt.Sprintf("Transferring at %s", ev.StationName)
+	case JourneyArrive:
+		return fmt.Sprintf("Arrived at %s (%.0f%%)", ev.StationName, ev.Sentiment)
+	case JourneyAbandon:
+		return fmt.Sprintf("Gave up at %s: %s", ev.StationName, ev.Detail)
+	}
+	return ev.Type
+}
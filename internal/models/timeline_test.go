@@ -0,0 +1,64 @@
+package models
+
+import (
+	"testing"
+)
+
+// timelineRecorder keeps the timelines in memory, with a clock that ticks a minute per event.
+type timelineRecorder struct {
+	events  Timeline
+	simTime int
+}
+
+func (tr *timelineRecorder) Record(event JourneyEvent) {
+	event.SimTime = tr.simTime
+	tr.simTime += 60
+	tr.events = append(tr.events, event)
+}
+
+func TestPassengerTimeline(t *testing.T) {
+	a, b, c := &Station{ID: 1, Name: "A"}, &Station{ID: 2, Name: "B"}, &Station{ID: 3, Name: "C"}
+	first, second := &Train{ID: 10, Name: "T1"}, &Train{ID: 20, Name: "T2"}
+	itinerary := Itinerary{Legs: []Leg{{LineID: 1, From: a, To: b}, {LineID: 2, From: b, To: c}}}
+	recorder := &timelineRecorder{simTime: 8 * 3600}
+
+	p := &Passenger{ID: "P-1", Name: "Ana", CurrentStation: a, DestinationStation: c, Itinerary: itinerary, Sentiment: 100}
+	p.StartTimeline(recorder)
+	p.StartWaiting()
+	p.DenyBoarding(first)
+	p.BoardTrain(first)
+	p.DisembarkTrain(b)
+	p.BoardTrain(second)
+	p.DisembarkTrain(c)
+
+	expected := []string{
+		JourneySpawn, JourneyWait, JourneyDeniedBoarding, JourneyBoard, JourneyDisembark,
+		JourneyTransfer, JourneyWait, JourneyBoard, JourneyDisembark, JourneyArrive,
+	}
+	timeline := recorder.events
+	if len(timeline) != len(expected) {
+		t.Fatal("Every moment of the journey should be recorded.")
+	}
+	for i, ev := range timeline {
+		if ev.Type != expected[i] || ev.PassengerID != "P-1" {
+			t.Fatal("The moments of the journey should be recorded in order.")
+		}
+	}
+	if timeline[3].TrainID != 10 || timeline[3].StationID != 1 || timeline[4].TrainID != 10 || timeline[4].StationID != 2 {
+		t.Fatal("Boarding and getting off should record the train and the station.")
+	}
+	if !timeline.Finished() || timeline.Duration() != 9*60 {
+		t.Fatal("The journey should be over, from the first to the last event.")
+	}
+	if lines := timeline.Describe(); lines[3] != "08:03:00 Boarded T1 at A" {
+		t.Fatal("The timeline should describe each moment with its simulation time.")
+	}
+
+	overnight := Timeline{{Type: JourneySpawn, Day: 0, SimTime: 86000}, {Type: JourneyAbandon, Day: 1, SimTime: 400}}
+	if overnight.Duration() != 800 || !overnight.Finished() {
+		t.Fatal("A journey should be able to cross midnight.")
+	}
+	if timeline.Contains(overnight[0]) || !timeline.Contains(timeline[5]) {
+		t.Fatal("Contains should find the events of the timeline only.")
+	}
+}
@@ -0,0 +1,144 @@
+package models
+
+import (
+	"math"
+	"sort"
+)
+
+// Timetable holds the scheduled arrivals of the trains, in seconds since midnight, for
+// every day type. It gives the delay of a train at a station and the advertised
+// headway of each platform. Day types without a schedule of their own run the one
+// of their fallback.
+type Timetable struct {
+	days map[DayType]*serviceDay
+}
+
+// serviceDay is the timetable of a day type.
+type serviceDay struct {
+	arrivals  map[timetableKey][]int   // Scheduled arrivals by train and station, sorted
+	platforms map[platformStop][]int   // Scheduled departures by platform and station
+	headways  map[platformStop]float64 // Median gap between departures, computed by Build
+}
+
+type timetableKey struct {
+	trainID, stationID int64
+}
+
+type platformStop struct {
+	platform  Platform
+	stationID int64
+}
+
+func NewTimetable() *Timetable {
+	return &Timetable{
+		days: make(map[DayType]*serviceDay),
+	}
+}
+
+// Add records a scheduled stop of a train on a day type. The platform is the one the
+// train leaves from, use the zero Platform when the direction is unknown.
+func (tt *Timetable) Add(dayType DayType, trainID, stationID int64, platform Platform, scheduled int) {
+	day, ok := tt.days[dayType]
+	if !ok {
+		day = &serviceDay{
+			arrivals:  make(map[timetableKey][]int),
+			platforms: make(map[platformStop][]int),
+			headways:  make(map[platformStop]float64),
+		}
+		tt.days[dayType] = day
+	}
+	key := timetableKey{trainID, stationID}
+	day.arrivals[key] = append(day.arrivals[key], scheduled)
+	if platform != (Platform{}) {
+		stop := platformStop{platform, stationID}
+		day.platforms[stop] = append(day.platforms[stop], scheduled)
+	}
+}
+
+// Build sorts the stops and computes the headways, it must be called after the last Add.
+func (tt *Timetable) Build() {
+	for _, day := range tt.days {
+		day.build()
+	}
+}
+
+func (day *serviceDay) build() {
+	for _, times := range day.arrivals {
+		sort.Ints(times)
+	}
+	for stop, times := range day.platforms {
+		sort.Ints(times)
+		gaps := make([]int, 0, len(times))
+		for i := 1; i < len(times); i++ {
+			if gap := times[i] - times[i-1]; gap > 0 {
+				gaps = append(gaps, gap)
+			}
+		}
+		if len(gaps) == 0 {
+			continue
+		}
+		sort.Ints(gaps)
+		day.headways[stop] = float64(gaps[len(gaps)/2])
+	}
+}
+
+// service returns the timetable a day type runs, following its fallbacks, or nil.
+func (tt *Timetable) service(dayType DayType) *serviceDay {
+	if tt == nil {
+		return nil
+	}
+	for source, ok := dayType, true; ok; source, ok = source.Fallback() {
+		if day, found := tt.days[source]; found {
+			return day
+		}
+	}
+	return nil
+}
+
+// Delay returns how late (positive) or early (negative) a train is at a station on a
+// day type, in seconds, compared with its closest scheduled arrival. It returns false
+// when the train has no stop scheduled there.
+func (tt *Timetable) Delay(dayType DayType, trainID, stationID int64, timeOfDay int) (int, bool) {
+	day := tt.service(dayType)
+	if day == nil {
+		return 0, false
+	}
+	times := day.arrivals[timetableKey{trainID, stationID}]
+	if len(times) == 0 {
+		return 0, false
+	}
+
+	best := 0
+	bestDist := math.MaxInt
+	for _, scheduled := range times {
+		delay := timeOfDay - scheduled
+		// The closest arrival may be on the other side of midnight.
+		if delay > 43200 {
+			delay -= 86400
+		} else if delay < -43200 {
+			delay += 86400
+		}
+		if dist := abs(delay); dist < bestDist {
+			best = delay
+			bestDist = dist
+		}
+	}
+	return best, true
+}
+
+// Headway returns the advertised time between trains leaving a station from a platform
+// on a day type, in seconds, or 0 when the timetable doesn't have enough departures.
+func (tt *Timetable) Headway(dayType DayType, platform Platform, stationID int64) float64 {
+	day := tt.service(dayType)
+	if day == nil {
+		return 0
+	}
+	return day.headways[platformStop{platform, stationID}]
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,65 @@
+package models
+
+import "testing"
+
+func TestTimetableDelay(t *testing.T) {
+	tt := NewTimetable()
+	tt.Add(DayTypeWeekday, 1, 10, Platform{}, 3600)
+	tt.Add(DayTypeWeekday, 1, 10, Platform{}, 86000)
+	tt.Build()
+
+	if delay, ok := tt.Delay(DayTypeWeekday, 1, 10, 3720); !ok || delay != 120 {
+		t.Fatal("The delay should be measured against the closest arrival.")
+	}
+	if delay, _ := tt.Delay(DayTypeWeekday, 1, 10, 3500); delay != -100 {
+		t.Fatal("Early trains should have a negative delay.")
+	}
+	if delay, _ := tt.Delay(DayTypeWeekday, 1, 10, 100); delay != 500 {
+		t.Fatal("The closest arrival may be before midnight.")
+	}
+	if _, ok := tt.Delay(DayTypeWeekday, 2, 10, 3600); ok {
+		t.Fatal("Trains without a stop at the station should have no delay.")
+	}
+
+	var empty *Timetable
+	if _, ok := empty.Delay(DayTypeWeekday, 1, 10, 0); ok || empty.Headway(DayTypeWeekday, Platform{}, 10) != 0 {
+		t.Fatal("A nil timetable should have no delays or headways.")
+	}
+}
+
+func TestTimetableHeadway(t *testing.T) {
+	tt := NewTimetable()
+	platform := Platform{LineID: 1, Forward: true}
+	for i, scheduled := range []int{0, 300, 600, 1500} {
+		tt.Add(DayTypeWeekday, int64(i), 10, platform, scheduled)
+	}
+	tt.Add(DayTypeWeekday, 9, 10, Platform{LineID: 1}, 50)
+	tt.Build()
+
+	if tt.Headway(DayTypeWeekday, platform, 10) != 300 {
+		t.Fatal("The headway should be the median gap between departures.")
+	}
+	if tt.Headway(DayTypeWeekday, Platform{LineID: 1}, 10) != 0 {
+		t.Fatal("A single departure should not have a headway.")
+	}
+}
+
+func TestTimetableDayTypes(t *testing.T) {
+	tt := NewTimetable()
+	tt.Add(DayTypeWeekday, 1, 10, Platform{}, 3600)
+	tt.Add(DayTypeSunday, 1, 10, Platform{}, 7200)
+	tt.Build()
+
+	if delay, _ := tt.Delay(DayTypeWeekday, 1, 10, 3600); delay != 0 {
+		t.Fatal("Weekdays should run the weekday timetable.")
+	}
+	if delay, _ := tt.Delay(DayTypeSaturday, 1, 10, 3600); delay != 0 {
+		t.Fatal("Saturdays without a timetable should run the weekday one.")
+	}
+	if delay, _ := tt.Delay(DayTypeSunday, 1, 10, 3600); delay != -3600 {
+		t.Fatal("Sundays should run their own timetable.")
+	}
+	if delay, _ := tt.Delay(DayTypeHoliday, 1, 10, 7200); delay != 0 {
+		t.Fatal("Holidays should run the Sunday timetable.")
+	}
+}
@@ -0,0 +1,171 @@
+package models
+
+import (
+	"fmt"
+	"math"
+)
+
+// EdgePolyline returns the full geometry of the edge going from one station to
+// another: the position of the first station, the points in between and the
+// position of the second station. When the edge has a platform offset the whole
+// track, platforms included, is shifted to the right of the travel direction.
+func EdgePolyline(central *Network[*Station], from, to *Station) (Polyline, error) {
+	points, offset, err := central.EdgeByKey(central.KeyOf(from), central.KeyOf(to))
+	if err != nil {
+		return Polyline{}, err
+	}
+
+	full := make([]Vector, 0, len(points)+2)
+	full = append(full, from.GetPosition())
+	full = append(full, points...)
+	full = append(full, to.GetPosition())
+	if offset != 0 {
+		full = OffsetPoints(full, offset)
+	}
+	return NewPolyline(full), nil
+}
+
+// TrackSegment is the geometry of a directed edge between two stations.
+type TrackSegment struct {
+	From *Station
+	To   *Station
+	Line Polyline
+}
+
+// TrackHit is the result of looking up the closest track to a point.
+type TrackHit struct {
+	Segment    *TrackSegment
+	Projection Projection
+}
+
+type gridCell struct {
+	x, y int
+}
+
+// SpatialIndex is a uniform grid over stations and track segments used for
+// hit-testing and nearest-neighbour queries.
+type SpatialIndex struct {
+	cellSize float64
+	stations map[gridCell][]*Station
+	segments map[gridCell][]int
+	tracks   []TrackSegment
+}
+
+// NewSpatialIndex builds an index with every station and every edge of the network.
+// The cell size should be close to the usual search radius.
+func NewSpatialIndex(central *Network[*Station], stations []*Station, cellSize float64) (*SpatialIndex, error) {
+	if cellSize <= 0 {
+		return nil, fmt.Errorf("invalid cell size: %f", cellSize)
+	}
+
+	si := &SpatialIndex{
+		cellSize: cellSize,
+		stations: make(map[gridCell][]*Station),
+		segments: make(map[gridCell][]int),
+		tracks:   make([]TrackSegment, 0),
+	}
+
+	stationsByKey := make(map[string]*Station, len(stations))
+	for _, st := range stations {
+		stationsByKey[central.KeyOf(st)] = st
+		c := si.cellOf(st.GetPosition())
+		si.stations[c] = append(si.stations[c], st)
+	}
+
+	for _, from := range stations {
+		neighbors, err := central.GetNeighborKeys(central.KeyOf(from))
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range neighbors {
+			to, ok := stationsByKey[key]
+			if !ok {
+				continue
+			}
+			line, err := EdgePolyline(central, from, to)
+			if err != nil {
+				return nil, err
+			}
+			si.tracks = append(si.tracks, TrackSegment{From: from, To: to, Line: line})
+			si.insertSegment(len(si.tracks) - 1)
+		}
+	}
+
+	return si, nil
+}
+
+// Tracks returns every track segment in the index.
+func (si *SpatialIndex) Tracks() []TrackSegment {
+	return si.tracks
+}
+
+// NearestStation returns the closest station to p within the given radius.
+func (si *SpatialIndex) NearestStation(p Vector, radius float64) (*Station, float64, bool) {
+	var nearest *Station
+	best := math.Inf(1)
+	si.visitCells(p, radius, func(c gridCell) {
+		for _, st := range si.stations[c] {
+			position := st.GetPosition()
+			d := position.Dist(p)
+			if d <= radius && d < best {
+				nearest = st
+				best = d
+			}
+		}
+	})
+	return nearest, best, nearest != nil
+}
+
+// NearestTrack returns the closest track segment to p within the given radius.
+func (si *SpatialIndex) NearestTrack(p Vector, radius float64) (TrackHit, bool) {
+	var hit TrackHit
+	found := false
+	seen := make(map[int]bool)
+	si.visitCells(p, radius, func(c gridCell) {
+		for _, idx := range si.segments[c] {
+			if seen[idx] {
+				continue
+			}
+			seen[idx] = true
+			proj, err := si.tracks[idx].Line.Project(p)
+			if err != nil || proj.Distance > radius {
+				continue
+			}
+			if !found || proj.Distance < hit.Projection.Distance {
+				hit = TrackHit{Segment: &si.tracks[idx], Projection: proj}
+				found = true
+			}
+		}
+	})
+	return hit, found
+}
+
+func (si *SpatialIndex) cellOf(p Vector) gridCell {
+	return gridCell{
+		x: int(math.Floor(p.X / si.cellSize)),
+		y: int(math.Floor(p.Y / si.cellSize)),
+	}
+}
+
+// insertSegment registers a segment in every cell touched by its bounding box.
+func (si *SpatialIndex) insertSegment(idx int) {
+	min, max := si.tracks[idx].Line.Bounds()
+	from, to := si.cellOf(min), si.cellOf(max)
+	for x := from.x; x <= to.x; x++ {
+		for y := from.y; y <= to.y; y++ {
+			c := gridCell{x, y}
+			si.segments[c] = append(si.segments[c], idx)
+		}
+	}
+}
+
+// visitCells calls fn for every cell that overlaps the square around p.
+func (si *SpatialIndex) visitCells(p Vector, radius float64, fn func(gridCell)) {
+	from := si.cellOf(NewVector(p.X-radius, p.Y-radius))
+	to := si.cellOf(NewVector(p.X+radius, p.Y+radius))
+	for x := from.x; x <= to.x; x++ {
+		for y := from.y; y <= to.y; y++ {
+			fn(gridCell{x, y})
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package models
+
+import (
+	"math"
+	"testing"
+)
+
+// newTrackNetwork returns a network with a long track from A to B along y = 0 and a
+// short one from C to D along y = 30, with a cell size of 10.
+func newTrackNetwork(t *testing.T) (*SpatialIndex, []*Station) {
+	positions := []Vector{NewVector(0, 0), NewVector(100, 0), NewVector(40, 30), NewVector(60, 30)}
+	stations := make([]*Station, len(positions))
+	central := NewNetwork(func(st *Station) string { return st.Name })
+	for i, p := range positions {
+		stations[i] = NewStation(int64(i+1), string(rune('A'+i)), p)
+	}
+	central.InsertVertices(stations)
+	central.InsertEdge(stations[0], stations[1], nil)
+	central.InsertEdge(stations[2], stations[3], nil)
+
+	si, err := NewSpatialIndex(central, stations, 10)
+	if err != nil {
+		t.Fatal("The index should have been built.")
+	}
+	return si, stations
+}
+
+func TestSpatialIndexInvalidCellSize(t *testing.T) {
+	central := NewNetwork(func(st *Station) string { return st.Name })
+	if _, err := NewSpatialIndex(central, nil, 0); err == nil {
+		t.Fatal("A cell size of 0 should be rejected.")
+	}
+}
+
+func TestSpatialIndexTracks(t *testing.T) {
+	si, _ := newTrackNetwork(t)
+
+	if len(si.Tracks()) != 4 {
+		t.Fatal("Every direction of every edge should be a track.")
+	}
+}
+
+func TestNearestStation(t *testing.T) {
+	si, stations := newTrackNetwork(t)
+
+	st, d, ok := si.NearestStation(NewVector(43, 28), 10)
+	if !ok || st != stations[2] || RoundFloat(d, 2) != RoundFloat(math.Sqrt(13), 2) {
+		t.Fatal("The closest station should have been found.")
+	}
+	if st, _, _ := si.NearestStation(NewVector(52, 30), 15); st != stations[3] {
+		t.Fatal("The closer of two stations within the radius should have been found.")
+	}
+	if _, _, ok := si.NearestStation(NewVector(20, 0), 10); ok {
+		t.Fatal("A station further than the radius should not be found.")
+	}
+}
+
+func TestNearestStationAcrossCells(t *testing.T) {
+	si, stations := newTrackNetwork(t)
+
+	// B sits on the boundary of two cells, the point is in the cell to its left
+	st, _, ok := si.NearestStation(NewVector(99.5, 0.5), 1)
+	if !ok || st != stations[1] {
+		t.Fatal("A station on a cell boundary should be found from the neighbouring cell.")
+	}
+	// A is at the origin, the point is in the cells of negative coordinates
+	st, _, ok = si.NearestStation(NewVector(-0.5, -0.5), 1)
+	if !ok || st != stations[0] {
+		t.Fatal("A station should be found from the cells of negative coordinates.")
+	}
+}
+
+func TestNearestTrack(t *testing.T) {
+	si, stations := newTrackNetwork(t)
+
+	// The middle of A-B is several cells away from both of its stations
+	hit, ok := si.NearestTrack(NewVector(50, 4), 5)
+	if !ok || hit.Segment.From != stations[0] && hit.Segment.From != stations[1] {
+		t.Fatal("The track should be found away from its stations.")
+	}
+	if RoundFloat(hit.Projection.Distance, 2) != 4 || RoundFloat(hit.Projection.Point.X, 2) != 50 {
+		t.Fatal("The point should have been projected onto the track.")
+	}
+
+	hit, ok = si.NearestTrack(NewVector(50, 20), 15)
+	if !ok || hit.Segment.From != stations[2] && hit.Segment.From != stations[3] {
+		t.Fatal("The closer of two tracks within the radius should have been found.")
+	}
+	if _, ok := si.NearestTrack(NewVector(50, 15), 5); ok {
+		t.Fatal("A track further than the radius should not be found.")
+	}
+}
+
+func TestNearestTrackAcrossCells(t *testing.T) {
+	si, _ := newTrackNetwork(t)
+
+	// C-D lies on the boundary between the rows of cells at y = 30, the point is
+	// in the row below it
+	hit, ok := si.NearestTrack(NewVector(50, 29.5), 1)
+	if !ok || RoundFloat(hit.Projection.Distance, 2) != 0.5 {
+		t.Fatal("A track on a cell boundary should be found from the neighbouring cell.")
+	}
+	// Past the end of A-B, in a cell the track doesn't reach
+	hit, ok = si.NearestTrack(NewVector(101, 0), 2)
+	if !ok || RoundFloat(hit.Projection.Distance, 2) != 1 {
+		t.Fatal("A track should be found from a cell next to its end.")
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"image"
 	_ "image/png"
+	"math"
+	"math/rand"
 	"sync"
 	"time"
 
@@ -36,22 +38,45 @@ type Train struct {
 	Next           *Station
 	forward        bool
 	destinations   Line
+	pendingLine    *Line      // Line edit waiting for the train to reach a station
+	routeMutex     sync.Mutex // Thread safety for line edits
 	q              Queue[Vector]
-	central        *Network[Station]
+	route          Polyline // Geometry of the edge between Current and Next
+	odometer       float64  // Distance covered along completed edges, in pixels
+	central        *Network[*Station]
 	waitCounter    int                // Ticks to wait at station (non-blocking)
 	waitTicks      int                // Precomputed wait duration in ticks
+	refreshTicks   int                // Ticks between refreshes of the predictions
+	refreshCounter int                // Ticks since the predictions were refreshed
+	arrived        bool               // Reached the end of the route while moving, Serve handles the arrival
 	eventChannel   chan<- interface{} // Channel to send events to Tenjin
 	tickCounter    int                // Counter for periodic tick events (emit every 60 ticks)
 	Capacity       int                // Maximum number of passengers
+	Seats          int                // Seated places, the rest of the capacity is standing room
 	Passengers     []*Passenger       // Current passengers on board
 	passengerMutex sync.RWMutex       // Thread safety for passenger operations
 	clock          ClockInterface     // Simulation clock for timing
+	timetable      *Timetable         // Scheduled arrivals, nil when the train has no schedule
+	delay          int                // Seconds behind schedule at the last station, guarded by routeMutex
+	predictions    []ArrivalPrediction // Latest predicted arrivals, guarded by routeMutex
+	legTicks       map[legKey]legTravel // Travel between two stops from a standstill, by station IDs and top speed
+	arrival        *TrainArrival       // Last arrival at a station, guarded by routeMutex
+	rng            *rand.Rand          // Shuffles the boarding queue, nil uses the global generator
+	commands       chan commandRequest // Commands waiting for the next tick, see Dispatcher
+	holdTicks      int                 // Ticks added to the dwell at the next station
+	skipStop       int64               // Station passed without stopping, 0 for none
+	turnAt         int64               // Station where the train turns back, 0 for the terminals
+	speedCap       float64             // Top speed set by a command in pixels/tick, 0 for none
+	leaving        bool                // Goes to the depot at the next station
+	inDepot        bool                // Out of service
 	Drawing
 }
 
 // ClockInterface provides access to simulation time
 type ClockInterface interface {
 	GetCurrentTimeOfDay() int // Returns seconds since midnight
+	GetDay() int              // Returns the simulated day
+	GetDayType() DayType      // Returns the service the simulated day runs
 }
 
 func NewTrain(
@@ -61,13 +86,14 @@ func NewTrain(
 	pos Vector,
 	initialStation *Station,
 	line Line,
-	central *Network[Station],
+	central *Network[*Station],
 	eventChannel chan<- interface{},
 	clock ClockInterface,
 ) Train {
 	img, frameWidth, frameHeight, frameCount := assets.GetTrainSprite()
 	// Precompute wait duration in ticks
 	waitTicks := int(control.DefaultConfig.TrainWaitInStation / control.DefaultConfig.LoopDuration)
+	refreshTicks := max(1, int(control.DefaultConfig.PredictionRate/control.DefaultConfig.LoopDuration))
 	return Train{
 		ID:           id,
 		Name:         name,
@@ -81,11 +107,14 @@ func NewTrain(
 		q:            Queue[Vector]{},
 		central:      central,
 		waitTicks:    waitTicks,
+		refreshTicks: refreshTicks,
 		eventChannel: eventChannel,
 		tickCounter:  0,
 		Capacity:     50, // Default capacity: 50 passengers
+		Seats:        20,
 		Passengers:   make([]*Passenger, 0),
 		clock:        clock,
+		commands:     make(chan commandRequest, max(1, control.DefaultConfig.Commands.Buffer)),
 		Drawing: Drawing{
 			Counter:     0,
 			FrameWidth:  frameWidth,
@@ -144,15 +173,11 @@ func (tr *Train) logArrival(stationName string) {
 			Train:       tr.Name,
 			StationID:   tr.Current.ID,
 			StationName: stationName,
-			Time:        time.Now(),
+			Time:        Now(),
 			SimTime:     simTime,
 			Position:    tr.Position,
 		}
-		select {
-		case tr.eventChannel <- event:
-		default:
-			// Channel full, skip event (non-blocking)
-		}
+		emit(tr.eventChannel, event)
 	}
 }
 
@@ -180,14 +205,10 @@ func (tr *Train) logDeparture(stationName string) {
 			StationID:   tr.Current.ID,
 			StationName: stationName,
 			NextStation: nextName,
-			Time:        time.Now(),
+			Time:        Now(),
 			Position:    tr.Position,
 		}
-		select {
-		case tr.eventChannel <- event:
-		default:
-			// Channel full, skip event (non-blocking)
-		}
+		emit(tr.eventChannel, event)
 	}
 }
 
@@ -222,51 +243,143 @@ func (tr *Train) getNextFromDestinations() *Station {
 	return next
 }
 
-func (tr *Train) Tick() {
-	// Increment tick counter for periodic events
-	tr.tickCounter++
+// GetLine returns the line the train runs on.
+func (tr *Train) GetLine() Line {
+	tr.routeMutex.Lock()
+	defer tr.routeMutex.Unlock()
+	return tr.destinations
+}
 
-	// Emit tick event every 60 ticks (once per second)
-	if tr.tickCounter >= 60 {
-		tr.emitTickEvent()
-		tr.tickCounter = 0
+// ReplaceLine updates the stations of the line the train runs on, if it has the same ID.
+// The change is applied when the train leaves its next station, so the route it is
+// currently following never changes under it.
+func (tr *Train) ReplaceLine(line Line) {
+	tr.routeMutex.Lock()
+	defer tr.routeMutex.Unlock()
+	if line.ID != tr.destinations.ID {
+		return
 	}
+	tr.pendingLine = &line
+}
 
-	// If waiting at station, decrement counter and skip this tick
-	if tr.waitCounter > 0 {
-		tr.waitCounter--
-		return
+// ServesLeg returns true if the train, leaving its current station, takes a passenger
+// to the end of the leg. A nil leg, for passengers without itinerary, is always served.
+func (tr *Train) ServesLeg(leg *Leg) bool {
+	if leg == nil {
+		return true
+	}
+	if tr.Current == nil || leg.From.ID != tr.Current.ID || leg.LineID != tr.destinations.ID {
+		return false
+	}
+	from, to := -1, -1
+	for i, st := range tr.destinations.Stations {
+		if st.ID == leg.From.ID {
+			from = i
+		}
+		if st.ID == leg.To.ID {
+			to = i
+		}
 	}
+	if from == -1 || to == -1 {
+		return false
+	}
+	// The train doesn't go past the station of a short-turn
+	if turn := stationIndex(tr.destinations.Stations, tr.turnAt); turn != -1 && (from < turn && turn < to || to < turn && turn < from) {
+		return false
+	}
+	return (to > from) == tr.departsForward(from)
+}
 
-	// If there is no next station, assign one from the destinations queue
-	if tr.Next == nil {
-		tr.Next = tr.getNextFromDestinations()
+// departsForward returns the direction the train takes when it leaves the station at
+// the given index of its line, reversing at the terminals like getNextFromDestinations
+// and at the station of a short-turn.
+func (tr *Train) departsForward(index int) bool {
+	if tr.turnAt != 0 && tr.destinations.Stations[index].ID == tr.turnAt {
+		return !tr.forward
+	}
+	return departsFrom(index, tr.forward, len(tr.destinations.Stations))
+}
 
-		// Adding points between the current station and the next one.
-		path, err := tr.central.AreConnected(*tr.Current, *tr.Next)
-		path = append(path, tr.Next.Position)
-		if err != nil {
-			errMsg := fmt.Sprintf("Error connecting stations %s to %s: %v", tr.Current.Name, tr.Next.Name, err)
-			control.Log(errMsg)
-			tr.emitErrorEvent(errMsg, "path_connection")
-		}
-		tr.addToQueue(path)
+// departsFrom returns the direction a train going forward or backward takes when it
+// leaves the station at the given index of a line of count stations.
+func departsFrom(index int, forward bool, count int) bool {
+	if forward && index == count-1 {
+		return false
+	}
+	if !forward && index == 0 {
+		return true
+	}
+	return forward
+}
 
-		tr.logDeparture(tr.Current.Name)
+// stepFrom returns the index of the station a train leaving the one at the given
+// index in the given direction goes to.
+func stepFrom(index int, forward bool) int {
+	if forward {
+		return index + 1
 	}
+	return index - 1
+}
+
+// SetRand sets the random generator of the train, so a seeded run boards the same
+// passengers every time.
+func (tr *Train) SetRand(rng *rand.Rand) {
+	tr.rng = rng
+}
 
-	// Update velocity based of direction of next location
+// SetTimetable gives the train its scheduled arrivals, used to know how late it runs.
+func (tr *Train) SetTimetable(timetable *Timetable) {
+	tr.routeMutex.Lock()
+	defer tr.routeMutex.Unlock()
+	tr.timetable = timetable
+}
+
+// GetDelay returns how many seconds the train was behind schedule at its last station,
+// negative when it was early.
+func (tr *Train) GetDelay() int {
+	tr.routeMutex.Lock()
+	defer tr.routeMutex.Unlock()
+	return tr.delay
+}
+
+func (tr *Train) updateDelay() {
+	if tr.clock == nil {
+		return
+	}
+	tr.routeMutex.Lock()
+	defer tr.routeMutex.Unlock()
+	if delay, ok := tr.timetable.Delay(tr.clock.GetDayType(), tr.ID, tr.Current.ID, tr.clock.GetCurrentTimeOfDay()); ok {
+		tr.delay = delay
+	}
+}
+
+func (tr *Train) applyPendingLine() {
+	tr.routeMutex.Lock()
+	defer tr.routeMutex.Unlock()
+	if tr.pendingLine != nil {
+		tr.destinations = *tr.pendingLine
+	}
+	tr.pendingLine = nil
+}
+
+// Move is the movement phase of a tick: the train follows its route and touches
+// nothing but its own position, so every train can move at the same time. Reaching
+// the station at the end of the route is left to Serve.
+func (tr *Train) Move() {
+	if tr.waitCounter > 0 || tr.Next == nil || tr.arrived {
+		return
+	}
+
+	// Update velocity based of direction of next location, Serve reports an empty queue
 	reach, err := tr.q.Peek()
 	if err != nil {
-		errMsg := fmt.Sprintf("Train %s: No items in queue", tr.Name)
-		control.Log(errMsg)
-		tr.emitErrorEvent(errMsg, "empty_queue")
 		return
 	}
 
 	direction := reach.SoftSub(tr.Position)
 	mag := direction.Magnitude()
-	where := tr.Current.Position.Dist(reach) / 4 // Larger slowing zone for faster trains
+	start, _, _ := tr.route.PositionAt(0)
+	where := start.Dist(reach) / 4 // Larger slowing zone for faster trains
 
 	// Slow down if we are close - scale velocity directly for visible deceleration.
 	if mag < where {
@@ -278,7 +391,7 @@ func (tr *Train) Tick() {
 
 	// Update position based on velocity
 	tr.velocity.Add(direction)
-	tr.velocity.Limit(tr.model.TopSpeed)
+	tr.velocity.Limit(tr.topSpeed())
 	tr.Position.Add(tr.velocity)
 	distance := tr.Position.Dist(reach)
 
@@ -288,20 +401,131 @@ func (tr *Train) Tick() {
 			return
 		}
 		tr.velocity.Scale(0)
-		if tr.q.Size() == 0 {
-			tr.Current = tr.Next
-			tr.Next = nil
+		tr.arrived = tr.q.Size() == 0
+	}
+}
 
-			// Log arrival
-			tr.logArrival(tr.Current.Name)
+// Serve is the station phase of a tick: the train arrives at the station it reached
+// while moving, exchanges passengers with it, waits and sets off to the next one.
+// Trains are served one after the other, as they share the stations and passengers.
+func (tr *Train) Serve() {
+	// Commands sent since the last tick, trains in the depot only take commands
+	tr.applyCommands()
+	if tr.inDepot {
+		return
+	}
 
-			// Passenger operations
-			tr.handlePassengerDisembark()
-			tr.handlePassengerBoarding()
+	// Increment tick counter for periodic events
+	tr.tickCounter++
 
-			// Use precomputed wait ticks
-			tr.waitCounter = tr.waitTicks
-			return
+	// Emit tick event every 60 ticks (once per second)
+	if tr.tickCounter >= 60 {
+		tr.emitTickEvent()
+		tr.tickCounter = 0
+	}
+
+	// Refresh the predictions as often as they are gathered
+	tr.refreshCounter++
+	if tr.refreshCounter >= tr.refreshTicks {
+		tr.refreshPredictions()
+		tr.refreshCounter = 0
+	}
+
+	if tr.arrived {
+		tr.arrive()
+		return
+	}
+
+	// If waiting at station, decrement counter and skip this tick
+	if tr.waitCounter > 0 {
+		tr.waitCounter--
+		return
+	}
+
+	// If there is no next station, assign one from the destinations queue
+	if tr.Next == nil {
+		tr.depart()
+	}
+
+	if tr.q.Size() == 0 {
+		errMsg := fmt.Sprintf("Train %s: No items in queue", tr.Name)
+		control.Log(errMsg)
+		tr.emitErrorEvent(errMsg, "empty_queue")
+	}
+}
+
+// depart sets off to the next station of the line, the train moves from the next tick.
+func (tr *Train) depart() {
+	tr.applyPendingLine()
+	if tr.turnAt != 0 && tr.turnAt == tr.Current.ID {
+		tr.forward = !tr.forward
+	}
+	tr.turnAt = 0
+	tr.Next = tr.getNextFromDestinations()
+
+	// Adding points between the current station and the next one.
+	route, err := EdgePolyline(tr.central, tr.Current, tr.Next)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error connecting stations %s to %s: %v", tr.Current.Name, tr.Next.Name, err)
+		control.Log(errMsg)
+		tr.emitErrorEvent(errMsg, "path_connection")
+		route = NewPolyline([]Vector{tr.Current.GetPosition(), tr.Next.GetPosition()})
+	}
+	tr.route = route
+	path := route.Points()
+	// Skip the start of the route when the train is already there, platform
+	// offsets can make it differ from the position where the train stopped.
+	if len(path) > 1 && tr.Position.Dist(path[0]) <= 1 {
+		path = path[1:]
+	}
+	tr.addToQueue(path)
+
+	tr.logDeparture(tr.Current.Name)
+}
+
+// arrive stops the train at the station at the end of its route, lets passengers
+// off and on and starts the wait.
+func (tr *Train) arrive() {
+	tr.arrived = false
+	tr.odometer += tr.route.Length()
+	tr.Current = tr.Next
+	tr.Next = nil
+
+	// A skipped stop is passed, the train leaves at the next tick
+	if tr.skipStop != 0 && tr.skipStop == tr.Current.ID {
+		tr.skipStop = 0
+		control.Log(fmt.Sprintf("%s passed %s without stopping", tr.Name, tr.Current.Name))
+		return
+	}
+
+	// Log arrival
+	tr.logArrival(tr.Current.Name)
+	tr.updateDelay()
+	tr.recordArrival()
+
+	// Passenger operations, the passengers going further than a short-turn or a
+	// train leaving service continue with the next train
+	tr.handlePassengerDisembark()
+	if tr.leaving {
+		tr.toDepot()
+		return
+	}
+	if tr.turnAt != 0 && tr.turnAt == tr.Current.ID {
+		tr.setDown()
+	}
+	tr.handlePassengerBoarding()
+
+	// Use precomputed wait ticks, with the hold of a command
+	tr.waitCounter = tr.waitTicks + tr.holdTicks
+	tr.holdTicks = 0
+	tr.refreshPredictions()
+}
+
+// TickPassengers is the passenger phase of a tick for the passengers on board.
+func (tr *Train) TickPassengers() {
+	for _, p := range tr.GetPassengers() {
+		if p.State == PassengerStateRiding {
+			p.Tick()
 		}
 	}
 }
@@ -340,6 +564,7 @@ func (tr *Train) emitTickEvent() {
 		Speed          float64
 		CurrentStation int64
 		NextStation    int64
+		Odometer       float64
 		Time           time.Time
 	}{
 		Type:           "train_tick",
@@ -349,14 +574,11 @@ func (tr *Train) emitTickEvent() {
 		Speed:          tr.velocity.Magnitude(),
 		CurrentStation: tr.Current.ID,
 		NextStation:    nextStationID,
-		Time:           time.Now(),
+		Odometer:       tr.GetOdometer(),
+		Time:           Now(),
 	}
 
-	select {
-	case tr.eventChannel <- event:
-	default:
-		// Channel full, skip event (non-blocking)
-	}
+	emit(tr.eventChannel, event)
 }
 
 // emitErrorEvent sends error events to Tenjin
@@ -376,14 +598,10 @@ func (tr *Train) emitErrorEvent(errMsg, context string) {
 		Train:   tr.Name,
 		Error:   errMsg,
 		Context: context,
-		Time:    time.Now(),
+		Time:    Now(),
 	}
 
-	select {
-	case tr.eventChannel <- event:
-	default:
-		// Channel full, skip event (non-blocking)
-	}
+	emit(tr.eventChannel, event)
 }
 
 // Passenger management methods
@@ -407,12 +625,20 @@ func (tr *Train) RemovePassenger(passenger *Passenger) bool {
 	tr.passengerMutex.Lock()
 	defer tr.passengerMutex.Unlock()
 
+	// Keep the boarding order, standing passengers take the seats that are freed
+	passengers, ok := removeFromQueue(tr.Passengers, passenger)
+	tr.Passengers = passengers
+	return ok
+}
+
+// HasSeat returns true if the passenger is seated, the first passengers to board get the seats
+func (tr *Train) HasSeat(passenger *Passenger) bool {
+	tr.passengerMutex.RLock()
+	defer tr.passengerMutex.RUnlock()
+
 	for i, p := range tr.Passengers {
 		if p.ID == passenger.ID {
-			// Remove passenger by swapping with last and truncating
-			tr.Passengers[i] = tr.Passengers[len(tr.Passengers)-1]
-			tr.Passengers = tr.Passengers[:len(tr.Passengers)-1]
-			return true
+			return i < tr.Seats
 		}
 	}
 	return false
@@ -469,6 +695,19 @@ func (tr *Train) GetDistanceToNext() float64 {
 	return PixelsToMeters(pixelDistance)
 }
 
+// GetOdometer returns the distance traveled in pixels, measured along the track
+// geometry: completed edges plus the progress made on the current one.
+func (tr *Train) GetOdometer() float64 {
+	if tr.Next == nil {
+		return tr.odometer
+	}
+	proj, err := tr.route.Project(tr.Position)
+	if err != nil {
+		return tr.odometer
+	}
+	return tr.odometer + proj.Along
+}
+
 // GetPassengers returns a copy of the passengers slice (thread-safe)
 func (tr *Train) GetPassengers() []*Passenger {
 	tr.passengerMutex.RLock()
@@ -494,33 +733,327 @@ func (tr *Train) GetPassengersForStation(stationID int64) []*Passenger {
 }
 
 // handlePassengerDisembark removes passengers who have reached their destination
+// or the end of their current leg
 func (tr *Train) handlePassengerDisembark() {
 	if tr.Current == nil {
 		return
 	}
 
-	passengers := tr.GetPassengersForStation(tr.Current.ID)
-	for _, p := range passengers {
-		tr.RemovePassenger(p)
-		p.DisembarkTrain(tr.Current)
+	day := tr.simDay()
+	for _, p := range tr.GetPassengers() {
+		if p.AlightsAt(tr.Current.ID) {
+			tr.letOff(p, day)
+		}
+	}
+}
+
+// letOff takes a passenger off the train at the current station
+func (tr *Train) letOff(p *Passenger, day int) {
+	tr.RemovePassenger(p)
+	tr.Current.recordAlighting(day)
+	p.DisembarkTrain(tr.Current)
+	// Transferring passengers walk to the platform of their next train.
+	if p.State == PassengerStateWaiting {
+		tr.Current.Transfer(p)
 	}
 }
 
-// handlePassengerBoarding boards waiting passengers up to capacity
+// Boarding policies, see control.Config.BoardingPolicy
+const (
+	BoardingFIFO       = "fifo"
+	BoardingRandomDoor = "random_door"
+)
+
+// handlePassengerBoarding boards the queue of the platform the train departs from,
+// up to capacity. Passengers left on the platform because the train is full are
+// denied boarding.
 func (tr *Train) handlePassengerBoarding() {
-	if tr.Current == nil || tr.IsFull() {
+	if tr.Current == nil {
 		return
 	}
+	platform, ok := tr.departurePlatform()
+	if !ok {
+		return
+	}
+
+	// Passengers of the platform, then the ones without itinerary that take any train
+	queue := tr.Current.GetPlatformQueue(platform)
+	queue = append(queue, tr.Current.GetPlatformQueue(Platform{})...)
+	if control.DefaultConfig.BoardingPolicy == BoardingRandomDoor {
+		shuffle := rand.Shuffle
+		if tr.rng != nil {
+			shuffle = tr.rng.Shuffle
+		}
+		shuffle(len(queue), func(i, j int) { queue[i], queue[j] = queue[j], queue[i] })
+	}
 
-	// Get waiting passengers at this station
-	waiting := tr.Current.GetWaitingPassengers()
-	for _, p := range waiting {
+	day := tr.simDay()
+	for _, p := range queue {
+		if !tr.ServesLeg(p.CurrentLeg()) {
+			continue
+		}
 		if tr.IsFull() {
-			break
+			p.DenyBoarding(tr)
+			continue
 		}
 		// Board passenger
 		tr.Current.RemovePassenger(p)
+		tr.Current.recordBoarding(day, p.waitSeconds())
 		tr.AddPassenger(p)
 		p.BoardTrain(tr)
 	}
 }
+
+// departurePlatform returns the platform of the current station the train leaves from.
+func (tr *Train) departurePlatform() (Platform, bool) {
+	return tr.platformAt(tr.Current)
+}
+
+// platformAt returns the platform the train leaves a station of its line from, when
+// it is the next or the current station.
+func (tr *Train) platformAt(station *Station) (Platform, bool) {
+	for i, st := range tr.destinations.Stations {
+		if st.ID == station.ID {
+			return Platform{LineID: tr.destinations.ID, Forward: tr.departsForward(i)}, true
+		}
+	}
+	return Platform{}, false
+}
+
+// incomingTo describes the train for the dashboard of a station, when it is on its
+// way there or stopped at it.
+func (tr *Train) incomingTo(st *Station) (IncomingTrain, bool) {
+	if tr.inDepot {
+		return IncomingTrain{}, false
+	}
+	next, current := tr.Next, tr.Current
+	distance := 0.0
+	switch {
+	case next != nil && next.ID == st.ID:
+		distance = tr.remainingDistance()
+	case next == nil && current != nil && current.ID == st.ID:
+	default:
+		return IncomingTrain{}, false
+	}
+	platform, ok := tr.platformAt(st)
+	if !ok {
+		return IncomingTrain{}, false
+	}
+	return IncomingTrain{
+		TrainID:    tr.ID,
+		TrainName:  tr.Name,
+		Platform:   platform,
+		Distance:   distance,
+		Passengers: tr.GetPassengerCount(),
+		Capacity:   tr.Capacity,
+	}, true
+}
+
+// remainingDistance returns the pixels left to the next station along the track.
+func (tr *Train) remainingDistance() float64 {
+	proj, err := tr.route.Project(tr.Position)
+	if err != nil {
+		return tr.Position.Dist(tr.Next.GetPosition())
+	}
+	return math.Max(0, tr.route.Length()-proj.Along)
+}
+
+// LastArrival returns the last arrival of the train at a station, false before the first.
+func (tr *Train) LastArrival() (TrainArrival, bool) {
+	tr.routeMutex.Lock()
+	defer tr.routeMutex.Unlock()
+	if tr.arrival == nil {
+		return TrainArrival{}, false
+	}
+	return *tr.arrival, true
+}
+
+func (tr *Train) recordArrival() {
+	platform, _ := tr.departurePlatform()
+	arrival := &TrainArrival{StationID: tr.Current.ID, Platform: platform, Time: tr.simNow()}
+	tr.routeMutex.Lock()
+	defer tr.routeMutex.Unlock()
+	tr.arrival = arrival
+}
+
+// GetPredictions returns the latest predicted arrivals of the train at the stations
+// of its line, refreshed every PredictionRate.
+func (tr *Train) GetPredictions() []ArrivalPrediction {
+	tr.routeMutex.Lock()
+	defer tr.routeMutex.Unlock()
+	return append([]ArrivalPrediction(nil), tr.predictions...)
+}
+
+func (tr *Train) refreshPredictions() {
+	predictions := tr.predictArrivals()
+	tr.routeMutex.Lock()
+	defer tr.routeMutex.Unlock()
+	tr.predictions = predictions
+}
+
+// predictArrivals estimates when the train gets to each stop of a round trip of its
+// line: the rest of the dwell at its station, the travel of every edge for its make
+// and the dwell at the stops in between. Holds at the station are part of the dwell,
+// and the commands of the train are followed: the hold at the next station, the
+// skipped stop, the short-turn and the last stop before the depot.
+func (tr *Train) predictArrivals() []ArrivalPrediction {
+	stations := tr.destinations.Stations
+	count := len(stations)
+	if tr.Current == nil || count < 2 {
+		return nil
+	}
+	tickSeconds := control.DefaultConfig.LoopDuration.Seconds() * control.DefaultConfig.SimulationSpeed
+	issued := tr.simNow()
+
+	predictions := make([]ArrivalPrediction, 0, 2*count)
+	seen := make(map[Platform]map[int64]bool)
+	add := func(index int, departs bool, ticks int, atPlatform bool) bool {
+		platform := Platform{LineID: tr.destinations.ID, Forward: departs}
+		st := stations[index]
+		if seen[platform][st.ID] {
+			return false
+		}
+		if seen[platform] == nil {
+			seen[platform] = make(map[int64]bool)
+		}
+		seen[platform][st.ID] = true
+		predictions = append(predictions, ArrivalPrediction{
+			TrainID:     tr.ID,
+			TrainName:   tr.Name,
+			StationID:   st.ID,
+			StationName: st.Name,
+			Platform:    platform,
+			ETA:         float64(ticks) * tickSeconds,
+			Issued:      issued,
+			AtPlatform:  atPlatform,
+		})
+		return true
+	}
+
+	// Get to the next station, from the platform or from where the train is
+	index, forward, ticks := -1, tr.forward, 0
+	hold, skip, turnAt := tr.holdTicks, tr.skipStop, tr.turnAt
+	model := tr.model
+	model.TopSpeed = tr.topSpeed()
+	if tr.Next == nil {
+		current := stationIndex(stations, tr.Current.ID)
+		if current == -1 {
+			return nil
+		}
+		departs := tr.departsForward(current)
+		add(current, departs, 0, true)
+		if stations[current].ID == turnAt {
+			turnAt = 0
+		}
+		index, forward = stepFrom(current, departs), departs
+		route, err := EdgePolyline(tr.central, stations[current], stations[index])
+		if err != nil {
+			return predictions
+		}
+		path := route.Points()
+		if len(path) > 1 && tr.Position.Dist(path[0]) <= 1 {
+			path = path[1:]
+		}
+		start, _, _ := route.PositionAt(0)
+		ticks = tr.waitCounter + travelTicks(model, start, tr.Position, 0, path)
+	} else {
+		if index = stationIndex(stations, tr.Next.ID); index == -1 {
+			return nil
+		}
+		start, _, _ := tr.route.PositionAt(0)
+		ticks = travelTicks(model, start, tr.Position, tr.velocity.Magnitude(), tr.q.items)
+	}
+
+	// Then around the line until every platform has its next arrival
+	for range 2 * (count - 1) {
+		departs := departsFrom(index, forward, count)
+		if stations[index].ID == turnAt {
+			departs, turnAt = !forward, 0
+		}
+		dwell := tr.waitTicks + hold
+		hold = 0
+		if stations[index].ID == skip {
+			skip, dwell = 0, 0
+		} else if !add(index, departs, ticks, false) || tr.leaving {
+			break
+		}
+		next := stepFrom(index, departs)
+		leg, err := tr.legTravelTicks(model, stations[index], stations[next])
+		if err != nil {
+			break
+		}
+		ticks += dwell + leg
+		index, forward = next, departs
+	}
+	return predictions
+}
+
+// topSpeed returns the top speed of the train in pixels/tick, its make's or the cap
+// of a command.
+func (tr *Train) topSpeed() float64 {
+	if tr.speedCap > 0 {
+		return min(tr.speedCap, tr.model.TopSpeed)
+	}
+	return tr.model.TopSpeed
+}
+
+// legKey identifies the travel of a leg for a top speed, the one of the make or of a
+// speed cap.
+type legKey struct {
+	from, to int64
+	topSpeed float64
+}
+
+// legTravel is the travel of a leg between two stops, kept with the length of the
+// edge it was computed for so an edited edge is computed again.
+type legTravel struct {
+	length float64
+	ticks  int
+}
+
+// legTravelTicks returns the ticks a train of the make takes between two stops from
+// a standstill. They only change with the edge, so they are computed once per leg.
+func (tr *Train) legTravelTicks(model Make, from, to *Station) (int, error) {
+	route, err := EdgePolyline(tr.central, from, to)
+	if err != nil {
+		return 0, err
+	}
+	key := legKey{from.ID, to.ID, model.TopSpeed}
+	length := route.Length()
+	if leg, ok := tr.legTicks[key]; ok && leg.length == length {
+		return leg.ticks, nil
+	}
+	path := route.Points()
+	ticks := travelTicks(model, path[0], path[0], 0, path[1:])
+	if tr.legTicks == nil {
+		tr.legTicks = make(map[legKey]legTravel)
+	}
+	tr.legTicks[key] = legTravel{length: length, ticks: ticks}
+	return ticks, nil
+}
+
+// stationIndex returns the index of a station in a list, -1 when it isn't there.
+func stationIndex(stations []*Station, stationID int64) int {
+	for i, st := range stations {
+		if st.ID == stationID {
+			return i
+		}
+	}
+	return -1
+}
+
+// simNow returns the simulation seconds since the first midnight, 0 without a clock
+func (tr *Train) simNow() int {
+	if tr.clock == nil {
+		return 0
+	}
+	return tr.clock.GetDay()*86400 + tr.clock.GetCurrentTimeOfDay()
+}
+
+// simDay returns the simulated day, 0 without a clock
+func (tr *Train) simDay() int {
+	if tr.clock == nil {
+		return 0
+	}
+	return tr.clock.GetDay()
+}
@@ -1,6 +1,10 @@
 package models
 
-import "math"
+import (
+	"math"
+	"sync/atomic"
+	"time"
+)
 
 func Map(value, start1, stop1, start2, stop2 float64) float64 {
 	newval := (value-start1)/(stop1-start1)*(stop2-start2) + start2
@@ -11,3 +15,37 @@ func RoundFloat(val float64, precision uint) float64 {
 	ratio := math.Pow(10, float64(precision))
 	return math.Round(val*ratio) / ratio
 }
+
+// timeSource gives the simulation time, see SetTimeSource
+var timeSource = time.Now
+
+// SetTimeSource makes the models read the time from the simulation clock, so waits
+// and journeys stand still while the simulation is paused and speed up with it.
+// It must be called before the simulation starts, the wall clock is used until then.
+func SetTimeSource(now func() time.Time) {
+	timeSource = now
+}
+
+// Now returns the current simulation time.
+func Now() time.Time {
+	return timeSource()
+}
+
+// droppedEvents counts the events that didn't fit in their channel
+var droppedEvents atomic.Int64
+
+// emit sends an event without waiting, an event that doesn't fit in the channel is
+// dropped and counted in DroppedEvents.
+func emit(events chan<- interface{}, event interface{}) {
+	select {
+	case events <- event:
+	default:
+		droppedEvents.Add(1)
+	}
+}
+
+// DroppedEvents returns how many events the trains and passengers dropped because
+// their channel was full.
+func DroppedEvents() int {
+	return int(droppedEvents.Load())
+}
@@ -0,0 +1,17 @@
+package models
+
+import "testing"
+
+func TestEmitCountsDrops(t *testing.T) {
+	events := make(chan interface{}, 1)
+	before := DroppedEvents()
+
+	emit(events, "first")
+	emit(events, "second")
+	if len(events) != 1 || <-events != "first" {
+		t.Fatal("The event should be sent while the channel has room.")
+	}
+	if DroppedEvents()-before != 1 {
+		t.Fatal("An event that doesn't fit should be dropped and counted.")
+	}
+}
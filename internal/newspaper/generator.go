@@ -64,6 +64,7 @@ func (g *Generator) buildPrompt(storyType StoryType, data map[string]interface{}
 		return fmt.Sprintf(
 			`Write a playful newspaper article about a metro system's daily performance.
 
+Day: %v (%v service)
 Score: %v (Grade: %s)
 Passenger Satisfaction: %.1f%%
 Service Efficiency: %.1f%%
@@ -71,7 +72,7 @@ Service Efficiency: %.1f%%
 Format:
 HEADLINE: (catchy, one line)
 ARTICLE: (2-3 sentences, playful tone, like a real newspaper but fun)`,
-			data["score"], data["grade"], data["satisfaction"], data["efficiency"],
+			data["date"], data["day_type"], data["score"], data["grade"], data["satisfaction"], data["efficiency"],
 		)
 
 	case StoryTypeRecord:
@@ -107,11 +108,45 @@ ARTICLE: (2-3 sentences, concerned but not too serious tone)`,
 Average Sentiment: %.1f/100
 Trend: %s
 Top Station: %s (%v waiting)
+Main Complaint: %s
 
 Format:
 HEADLINE: (catchy, one line)
 ARTICLE: (2-3 sentences, empathetic tone)`,
-			data["sentiment"], data["trend"], data["station"], data["waiting"],
+			data["sentiment"], data["trend"], data["station"], data["waiting"], data["complaint"],
+		)
+
+	case StoryTypeCommuter:
+		return fmt.Sprintf(
+			`Write a playful newspaper column following a regular metro commuter.
+
+Commuter: %s from %s, works near %s
+Trips so far: %v (%v bad days)
+Satisfaction with the metro: %.1f/100
+Latest trip: %s
+
+Format:
+HEADLINE: (catchy, one line)
+ARTICLE: (2-3 sentences, personal tone, like a recurring column)`,
+			data["name"], data["home"], data["work"], data["trips"], data["bad_days"],
+			data["satisfaction"], data["last_trip"],
+		)
+
+	case StoryTypeEvent:
+		return fmt.Sprintf(
+			`Write a playful newspaper article about how a metro system coped with the crowds of a special event.
+
+Event: %s at %s station (%v attendees)
+Trips to and from the event: %v (%v gave up and found another way)
+Times fans were left behind by full trains: %v
+Fan Sentiment: %.1f/100
+Verdict: the metro %s
+
+Format:
+HEADLINE: (catchy, one line)
+ARTICLE: (2-3 sentences, like a match report about the metro)`,
+			data["name"], data["station"], data["attendance"], data["trips"], data["abandoned"],
+			data["denied"], data["sentiment"], data["verdict"],
 		)
 
 	case StoryTypePunctuality:
@@ -16,7 +16,7 @@ type Newspaper struct {
 
 	// Current newspaper cache
 	currentEdition  *Edition
-	editionDate     time.Time
+	editionDay      int // Simulated day of the current edition
 	editionMutex    sync.RWMutex
 
 	// Generation state
@@ -26,7 +26,8 @@ type Newspaper struct {
 
 // Edition represents a complete newspaper with multiple stories
 type Edition struct {
-	Date    time.Time
+	Date    time.Time // Simulated date
+	Day     int       // Simulated day
 	Stories []Story
 }
 
@@ -79,6 +80,10 @@ func (n *Newspaper) GenerateEdition(ctx context.Context, metrics *analysis.Metri
 			data = storyData.Performance
 		case StoryTypeSentiment:
 			data = storyData.Sentiment
+		case StoryTypeCommuter:
+			data = storyData.Commuter
+		case StoryTypeEvent:
+			data = storyData.Event
 		case StoryTypeRecord:
 			if len(storyData.Records) > 0 {
 				data = storyData.Records[0] // Use first record
@@ -103,16 +108,17 @@ func (n *Newspaper) GenerateEdition(ctx context.Context, metrics *analysis.Metri
 		stories = append(stories, story)
 	}
 
-	// Create new edition
+	// Create new edition for the simulated day of the metrics
 	edition := &Edition{
-		Date:    time.Now(),
+		Date:    metrics.Date,
+		Day:     metrics.Day,
 		Stories: stories,
 	}
 
 	// Cache the edition
 	n.editionMutex.Lock()
 	n.currentEdition = edition
-	n.editionDate = edition.Date
+	n.editionDay = edition.Day
 	n.editionMutex.Unlock()
 
 	duration := time.Since(startTime)
@@ -129,8 +135,8 @@ func (n *Newspaper) GetCurrentEdition() *Edition {
 	return n.currentEdition
 }
 
-// NeedsNewEdition checks if a new edition should be generated
-func (n *Newspaper) NeedsNewEdition() bool {
+// NeedsNewEdition checks if a new edition should be generated on the given simulated day
+func (n *Newspaper) NeedsNewEdition(day int) bool {
 	n.editionMutex.RLock()
 	defer n.editionMutex.RUnlock()
 
@@ -139,12 +145,8 @@ func (n *Newspaper) NeedsNewEdition() bool {
 		return true
 	}
 
-	// Check if day has changed
-	now := time.Now()
-	editionDay := n.editionDate.YearDay()
-	currentDay := now.YearDay()
-
-	return editionDay != currentDay
+	// Check if the simulated day has changed
+	return n.editionDay != day
 }
 
 // IsGenerating returns true if an edition is currently being generated
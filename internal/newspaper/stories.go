@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/odin-software/metro/internal/models"
 	"github.com/odin-software/metro/internal/tenjin/analysis"
 )
 
@@ -16,6 +17,8 @@ const (
 	StoryTypeIncident    StoryType = "incident"
 	StoryTypeSentiment   StoryType = "sentiment"
 	StoryTypePunctuality StoryType = "punctuality"
+	StoryTypeCommuter    StoryType = "commuter"
+	StoryTypeEvent       StoryType = "special_event"
 )
 
 // Story represents a generated newspaper article
@@ -33,6 +36,8 @@ type StoryData struct {
 	Incidents   []map[string]interface{}
 	Sentiment   map[string]interface{}
 	Punctuality map[string]interface{}
+	Commuter    map[string]interface{}
+	Event       map[string]interface{}
 }
 
 // CollectStoryData gathers interesting data from Tenjin metrics
@@ -43,6 +48,8 @@ func CollectStoryData(metrics *analysis.Metrics) StoryData {
 		Incidents:   []map[string]interface{}{},
 		Sentiment:   make(map[string]interface{}),
 		Punctuality: make(map[string]interface{}),
+		Commuter:    make(map[string]interface{}),
+		Event:       make(map[string]interface{}),
 	}
 
 	// Performance story data (always generated)
@@ -52,6 +59,8 @@ func CollectStoryData(metrics *analysis.Metrics) StoryData {
 	data.Performance["efficiency"] = metrics.Score.ServiceEfficiency
 	data.Performance["capacity"] = metrics.Score.SystemCapacity
 	data.Performance["reliability"] = metrics.Score.Reliability
+	data.Performance["date"] = metrics.Date.Format("Monday, January 2")
+	data.Performance["day_type"] = string(metrics.DayType)
 
 	// Sentiment story data (if passengers exist)
 	if metrics.TotalPassengers > 0 {
@@ -87,6 +96,13 @@ func CollectStoryData(metrics *analysis.Metrics) StoryData {
 		} else {
 			data.Sentiment["trend"] = "declining"
 		}
+
+		// What passengers complain about the most
+		if metrics.Score.TopComplaint != "" {
+			data.Sentiment["complaint"] = models.SentimentCause(metrics.Score.TopComplaint).Description()
+		} else {
+			data.Sentiment["complaint"] = "nothing in particular"
+		}
 	}
 
 	// Punctuality story data (if arrivals have been tracked)
@@ -118,6 +134,44 @@ func CollectStoryData(metrics *analysis.Metrics) StoryData {
 		}
 	}
 
+	// Commuter story data, the same commuter is followed every edition
+	if featured, ok := featuredCommuter(metrics.Commuters); ok {
+		data.Commuter["name"] = featured.Name
+		data.Commuter["home"] = featured.HomeStation
+		data.Commuter["work"] = featured.WorkStation
+		data.Commuter["trips"] = featured.Trips
+		data.Commuter["bad_days"] = featured.BadDays
+		data.Commuter["satisfaction"] = featured.Satisfaction
+		if featured.Abandoned {
+			data.Commuter["last_trip"] = "gave up and left the station"
+		} else if featured.LastTrip >= 70 {
+			data.Commuter["last_trip"] = "a smooth ride"
+		} else if featured.LastTrip >= 50 {
+			data.Commuter["last_trip"] = "an ordinary ride"
+		} else {
+			data.Commuter["last_trip"] = "a miserable ride"
+		}
+	}
+
+	// Special event story data, about the event that brought the biggest crowd
+	if event, ok := biggestEvent(metrics.SpecialEvents); ok {
+		data.Event["name"] = event.Name
+		data.Event["station"] = event.Station
+		data.Event["attendance"] = event.Attendance
+		data.Event["trips"] = event.Trips
+		data.Event["abandoned"] = event.Abandoned
+		data.Event["denied"] = event.DeniedBoardings
+		data.Event["sentiment"] = event.Sentiment
+		abandonedShare := float64(event.Abandoned) / float64(event.Trips)
+		if abandonedShare > 0.1 || event.Sentiment < 50 {
+			data.Event["verdict"] = "buckled under the crowds"
+		} else if event.DeniedBoardings > 0 || event.Sentiment < 70 {
+			data.Event["verdict"] = "struggled but got the fans through"
+		} else {
+			data.Event["verdict"] = "handled the crowds with ease"
+		}
+	}
+
 	// Record detection (busiest station)
 	if len(metrics.ArrivalsPerStation) > 0 {
 		maxArrivals := int64(0)
@@ -182,6 +236,16 @@ func SelectStoriesToGenerate(data StoryData) []StoryType {
 		stories = append(stories, StoryTypePunctuality)
 	}
 
+	// Add commuter story once someone has been followed
+	if _, ok := data.Commuter["name"]; ok {
+		stories = append(stories, StoryTypeCommuter)
+	}
+
+	// Add special event story once attendees have travelled
+	if _, ok := data.Event["name"]; ok {
+		stories = append(stories, StoryTypeEvent)
+	}
+
 	// Add one record story if any exist
 	if len(data.Records) > 0 {
 		stories = append(stories, StoryTypeRecord)
@@ -194,3 +258,32 @@ func SelectStoriesToGenerate(data StoryData) []StoryType {
 
 	return stories
 }
+
+// featuredCommuter returns the commuter with the lowest ID, so editions follow the same person
+func featuredCommuter(commuters map[string]analysis.CommuterStatus) (analysis.CommuterStatus, bool) {
+	var featured analysis.CommuterStatus
+	found := false
+	for id, c := range commuters {
+		if !found || id < featured.ID {
+			featured = c
+			found = true
+		}
+	}
+	return featured, found
+}
+
+// biggestEvent returns the special event with the most trips, the lowest ID on ties
+func biggestEvent(events map[int64]analysis.SpecialEventStatus) (analysis.SpecialEventStatus, bool) {
+	var biggest analysis.SpecialEventStatus
+	found := false
+	for _, ev := range events {
+		if ev.Trips == 0 {
+			continue
+		}
+		if !found || ev.Trips > biggest.Trips || (ev.Trips == biggest.Trips && ev.ID < biggest.ID) {
+			biggest = ev
+			found = true
+		}
+	}
+	return biggest, found
+}
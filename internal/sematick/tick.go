@@ -1,5 +1,7 @@
 // Package sematick is a ticker that sends a single message at specified intervals
-// to every subscribed channel. This ticker can be stopped, paused or resumed.
+// to every subscribed channel. This ticker can be stopped, paused, resumed, stepped
+// while paused and sped up or slowed down. While paused it sleeps until it is resumed
+// or stepped instead of waking up every interval.
 package sematick
 
 import (
@@ -12,28 +14,55 @@ type Ticker struct {
 	mux      sync.Mutex       // mutex to access the channels
 	channels []chan time.Time // channels to store the subscribed timers
 
-	count       int
+	count       uint64       // ticks sent to the subscribers
+	steps       int64        // ticks left to send while paused
 	state       uint32       // 0 = stopped, 1 = running, 2 = paused
-	ticker      *time.Ticker // principal timer
-	tickerMux   sync.Mutex   // mutex for the underlying ticker
+	ticker      clockTicker // principal timer
+	tickerMux   sync.Mutex  // mutex for the underlying ticker
 	interval    time.Duration
 	stopChannel chan struct{}
+	wake        chan struct{} // signals a sleeping ticker to check its state again
+}
+
+// clockTicker is what the sematick needs of a time.Ticker, so tests can tick it by hand.
+type clockTicker interface {
+	Chan() <-chan time.Time
+	Stop()
+	Reset(interval time.Duration)
+}
+
+// timeTicker is a clockTicker on the wall clock.
+type timeTicker struct {
+	*time.Ticker
+}
+
+func (tt timeTicker) Chan() <-chan time.Time {
+	return tt.C
+}
+
+func newTimeTicker(interval time.Duration) clockTicker {
+	return timeTicker{time.NewTicker(interval)}
 }
 
 // NewTicker creates a new sematick, pushes time.Time messages at the
 // desired interval and can start either as 0 which equals stopped
 // or 1 which equals running.
 func NewTicker(interval time.Duration, initialState int) *Ticker {
+	return newTicker(interval, initialState, newTimeTicker)
+}
+
+// newTicker creates a sematick ticked by the clock tickers newClock returns.
+func newTicker(interval time.Duration, initialState int, newClock func(time.Duration) clockTicker) *Ticker {
 	t := &Ticker{
 		interval: interval,
+		state:    uint32(initialState), // Set before returning, so it can be paused or stepped right away
+		wake:     make(chan struct{}, 1),
 	}
 
 	go func() {
 		t.tickerMux.Lock()
-		atomic.StoreUint32(&t.state, uint32(initialState))
-
-		t.stopChannel = make(chan struct{})
-		t.ticker = time.NewTicker(interval)
+		t.stopChannel = make(chan struct{}, 1) // Buffered so Stop never waits for a sleeping ticker
+		t.ticker = newClock(t.interval)
 		t.tickerMux.Unlock()
 
 		t.tick()
@@ -57,7 +86,7 @@ func (t *Ticker) Subscribe() <-chan time.Time {
 
 // Count gets the amount of times the main ticker has sent a message.
 func (t *Ticker) Count() int {
-	return t.count
+	return int(atomic.LoadUint64(&t.count))
 }
 
 // Pause changes the state to pause, ticks still happen but are not
@@ -69,7 +98,50 @@ func (t *Ticker) Pause() {
 // Resume changes state to resume, this works as a play button when
 // the ticker was initialized in the stopped state.
 func (t *Ticker) Resume() {
+	atomic.StoreInt64(&t.steps, 0)
 	atomic.StoreUint32(&t.state, 1)
+	t.wakeUp()
+}
+
+// Paused returns true when ticks are not being sent, stopped included.
+func (t *Ticker) Paused() bool {
+	return atomic.LoadUint32(&t.state) != 1
+}
+
+// Step sends the next n ticks while paused, at the usual interval.
+// It does nothing when the ticker is running or stopped.
+func (t *Ticker) Step(n int) {
+	if n <= 0 || atomic.LoadUint32(&t.state) != 2 {
+		return
+	}
+	atomic.AddInt64(&t.steps, int64(n))
+	t.wakeUp()
+}
+
+// wakeUp lets a sleeping ticker know the state changed.
+func (t *Ticker) wakeUp() {
+	select {
+	case t.wake <- struct{}{}:
+	default:
+	}
+}
+
+// SetInterval changes the time between ticks.
+func (t *Ticker) SetInterval(interval time.Duration) {
+	t.tickerMux.Lock()
+	defer t.tickerMux.Unlock()
+
+	t.interval = interval
+	if t.ticker != nil {
+		t.ticker.Reset(interval)
+	}
+}
+
+// Interval returns the time between ticks.
+func (t *Ticker) Interval() time.Duration {
+	t.tickerMux.Lock()
+	defer t.tickerMux.Unlock()
+	return t.interval
 }
 
 // Stop stops ticking and quits the main goroutine.
@@ -89,24 +161,78 @@ func (t *Ticker) Stop() {
 func (t *Ticker) tick() {
 	for {
 		select {
-		case tick := <-t.ticker.C:
-			t.mux.Lock()
-			if atomic.LoadUint32(&t.state) != 1 {
-				t.mux.Unlock()
+		case tick := <-t.ticker.Chan():
+			if !t.shouldSend() {
+				if !t.sleep() {
+					return
+				}
 				continue
 			}
+			// Counted before sending, so a subscriber that was busy can tell from
+			// the count how many ticks it missed.
+			atomic.AddUint64(&t.count, 1)
+			t.mux.Lock()
 			for i := range t.channels {
 				select {
 				case t.channels[i] <- tick:
 				default:
-					t.count++
 				}
 			}
 			t.mux.Unlock()
 		case <-t.stopChannel:
 			return
-		default:
-			t.count++
 		}
 	}
 }
+
+// sleep stops the underlying ticker until there are ticks to send again, so a paused
+// simulation costs nothing. It returns false when the ticker was stopped meanwhile.
+func (t *Ticker) sleep() bool {
+	t.tickerMux.Lock()
+	t.ticker.Stop()
+	t.tickerMux.Unlock()
+
+	for !t.canSend() {
+		select {
+		case <-t.wake:
+		case <-t.stopChannel:
+			return false
+		}
+	}
+
+	t.tickerMux.Lock()
+	t.ticker.Reset(t.interval)
+	t.tickerMux.Unlock()
+	return true
+}
+
+// canSend returns true when the next tick would go to the subscribers.
+func (t *Ticker) canSend() bool {
+	switch atomic.LoadUint32(&t.state) {
+	case 1:
+		return true
+	case 2:
+		return atomic.LoadInt64(&t.steps) > 0
+	}
+	return false
+}
+
+// shouldSend returns true when the tick goes to the subscribers: while running, or
+// while paused with steps left, using one of them.
+func (t *Ticker) shouldSend() bool {
+	switch atomic.LoadUint32(&t.state) {
+	case 1:
+		return true
+	case 2:
+		for {
+			steps := atomic.LoadInt64(&t.steps)
+			if steps <= 0 {
+				return false
+			}
+			if atomic.CompareAndSwapInt64(&t.steps, steps, steps-1) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,135 @@
+package sematick
+
+import (
+	"testing"
+	"time"
+)
+
+// fakeClock is a clock ticker the tests tick by hand. It reports the intervals it is
+// started or reset with and when it is stopped.
+type fakeClock struct {
+	ticks     chan time.Time
+	intervals chan time.Duration
+	stops     chan struct{}
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{
+		ticks:     make(chan time.Time),
+		intervals: make(chan time.Duration, 16),
+		stops:     make(chan struct{}, 16),
+	}
+}
+
+func (fc *fakeClock) Chan() <-chan time.Time {
+	return fc.ticks
+}
+
+func (fc *fakeClock) Stop() {
+	fc.stops <- struct{}{}
+}
+
+func (fc *fakeClock) Reset(interval time.Duration) {
+	fc.intervals <- interval
+}
+
+// tick sends a tick, it returns once the ticker took it.
+func (fc *fakeClock) tick() {
+	fc.ticks <- time.Now()
+}
+
+// newFakeTicker returns a sematick ticked by hand, already started.
+func newFakeTicker(t *testing.T, interval time.Duration, state int) (*Ticker, *fakeClock) {
+	fc := newFakeClock()
+	ticker := newTicker(interval, state, func(interval time.Duration) clockTicker {
+		fc.intervals <- interval
+		return fc
+	})
+	t.Cleanup(ticker.Stop)
+	if <-fc.intervals != interval {
+		t.Fatal("The clock should start at the interval of the ticker.")
+	}
+	return ticker, fc
+}
+
+// asleep ticks a ticker that has nothing to send and waits until it fell asleep.
+func asleep(fc *fakeClock) {
+	fc.tick()
+	<-fc.stops
+}
+
+func TestTickerStepWhilePaused(t *testing.T) {
+	ticker, fc := newFakeTicker(t, time.Millisecond, 2)
+	ch := ticker.Subscribe()
+
+	asleep(fc)
+	if len(ch) != 0 || ticker.Count() != 0 || !ticker.Paused() {
+		t.Fatal("A paused ticker should not send ticks.")
+	}
+	ticker.Step(3)
+	if <-fc.intervals != time.Millisecond {
+		t.Fatal("Stepping should wake the ticker up at its interval.")
+	}
+	for i := 0; i < 3; i++ {
+		fc.tick()
+		<-ch
+	}
+	asleep(fc)
+	if len(ch) != 0 || ticker.Count() != 3 {
+		t.Fatal("Stepping a paused ticker should send exactly the given ticks.")
+	}
+	if !ticker.Paused() {
+		t.Fatal("The ticker should stay paused after stepping.")
+	}
+
+	ticker.Step(0)
+	if len(fc.intervals) != 0 {
+		t.Fatal("Stepping no ticks should send nothing.")
+	}
+}
+
+func TestTickerStepWhileRunning(t *testing.T) {
+	ticker, fc := newFakeTicker(t, time.Millisecond, 1)
+	ch := ticker.Subscribe()
+
+	fc.tick()
+	<-ch
+	ticker.Step(1000)
+	ticker.Pause()
+	asleep(fc)
+	if len(ch) != 0 || ticker.Count() != 1 {
+		t.Fatal("Steps should be ignored while the ticker runs.")
+	}
+}
+
+func TestTickerResumeClearsSteps(t *testing.T) {
+	ticker, fc := newFakeTicker(t, time.Millisecond, 2)
+	ch := ticker.Subscribe()
+
+	asleep(fc)
+	ticker.Step(1000)
+	<-fc.intervals
+	ticker.Resume()
+	if ticker.Paused() {
+		t.Fatal("The ticker should run once resumed.")
+	}
+	fc.tick()
+	<-ch
+	ticker.Pause()
+	asleep(fc)
+	if len(ch) != 0 || ticker.Count() != 1 {
+		t.Fatal("Resuming should drop the steps left, they must not run after pausing again.")
+	}
+}
+
+func TestTickerSetInterval(t *testing.T) {
+	ticker, fc := newFakeTicker(t, time.Hour, 1)
+
+	ticker.SetInterval(time.Millisecond)
+	if ticker.Interval() != time.Millisecond {
+		t.Fatal("The interval should have been changed.")
+	}
+	if <-fc.intervals != time.Millisecond {
+		t.Fatal("The ticker should tick at the new interval.")
+	}
+}
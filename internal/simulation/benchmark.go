@@ -0,0 +1,78 @@
+package simulation
+
+import (
+	"fmt"
+	"math"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/odin-software/metro/control"
+)
+
+// BenchmarkReport is what running the simulation cost, measured by Benchmark.
+type BenchmarkReport struct {
+	Profile    string
+	Trains     int
+	Minutes    int           // Simulated minutes run
+	Wall       time.Duration // Real time the run took
+	CPU        time.Duration // CPU time of the process during the run, 0 when unknown
+	Allocated  uint64        // Bytes allocated during the run
+	PeakHeap   uint64        // Largest heap in use at the end of a simulated minute
+	Sys        uint64        // Bytes obtained from the OS at the end of the run
+	Passengers int           // Passengers travelling at the end of the run
+}
+
+// Benchmark runs the given simulated minutes as fast as it can and measures the CPU
+// time and the memory they take. The ticker, the display and Tenjin are left out.
+func (s *Scheduler) Benchmark(minutes int) BenchmarkReport {
+	tick := control.DefaultConfig.LoopDuration.Seconds() * control.DefaultConfig.SimulationSpeed
+	ticksPerMinute := int(math.Round(60 / tick))
+	report := BenchmarkReport{Profile: control.DefaultConfig.Profile, Trains: len(s.trains), Minutes: minutes}
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	cpuStart, start := cpuTime(), time.Now()
+	for m := 0; m < minutes; m++ {
+		for i := 0; i < ticksPerMinute; i++ {
+			s.Tick()
+		}
+		runtime.ReadMemStats(&after)
+		report.PeakHeap = max(report.PeakHeap, after.HeapInuse)
+	}
+	report.Wall = time.Since(start)
+	if cpuStart > 0 {
+		report.CPU = cpuTime() - cpuStart
+	}
+	report.Allocated = after.TotalAlloc - before.TotalAlloc
+	report.Sys = after.Sys
+	report.Passengers = s.Passengers()
+	return report
+}
+
+// String describes the report per simulated minute.
+func (r BenchmarkReport) String() string {
+	if r.Minutes <= 0 {
+		return "Benchmark: nothing was simulated\n"
+	}
+	minutes := float64(r.Minutes)
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Benchmark: %d trains, %d simulated minutes, %s profile\n", r.Trains, r.Minutes, r.Profile)
+	fmt.Fprintf(&sb, "  Real time:       %v (%.1fx real time)\n", r.Wall.Round(time.Millisecond), minutes*float64(time.Minute)/float64(r.Wall))
+	if r.CPU > 0 {
+		fmt.Fprintf(&sb, "  CPU per minute:  %v\n", (r.CPU / time.Duration(r.Minutes)).Round(time.Microsecond))
+	} else {
+		fmt.Fprintf(&sb, "  CPU per minute:  unknown on this platform\n")
+	}
+	fmt.Fprintf(&sb, "  Allocated/min:   %s\n", formatBytes(uint64(float64(r.Allocated)/minutes)))
+	fmt.Fprintf(&sb, "  Peak heap:       %s\n", formatBytes(r.PeakHeap))
+	fmt.Fprintf(&sb, "  From the OS:     %s\n", formatBytes(r.Sys))
+	fmt.Fprintf(&sb, "  Passengers:      %d\n", r.Passengers)
+	return sb.String()
+}
+
+// formatBytes writes a size in MB.
+func formatBytes(bytes uint64) string {
+	return fmt.Sprintf("%.1f MB", float64(bytes)/(1<<20))
+}
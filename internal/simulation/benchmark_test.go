@@ -0,0 +1,48 @@
+package simulation
+
+import (
+	"math"
+	"testing"
+
+	"github.com/odin-software/metro/control"
+)
+
+// BenchmarkSimulatedMinute runs a simulated minute per iteration with 69 trains, as
+// many as the Santo Domingo setup, on a line of the test scheduler.
+func BenchmarkSimulatedMinute(b *testing.B) {
+	control.DefaultConfig.LogsDirectory = b.TempDir() + "/"
+	control.DefaultConfig.StdLogs = false
+	control.InitLogger()
+	s, out := newTestScheduler(1, 69)
+	go func() {
+		for range out {
+		}
+	}()
+	tick := control.DefaultConfig.LoopDuration.Seconds() * control.DefaultConfig.SimulationSpeed
+	ticksPerMinute := int(math.Round(60 / tick))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for t := 0; t < ticksPerMinute; t++ {
+			s.Tick()
+		}
+	}
+}
+
+func TestBenchmarkReport(t *testing.T) {
+	logToTemp(t)
+	s, _ := newTestScheduler(1, 6)
+
+	report := s.Benchmark(2)
+	if report.Trains != 6 || report.Minutes != 2 || report.Wall <= 0 || report.Allocated == 0 {
+		t.Fatal("The benchmark should measure the minutes it ran.")
+	}
+	tick := control.DefaultConfig.LoopDuration.Seconds() * control.DefaultConfig.SimulationSpeed
+	if s.Ticks() != 2*int(math.Round(60/tick)) {
+		t.Fatal("The benchmark should run the ticks of the simulated minutes.")
+	}
+	if (BenchmarkReport{}).String() != "Benchmark: nothing was simulated\n" {
+		t.Fatal("An empty report should say nothing was simulated.")
+	}
+}
@@ -0,0 +1,12 @@
+//go:build !unix
+
+package simulation
+
+import (
+	"time"
+)
+
+// cpuTime is unknown where getrusage isn't available.
+func cpuTime() time.Duration {
+	return 0
+}
@@ -0,0 +1,313 @@
+// Package simulation runs the ticks of the simulation. Every tick goes through the
+// same phases in the same order: network edits, movement, station interactions,
+// passenger updates, arrival predictions and the event flush. Only the movement of
+// the trains runs in parallel, everything that shares stations and passengers runs
+// one entity at a time in a fixed order, so a seeded run is reproducible.
+package simulation
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/odin-software/metro/control"
+	"github.com/odin-software/metro/internal/models"
+	"github.com/odin-software/metro/internal/sematick"
+)
+
+// Clock is the simulation clock, moved forward at the start of every tick.
+type Clock interface {
+	Update()
+}
+
+// Spawner brings new passengers to the stations.
+type Spawner interface {
+	Spawn()
+}
+
+// Predictor gathers the predicted arrivals of the trains.
+type Predictor interface {
+	Refresh()
+}
+
+// NetworkSpawner is a spawner that follows the edits of the network.
+type NetworkSpawner interface {
+	Spawner
+	SetNetwork(stations []*models.Station, lines []models.Line)
+}
+
+// Editor applies the edits of the network submitted since the last tick.
+type Editor interface {
+	ApplyEdits() bool
+	Stations() []*models.Station
+	Lines() []models.Line
+}
+
+// maxCatchUp bounds the ticks run for a single message of the ticker, a second of
+// simulation. Missed ticks beyond it are skipped.
+const maxCatchUp = 60
+
+// eventBuffer is the room for the events emitted during a single tick.
+const eventBuffer = 4096
+
+// Scheduler ticks the trains, stations and passengers of the simulation. Nothing
+// else may write them, readers lock the scheduler to see them between ticks.
+type Scheduler struct {
+	clock        Clock
+	trains       []*models.Train
+	stations     []*models.Station
+	spawner      Spawner
+	spawnEvery   int                // Ticks between spawns
+	predictor    Predictor          // Nil when arrivals are not predicted
+	predictEvery int                // Ticks between refreshes of the predictions
+	editor       Editor             // Nil when the network is never edited
+	events       chan interface{}   // Events emitted during the tick, nil without a destination
+	out          chan<- interface{} // Where the events go at the end of the tick
+	workers      int                // Goroutines moving the trains
+	ticks        atomic.Int64       // Ticks run, read without waiting for the tick
+	skipped      int                // Ticks missed because the scheduler fell behind
+	dropped      int                // Events lost because the destination was full
+	mutex        sync.Mutex
+}
+
+// NewScheduler creates a scheduler moving the clock forward every tick. The events
+// of the entities are sent to out at the end of each tick, out can be nil.
+func NewScheduler(clock Clock, out chan<- interface{}) *Scheduler {
+	s := &Scheduler{
+		clock:   clock,
+		out:     out,
+		workers: runtime.GOMAXPROCS(0),
+	}
+	if out != nil {
+		s.events = make(chan interface{}, eventBuffer)
+	}
+	return s
+}
+
+// Events returns the channel the trains and passengers emit their events on, nil when
+// the events have nowhere to go.
+func (s *Scheduler) Events() chan<- interface{} {
+	if s.events == nil {
+		return nil
+	}
+	return s.events
+}
+
+// SetEntities sets the trains and stations ticked, in the order they are served.
+func (s *Scheduler) SetEntities(trains []*models.Train, stations []*models.Station) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.trains = trains
+	s.stations = stations
+}
+
+// SetSpawner spawns passengers every given ticks, before the passengers are updated.
+func (s *Scheduler) SetSpawner(spawner Spawner, every int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.spawner = spawner
+	s.spawnEvery = max(1, every)
+}
+
+// SetPredictor refreshes the predictions every given ticks, after the passengers are
+// updated, so they follow the simulation time while it is paused or warped.
+func (s *Scheduler) SetPredictor(predictor Predictor, every int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.predictor = predictor
+	s.predictEvery = max(1, every)
+}
+
+// SetEditor applies the edits of the network at the start of every tick. The stations
+// added are ticked from then on, and passengers spawn for them.
+func (s *Scheduler) SetEditor(editor Editor) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.editor = editor
+}
+
+// Run ticks the simulation with the ticker until the context is done. The ticks
+// missed while a tick took too long are run with the next message of the ticker, so
+// the simulation doesn't lose time, up to maxCatchUp at once.
+func (s *Scheduler) Run(ctx context.Context, ticker *sematick.Ticker) {
+	sub := ticker.Subscribe()
+	handled := ticker.Count()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sub:
+			handled = s.catchUp(ticker.Count(), handled)
+		}
+	}
+}
+
+// catchUp runs the ticks the ticker counted since the ones handled, up to maxCatchUp,
+// and returns the ticks handled. A message of the ticker can come after its tick was
+// run with the one before it, then nothing is due.
+func (s *Scheduler) catchUp(count, handled int) int {
+	due := count - handled
+	if due > maxCatchUp {
+		s.skip(due - maxCatchUp)
+		due = maxCatchUp
+	}
+	for ; due > 0; due-- {
+		s.Tick()
+	}
+	return max(handled, count)
+}
+
+// skip counts the ticks the scheduler couldn't catch up with.
+func (s *Scheduler) skip(ticks int) {
+	s.mutex.Lock()
+	s.skipped += ticks
+	skipped := s.skipped
+	s.mutex.Unlock()
+	control.Log(fmt.Sprintf("Simulation fell behind, skipped %d ticks (%d so far)", ticks, skipped))
+}
+
+// Tick runs a tick of the simulation, phase after phase.
+func (s *Scheduler) Tick() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.clock.Update()
+	s.edit()
+	s.move()
+	s.serve()
+	s.updatePassengers()
+	s.predict()
+	s.flush()
+	s.ticks.Add(1)
+}
+
+// Ticks returns how many ticks have run. Unlike Stats, it doesn't wait for the
+// current tick, so it can be called with the scheduler locked.
+func (s *Scheduler) Ticks() int {
+	return int(s.ticks.Load())
+}
+
+// Passengers returns how many passengers are in the stations, walking between the
+// stations of a complex and on the trains.
+func (s *Scheduler) Passengers() int {
+	count := 0
+	for _, st := range s.stations {
+		count += st.GetWaitingPassengersCount() + st.GetEntryQueueCount() + st.GetWalkingPassengersCount()
+	}
+	for _, tr := range s.trains {
+		count += tr.GetPassengerCount()
+	}
+	return count
+}
+
+// edit is the network phase, the edits submitted since the last tick are applied
+// before anything moves.
+func (s *Scheduler) edit() {
+	if s.editor == nil || !s.editor.ApplyEdits() {
+		return
+	}
+	s.stations = s.editor.Stations()
+	if spawner, ok := s.spawner.(NetworkSpawner); ok {
+		spawner.SetNetwork(s.stations, s.editor.Lines())
+	}
+}
+
+// move is the movement phase, the trains move in parallel.
+func (s *Scheduler) move() {
+	workers := min(s.workers, len(s.trains))
+	if workers <= 1 {
+		for _, tr := range s.trains {
+			tr.Move()
+		}
+		return
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := w; i < len(s.trains); i += workers {
+				s.trains[i].Move()
+			}
+		}(w)
+	}
+	wg.Wait()
+}
+
+// serve is the station interactions phase: the trains arrive, exchange passengers
+// and depart, then the stations let passengers through the gates to the platforms.
+func (s *Scheduler) serve() {
+	for _, tr := range s.trains {
+		tr.Serve()
+	}
+	for _, st := range s.stations {
+		st.Tick()
+	}
+}
+
+// updatePassengers is the passenger phase: new passengers arrive, then the waiting
+// and riding ones update their sentiment and may give up.
+func (s *Scheduler) updatePassengers() {
+	if s.spawner != nil && s.ticks.Load()%int64(s.spawnEvery) == 0 {
+		s.spawner.Spawn()
+	}
+	for _, st := range s.stations {
+		st.TickPassengers()
+	}
+	for _, tr := range s.trains {
+		tr.TickPassengers()
+	}
+}
+
+// predict is the prediction phase, the predicted arrivals are gathered every few ticks.
+func (s *Scheduler) predict() {
+	if s.predictor != nil && s.ticks.Load()%int64(s.predictEvery) == 0 {
+		s.predictor.Refresh()
+	}
+}
+
+// flush is the event phase, the events of the tick go out in the order they were
+// emitted. Events that don't fit in the destination are dropped and counted.
+func (s *Scheduler) flush() {
+	for s.events != nil {
+		select {
+		case event := <-s.events:
+			select {
+			case s.out <- event:
+			default:
+				s.dropped++
+			}
+		default:
+			return
+		}
+	}
+}
+
+// Lock holds the simulation between two ticks, for the readers of the trains,
+// stations and passengers like the display.
+func (s *Scheduler) Lock() {
+	s.mutex.Lock()
+}
+
+// Unlock lets the simulation tick again.
+func (s *Scheduler) Unlock() {
+	s.mutex.Unlock()
+}
+
+// Stats are the counts of the scheduler.
+type Stats struct {
+	Ticks   int // Ticks run
+	Skipped int // Ticks missed because the scheduler fell behind
+	Dropped int // Events lost because the tick emitted more than it holds or Tenjin was busy
+}
+
+// Stats returns the counts of the scheduler. The events dropped count the ones the
+// trains and passengers couldn't emit too.
+func (s *Scheduler) Stats() Stats {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return Stats{Ticks: s.Ticks(), Skipped: s.skipped, Dropped: s.dropped + models.DroppedEvents()}
+}
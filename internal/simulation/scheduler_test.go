@@ -0,0 +1,312 @@
+package simulation
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/odin-software/metro/control"
+	"github.com/odin-software/metro/internal/models"
+	"github.com/odin-software/metro/internal/sematick"
+)
+
+func logToTemp(t *testing.T) {
+	control.DefaultConfig.LogsDirectory = t.TempDir() + "/"
+	control.InitLogger()
+}
+
+// testClock is a simulation clock moved only by the scheduler.
+type testClock struct {
+	seconds float64
+}
+
+func (c *testClock) Update() {
+	c.seconds += control.DefaultConfig.LoopDuration.Seconds() * control.DefaultConfig.SimulationSpeed
+}
+
+func (c *testClock) GetCurrentTimeOfDay() int   { return (8*3600 + int(c.seconds)) % 86400 }
+func (c *testClock) GetDay() int                { return (8*3600 + int(c.seconds)) / 86400 }
+func (c *testClock) GetDayType() models.DayType { return models.DayTypeWeekday }
+func (c *testClock) Now() time.Time {
+	return time.Date(2025, 1, 6, 8, 0, 0, 0, time.UTC).Add(time.Duration(c.seconds * float64(time.Second)))
+}
+
+// testSpawner brings a passenger to a random station every time it runs.
+type testSpawner struct {
+	rng      *rand.Rand
+	stations []*models.Station
+	planner  *models.JourneyPlanner
+	count    int
+	from     map[int64]int // Passengers spawned at each station
+}
+
+func (sp *testSpawner) SetNetwork(stations []*models.Station, lines []models.Line) {
+	sp.stations = stations
+	sp.planner = models.NewJourneyPlanner(lines)
+}
+
+func (sp *testSpawner) Spawn() {
+	from := sp.stations[sp.rng.Intn(len(sp.stations))]
+	to := sp.stations[sp.rng.Intn(len(sp.stations))]
+	if from == to {
+		return
+	}
+	itinerary, err := sp.planner.Plan(from.ID, to.ID)
+	if err != nil {
+		return
+	}
+	sp.count++
+	sp.from[from.ID]++
+	passenger := models.NewPassenger(fmt.Sprintf("P-%d", sp.count), "", models.DefaultPersonas[0], from, to, nil)
+	passenger.Itinerary = itinerary
+	from.Enter(passenger)
+}
+
+// newTestScheduler returns a scheduler of a line of six stations with the given
+// trains spread along it, passengers spawned from the seed.
+func newTestScheduler(seed int64, trains int) (*Scheduler, chan interface{}) {
+	clock := &testClock{}
+	models.SetTimeSource(clock.Now)
+	out := make(chan interface{}, 1<<16)
+	s := NewScheduler(clock, out)
+
+	stations := make([]*models.Station, 6)
+	central := models.NewNetwork(func(st *models.Station) string { return st.Name })
+	for i := range stations {
+		stations[i] = &models.Station{ID: int64(i + 1), Name: string(rune('A' + i)), Zone: 1}
+		stations[i].SetPosition(models.NewVector(float64(i*150), float64(i%2*40)))
+		stations[i].PlatformCapacity = 20
+		stations[i].GateThroughput = 30
+	}
+	central.InsertVertices(stations)
+	for i := 1; i < len(stations); i++ {
+		central.InsertEdge(stations[i-1], stations[i], nil)
+	}
+	line := models.Line{ID: 1, Name: "L1", Stations: stations}
+
+	ptrs := make([]*models.Train, trains)
+	for i := range ptrs {
+		st := stations[i%len(stations)]
+		tr := models.NewTrain(int64(i+1), fmt.Sprintf("T%d", i+1), models.NewMake("M", "", 0.05, 2), st.GetPosition(), st, line, central, s.Events(), clock)
+		tr.Capacity = 8
+		tr.SetRand(rand.New(rand.NewSource(seed + tr.ID)))
+		ptrs[i] = &tr
+	}
+	s.SetEntities(ptrs, stations)
+	s.SetSpawner(&testSpawner{
+		rng:      rand.New(rand.NewSource(seed)),
+		stations: stations,
+		planner:  models.NewJourneyPlanner([]models.Line{line}),
+		from:     make(map[int64]int),
+	}, 10)
+	s.SetEditor(models.NewNetworkEditor(central, stations, []models.Line{line}, ptrs, &testNetworkStore{nextID: 6}))
+	return s, out
+}
+
+// testNetworkStore persists nothing, it only hands out the IDs of the new stations.
+type testNetworkStore struct {
+	nextID int64
+}
+
+func (ns *testNetworkStore) AddStation(name string, position models.Vector) (int64, error) {
+	ns.nextID++
+	return ns.nextID, nil
+}
+
+func (ns *testNetworkStore) MoveStation(id int64, name string, position models.Vector) error {
+	return nil
+}
+
+func (ns *testNetworkStore) AddEdge(fromID, toID int64, points []models.Vector) error { return nil }
+func (ns *testNetworkStore) DeleteEdge(fromID, toID int64) error                      { return nil }
+func (ns *testNetworkStore) ReorderLine(lineID int64, stationIDs []int64) error       { return nil }
+
+// snapshot describes where the trains and the passengers of the scheduler are.
+func snapshot(s *Scheduler) string {
+	s.Lock()
+	defer s.Unlock()
+	state := ""
+	for _, tr := range s.trains {
+		next := int64(0)
+		if tr.Next != nil {
+			next = tr.Next.ID
+		}
+		state += fmt.Sprintf("%s %.6f,%.6f %d->%d", tr.Name, tr.Position.X, tr.Position.Y, tr.Current.ID, next)
+		for _, p := range tr.GetPassengers() {
+			state += " " + p.ID
+		}
+		state += "\n"
+	}
+	for _, st := range s.stations {
+		state += fmt.Sprintf("%s gates %d walking %d:", st.Name, st.GetEntryQueueCount(), st.GetWalkingPassengersCount())
+		for _, p := range st.GetWaitingPassengers() {
+			state += " " + p.ID
+		}
+		state += "\n"
+	}
+	return state
+}
+
+func TestSchedulerIsReproducible(t *testing.T) {
+	logToTemp(t)
+
+	states := make([]string, 2)
+	events := make([]int, 2)
+	for run := range states {
+		s, out := newTestScheduler(7, 12)
+		for i := 0; i < 6000; i++ {
+			s.Tick()
+		}
+		states[run] = snapshot(s)
+		events[run] = len(out)
+		if !strings.Contains(states[run], " P-") || s.Stats().Ticks != 6000 {
+			t.Fatal("The simulation should have run with passengers.")
+		}
+	}
+
+	if states[0] != states[1] {
+		t.Fatalf("Two runs with the same seed should end the same:\n%s\n%s", states[0], states[1])
+	}
+	if events[0] != events[1] {
+		t.Fatal("Two runs with the same seed should emit the same events.")
+	}
+}
+
+func TestSchedulerAppliesEdits(t *testing.T) {
+	logToTemp(t)
+	s, _ := newTestScheduler(7, 4)
+	editor := s.editor.(*models.NetworkEditor)
+
+	added := editor.Submit(models.NetworkEdit{Kind: models.EditAddStation, Name: "G", Position: models.NewVector(900, 0)})
+	editor.Submit(models.NetworkEdit{Kind: models.EditAddEdge, StationID: 6, ToStationID: 7})
+	extended := editor.Submit(models.NetworkEdit{Kind: models.EditReorderLine, LineID: 1, StationIDs: []int64{1, 2, 3, 4, 5, 6, 7}})
+	if len(s.stations) != 6 {
+		t.Fatal("The edits should wait for the next tick.")
+	}
+	s.Tick()
+	result := <-added
+	if result.Err != nil || (<-extended).Err != nil {
+		t.Fatal("The edits should have been applied.")
+	}
+	spawner := s.spawner.(*testSpawner)
+	if len(s.stations) != 7 || s.stations[6] != result.Station || len(spawner.stations) != 7 {
+		t.Fatal("The station added should be ticked and get passengers.")
+	}
+
+	for i := 0; i < 6000; i++ {
+		s.Tick()
+	}
+	st := result.Station
+	if spawner.from[st.ID] == 0 || st.GetEntryQueueCount() >= spawner.from[st.ID] {
+		t.Fatal("The passengers of the station added should go through its gates.")
+	}
+}
+
+func TestSchedulerCatchUp(t *testing.T) {
+	logToTemp(t)
+	s := NewScheduler(&testClock{}, nil)
+
+	if handled := s.catchUp(3, 0); handled != 3 || s.Stats().Ticks != 3 {
+		t.Fatal("The ticks missed should be run with the next message of the ticker.")
+	}
+	if handled := s.catchUp(3, 3); handled != 3 || s.Stats().Ticks != 3 {
+		t.Fatal("A message for a tick already run should run nothing.")
+	}
+	if handled := s.catchUp(103, 3); handled != 103 || s.Stats().Ticks != 3+maxCatchUp {
+		t.Fatal("No more than maxCatchUp ticks should be run at once.")
+	}
+	if stats := s.Stats(); stats.Skipped != 100-maxCatchUp {
+		t.Fatal("The ticks beyond maxCatchUp should be skipped and counted.")
+	}
+}
+
+func TestSchedulerPassengersCountsWalkers(t *testing.T) {
+	logToTemp(t)
+	s := NewScheduler(&testClock{}, nil)
+	a, b := &models.Station{ID: 1, Name: "A", EntranceWalk: time.Hour}, &models.Station{ID: 2, Name: "B"}
+	s.SetEntities(nil, []*models.Station{a, b})
+	for i := 0; i < 2; i++ {
+		a.Enter(&models.Passenger{ID: fmt.Sprint(i), DestinationStation: b})
+	}
+
+	s.Tick()
+	if a.GetWalkingPassengersCount() != 2 || s.Passengers() != 2 {
+		t.Fatal("The passengers walking to the platforms should be counted.")
+	}
+}
+
+// countingPredictor counts its refreshes.
+type countingPredictor struct {
+	refreshes int
+}
+
+func (p *countingPredictor) Refresh() { p.refreshes++ }
+
+func TestSchedulerPredicts(t *testing.T) {
+	logToTemp(t)
+	s := NewScheduler(&testClock{}, nil)
+	predictor := &countingPredictor{}
+	s.SetPredictor(predictor, 3)
+
+	for i := 0; i < 9; i++ {
+		s.Tick()
+	}
+	if predictor.refreshes != 3 {
+		t.Fatalf("The predictions should be refreshed every 3 ticks, got %d refreshes in 9.", predictor.refreshes)
+	}
+}
+
+func TestSchedulerRunFallsBehind(t *testing.T) {
+	logToTemp(t)
+	s := NewScheduler(&testClock{}, nil)
+	ticker := sematick.NewTicker(2*time.Millisecond, 2)
+	defer ticker.Stop()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.Run(ctx, ticker)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	// Steps sent before Run subscribes are never run, step until one is
+	waitFor(t, func() bool {
+		ticker.Step(1)
+		time.Sleep(5 * time.Millisecond)
+		return s.Stats().Ticks > 0
+	})
+	ran, counted := s.Stats().Ticks, ticker.Count()
+
+	// Hold the simulation while the ticker keeps going
+	s.Lock()
+	ticker.Step(150)
+	waitFor(t, func() bool { return ticker.Count() >= counted+150 })
+	s.Unlock()
+
+	waitFor(t, func() bool {
+		stats := s.Stats()
+		return stats.Ticks-ran+stats.Skipped == ticker.Count()-counted
+	})
+	stats := s.Stats()
+	if stats.Skipped == 0 || stats.Ticks-ran > 2*maxCatchUp {
+		t.Fatal("A scheduler that fell far behind should skip the ticks beyond maxCatchUp.")
+	}
+}
+
+// waitFor waits up to a few seconds for the condition to hold.
+func waitFor(t *testing.T, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("The condition never held.")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
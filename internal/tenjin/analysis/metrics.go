@@ -5,8 +5,11 @@ import (
 	"sync"
 	"time"
 
+	"github.com/odin-software/metro/control"
 	"github.com/odin-software/metro/internal/models"
+	"github.com/odin-software/metro/internal/tenjin/intelligence"
 	"github.com/odin-software/metro/internal/tenjin/scoring"
+	"github.com/odin-software/metro/internal/tenjin/strategy"
 )
 
 // Metrics holds the current state of system metrics
@@ -18,6 +21,8 @@ type Metrics struct {
 	TotalDistanceTraveled   float64
 	TrainsPerLine           map[string]int // Future: when we track line info
 	ErrorCount              int
+	Commands                int // Commands answered by the trains
+	RejectedCommands        int // Commands the trains rejected
 	TotalPassengers         int
 	PassengersWaiting       int
 	PassengersRiding        int
@@ -34,6 +39,83 @@ type Metrics struct {
 	LateArrivals         int     // More than 2 minutes late
 	AverageDelay         float64 // Average delay in seconds (negative = early)
 	OnTimePercentage     float64 // Percentage of on-time arrivals
+	// Crowding metrics
+	DeniedBoardings           int           // Times a passenger was left behind by a full train
+	PassengersLeftBehind      int           // Passengers left behind at least once
+	DeniedBoardingsPerStation map[int64]int // Denied boardings by station
+	// Sentiment causes
+	SentimentByCause map[string]float64 // Total change of sentiment by cause, negative is unhappiness
+	// Lost ridership
+	LostRidership          int           // Passengers that left without travelling
+	Balked                 int           // Left because the queue was too long when they arrived
+	Reneged                int           // Left because they ran out of patience
+	AbandonmentsPerStation map[int64]int // Lost ridership by station
+	// Commuters, kept across days
+	Commuters            map[string]CommuterStatus // Latest status of every commuter that made a trip
+	CommuterSatisfaction float64                   // Average baseline satisfaction of the commuters
+	// Fares and operating cost
+	Revenue           float64            // Fares charged at the gates
+	RevenuePerLine    map[string]float64 // Fares shared between the lines of each journey
+	RevenuePerStation map[int64]float64  // Fares by the station where they were charged
+	RevenuePerHour    map[int]float64    // Fares by simulation hour (0-23)
+	TrainHours        float64            // Hours of service run by the trains, in simulation time
+	OperatingCost     float64            // Train-hours at control.Config.Fares.TrainHourCost
+	FareboxRecovery   float64            // Percentage of the operating cost paid by fares
+	// Special events
+	SpecialEvents map[int64]SpecialEventStatus // How the metro coped with the crowds of each event
+	// Arrival predictions, kept across days
+	PredictionAccuracy []models.PredictionAccuracy // How the countdowns did against the actual arrivals, by horizon
+	// Interventions recommended by the intelligence layer
+	Recommendations []intelligence.Recommendation // Current recommendations, most severe first
+	// Headway regularisation
+	Regularity     []strategy.Regularity // How evenly spaced the trains of every line run
+	HeadwayControl bool                  // Tenjin holds and slows down trains to even out the headways
+	// Simulated day the daily metrics are for
+	Day      int            // Simulated day, starting at 0
+	Date     time.Time      // Date of the simulated day
+	DayType  models.DayType // Service the day runs
+	PastDays []DaySummary   // How the previous days went, oldest first
+}
+
+// DaySummary is how a simulated day went, to compare weekdays with weekends
+type DaySummary struct {
+	Day              int
+	Date             time.Time
+	DayType          models.DayType
+	Arrived          int     // Passengers that reached their destination
+	Boardings        int     // Passengers that boarded a train
+	LostRidership    int     // Passengers that left without travelling
+	OnTimePercentage float64 // Percentage of on-time arrivals of the trains
+	Revenue          float64 // Fares charged at the gates
+	FareboxRecovery  float64 // Percentage of the operating cost paid by fares
+	AverageScore     float64 // Average system score of the day
+}
+
+// CommuterStatus is what a recurring commuter thinks of the metro after their last trip
+type CommuterStatus struct {
+	ID           string
+	Name         string
+	HomeStation  string
+	WorkStation  string
+	LastTrip     float64 // Sentiment at the end of the last trip
+	Abandoned    bool    // The last trip was abandoned
+	Satisfaction float64 // Baseline satisfaction carried across days
+	Trips        int
+	BadDays      int
+}
+
+// SpecialEventStatus is how the trips of the attendees of a special event went
+type SpecialEventStatus struct {
+	ID              int64
+	Name            string
+	Station         string
+	Attendance      int
+	Trips           int     // Trips to and from the event that ended
+	Arrived         int     // Attendees that made it to the event
+	WentHome        int     // Attendees that made it home after the event
+	Abandoned       int     // Attendees that gave up on the metro
+	DeniedBoardings int     // Times attendees were left behind by full trains
+	Sentiment       float64 // Average sentiment at the end of the trips
 }
 
 // MetricsEngine calculates and maintains metrics from events
@@ -42,19 +124,33 @@ type MetricsEngine struct {
 	mu                     sync.RWMutex
 	trainSpeeds            map[string]float64    // Track individual train speeds for averaging
 	trainDistances         map[string]float64    // Track cumulative distance per train
+	trainOdometers         map[string]float64    // Last odometer reading per train
+	trainLastTick          map[string]time.Time  // Last tick per train, to count train-hours
 	passengerStates        map[string]string     // Track passenger states (waiting/riding/arrived)
 	passengerSentiment     map[string]float64    // Track passenger sentiment
 	scoreHistory           *scoring.ScoreHistory // Score tracking
 	totalStations          int                   // Total stations in system
 	stationsWithPassengers map[int64]bool        // Stations that have served passengers
-	currentDay             time.Time             // Track current day for daily resets
+	currentDay             int                   // Track current simulated day for daily resets
 	delays                 []float64             // Track all delays for averaging
+	leftBehind             map[string]bool       // Passengers denied boarding at least once today
 	scheduleDB             ScheduleDB            // Interface for schedule lookups
+	clock                  SimulationClock       // Simulated day and date
+}
+
+// maxPastDays is how many previous simulated days are summarized, four weeks
+const maxPastDays = 28
+
+// SimulationClock gives the metrics the simulated day, the daily metrics start over at its midnight
+type SimulationClock interface {
+	GetDay() int
+	GetDate() time.Time
+	GetDayType() models.DayType
 }
 
 // ScheduleDB provides schedule lookup functionality
 type ScheduleDB interface {
-	GetScheduleByTrainAndStation(trainID, stationID int64) (Schedule, error)
+	GetScheduleByTrainAndStation(trainID, stationID int64, dayType models.DayType) (Schedule, error)
 }
 
 // Schedule represents a scheduled stop
@@ -65,9 +161,9 @@ type Schedule struct {
 }
 
 // NewMetricsEngine creates a new metrics engine
-func NewMetricsEngine(totalTrains int, scheduleDB ScheduleDB) *MetricsEngine {
+func NewMetricsEngine(totalTrains int, scheduleDB ScheduleDB, clock SimulationClock) *MetricsEngine {
 	now := time.Now()
-	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	day := clock.GetDay()
 
 	return &MetricsEngine{
 		current: Metrics{
@@ -77,17 +173,34 @@ func NewMetricsEngine(totalTrains int, scheduleDB ScheduleDB) *MetricsEngine {
 			TrainsPerLine:        make(map[string]int),
 			LastUpdated:          now,
 			Score:                scoring.ScoreComponents{Overall: 100.0, Grade: "S"},
+
+			DeniedBoardingsPerStation: make(map[int64]int),
+			SentimentByCause:          make(map[string]float64),
+			AbandonmentsPerStation:    make(map[int64]int),
+			Commuters:                 make(map[string]CommuterStatus),
+			RevenuePerLine:            make(map[string]float64),
+			RevenuePerStation:         make(map[int64]float64),
+			RevenuePerHour:            make(map[int]float64),
+			SpecialEvents:             make(map[int64]SpecialEventStatus),
+			Day:                       day,
+			Date:                      clock.GetDate(),
+			DayType:                   clock.GetDayType(),
+			PastDays:                  make([]DaySummary, 0),
 		},
 		trainSpeeds:            make(map[string]float64),
 		trainDistances:         make(map[string]float64),
+		trainOdometers:         make(map[string]float64),
+		trainLastTick:          make(map[string]time.Time),
 		passengerStates:        make(map[string]string),
 		passengerSentiment:     make(map[string]float64),
-		scoreHistory:           scoring.NewScoreHistory(),
+		scoreHistory:           scoring.NewScoreHistory(day),
 		stationsWithPassengers: make(map[int64]bool),
 		totalStations:          0, // Will be set based on events
-		currentDay:             dayStart,
+		currentDay:             day,
 		delays:                 make([]float64, 0),
+		leftBehind:             make(map[string]bool),
 		scheduleDB:             scheduleDB,
+		clock:                  clock,
 	}
 }
 
@@ -130,13 +243,17 @@ func (m *MetricsEngine) ProcessEvents(events []interface{}) {
 			Speed          float64
 			CurrentStation int64
 			NextStation    int64
+			Odometer       float64
 			Time           time.Time
 		}); ok && e.Type == "train_tick" {
 			// Update speed tracking
 			m.trainSpeeds[e.Train] = e.Speed
-			// Update distance (speed * time since last tick, roughly)
-			// Since we tick every second, distance = speed * 1 second
-			m.trainDistances[e.Train] += e.Speed
+			// Update distance from the odometer delta, measured along the track geometry
+			if last, seen := m.trainOdometers[e.Train]; seen && e.Odometer > last {
+				m.trainDistances[e.Train] += e.Odometer - last
+			}
+			m.trainOdometers[e.Train] = e.Odometer
+			m.trackTrainHours(e.Train, e.Time)
 		} else if e, ok := event.(struct {
 			Type    string
 			Train   string
@@ -145,10 +262,26 @@ func (m *MetricsEngine) ProcessEvents(events []interface{}) {
 			Time    time.Time
 		}); ok && e.Type == "train_error" {
 			m.current.ErrorCount++
+		} else if e, ok := event.(struct {
+			Type      string
+			TrainID   int64
+			Train     string
+			CommandID int64
+			Kind      string
+			Source    string
+			Accepted  bool
+			Reason    string
+			Time      time.Time
+		}); ok && e.Type == "train_command" {
+			m.current.Commands++
+			if !e.Accepted {
+				m.current.RejectedCommands++
+			}
 		} else if e, ok := event.(struct {
 			Type            string
 			PassengerID     string
 			PassengerName   string
+			Persona         string
 			StationID       int64
 			StationName     string
 			DestinationID   int64
@@ -208,6 +341,136 @@ func (m *MetricsEngine) ProcessEvents(events []interface{}) {
 			Time          time.Time
 		}); ok && e.Type == "passenger_wait" {
 			m.passengerSentiment[e.PassengerID] = e.Sentiment
+		} else if e, ok := event.(struct {
+			Type          string
+			PassengerID   string
+			PassengerName string
+			TrainName     string
+			StationID     int64
+			StationName   string
+			DeniedCount   int
+			Sentiment     float64
+			Time          time.Time
+		}); ok && e.Type == "passenger_denied_boarding" {
+			m.passengerSentiment[e.PassengerID] = e.Sentiment
+			m.current.DeniedBoardings++
+			m.current.DeniedBoardingsPerStation[e.StationID]++
+			if !m.leftBehind[e.PassengerID] {
+				m.leftBehind[e.PassengerID] = true
+				m.current.PassengersLeftBehind++
+			}
+		} else if e, ok := event.(struct {
+			Type          string
+			PassengerID   string
+			PassengerName string
+			Cause         string
+			Delta         float64
+			Sentiment     float64
+			Time          time.Time
+		}); ok && e.Type == "passenger_sentiment" {
+			if _, active := m.passengerStates[e.PassengerID]; active {
+				m.passengerSentiment[e.PassengerID] = e.Sentiment
+			}
+			m.current.SentimentByCause[e.Cause] += e.Delta
+		} else if e, ok := event.(struct {
+			Type          string
+			PassengerID   string
+			PassengerName string
+			StationID     int64
+			StationName   string
+			Reason        string
+			WaitDuration  time.Duration
+			Sentiment     float64
+			Time          time.Time
+		}); ok && e.Type == "passenger_abandon" {
+			// Passenger gave up - remove from tracking and count the lost trip
+			delete(m.passengerStates, e.PassengerID)
+			delete(m.passengerSentiment, e.PassengerID)
+			m.current.LostRidership++
+			m.current.AbandonmentsPerStation[e.StationID]++
+			if e.Reason == string(models.AbandonBalk) {
+				m.current.Balked++
+			} else {
+				m.current.Reneged++
+			}
+		} else if e, ok := event.(struct {
+			Type          string
+			CommuterID    string
+			CommuterName  string
+			HomeStation   string
+			WorkStation   string
+			TripSentiment float64
+			Satisfaction  float64
+			Abandoned     bool
+			Trips         int
+			BadDays       int
+			Time          time.Time
+		}); ok && e.Type == "commuter_trip" {
+			m.current.Commuters[e.CommuterID] = CommuterStatus{
+				ID:           e.CommuterID,
+				Name:         e.CommuterName,
+				HomeStation:  e.HomeStation,
+				WorkStation:  e.WorkStation,
+				LastTrip:     e.TripSentiment,
+				Abandoned:    e.Abandoned,
+				Satisfaction: e.Satisfaction,
+				Trips:        e.Trips,
+				BadDays:      e.BadDays,
+			}
+			total := 0.0
+			for _, c := range m.current.Commuters {
+				total += c.Satisfaction
+			}
+			m.current.CommuterSatisfaction = total / float64(len(m.current.Commuters))
+		} else if e, ok := event.(struct {
+			Type          string
+			PassengerID   string
+			PassengerName string
+			Persona       string
+			StationID     int64
+			StationName   string
+			Gate          string
+			Amount        float64
+			Lines         []string
+			SimTime       int
+			Time          time.Time
+		}); ok && e.Type == "passenger_fare" {
+			m.current.Revenue += e.Amount
+			m.current.RevenuePerStation[e.StationID] += e.Amount
+			m.current.RevenuePerHour[(e.SimTime/3600)%24] += e.Amount
+			for _, line := range e.Lines {
+				m.current.RevenuePerLine[line] += e.Amount / float64(len(e.Lines))
+			}
+		} else if e, ok := event.(struct {
+			Type            string
+			EventID         int64
+			EventName       string
+			StationID       int64
+			StationName     string
+			Attendance      int
+			PassengerID     string
+			Arriving        bool
+			Abandoned       bool
+			DeniedBoardings int
+			Sentiment       float64
+			Time            time.Time
+		}); ok && e.Type == "special_event_trip" {
+			status := m.current.SpecialEvents[e.EventID]
+			status.ID = e.EventID
+			status.Name = e.EventName
+			status.Station = e.StationName
+			status.Attendance = e.Attendance
+			status.Sentiment = (status.Sentiment*float64(status.Trips) + e.Sentiment) / float64(status.Trips+1)
+			status.Trips++
+			status.DeniedBoardings += e.DeniedBoardings
+			if e.Abandoned {
+				status.Abandoned++
+			} else if e.Arriving {
+				status.Arrived++
+			} else {
+				status.WentHome++
+			}
+			m.current.SpecialEvents[e.EventID] = status
 		}
 	}
 
@@ -216,6 +479,22 @@ func (m *MetricsEngine) ProcessEvents(events []interface{}) {
 	m.current.LastUpdated = time.Now()
 }
 
+// trackTrainHours adds the simulation time since the last tick of the train to the
+// train-hours. Tick events carry the simulation time, so pauses are not counted, and
+// gaps longer than a few ticks, when events were dropped, are skipped.
+func (m *MetricsEngine) trackTrainHours(train string, at time.Time) {
+	last, seen := m.trainLastTick[train]
+	m.trainLastTick[train] = at
+	if !seen {
+		return
+	}
+	elapsed := at.Sub(last)
+	if elapsed <= 0 || elapsed.Seconds() > 5*control.DefaultConfig.SimulationSpeed {
+		return
+	}
+	m.current.TrainHours += elapsed.Hours()
+}
+
 // calculateAverages recomputes average speed and total distance
 // trackPunctuality compares actual arrival time with scheduled time
 func (m *MetricsEngine) trackPunctuality(trainID, stationID int64, actualTime int) {
@@ -225,7 +504,7 @@ func (m *MetricsEngine) trackPunctuality(trainID, stationID int64, actualTime in
 	}
 
 	// Look up scheduled time
-	schedule, err := m.scheduleDB.GetScheduleByTrainAndStation(trainID, stationID)
+	schedule, err := m.scheduleDB.GetScheduleByTrainAndStation(trainID, stationID, m.current.DayType)
 	if err != nil {
 		// No schedule found for this train/station combo (not an error, just skip)
 		return
@@ -279,6 +558,13 @@ func (m *MetricsEngine) calculateAverages() {
 	}
 	m.current.TotalDistanceTraveled = totalDistance
 
+	// Operating cost and how much of it the fares pay
+	m.current.OperatingCost = m.current.TrainHours * control.DefaultConfig.Fares.TrainHourCost
+	m.current.FareboxRecovery = 0
+	if m.current.OperatingCost > 0 {
+		m.current.FareboxRecovery = m.current.Revenue / m.current.OperatingCost * 100
+	}
+
 	// Count passenger states
 	m.current.PassengersWaiting = 0
 	m.current.PassengersRiding = 0
@@ -313,15 +599,18 @@ func (m *MetricsEngine) calculateAverages() {
 
 // calculateScore computes the overall system score based on current metrics
 func (m *MetricsEngine) calculateScore() {
-	// Check if we need to reset for a new day
-	now := time.Now()
-	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
-	if dayStart.After(m.currentDay) {
-		m.currentDay = dayStart
+	// Check if we need to reset for a new simulated day
+	if day := m.clock.GetDay(); day != m.currentDay {
+		m.archiveDay()
+		m.currentDay = day
+		m.current.Day = day
+		m.current.Date = m.clock.GetDate()
+		m.current.DayType = m.clock.GetDayType()
 		// Reset cumulative metrics for new day (without locking - already locked by caller)
 		m.current.ArrivalsPerStation = make(map[int64]int)
 		m.current.DeparturesPerStation = make(map[int64]int)
 		m.current.ErrorCount = 0
+		m.current.Commands, m.current.RejectedCommands = 0, 0
 		m.current.TotalDistanceTraveled = 0
 		m.current.PassengersArrived = 0
 		m.current.PassengerBoardings = 0
@@ -337,6 +626,12 @@ func (m *MetricsEngine) calculateScore() {
 		m.current.AverageDelay = 0
 		m.current.OnTimePercentage = 0
 		m.delays = make([]float64, 0)
+		// Reset crowding and sentiment cause metrics
+		m.resetCrowding()
+		m.current.SentimentByCause = make(map[string]float64)
+		m.resetLostRidership()
+		m.resetRevenue()
+		m.current.SpecialEvents = make(map[int64]SpecialEventStatus)
 		// Note: Don't reset passengerStates/passengerSentiment - those track active passengers
 	}
 
@@ -393,6 +688,10 @@ func (m *MetricsEngine) calculateScore() {
 		MaxStationCongestion:   maxCongestion,
 		AverageStationWait:     avgStationWait,
 		TrainErrors:            m.current.ErrorCount,
+		SentimentByCause:       m.current.SentimentByCause,
+		LostPassengers:         m.current.LostRidership,
+		Commuters:              len(m.current.Commuters),
+		CommuterSatisfaction:   m.current.CommuterSatisfaction,
 	}
 
 	// Calculate score
@@ -402,7 +701,53 @@ func (m *MetricsEngine) calculateScore() {
 	m.current.Score = score
 
 	// Record in history
-	m.scoreHistory.Update(score)
+	m.scoreHistory.Update(score, m.currentDay)
+}
+
+// archiveDay summarizes the day that is ending, the lock must be held
+func (m *MetricsEngine) archiveDay() {
+	m.current.PastDays = append(m.current.PastDays, DaySummary{
+		Day:              m.current.Day,
+		Date:             m.current.Date,
+		DayType:          m.current.DayType,
+		Arrived:          m.current.PassengersArrived,
+		Boardings:        m.current.PassengerBoardings,
+		LostRidership:    m.current.LostRidership,
+		OnTimePercentage: m.current.OnTimePercentage,
+		Revenue:          m.current.Revenue,
+		FareboxRecovery:  m.current.FareboxRecovery,
+		AverageScore:     m.scoreHistory.GetDailyStats().AverageScore,
+	})
+	if len(m.current.PastDays) > maxPastDays {
+		m.current.PastDays = m.current.PastDays[len(m.current.PastDays)-maxPastDays:]
+	}
+}
+
+// resetCrowding clears the denied boarding counters, the lock must be held
+func (m *MetricsEngine) resetCrowding() {
+	m.current.DeniedBoardings = 0
+	m.current.PassengersLeftBehind = 0
+	m.current.DeniedBoardingsPerStation = make(map[int64]int)
+	m.leftBehind = make(map[string]bool)
+}
+
+// resetLostRidership clears the abandonment counters, the lock must be held
+func (m *MetricsEngine) resetLostRidership() {
+	m.current.LostRidership = 0
+	m.current.Balked = 0
+	m.current.Reneged = 0
+	m.current.AbandonmentsPerStation = make(map[int64]int)
+}
+
+// resetRevenue clears the fares and the operating cost, the lock must be held
+func (m *MetricsEngine) resetRevenue() {
+	m.current.Revenue = 0
+	m.current.RevenuePerLine = make(map[string]float64)
+	m.current.RevenuePerStation = make(map[int64]float64)
+	m.current.RevenuePerHour = make(map[int]float64)
+	m.current.TrainHours = 0
+	m.current.OperatingCost = 0
+	m.current.FareboxRecovery = 0
 }
 
 // GetMetrics returns a copy of current metrics (thread-safe)
@@ -424,9 +769,76 @@ func (m *MetricsEngine) GetMetrics() Metrics {
 		metrics.DeparturesPerStation[k] = v
 	}
 
+	metrics.DeniedBoardingsPerStation = make(map[int64]int)
+	for k, v := range m.current.DeniedBoardingsPerStation {
+		metrics.DeniedBoardingsPerStation[k] = v
+	}
+
+	metrics.SentimentByCause = make(map[string]float64)
+	for k, v := range m.current.SentimentByCause {
+		metrics.SentimentByCause[k] = v
+	}
+
+	metrics.AbandonmentsPerStation = make(map[int64]int)
+	for k, v := range m.current.AbandonmentsPerStation {
+		metrics.AbandonmentsPerStation[k] = v
+	}
+
+	metrics.Commuters = make(map[string]CommuterStatus)
+	for k, v := range m.current.Commuters {
+		metrics.Commuters[k] = v
+	}
+
+	metrics.RevenuePerLine = make(map[string]float64)
+	for k, v := range m.current.RevenuePerLine {
+		metrics.RevenuePerLine[k] = v
+	}
+
+	metrics.RevenuePerStation = make(map[int64]float64)
+	for k, v := range m.current.RevenuePerStation {
+		metrics.RevenuePerStation[k] = v
+	}
+
+	metrics.RevenuePerHour = make(map[int]float64)
+	for k, v := range m.current.RevenuePerHour {
+		metrics.RevenuePerHour[k] = v
+	}
+
+	metrics.SpecialEvents = make(map[int64]SpecialEventStatus)
+	for k, v := range m.current.SpecialEvents {
+		metrics.SpecialEvents[k] = v
+	}
+
+	metrics.PredictionAccuracy = append([]models.PredictionAccuracy(nil), m.current.PredictionAccuracy...)
+	metrics.Recommendations = append([]intelligence.Recommendation(nil), m.current.Recommendations...)
+	metrics.Regularity = append([]strategy.Regularity(nil), m.current.Regularity...)
+	metrics.PastDays = append([]DaySummary(nil), m.current.PastDays...)
+
 	return metrics
 }
 
+// SetPredictionAccuracy updates how the arrival predictions did against the actual arrivals
+func (m *MetricsEngine) SetPredictionAccuracy(accuracy []models.PredictionAccuracy) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.current.PredictionAccuracy = accuracy
+}
+
+// SetRecommendations updates the interventions recommended by the intelligence layer
+func (m *MetricsEngine) SetRecommendations(recommendations []intelligence.Recommendation) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.current.Recommendations = recommendations
+}
+
+// SetRegularity updates the regularity of the lines and whether Tenjin regularises them
+func (m *MetricsEngine) SetRegularity(regularity []strategy.Regularity, enabled bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.current.Regularity = regularity
+	m.current.HeadwayControl = enabled
+}
+
 // GetFormattedOutput returns a human-readable metrics summary
 func (m *MetricsEngine) GetFormattedOutput() string {
 	m.mu.RLock()
@@ -434,6 +846,7 @@ func (m *MetricsEngine) GetFormattedOutput() string {
 
 	output := fmt.Sprintf("\n=== TENJIN METRICS (Updated: %s) ===\n",
 		m.current.LastUpdated.Format("15:04:05"))
+	output += fmt.Sprintf("Day %d: %s (%s)\n", m.current.Day+1, models.FormatDate(m.current.Date), m.current.DayType)
 
 	// System Score (prominent display)
 	output += fmt.Sprintf("\n*** SYSTEM SCORE: %.1f (%s) ***\n",
@@ -448,6 +861,9 @@ func (m *MetricsEngine) GetFormattedOutput() string {
 	output += fmt.Sprintf("Average Speed: %.2f\n", m.current.AverageSpeed)
 	output += fmt.Sprintf("Total Distance Traveled: %.2f\n", m.current.TotalDistanceTraveled)
 	output += fmt.Sprintf("Total Errors: %d\n", m.current.ErrorCount)
+	if m.current.Commands > 0 {
+		output += fmt.Sprintf("Commands: %d (%d rejected)\n", m.current.Commands, m.current.RejectedCommands)
+	}
 
 	output += "\n--- PASSENGERS ---\n"
 	output += fmt.Sprintf("Total Passengers: %d\n", m.current.TotalPassengers)
@@ -456,6 +872,17 @@ func (m *MetricsEngine) GetFormattedOutput() string {
 	output += fmt.Sprintf("Total Boardings: %d | Disembarkments: %d\n",
 		m.current.PassengerBoardings, m.current.PassengerDisembarkments)
 	output += fmt.Sprintf("Average Sentiment: %.1f/100\n", m.current.AverageSentiment)
+	output += fmt.Sprintf("Left Behind: %d passengers | Denied Boardings: %d\n",
+		m.current.PassengersLeftBehind, m.current.DeniedBoardings)
+	output += fmt.Sprintf("Lost Ridership: %d passengers (balked: %d | reneged: %d)\n",
+		m.current.LostRidership, m.current.Balked, m.current.Reneged)
+	if len(m.current.Commuters) > 0 {
+		output += fmt.Sprintf("Commuters: %d | Satisfaction: %.1f/100\n",
+			len(m.current.Commuters), m.current.CommuterSatisfaction)
+	}
+	if m.current.Score.TopComplaint != "" {
+		output += fmt.Sprintf("Main Complaint: %s\n", models.SentimentCause(m.current.Score.TopComplaint).Description())
+	}
 
 	// Punctuality metrics
 	if m.current.TotalArrivalsChecked > 0 {
@@ -472,6 +899,52 @@ func (m *MetricsEngine) GetFormattedOutput() string {
 		}
 	}
 
+	// Revenue metrics
+	if m.current.Revenue > 0 || m.current.OperatingCost > 0 {
+		output += "\n--- REVENUE ---\n"
+		output += fmt.Sprintf("Fares: %.2f | Operating Cost: %.2f (%.1f train-hours)\n",
+			m.current.Revenue, m.current.OperatingCost, m.current.TrainHours)
+		output += fmt.Sprintf("Farebox Recovery: %.1f%%\n", m.current.FareboxRecovery)
+		for line, revenue := range m.current.RevenuePerLine {
+			output += fmt.Sprintf("  %s: %.2f\n", line, revenue)
+		}
+	}
+
+	// Special event metrics
+	if len(m.current.SpecialEvents) > 0 {
+		output += "\n--- SPECIAL EVENTS ---\n"
+		for _, ev := range m.current.SpecialEvents {
+			output += fmt.Sprintf("  %s at %s: %d trips (arrived: %d | went home: %d | abandoned: %d) | Denied Boardings: %d | Sentiment: %.1f/100\n",
+				ev.Name, ev.Station, ev.Trips, ev.Arrived, ev.WentHome, ev.Abandoned, ev.DeniedBoardings, ev.Sentiment)
+		}
+	}
+
+	// Arrival prediction metrics
+	if len(m.current.PredictionAccuracy) > 0 {
+		output += "\n--- ARRIVAL PREDICTIONS ---\n"
+		for _, acc := range m.current.PredictionAccuracy {
+			if acc.Count == 0 {
+				continue
+			}
+			output += fmt.Sprintf("  %s ahead: %d arrivals | Mean Error: %+.0fs | Mean Absolute Error: %.0fs\n",
+				acc.Horizon, acc.Count, acc.MeanError, acc.MeanAbsError)
+		}
+	}
+
+	// Interventions recommended by the intelligence layer
+	output += intelligence.Format(m.current.Recommendations)
+	output += strategy.Format(m.current.Regularity, m.current.HeadwayControl)
+
+	// Previous days, to compare weekdays with weekends
+	if len(m.current.PastDays) > 0 {
+		output += "\n--- PAST DAYS ---\n"
+		for _, d := range m.current.PastDays {
+			output += fmt.Sprintf("  %s (%s): %d arrived | %d boardings | %d lost | On-Time: %.1f%% | Fares: %.2f (%.1f%% recovery) | Score: %.1f\n",
+				models.FormatDate(d.Date), d.DayType, d.Arrived, d.Boardings, d.LostRidership,
+				d.OnTimePercentage, d.Revenue, d.FareboxRecovery, d.AverageScore)
+		}
+	}
+
 	output += fmt.Sprintf("\nStation Arrivals (%d stations):\n", len(m.current.ArrivalsPerStation))
 	for stationID, count := range m.current.ArrivalsPerStation {
 		output += fmt.Sprintf("  Station %d: %d arrivals\n", stationID, count)
@@ -495,6 +968,7 @@ func (m *MetricsEngine) Reset() {
 	m.current.ArrivalsPerStation = make(map[int64]int)
 	m.current.DeparturesPerStation = make(map[int64]int)
 	m.current.ErrorCount = 0
+	m.current.Commands, m.current.RejectedCommands = 0, 0
 	m.current.TotalDistanceTraveled = 0
 	m.current.PassengersArrived = 0
 	m.current.PassengerBoardings = 0
@@ -502,6 +976,11 @@ func (m *MetricsEngine) Reset() {
 	m.trainSpeeds = make(map[string]float64)
 	m.trainDistances = make(map[string]float64)
 	m.stationsWithPassengers = make(map[int64]bool)
+	m.resetCrowding()
+	m.current.SentimentByCause = make(map[string]float64)
+	m.resetLostRidership()
+	m.resetRevenue()
+	m.current.SpecialEvents = make(map[int64]SpecialEventStatus)
 	m.current.LastUpdated = time.Now()
 	// Note: Don't reset passengerStates/passengerSentiment - those track active passengers
 }
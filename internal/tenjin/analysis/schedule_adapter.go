@@ -2,6 +2,7 @@ package analysis
 
 import (
 	"github.com/odin-software/metro/internal/baso"
+	"github.com/odin-software/metro/internal/models"
 )
 
 // BasoScheduleAdapter adapts baso.Baso to implement the ScheduleDB interface
@@ -16,9 +17,9 @@ func NewBasoScheduleAdapter() *BasoScheduleAdapter {
 	}
 }
 
-// GetScheduleByTrainAndStation looks up a schedule entry
-func (a *BasoScheduleAdapter) GetScheduleByTrainAndStation(trainID, stationID int64) (Schedule, error) {
-	dbSchedule, err := a.baso.GetScheduleByTrainAndStation(trainID, stationID)
+// GetScheduleByTrainAndStation looks up a schedule entry of a day type
+func (a *BasoScheduleAdapter) GetScheduleByTrainAndStation(trainID, stationID int64, dayType models.DayType) (Schedule, error) {
+	dbSchedule, err := a.baso.GetScheduleByTrainAndStation(trainID, stationID, dayType)
 	if err != nil {
 		return Schedule{}, err
 	}
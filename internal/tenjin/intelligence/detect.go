@@ -0,0 +1,417 @@
+package intelligence
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/odin-software/metro/internal/models"
+)
+
+// minHold is the shortest hold worth recommending, in seconds.
+const minHold = 10
+
+// overcrowding finds the stations whose platforms are fuller than the config allows
+// and recommends another train on the line most of their passengers wait for.
+func (e *Engine) overcrowding(state State) []Recommendation {
+	recommendations := make([]Recommendation, 0)
+	for _, st := range state.Stations {
+		level := st.Crowding()
+		if level < e.config.Crowding {
+			continue
+		}
+		line, waiting := busiestLine(state, st)
+		if line.ID == 0 {
+			continue
+		}
+
+		severity := SeverityWarning
+		if level >= 1 {
+			severity = SeverityCritical
+		}
+		rationale := fmt.Sprintf("%s has %d waiting, %.0f%% of its platforms", st.Name, st.Waiting, level*100)
+		if denied := state.DeniedBoardings[st.ID]; denied > 0 {
+			rationale += fmt.Sprintf(", %d denied boardings today", denied)
+		}
+		if waiting > 0 {
+			rationale += fmt.Sprintf(", %d of them for %s", waiting, line.Name)
+		}
+		recommendations = append(recommendations, Recommendation{
+			Kind:        KindInjectTrain,
+			Detection:   DetectOvercrowding,
+			Severity:    severity,
+			StationID:   st.ID,
+			StationName: st.Name,
+			LineID:      line.ID,
+			LineName:    line.Name,
+			Rationale:   rationale,
+		})
+	}
+	return recommendations
+}
+
+// busiestLine returns the line with the most passengers waiting on its platforms at
+// a station, or the first line through the station when none of them has a line.
+func busiestLine(state State, st StationState) (LineState, int) {
+	byLine := make(map[int64]int)
+	for platform, count := range st.Platforms {
+		if platform.LineID != 0 {
+			byLine[platform.LineID] += count
+		}
+	}
+	best, waiting := LineState{}, 0
+	for _, ln := range state.Lines {
+		if count := byLine[ln.ID]; count > waiting {
+			best, waiting = ln, count
+		}
+	}
+	if best.ID != 0 {
+		return best, waiting
+	}
+	for _, ln := range state.Lines {
+		for _, id := range ln.Stations {
+			if id == st.ID {
+				return ln, 0
+			}
+		}
+	}
+	return LineState{}, 0
+}
+
+// headways compares the predicted arrivals of consecutive trains at each platform
+// with the timetable headway. A train bunched with the one ahead is held at its next
+// stop, and so is a train with too long a gap behind it, by half the difference so
+// the spacing evens out on both sides. Platforms without a timetable are skipped.
+func (e *Engine) headways(state State) []Recommendation {
+	// Trains are only held at their next stop, the soonest of their arrivals
+	next := make(map[int64]models.ArrivalPrediction)
+	for _, p := range state.Platforms {
+		for _, arrival := range p.Arrivals {
+			if soonest, ok := next[arrival.TrainID]; !ok || arrival.ETA < soonest.ETA {
+				next[arrival.TrainID] = arrival
+			}
+		}
+	}
+	isNext := func(arrival models.ArrivalPrediction) bool {
+		soonest := next[arrival.TrainID]
+		return soonest.StationID == arrival.StationID && soonest.Platform == arrival.Platform
+	}
+
+	maxHold := e.config.MaxHold.Seconds()
+	recommendations := make([]Recommendation, 0)
+	for _, p := range state.Platforms {
+		if p.Headway <= 0 || len(p.Arrivals) < 2 {
+			continue
+		}
+		line, _ := state.line(p.Platform.LineID)
+		bunched, gap := e.config.Bunching*p.Headway, e.config.Gap*p.Headway
+		for i := 1; i < len(p.Arrivals); i++ {
+			lead, follow := p.Arrivals[i-1], p.Arrivals[i]
+			headway := float64(follow.Arrival() - lead.Arrival())
+
+			if headway < bunched && isNext(follow) {
+				hold := math.Min(maxHold, (p.Headway-headway)/2)
+				if hold < minHold {
+					continue
+				}
+				severity := SeverityWarning
+				if headway < bunched/2 {
+					severity = SeverityCritical
+				}
+				recommendations = append(recommendations, Recommendation{
+					Kind:        KindHold,
+					Detection:   DetectBunching,
+					Severity:    severity,
+					TrainID:     follow.TrainID,
+					TrainName:   follow.TrainName,
+					StationID:   follow.StationID,
+					StationName: follow.StationName,
+					LineID:      line.ID,
+					LineName:    line.Name,
+					Seconds:     int(math.Round(hold)),
+					Rationale: fmt.Sprintf("%s is due at %s %s after %s, the timetable headway is %s",
+						follow.TrainName, follow.StationName, formatDuration(headway), lead.TrainName, formatDuration(p.Headway)),
+				})
+			}
+
+			if headway > gap && isNext(lead) {
+				hold := math.Min(maxHold, (headway-p.Headway)/2)
+				if hold < minHold {
+					continue
+				}
+				severity := SeverityWarning
+				if headway > 2*gap {
+					severity = SeverityCritical
+				}
+				recommendations = append(recommendations, Recommendation{
+					Kind:        KindHold,
+					Detection:   DetectGap,
+					Severity:    severity,
+					TrainID:     lead.TrainID,
+					TrainName:   lead.TrainName,
+					StationID:   lead.StationID,
+					StationName: lead.StationName,
+					LineID:      line.ID,
+					LineName:    line.Name,
+					Seconds:     int(math.Round(hold)),
+					Rationale: fmt.Sprintf("%s is due at %s %s before %s, the timetable headway is %s",
+						lead.TrainName, lead.StationName, formatDuration(headway), follow.TrainName, formatDuration(p.Headway)),
+				})
+			}
+		}
+	}
+	return recommendations
+}
+
+// segment is a run of a line between two consecutive stations.
+type segment struct {
+	lineID int64
+	from   int64
+	to     int64
+}
+
+// segmentRuns keeps the delay gained on the last runs over a segment.
+type segmentRuns struct {
+	gains []float64
+}
+
+// mean returns the average delay gained.
+func (sr *segmentRuns) mean() float64 {
+	total := 0.0
+	for _, gain := range sr.gains {
+		total += gain
+	}
+	return total / float64(len(sr.gains))
+}
+
+// trainArrival is the last arrival of a train and how late it was there.
+type trainArrival struct {
+	arrival models.TrainArrival
+	delay   int
+}
+
+// lateness records the delay the trains gained between their last two stations,
+// dwell at the first included, and recommends more running time for the segments
+// where the trains lose time run after run.
+func (e *Engine) lateness(state State) []Recommendation {
+	for _, tr := range state.Trains {
+		if !tr.HasArrival {
+			continue
+		}
+		last, seen := e.arrivals[tr.ID]
+		e.arrivals[tr.ID] = trainArrival{tr.Arrival, tr.Delay}
+		if !seen || last.arrival == tr.Arrival || !consecutive(state, tr.LineID, last.arrival.StationID, tr.Arrival.StationID) {
+			continue
+		}
+		key := segment{tr.LineID, last.arrival.StationID, tr.Arrival.StationID}
+		runs := e.segments[key]
+		if runs == nil {
+			runs = &segmentRuns{}
+			e.segments[key] = runs
+		}
+		runs.gains = append(runs.gains, float64(tr.Delay-last.delay))
+		if len(runs.gains) > e.config.LatenessRuns {
+			runs.gains = runs.gains[len(runs.gains)-e.config.LatenessRuns:]
+		}
+	}
+
+	// Segments judged and those losing time, by line and direction
+	type direction struct {
+		lineID   int64
+		terminal int64
+	}
+	threshold := e.config.LatenessGain.Seconds()
+	judged := make(map[direction]int)
+	late := make(map[direction][]segment)
+	for key, runs := range e.segments {
+		if len(runs.gains) < e.config.LatenessRuns {
+			continue
+		}
+		dir := direction{key.lineID, towards(state, key)}
+		judged[dir]++
+		if runs.mean() >= threshold {
+			late[dir] = append(late[dir], key)
+		}
+	}
+
+	recommendations := make([]Recommendation, 0)
+	for dir, segments := range late {
+		line, _ := state.line(dir.lineID)
+		sort.Slice(segments, func(i, j int) bool {
+			return e.segments[segments[i]].mean() > e.segments[segments[j]].mean()
+		})
+
+		// When most of a direction loses time it is the timetable of the line, not a segment
+		if judged[dir] > 1 && 2*len(segments) > judged[dir] {
+			gain := 0.0
+			for _, key := range segments {
+				gain += e.segments[key].mean()
+			}
+			gain /= float64(len(segments))
+			terminal := state.stationName(dir.terminal)
+			recommendations = append(recommendations, Recommendation{
+				Kind:          KindRunningTime,
+				Detection:     DetectLateness,
+				Severity:      lateSeverity(gain, threshold),
+				ToStationID:   dir.terminal,
+				ToStationName: terminal,
+				LineID:        line.ID,
+				LineName:      line.Name,
+				Seconds:       int(math.Round(gain)),
+				Rationale: fmt.Sprintf("trains lost %s on average on %d of the %d segments of %s towards %s, worst from %s to %s",
+					formatDuration(gain), len(segments), judged[dir], line.Name, terminal,
+					state.stationName(segments[0].from), state.stationName(segments[0].to)),
+			})
+			continue
+		}
+
+		for _, key := range segments {
+			runs := e.segments[key]
+			gain := runs.mean()
+			from, to := state.stationName(key.from), state.stationName(key.to)
+			recommendations = append(recommendations, Recommendation{
+				Kind:          KindRunningTime,
+				Detection:     DetectLateness,
+				Severity:      lateSeverity(gain, threshold),
+				StationID:     key.from,
+				StationName:   from,
+				ToStationID:   key.to,
+				ToStationName: to,
+				LineID:        line.ID,
+				LineName:      line.Name,
+				Seconds:       int(math.Round(gain)),
+				Rationale: fmt.Sprintf("trains lost %s on average over the last %d runs from %s to %s",
+					formatDuration(gain), len(runs.gains), from, to),
+			})
+		}
+	}
+	return recommendations
+}
+
+// lateSeverity is critical when the delay gained is twice the threshold.
+func lateSeverity(gain, threshold float64) Severity {
+	if gain >= 2*threshold {
+		return SeverityCritical
+	}
+	return SeverityWarning
+}
+
+// towards returns the terminal of the line a segment runs towards, 0 when the line
+// doesn't have its stations.
+func towards(state State, seg segment) int64 {
+	line, ok := state.line(seg.lineID)
+	if !ok || len(line.Stations) == 0 {
+		return 0
+	}
+	for _, id := range line.Stations {
+		switch id {
+		case seg.from:
+			return line.Stations[len(line.Stations)-1]
+		case seg.to:
+			return line.Stations[0]
+		}
+	}
+	return 0
+}
+
+// consecutive returns true if two stations are next to each other on a line.
+func consecutive(state State, lineID, from, to int64) bool {
+	line, ok := state.line(lineID)
+	if !ok {
+		return false
+	}
+	for i := 1; i < len(line.Stations); i++ {
+		a, b := line.Stations[i-1], line.Stations[i]
+		if a == from && b == to || a == to && b == from {
+			return true
+		}
+	}
+	return false
+}
+
+// lineLoad is the load of a line, averaged over the load window.
+type lineLoad struct {
+	average float64
+	since   int // When the line was first evaluated
+	last    int // When the average was last updated
+}
+
+// mismatch compares the passengers of each line, riding and waiting for it, with the
+// places of its trains, averaged over the load window. Lines carrying more than the
+// config allows need another train, lines with almost nobody can spare one.
+func (e *Engine) mismatch(state State) []Recommendation {
+	window := e.config.LoadWindow.Seconds()
+	recommendations := make([]Recommendation, 0)
+	for _, line := range state.Lines {
+		trains, places, riding, waiting := 0, 0, 0, 0
+		for _, tr := range state.Trains {
+			if tr.LineID == line.ID {
+				trains++
+				places += tr.Capacity
+				riding += tr.Passengers
+			}
+		}
+		for _, st := range state.Stations {
+			for platform, count := range st.Platforms {
+				if platform.LineID == line.ID {
+					waiting += count
+				}
+			}
+		}
+
+		if places == 0 {
+			if waiting > 0 {
+				recommendations = append(recommendations, Recommendation{
+					Kind:      KindInjectTrain,
+					Detection: DetectMismatch,
+					Severity:  SeverityCritical,
+					LineID:    line.ID,
+					LineName:  line.Name,
+					Rationale: fmt.Sprintf("%s has no trains and %d waiting", line.Name, waiting),
+				})
+			}
+			continue
+		}
+
+		load := float64(riding+waiting) / float64(places)
+		ll := e.loads[line.ID]
+		if ll == nil {
+			ll = &lineLoad{average: load, since: state.Time, last: state.Time}
+			e.loads[line.ID] = ll
+		} else if state.Time > ll.last {
+			ll.average += (load - ll.average) * math.Min(1, float64(state.Time-ll.last)/window)
+			ll.last = state.Time
+		}
+		if float64(state.Time-ll.since) < window {
+			continue
+		}
+
+		rationale := fmt.Sprintf("%s carried %.0f%% of its places over the last %s, now %d riding and %d waiting on %d trains",
+			line.Name, ll.average*100, formatDuration(window), riding, waiting, trains)
+		switch {
+		case ll.average >= e.config.HighLoad:
+			severity := SeverityWarning
+			if ll.average >= 1 {
+				severity = SeverityCritical
+			}
+			recommendations = append(recommendations, Recommendation{
+				Kind:      KindInjectTrain,
+				Detection: DetectMismatch,
+				Severity:  severity,
+				LineID:    line.ID,
+				LineName:  line.Name,
+				Rationale: rationale,
+			})
+		case ll.average <= e.config.LowLoad && trains > 1:
+			recommendations = append(recommendations, Recommendation{
+				Kind:      KindWithdrawTrain,
+				Detection: DetectMismatch,
+				Severity:  SeverityInfo,
+				LineID:    line.ID,
+				LineName:  line.Name,
+				Rationale: rationale,
+			})
+		}
+	}
+	return recommendations
+}
@@ -0,0 +1,455 @@
+package intelligence
+
+import (
+	"testing"
+
+	"github.com/odin-software/metro/control"
+	"github.com/odin-software/metro/internal/models"
+)
+
+var (
+	line1 = LineState{ID: 1, Name: "L1", Stations: []int64{1, 2, 3, 4}}
+	line2 = LineState{ID: 2, Name: "L2", Stations: []int64{2, 5}}
+)
+
+func newTestEngine() *Engine {
+	return NewEngine(control.DefaultConfig.Intelligence)
+}
+
+// forward is the platform of a line towards its last station.
+func forward(lineID int64) models.Platform {
+	return models.Platform{LineID: lineID, Forward: true}
+}
+
+func TestBusiestLine(t *testing.T) {
+	state := State{Lines: []LineState{line1, line2}}
+	cases := []struct {
+		name      string
+		station   StationState
+		wantLine  int64
+		wantCount int
+	}{
+		{
+			name:      "most waiting",
+			station:   StationState{ID: 2, Platforms: map[models.Platform]int{forward(1): 10, forward(2): 30, {LineID: 1}: 15}},
+			wantLine:  2,
+			wantCount: 30,
+		},
+		{
+			name:      "both directions",
+			station:   StationState{ID: 2, Platforms: map[models.Platform]int{forward(1): 20, {LineID: 1}: 15, forward(2): 30}},
+			wantLine:  1,
+			wantCount: 35,
+		},
+		{
+			name:     "no platform has a line",
+			station:  StationState{ID: 5, Platforms: map[models.Platform]int{{}: 40}},
+			wantLine: 2,
+		},
+		{
+			name:     "nobody waiting",
+			station:  StationState{ID: 3},
+			wantLine: 1,
+		},
+		{
+			name:    "no line through the station",
+			station: StationState{ID: 9, Platforms: map[models.Platform]int{{}: 40}},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			line, waiting := busiestLine(state, c.station)
+			if line.ID != c.wantLine || waiting != c.wantCount {
+				t.Fatalf("The busiest line should be %d with %d waiting, got %d with %d.", c.wantLine, c.wantCount, line.ID, waiting)
+			}
+		})
+	}
+}
+
+func TestOvercrowding(t *testing.T) {
+	cases := []struct {
+		name     string
+		station  StationState
+		denied   int
+		want     bool
+		severity Severity
+		line     int64
+	}{
+		{
+			name:    "under the crowding level",
+			station: StationState{ID: 2, Name: "B", Waiting: 79, Capacity: 100, Platforms: map[models.Platform]int{forward(1): 79}},
+		},
+		{
+			name:    "no capacity",
+			station: StationState{ID: 2, Name: "B", Waiting: 79, Platforms: map[models.Platform]int{forward(1): 79}},
+		},
+		{
+			name:     "warning",
+			station:  StationState{ID: 2, Name: "B", Waiting: 80, Capacity: 100, Platforms: map[models.Platform]int{forward(1): 20, forward(2): 60}},
+			want:     true,
+			severity: SeverityWarning,
+			line:     2,
+		},
+		{
+			name:     "critical",
+			station:  StationState{ID: 2, Name: "B", Waiting: 100, Capacity: 100, Platforms: map[models.Platform]int{forward(1): 70, forward(2): 30}},
+			denied:   12,
+			want:     true,
+			severity: SeverityCritical,
+			line:     1,
+		},
+		{
+			name:     "no platform has a line",
+			station:  StationState{ID: 5, Name: "E", Waiting: 90, Capacity: 100, Platforms: map[models.Platform]int{{}: 90}},
+			want:     true,
+			severity: SeverityWarning,
+			line:     2,
+		},
+		{
+			name:    "no line through the station",
+			station: StationState{ID: 9, Name: "I", Waiting: 90, Capacity: 100},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			state := State{
+				Stations:        []StationState{c.station},
+				Lines:           []LineState{line1, line2},
+				DeniedBoardings: map[int64]int{c.station.ID: c.denied},
+			}
+			recommendations := newTestEngine().overcrowding(state)
+			if !c.want {
+				if len(recommendations) != 0 {
+					t.Fatalf("No train should be recommended, got %v.", recommendations)
+				}
+				return
+			}
+			if len(recommendations) != 1 {
+				t.Fatalf("A train should be recommended, got %v.", recommendations)
+			}
+			r := recommendations[0]
+			if r.Kind != KindInjectTrain || r.Detection != DetectOvercrowding || r.StationID != c.station.ID {
+				t.Fatal("An overcrowded station should ask for another train.")
+			}
+			if r.Severity != c.severity || r.LineID != c.line {
+				t.Fatalf("The recommendation should be %s on line %d, got %s on line %d.", c.severity, c.line, r.Severity, r.LineID)
+			}
+		})
+	}
+}
+
+func TestHeadways(t *testing.T) {
+	type hold struct {
+		detection Detection
+		trainID   int64
+		seconds   int
+		severity  Severity
+	}
+	cases := []struct {
+		name     string
+		headway  float64 // Timetable headway of the platform
+		after    float64 // Seconds between the two trains
+		bunching float64 // Share of the headway overriding the config, when set
+		want     []hold
+	}{
+		{name: "even", headway: 300, after: 300},
+		{name: "no timetable", after: 10},
+		{
+			name:    "bunched",
+			headway: 300,
+			after:   130,
+			want:    []hold{{DetectBunching, 2, 85, SeverityWarning}},
+		},
+		{
+			name:    "bunched at the critical level",
+			headway: 300,
+			after:   70,
+			want:    []hold{{DetectBunching, 2, 90, SeverityCritical}},
+		},
+		{
+			name:    "bunched over the longest hold",
+			headway: 300,
+			after:   10,
+			want:    []hold{{DetectBunching, 2, 90, SeverityCritical}},
+		},
+		{
+			name:     "bunched under the shortest hold",
+			headway:  300,
+			after:    282,
+			bunching: 0.95,
+		},
+		{
+			name:     "bunched at the shortest hold",
+			headway:  300,
+			after:    280,
+			bunching: 0.95,
+			want:     []hold{{DetectBunching, 2, 10, SeverityWarning}},
+		},
+		{
+			name:    "gap",
+			headway: 300,
+			after:   460,
+			want:    []hold{{DetectGap, 1, 80, SeverityWarning}},
+		},
+		{
+			name:    "gap over the longest hold",
+			headway: 300,
+			after:   600,
+			want:    []hold{{DetectGap, 1, 90, SeverityWarning}},
+		},
+		{
+			name:    "gap at the critical level",
+			headway: 300,
+			after:   901,
+			want:    []hold{{DetectGap, 1, 90, SeverityCritical}},
+		},
+		{
+			name:    "gap under the shortest hold",
+			headway: 15,
+			after:   30,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			e := newTestEngine()
+			if c.bunching != 0 {
+				e.config.Bunching = c.bunching
+			}
+			state := State{
+				Lines: []LineState{line1},
+				Platforms: []PlatformState{{
+					StationID: 2,
+					Platform:  forward(1),
+					Headway:   c.headway,
+					Arrivals: []models.ArrivalPrediction{
+						{TrainID: 1, TrainName: "T1", StationID: 2, StationName: "B", Platform: forward(1), Issued: 1000, ETA: 60},
+						{TrainID: 2, TrainName: "T2", StationID: 2, StationName: "B", Platform: forward(1), Issued: 1000, ETA: 60 + c.after},
+					},
+				}},
+			}
+
+			recommendations := e.headways(state)
+			if len(recommendations) != len(c.want) {
+				t.Fatalf("There should be %d holds, got %v.", len(c.want), recommendations)
+			}
+			for i, want := range c.want {
+				r := recommendations[i]
+				if r.Kind != KindHold || r.StationID != 2 || r.LineID != 1 || r.LineName != "L1" {
+					t.Fatal("The train should be held at the station of the platform.")
+				}
+				got := hold{r.Detection, r.TrainID, r.Seconds, r.Severity}
+				if got != want {
+					t.Fatalf("The hold should be %v, got %v.", want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestHeadwaysHoldAtNextStop(t *testing.T) {
+	arrival := func(trainID, stationID int64, eta float64) models.ArrivalPrediction {
+		return models.ArrivalPrediction{TrainID: trainID, StationID: stationID, Platform: forward(1), Issued: 1000, ETA: eta}
+	}
+	state := State{
+		Lines: []LineState{line1},
+		Platforms: []PlatformState{
+			{StationID: 2, Platform: forward(1), Headway: 300, Arrivals: []models.ArrivalPrediction{arrival(1, 2, 60), arrival(2, 2, 100)}},
+			{StationID: 3, Platform: forward(1), Headway: 300, Arrivals: []models.ArrivalPrediction{arrival(1, 3, 200), arrival(2, 3, 240)}},
+		},
+	}
+
+	recommendations := newTestEngine().headways(state)
+	if len(recommendations) != 1 || recommendations[0].TrainID != 2 || recommendations[0].StationID != 2 {
+		t.Fatalf("A bunched train should only be held at its next stop, got %v.", recommendations)
+	}
+}
+
+// arrive records the arrival of a train at a station on line 1 with its delay.
+func arrive(e *Engine, trainID, stationID int64, time, delay int) []Recommendation {
+	state := State{
+		Time:  time,
+		Lines: []LineState{line1},
+		Trains: []TrainState{{
+			ID:         trainID,
+			LineID:     1,
+			Delay:      delay,
+			Arrival:    models.TrainArrival{StationID: stationID, Platform: forward(1), Time: time},
+			HasArrival: true,
+		}},
+	}
+	return e.lateness(state)
+}
+
+// runs sends a train per run over each segment of line 1 from its station, gaining
+// the delay of the segment, and returns the recommendations after the last run.
+func runs(e *Engine, runs int, gains map[int64]int) []Recommendation {
+	var recommendations []Recommendation
+	trainID, time := int64(0), 0
+	for i := 0; i < runs; i++ {
+		for from, gain := range gains {
+			trainID++
+			time += 100
+			arrive(e, trainID, from, time, 20)
+			recommendations = arrive(e, trainID, from+1, time+90, 20+gain)
+		}
+	}
+	return recommendations
+}
+
+func TestLateness(t *testing.T) {
+	type late struct {
+		from     int64
+		to       int64
+		seconds  int
+		severity Severity
+	}
+	cases := []struct {
+		name  string
+		runs  int
+		gains map[int64]int // Delay gained on each run from a station to the next
+		want  []late
+	}{
+		{name: "on time", runs: 5, gains: map[int64]int{1: 0}},
+		{name: "under the threshold", runs: 5, gains: map[int64]int{1: 29}},
+		{name: "not enough runs", runs: 4, gains: map[int64]int{1: 40}},
+		{
+			name:  "late",
+			runs:  5,
+			gains: map[int64]int{1: 40},
+			want:  []late{{1, 2, 40, SeverityWarning}},
+		},
+		{
+			name:  "late at the critical level",
+			runs:  5,
+			gains: map[int64]int{1: 60},
+			want:  []late{{1, 2, 60, SeverityCritical}},
+		},
+		{
+			name:  "late on a segment of the line",
+			runs:  5,
+			gains: map[int64]int{1: 0, 2: 45, 3: 0},
+			want:  []late{{2, 3, 45, SeverityWarning}},
+		},
+		{
+			name:  "late on most of the line",
+			runs:  5,
+			gains: map[int64]int{1: 40, 2: 80, 3: 0},
+			want:  []late{{0, 4, 60, SeverityCritical}},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			recommendations := runs(newTestEngine(), c.runs, c.gains)
+			if len(recommendations) != len(c.want) {
+				t.Fatalf("There should be %d running times, got %v.", len(c.want), recommendations)
+			}
+			for i, want := range c.want {
+				r := recommendations[i]
+				if r.Kind != KindRunningTime || r.Detection != DetectLateness || r.LineID != 1 {
+					t.Fatal("A late segment should ask for more running time.")
+				}
+				got := late{r.StationID, r.ToStationID, r.Seconds, r.Severity}
+				if got != want {
+					t.Fatalf("The running time should be %v, got %v.", want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestLatenessSameArrival(t *testing.T) {
+	e := newTestEngine()
+	arrive(e, 1, 1, 100, 0)
+	for i := 0; i < 10; i++ {
+		arrive(e, 1, 2, 200, 40)
+	}
+	if runs := e.segments[segment{1, 1, 2}]; runs == nil || len(runs.gains) != 1 {
+		t.Fatal("An arrival seen again should count as a single run.")
+	}
+	arrive(e, 1, 4, 300, 80)
+	if len(e.segments) != 1 {
+		t.Fatal("Stations that aren't next to each other should not make a segment.")
+	}
+}
+
+func TestMismatch(t *testing.T) {
+	train := func(id int64, passengers int) TrainState {
+		return TrainState{ID: id, LineID: 1, Passengers: passengers, Capacity: 100}
+	}
+	cases := []struct {
+		name     string
+		trains   []TrainState
+		waiting  int
+		want     Kind
+		severity Severity
+		early    bool // Recommended before the load window passed
+	}{
+		{name: "no trains, nobody waiting"},
+		{
+			name:     "no trains",
+			waiting:  5,
+			want:     KindInjectTrain,
+			severity: SeverityCritical,
+			early:    true,
+		},
+		{name: "even", trains: []TrainState{train(1, 40), train(2, 40)}, waiting: 20},
+		{
+			name:     "high load",
+			trains:   []TrainState{train(1, 70), train(2, 60)},
+			waiting:  20,
+			want:     KindInjectTrain,
+			severity: SeverityWarning,
+		},
+		{
+			name:     "over capacity",
+			trains:   []TrainState{train(1, 100), train(2, 90)},
+			waiting:  10,
+			want:     KindInjectTrain,
+			severity: SeverityCritical,
+		},
+		{
+			name:     "low load",
+			trains:   []TrainState{train(1, 4), train(2, 0)},
+			want:     KindWithdrawTrain,
+			severity: SeverityInfo,
+		},
+		{name: "low load on a single train", trains: []TrainState{train(1, 0)}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			e := newTestEngine()
+			state := State{
+				Lines:  []LineState{line1},
+				Trains: c.trains,
+				Stations: []StationState{
+					{ID: 2, Platforms: map[models.Platform]int{forward(1): c.waiting, forward(2): 50}},
+				},
+			}
+
+			recommendations := e.mismatch(state)
+			if !c.early && len(recommendations) != 0 {
+				t.Fatal("The load of a line should be averaged over the load window first.")
+			}
+			state.Time = int(e.config.LoadWindow.Seconds())
+			recommendations = e.mismatch(state)
+			if c.want == "" {
+				if len(recommendations) != 0 {
+					t.Fatalf("Nothing should be recommended, got %v.", recommendations)
+				}
+				return
+			}
+			if len(recommendations) != 1 {
+				t.Fatalf("A recommendation should be made, got %v.", recommendations)
+			}
+			r := recommendations[0]
+			if r.Kind != c.want || r.Severity != c.severity || r.Detection != DetectMismatch || r.LineID != 1 {
+				t.Fatalf("The recommendation should be %s %s, got %s %s.", c.severity, c.want, r.Severity, r.Kind)
+			}
+		})
+	}
+}
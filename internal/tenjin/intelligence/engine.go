@@ -0,0 +1,106 @@
+package intelligence
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/odin-software/metro/control"
+)
+
+// Engine evaluates the state of the simulation and keeps the recommendations that
+// still apply. Evaluate is called from a single goroutine, the recommendations can
+// be read from any.
+type Engine struct {
+	config      control.IntelligenceConfig
+	segments    map[segment]*segmentRuns // Delay gained on each segment, for chronic lateness
+	arrivals    map[int64]trainArrival   // Last arrival seen of each train
+	loads       map[int64]*lineLoad      // Average load of each line
+	current     map[key]Recommendation
+	recommended []Recommendation // Current recommendations, most severe first
+	mu          sync.RWMutex
+}
+
+func NewEngine(config control.IntelligenceConfig) *Engine {
+	return &Engine{
+		config:   config,
+		segments: make(map[segment]*segmentRuns),
+		arrivals: make(map[int64]trainArrival),
+		loads:    make(map[int64]*lineLoad),
+		current:  make(map[key]Recommendation),
+	}
+}
+
+// Evaluate runs the detections on the state and replaces the recommendations. It
+// returns the recommendations that weren't there at the last evaluation.
+func (e *Engine) Evaluate(state State) []Recommendation {
+	found := make(map[key]Recommendation)
+	add := func(recommendations []Recommendation) {
+		for _, r := range recommendations {
+			if previous, ok := found[r.key()]; ok {
+				r = merge(previous, r)
+			}
+			found[r.key()] = r
+		}
+	}
+	add(e.overcrowding(state))
+	add(e.headways(state))
+	add(e.lateness(state))
+	add(e.mismatch(state))
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	added := make([]Recommendation, 0)
+	for k, r := range found {
+		if previous, ok := e.current[k]; ok {
+			r.Since = previous.Since
+		} else {
+			r.Since = state.Time
+			added = append(added, r)
+		}
+		found[k] = r
+	}
+	e.current = found
+	e.recommended = make([]Recommendation, 0, len(found))
+	for _, r := range found {
+		e.recommended = append(e.recommended, r)
+	}
+	sortRecommendations(e.recommended)
+	sortRecommendations(added)
+	return added
+}
+
+// Recommendations returns the current recommendations, most severe first.
+func (e *Engine) Recommendations() []Recommendation {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return append([]Recommendation(nil), e.recommended...)
+}
+
+// sortRecommendations sorts by severity, then the oldest first.
+func sortRecommendations(recommendations []Recommendation) {
+	sort.Slice(recommendations, func(i, j int) bool {
+		a, b := recommendations[i], recommendations[j]
+		if a.Severity != b.Severity {
+			return a.Severity > b.Severity
+		}
+		if a.Since != b.Since {
+			return a.Since < b.Since
+		}
+		return a.String() < b.String()
+	})
+}
+
+// Format writes the recommendations for the metrics report.
+func Format(recommendations []Recommendation) string {
+	if len(recommendations) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("\n--- RECOMMENDATIONS ---\n")
+	for _, r := range recommendations {
+		fmt.Fprintf(&sb, "  [%s] %s: %s\n", r.Severity, r.String(), r.Rationale)
+	}
+	return sb.String()
+}
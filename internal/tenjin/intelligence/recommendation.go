@@ -0,0 +1,119 @@
+// Package intelligence is the thinking layer of Tenjin. It evaluates the state of
+// the simulation every tick, detects what is going wrong and recommends what to do
+// about it, for the operator and for the action layer.
+package intelligence
+
+import (
+	"fmt"
+	"math"
+)
+
+// Severity is how urgent a recommendation is.
+type Severity int
+
+const (
+	SeverityInfo Severity = iota
+	SeverityWarning
+	SeverityCritical
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityCritical:
+		return "critical"
+	case SeverityWarning:
+		return "warning"
+	}
+	return "info"
+}
+
+// Detection is what the state showed that led to a recommendation.
+type Detection string
+
+const (
+	DetectOvercrowding Detection = "overcrowding"    // More passengers on the platforms than they comfortably hold
+	DetectBunching     Detection = "bunching"        // A train too close behind the one ahead
+	DetectGap          Detection = "gap"             // A train too far ahead of the one behind
+	DetectLateness     Detection = "lateness"        // Trains losing time on the same segment run after run
+	DetectMismatch     Detection = "demand_mismatch" // More or fewer trains on a line than its passengers need
+)
+
+// Kind is the intervention a recommendation asks for.
+type Kind string
+
+const (
+	KindHold          Kind = "hold"           // Hold a train at a station
+	KindInjectTrain   Kind = "inject_train"   // Put another train in service on a line
+	KindWithdrawTrain Kind = "withdraw_train" // Take a train of a line out of service
+	KindRunningTime   Kind = "running_time"   // Give a segment more running time in the timetable
+)
+
+// Recommendation is an intervention Tenjin suggests, with why it does.
+type Recommendation struct {
+	Kind          Kind
+	Detection     Detection
+	Severity      Severity
+	TrainID       int64 // Train to act on, 0 for recommendations about a line
+	TrainName     string
+	StationID     int64 // Station of the hold, start of the segment of a running time, 0 for the whole line
+	StationName   string
+	ToStationID   int64 // End of the segment of a running time, terminal of the direction for the whole line
+	ToStationName string
+	LineID        int64
+	LineName      string
+	Seconds       int    // Hold, or running time to add
+	Rationale     string // What was detected, in words
+	Since         int    // When it was first made, in simulation seconds since the first midnight
+}
+
+// String describes the intervention, like "hold T12 at Los Taínos 45 s".
+func (r Recommendation) String() string {
+	switch r.Kind {
+	case KindHold:
+		return fmt.Sprintf("hold %s at %s %d s", r.TrainName, r.StationName, r.Seconds)
+	case KindInjectTrain:
+		return fmt.Sprintf("inject a train on %s", r.LineName)
+	case KindWithdrawTrain:
+		return fmt.Sprintf("withdraw a train from %s", r.LineName)
+	case KindRunningTime:
+		if r.StationID == 0 {
+			return fmt.Sprintf("add %d s of running time per stop on %s towards %s", r.Seconds, r.LineName, r.ToStationName)
+		}
+		return fmt.Sprintf("add %d s of running time from %s to %s", r.Seconds, r.StationName, r.ToStationName)
+	}
+	return string(r.Kind)
+}
+
+// key identifies the intervention, the same one recommended for two detections is
+// a single recommendation.
+type key struct {
+	kind      Kind
+	trainID   int64
+	stationID int64
+	toID      int64
+	lineID    int64
+}
+
+func (r Recommendation) key() key {
+	return key{r.Kind, r.TrainID, r.StationID, r.ToStationID, r.LineID}
+}
+
+// merge combines two recommendations of the same intervention: the most severe one
+// is kept, with both rationales.
+func merge(a, b Recommendation) Recommendation {
+	if b.Severity > a.Severity || b.Severity == a.Severity && b.Seconds > a.Seconds {
+		a, b = b, a
+	}
+	if b.Rationale != "" && b.Rationale != a.Rationale {
+		a.Rationale += "; " + b.Rationale
+	}
+	return a
+}
+
+// formatDuration writes seconds like "45 s" or "6 min".
+func formatDuration(seconds float64) string {
+	if math.Abs(seconds) < 120 {
+		return fmt.Sprintf("%.0f s", seconds)
+	}
+	return fmt.Sprintf("%.0f min", seconds/60)
+}
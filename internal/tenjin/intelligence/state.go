@@ -0,0 +1,95 @@
+package intelligence
+
+import (
+	"fmt"
+
+	"github.com/odin-software/metro/internal/models"
+)
+
+// State is what the intelligence layer sees of the simulation at a tick, read
+// between two ticks of the simulation so it is consistent.
+type State struct {
+	Time            int             // Simulation seconds since the first midnight
+	Stations        []StationState  // In the order of the simulation
+	Lines           []LineState     // In the order of the simulation
+	Trains          []TrainState    // In the order of the simulation
+	Platforms       []PlatformState // Platforms with predicted arrivals
+	DeniedBoardings map[int64]int   // Denied boardings of the day by station, from the metrics
+}
+
+// StationState is a station and the passengers waiting there.
+type StationState struct {
+	ID        int64
+	Name      string
+	Waiting   int                     // Passengers on the platforms
+	Capacity  int                     // Passengers the platforms hold, 0 is unlimited
+	Platforms map[models.Platform]int // Passengers waiting on each platform
+}
+
+// Crowding is how full the platforms are, 1 is at capacity. Stations without a
+// capacity are never crowded.
+func (st StationState) Crowding() float64 {
+	if st.Capacity <= 0 {
+		return 0
+	}
+	return float64(st.Waiting) / float64(st.Capacity)
+}
+
+// LineState is a line and the stations it runs through, in order.
+type LineState struct {
+	ID       int64
+	Name     string
+	Stations []int64
+}
+
+// TrainState is a train, where it is and how late it runs.
+type TrainState struct {
+	ID          int64
+	Name        string
+	LineID      int64
+	Delay       int                 // Seconds behind schedule at the last station, negative when early
+	Arrival     models.TrainArrival // Last arrival at a station
+	HasArrival  bool                // False before the first arrival
+	Passengers  int
+	Capacity    int
+	Position    models.LinePosition // Where the train is on the round trip of its line
+	Positioned  bool                // False when the position isn't known
+	TopSpeedKmH float64             // Top speed of its make
+	SpeedCapKmH float64             // Speed cap of a command, 0 for none
+}
+
+// PlatformState is a platform of a station and the trains predicted to get there.
+type PlatformState struct {
+	StationID int64
+	Platform  models.Platform
+	Headway   float64                    // Timetable headway in seconds, 0 when it has none
+	Arrivals  []models.ArrivalPrediction // Soonest first, one per train
+}
+
+// station returns the station with the given ID, false when it isn't in the state.
+func (s State) station(id int64) (StationState, bool) {
+	for _, st := range s.Stations {
+		if st.ID == id {
+			return st, true
+		}
+	}
+	return StationState{}, false
+}
+
+// line returns the line with the given ID, false when it isn't in the state.
+func (s State) line(id int64) (LineState, bool) {
+	for _, ln := range s.Lines {
+		if ln.ID == id {
+			return ln, true
+		}
+	}
+	return LineState{}, false
+}
+
+// stationName returns the name of a station, its ID when it isn't in the state.
+func (s State) stationName(id int64) string {
+	if st, ok := s.station(id); ok {
+		return st.Name
+	}
+	return fmt.Sprintf("station %d", id)
+}
@@ -9,14 +9,18 @@ import (
 type Collector struct {
 	eventChannel <-chan interface{}
 	buffer       []interface{}
+	limit        int // Events buffered between collections, 0 is unlimited
+	dropped      int // Events dropped because the buffer was full
 	mu           sync.RWMutex
 	lastCollect  time.Time
 }
 
-// NewCollector creates a new event collector
-func NewCollector(eventChannel <-chan interface{}) *Collector {
+// NewCollector creates a new event collector holding up to limit events between
+// collections, 0 for no limit
+func NewCollector(eventChannel <-chan interface{}, limit int) *Collector {
 	return &Collector{
 		eventChannel: eventChannel,
+		limit:        limit,
 		buffer:       make([]interface{}, 0, 100),
 		lastCollect:  time.Now(),
 	}
@@ -42,7 +46,11 @@ func (c *Collector) Collect() []interface{} {
 func (c *Collector) Start() {
 	for event := range c.eventChannel {
 		c.mu.Lock()
-		c.buffer = append(c.buffer, event)
+		if c.limit > 0 && len(c.buffer) >= c.limit {
+			c.dropped++
+		} else {
+			c.buffer = append(c.buffer, event)
+		}
 		c.mu.Unlock()
 	}
 }
@@ -54,6 +62,13 @@ func (c *Collector) GetBufferSize() int {
 	return len(c.buffer)
 }
 
+// GetDroppedCount returns how many events didn't fit in the buffer
+func (c *Collector) GetDroppedCount() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.dropped
+}
+
 // GetLastCollectTime returns when the last collection happened
 func (c *Collector) GetLastCollectTime() time.Time {
 	c.mu.RLock()
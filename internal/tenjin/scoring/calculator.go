@@ -12,6 +12,7 @@ type ScoreComponents struct {
 	Reliability           float64 // 0-100, weighted 10%
 	Overall               float64 // Weighted sum (0-100)
 	Grade                 string  // S, A, B, C, D, F
+	TopComplaint          string  // Sentiment cause that made passengers the most unhappy
 }
 
 // ScoreInputs contains the metrics needed to calculate the score
@@ -21,11 +22,16 @@ type ScoreInputs struct {
 	WaitingPassengers   int
 	RidingPassengers    int
 	ArrivedPassengers   int
+	LostPassengers      int // Passengers that gave up and left without travelling
 	AverageSentiment    float64
 	ZeroSentimentCount  int // Count of passengers with 0 sentiment
 	TotalBoardings      int
 	TotalDisembarkments int
 
+	// Commuter metrics, they remember previous days
+	Commuters            int
+	CommuterSatisfaction float64 // Average baseline satisfaction (0-100)
+
 	// Journey metrics
 	TotalJourneyTime  float64 // Sum of all journey times (seconds)
 	CompletedJourneys int     // Number of completed journeys
@@ -44,6 +50,9 @@ type ScoreInputs struct {
 
 	// Reliability metrics
 	TrainErrors int
+
+	// Total change of sentiment by cause, negative is unhappiness
+	SentimentByCause map[string]float64
 }
 
 // CalculateScore computes the overall score and its components
@@ -71,6 +80,8 @@ func CalculateScore(inputs ScoreInputs) ScoreComponents {
 	// Assign Grade
 	components.Grade = assignGrade(components.Overall)
 
+	components.TopComplaint = topComplaint(inputs.SentimentByCause)
+
 	return components
 }
 
@@ -86,7 +97,16 @@ func calculatePassengerSatisfaction(inputs ScoreInputs) float64 {
 	// Penalty: -5 points per passenger with 0 sentiment
 	zeroSentimentPenalty := float64(inputs.ZeroSentimentCount) * 5.0
 
-	score := baseScore - zeroSentimentPenalty
+	// Penalty: up to -50 points for the share of trips lost to abandonment,
+	// passengers who gave up no longer count in the average sentiment
+	lostPenalty := lostTripRate(inputs) * 50.0
+
+	// Commuters remember previous days, they give the score long-term memory
+	if inputs.Commuters > 0 {
+		baseScore = baseScore*0.7 + inputs.CommuterSatisfaction*0.3
+	}
+
+	score := baseScore - zeroSentimentPenalty - lostPenalty
 
 	// Clamp to 0-100
 	return math.Max(0, math.Min(100, score))
@@ -101,9 +121,9 @@ func calculateServiceEfficiency(inputs ScoreInputs) float64 {
 	score := 0.0
 
 	// Factor 1: Completion rate (50% of efficiency score)
-	// Percentage of passengers who arrived vs total spawned
+	// Percentage of passengers who arrived vs total spawned, lost trips never complete
 	completionRate := 0.0
-	totalSpawned := inputs.ArrivedPassengers + inputs.WaitingPassengers + inputs.RidingPassengers
+	totalSpawned := inputs.ArrivedPassengers + inputs.WaitingPassengers + inputs.RidingPassengers + inputs.LostPassengers
 	if totalSpawned > 0 {
 		completionRate = (float64(inputs.ArrivedPassengers) / float64(totalSpawned)) * 100.0
 	}
@@ -184,6 +204,28 @@ func calculateReliability(inputs ScoreInputs) float64 {
 	return math.Max(0, math.Min(100, score))
 }
 
+// lostTripRate returns the share (0-1) of finished trips that were abandoned
+func lostTripRate(inputs ScoreInputs) float64 {
+	finished := inputs.ArrivedPassengers + inputs.LostPassengers
+	if finished == 0 {
+		return 0
+	}
+	return float64(inputs.LostPassengers) / float64(finished)
+}
+
+// topComplaint returns the cause with the largest loss of sentiment, empty if there is none
+func topComplaint(byCause map[string]float64) string {
+	worst := ""
+	lowest := 0.0
+	for cause, total := range byCause {
+		if total < lowest || (total == lowest && total < 0 && cause < worst) {
+			worst = cause
+			lowest = total
+		}
+	}
+	return worst
+}
+
 // assignGrade returns a letter grade based on the overall score
 func assignGrade(score float64) string {
 	switch {
@@ -5,14 +5,16 @@ import (
 	"time"
 )
 
-// ScoreHistory tracks scores over time with daily reset
+// ScoreHistory tracks scores over time with a reset every simulated day
 type ScoreHistory struct {
-	CurrentDay       time.Time       // Start of current day
-	CurrentScore     ScoreComponents // Latest score
-	ScoreHistory     []ScoreSnapshot // Historical snapshots
-	DailyStats       DailyStatistics // Stats for current day
-	mu               sync.RWMutex    // Thread safety
-	maxHistoryLength int             // Max snapshots to keep
+	CurrentDay       int               // Simulated day being tracked
+	CurrentScore     ScoreComponents   // Latest score
+	ScoreHistory     []ScoreSnapshot   // Historical snapshots
+	DailyStats       DailyStatistics   // Stats for current day
+	PastDays         []DailyStatistics // Stats of the previous days, oldest first
+	mu               sync.RWMutex      // Thread safety
+	maxHistoryLength int               // Max snapshots to keep
+	maxPastDays      int               // Max previous days to keep
 }
 
 // ScoreSnapshot represents a score at a specific time
@@ -23,7 +25,8 @@ type ScoreSnapshot struct {
 
 // DailyStatistics tracks aggregate stats for the day
 type DailyStatistics struct {
-	DayStart           time.Time
+	Day                int       // Simulated day
+	DayStart           time.Time // When the simulated day started, in real time
 	MinScore           float64
 	MaxScore           float64
 	AverageScore       float64
@@ -38,18 +41,20 @@ type DailyStatistics struct {
 	LastGradeStartTime time.Time
 }
 
-// NewScoreHistory creates a new score history tracker
-func NewScoreHistory() *ScoreHistory {
+// NewScoreHistory creates a new score history tracker starting on a simulated day
+func NewScoreHistory(day int) *ScoreHistory {
 	now := time.Now()
-	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 
 	return &ScoreHistory{
-		CurrentDay:       dayStart,
+		CurrentDay:       day,
 		CurrentScore:     ScoreComponents{Overall: 100.0, Grade: "S"},
 		ScoreHistory:     make([]ScoreSnapshot, 0),
+		PastDays:         make([]DailyStatistics, 0),
 		maxHistoryLength: 3600, // Keep 1 hour of history at 1 second intervals
+		maxPastDays:      28,   // Keep four simulated weeks
 		DailyStats: DailyStatistics{
-			DayStart:           dayStart,
+			Day:                day,
+			DayStart:           now,
 			MinScore:           100.0,
 			MaxScore:           100.0,
 			AverageScore:       100.0,
@@ -59,17 +64,16 @@ func NewScoreHistory() *ScoreHistory {
 	}
 }
 
-// Update records a new score, checking for daily reset
-func (sh *ScoreHistory) Update(components ScoreComponents) {
+// Update records a new score of a simulated day, checking for daily reset
+func (sh *ScoreHistory) Update(components ScoreComponents, day int) {
 	sh.mu.Lock()
 	defer sh.mu.Unlock()
 
 	now := time.Now()
-	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 
 	// Check if we need to reset for a new day
-	if dayStart.After(sh.CurrentDay) {
-		sh.resetForNewDay(dayStart)
+	if day != sh.CurrentDay {
+		sh.resetForNewDay(day, now)
 	}
 
 	// Update current score
@@ -91,20 +95,26 @@ func (sh *ScoreHistory) Update(components ScoreComponents) {
 	sh.updateDailyStats(components, now)
 }
 
-// resetForNewDay resets tracking for a new day
-func (sh *ScoreHistory) resetForNewDay(dayStart time.Time) {
-	// Archive previous day's stats if needed (future: save to DB)
+// resetForNewDay archives the stats of the previous day and resets tracking for a new day
+func (sh *ScoreHistory) resetForNewDay(day int, now time.Time) {
+	// Close the time at the last grade and archive the previous day
+	sh.addGradeTime(&sh.DailyStats, now)
+	sh.PastDays = append(sh.PastDays, sh.DailyStats)
+	if len(sh.PastDays) > sh.maxPastDays {
+		sh.PastDays = sh.PastDays[len(sh.PastDays)-sh.maxPastDays:]
+	}
 
 	// Reset for new day
-	sh.CurrentDay = dayStart
+	sh.CurrentDay = day
 	sh.ScoreHistory = make([]ScoreSnapshot, 0)
 	sh.DailyStats = DailyStatistics{
-		DayStart:           dayStart,
+		Day:                day,
+		DayStart:           now,
 		MinScore:           100.0,
 		MaxScore:           100.0,
 		AverageScore:       100.0,
 		LastGrade:          "S",
-		LastGradeStartTime: dayStart,
+		LastGradeStartTime: now,
 	}
 }
 
@@ -127,28 +137,33 @@ func (sh *ScoreHistory) updateDailyStats(components ScoreComponents, now time.Ti
 	// Track time at each grade
 	if components.Grade != stats.LastGrade {
 		// Grade changed, record time spent at previous grade
-		timeDiff := now.Sub(stats.LastGradeStartTime)
-
-		switch stats.LastGrade {
-		case "S":
-			stats.TimeAtGradeS += timeDiff
-		case "A":
-			stats.TimeAtGradeA += timeDiff
-		case "B":
-			stats.TimeAtGradeB += timeDiff
-		case "C":
-			stats.TimeAtGradeC += timeDiff
-		case "D":
-			stats.TimeAtGradeD += timeDiff
-		case "F":
-			stats.TimeAtGradeF += timeDiff
-		}
-
+		sh.addGradeTime(stats, now)
 		stats.LastGrade = components.Grade
-		stats.LastGradeStartTime = now
 	}
 }
 
+// addGradeTime adds the time spent at the last grade until now
+func (sh *ScoreHistory) addGradeTime(stats *DailyStatistics, now time.Time) {
+	timeDiff := now.Sub(stats.LastGradeStartTime)
+
+	switch stats.LastGrade {
+	case "S":
+		stats.TimeAtGradeS += timeDiff
+	case "A":
+		stats.TimeAtGradeA += timeDiff
+	case "B":
+		stats.TimeAtGradeB += timeDiff
+	case "C":
+		stats.TimeAtGradeC += timeDiff
+	case "D":
+		stats.TimeAtGradeD += timeDiff
+	case "F":
+		stats.TimeAtGradeF += timeDiff
+	}
+
+	stats.LastGradeStartTime = now
+}
+
 // GetCurrentScore returns the current score (thread-safe)
 func (sh *ScoreHistory) GetCurrentScore() ScoreComponents {
 	sh.mu.RLock()
@@ -163,6 +178,13 @@ func (sh *ScoreHistory) GetDailyStats() DailyStatistics {
 	return sh.DailyStats
 }
 
+// GetPastDays returns the statistics of the previous simulated days, oldest first
+func (sh *ScoreHistory) GetPastDays() []DailyStatistics {
+	sh.mu.RLock()
+	defer sh.mu.RUnlock()
+	return append([]DailyStatistics(nil), sh.PastDays...)
+}
+
 // GetRecentHistory returns the last N score snapshots
 func (sh *ScoreHistory) GetRecentHistory(count int) []ScoreSnapshot {
 	sh.mu.RLock()
@@ -0,0 +1,113 @@
+package tenjin
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/odin-software/metro/internal/models"
+	"github.com/odin-software/metro/internal/tenjin/analysis"
+	"github.com/odin-software/metro/internal/tenjin/intelligence"
+	"github.com/odin-software/metro/internal/tenjin/strategy"
+)
+
+// Clock is the simulation clock Tenjin follows
+type Clock interface {
+	analysis.SimulationClock
+	GetCurrentTimeOfDay() int
+}
+
+// Simulation is what Tenjin reads to evaluate the state of the metro
+type Simulation struct {
+	Locker    sync.Locker // Held while the trains and stations are read, between two ticks
+	Trains    []*models.Train
+	Stations  func() []*models.Station // Current stations, edits included
+	Lines     func() []models.Line     // Current lines, edits included
+	Timetable *models.Timetable        // Headways of the platforms, nil without a timetable
+	Commands  strategy.Sender          // Where the strategies send their commands, nil only measures
+}
+
+// snapshot reads the state of the simulation for the intelligence layer
+func (t *Tenjin) snapshot() intelligence.State {
+	sim := t.simulation
+	stations := sim.Stations()
+	state := intelligence.State{
+		Time:     t.clock.GetDay()*86400 + t.clock.GetCurrentTimeOfDay(),
+		Stations: make([]intelligence.StationState, 0, len(stations)),
+		Trains:   make([]intelligence.TrainState, 0, len(sim.Trains)),
+	}
+	for _, ln := range sim.Lines() {
+		line := intelligence.LineState{ID: ln.ID, Name: ln.Name, Stations: make([]int64, len(ln.Stations))}
+		for i, st := range ln.Stations {
+			line.Stations[i] = st.ID
+		}
+		state.Lines = append(state.Lines, line)
+	}
+
+	sim.Locker.Lock()
+	predictions := make([]models.ArrivalPrediction, 0)
+	for _, st := range stations {
+		state.Stations = append(state.Stations, intelligence.StationState{
+			ID:        st.ID,
+			Name:      st.Name,
+			Waiting:   st.GetWaitingPassengersCount(),
+			Capacity:  st.PlatformCapacity,
+			Platforms: st.GetPlatformCounts(),
+		})
+	}
+	for _, tr := range sim.Trains {
+		if !tr.InService() {
+			continue
+		}
+		arrival, arrived := tr.LastArrival()
+		position, positioned := tr.GetLinePosition()
+		state.Trains = append(state.Trains, intelligence.TrainState{
+			ID:          tr.ID,
+			Name:        tr.Name,
+			LineID:      tr.GetLine().ID,
+			Delay:       tr.GetDelay(),
+			Arrival:     arrival,
+			HasArrival:  arrived,
+			Passengers:  tr.GetPassengerCount(),
+			Capacity:    tr.Capacity,
+			Position:    position,
+			Positioned:  positioned,
+			TopSpeedKmH: tr.GetTopSpeedKmH(),
+			SpeedCapKmH: tr.GetSpeedCapKmH(),
+		})
+		predictions = append(predictions, tr.GetPredictions()...)
+	}
+	sim.Locker.Unlock()
+
+	// Arrivals by platform, soonest first, with the headway of the timetable
+	type platformKey struct {
+		stationID int64
+		platform  models.Platform
+	}
+	platforms := make(map[platformKey][]models.ArrivalPrediction)
+	for _, p := range predictions {
+		key := platformKey{p.StationID, p.Platform}
+		platforms[key] = append(platforms[key], p)
+	}
+	dayType := t.clock.GetDayType()
+	for key, arrivals := range platforms {
+		sort.Slice(arrivals, func(i, j int) bool { return arrivals[i].Arrival() < arrivals[j].Arrival() })
+		platform := intelligence.PlatformState{StationID: key.stationID, Platform: key.platform, Arrivals: arrivals}
+		if sim.Timetable != nil {
+			platform.Headway = sim.Timetable.Headway(dayType, key.platform, key.stationID)
+		}
+		state.Platforms = append(state.Platforms, platform)
+	}
+	sort.Slice(state.Platforms, func(i, j int) bool {
+		a, b := state.Platforms[i], state.Platforms[j]
+		if a.StationID != b.StationID {
+			return a.StationID < b.StationID
+		}
+		if a.Platform.LineID != b.Platform.LineID {
+			return a.Platform.LineID < b.Platform.LineID
+		}
+		return a.Platform.Forward && !b.Platform.Forward
+	})
+
+	state.DeniedBoardings = t.analysis.GetMetrics().DeniedBoardingsPerStation
+	return state
+}
@@ -0,0 +1,297 @@
+// Package strategy is the action layer of Tenjin: strategies that read the state the
+// intelligence layer sees and act on the trains through the train commands.
+package strategy
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/odin-software/metro/control"
+	"github.com/odin-software/metro/internal/models"
+	"github.com/odin-software/metro/internal/tenjin/intelligence"
+)
+
+// Source is who the commands of the strategies come from.
+const Source = "tenjin"
+
+// Sender sends commands to the trains, like the models.Dispatcher.
+type Sender interface {
+	Send(command models.Command) <-chan models.CommandAck
+}
+
+// Regularity is how evenly spaced the trains of a line run.
+type Regularity struct {
+	LineID    int64
+	LineName  string
+	Trains    int
+	Target    float64 // Headway aimed for, in seconds
+	Timetable bool    // The target is the timetable headway, otherwise even spacing
+	Before    float64 // Coefficient of variation of the headways when the strategy was last switched on, -1 before that
+	Current   float64 // Coefficient of variation of the headways, averaged over the smoothing
+	Holds     int     // Holds sent since the strategy was last switched on
+	SpeedCaps int     // Speed caps sent and lifted since the strategy was last switched on
+}
+
+// Headway is the headway regularisation strategy. It measures the forward and
+// backward headways of every train from the positions of the trains of its line,
+// and once per stop holds the trains closer to the one ahead than to the one behind
+// and slows down the ones closing on the train ahead. Evaluate is called from a
+// single goroutine, the strategy can be switched and read from any.
+type Headway struct {
+	config     control.HeadwayConfig
+	sender     Sender // Nil only measures the headways
+	enabled    bool
+	switched   bool                          // Switched on since the last evaluation
+	lines      map[int64]*lineRegularity     // By line ID
+	decided    map[int64]models.TrainArrival // Stop each train was last acted on at
+	capped     map[int64]float64             // Speed caps in km/h sent to the trains and still in force
+	last       int                           // Time of the last evaluation
+	regularity []Regularity                  // Lines in the order of the state
+	mu         sync.RWMutex
+}
+
+// lineRegularity is the regularity of a line with whether its headways were
+// measured yet, the first measure starts the average.
+type lineRegularity struct {
+	Regularity
+	measured bool
+}
+
+// NewHeadway creates the strategy, switched on when the config says so. The
+// commands go to the sender, a nil sender only measures the headways.
+func NewHeadway(config control.HeadwayConfig, sender Sender) *Headway {
+	return &Headway{
+		config:   config,
+		sender:   sender,
+		enabled:  config.Enabled,
+		switched: config.Enabled,
+		lines:    make(map[int64]*lineRegularity),
+		decided:  make(map[int64]models.TrainArrival),
+		capped:   make(map[int64]float64),
+	}
+}
+
+// SetEnabled switches the strategy on or off. Switched off, the speed caps it sent
+// are lifted at the next evaluation.
+func (h *Headway) SetEnabled(enabled bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if enabled && !h.enabled {
+		h.switched = true
+	}
+	h.enabled = enabled
+}
+
+// Enabled returns true when the strategy acts on the trains.
+func (h *Headway) Enabled() bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.enabled
+}
+
+// Regularity returns how evenly spaced the trains of every line run, in the order of
+// the lines.
+func (h *Headway) Regularity() []Regularity {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return append([]Regularity(nil), h.regularity...)
+}
+
+// Evaluate measures the headways of the lines and, switched on, sends the holds and
+// speed caps that even them out.
+func (h *Headway) Evaluate(state intelligence.State) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	positions := make(map[int64][]models.LinePosition)
+	trains := make(map[int64]intelligence.TrainState)
+	for _, tr := range state.Trains {
+		if tr.Positioned {
+			positions[tr.LineID] = append(positions[tr.LineID], tr.Position)
+			trains[tr.ID] = tr
+		}
+	}
+	act := h.enabled && h.sender != nil
+
+	h.regularity = h.regularity[:0]
+	for _, ln := range state.Lines {
+		line, ok := h.lines[ln.ID]
+		if !ok {
+			line = &lineRegularity{Regularity: Regularity{LineID: ln.ID, Before: -1}}
+			h.lines[ln.ID] = line
+		}
+		headways := models.LineHeadways(positions[ln.ID])
+		line.LineName = ln.Name
+		line.Trains = len(headways)
+		line.Target, line.Timetable = h.target(state, ln, headways)
+		h.measure(line, headways, state.Time)
+		if h.switched {
+			line.Before, line.Holds, line.SpeedCaps = line.Current, 0, 0
+		}
+		if act && len(headways) > 1 {
+			for _, hw := range headways {
+				h.regulate(line, trains[hw.Position.TrainID], hw)
+			}
+		}
+		h.regularity = append(h.regularity, line.Regularity)
+	}
+	if !h.enabled {
+		h.release()
+	}
+	h.switched = false
+	h.last = state.Time
+}
+
+// target returns the headway the trains of a line aim for: the timetable headway of
+// its platforms when the line has trains enough for it and holds within MaxHold at
+// every stop can stretch the round trip to it, even spacing around the round trip
+// otherwise. A line with too many trains for its timetable still runs evenly.
+func (h *Headway) target(state intelligence.State, ln intelligence.LineState, headways []models.Headway) (float64, bool) {
+	if len(headways) == 0 || len(ln.Stations) < 2 {
+		return 0, false
+	}
+	even := 0.0
+	for _, hw := range headways {
+		even += hw.Forward
+	}
+	even /= float64(len(headways))
+
+	timetable := make([]float64, 0)
+	for _, p := range state.Platforms {
+		if p.Platform.LineID == ln.ID && p.Headway > 0 {
+			timetable = append(timetable, p.Headway)
+		}
+	}
+	if len(timetable) == 0 {
+		return even, false
+	}
+	sort.Float64s(timetable)
+	headway := timetable[len(timetable)/2]
+	stops := float64(2 * (len(ln.Stations) - 1))
+	if stretch := (headway - even) * float64(len(headways)) / stops; headway >= even && stretch <= h.config.MaxHold.Seconds() {
+		return headway, true
+	}
+	return even, false
+}
+
+// measure updates the coefficient of variation of the headways of a line, averaged
+// over the smoothing.
+func (h *Headway) measure(line *lineRegularity, headways []models.Headway, now int) {
+	if len(headways) < 2 {
+		return
+	}
+	forward := make([]float64, len(headways))
+	for i, hw := range headways {
+		forward[i] = hw.Forward
+	}
+	cv := models.CoefficientOfVariation(forward)
+	weight := 1.0
+	if line.measured && h.config.Smoothing > 0 {
+		weight = math.Min(1, float64(now-h.last)/h.config.Smoothing.Seconds())
+	}
+	line.Current += (cv - line.Current) * weight
+	line.measured = true
+}
+
+// regulate acts on a train once per stop. A train closer to the one ahead than to
+// the one behind is held at the stop by part of the difference, which evens out the
+// headways around it, and held up to the target on a line following its timetable.
+// A train closing on the one ahead also runs slower until the gap opens again.
+func (h *Headway) regulate(line *lineRegularity, tr intelligence.TrainState, hw models.Headway) {
+	if previous, ok := h.decided[tr.ID]; ok && previous == tr.Arrival {
+		return
+	}
+	h.decided[tr.ID] = tr.Arrival
+
+	correction := (hw.Backward - hw.Forward) / 2
+	if line.Timetable {
+		correction = math.Max(correction, line.Target-hw.Forward)
+	}
+	correction *= h.config.Gain
+	if hold := math.Min(correction, h.config.MaxHold.Seconds()); hold >= h.config.MinHold.Seconds() {
+		h.send(models.Command{
+			TrainID:   tr.ID,
+			Kind:      models.CommandHold,
+			Seconds:   int(hold + 0.5),
+			StationID: hw.Position.StationID,
+		})
+		line.Holds++
+	}
+
+	// Speed caps of the operator or other agents are left alone
+	capped, ours := h.capped[tr.ID]
+	if ours && math.Abs(tr.SpeedCapKmH-capped) > 0.5 {
+		delete(h.capped, tr.ID)
+		capped, ours = 0, false
+	}
+	if tr.SpeedCapKmH > 0 && !ours {
+		return
+	}
+	speed := 0.0
+	if hw.Forward < line.Target*h.config.Slowdown && hw.Backward > hw.Forward {
+		share := math.Max(h.config.MinShare, hw.Forward/line.Target)
+		speed = math.Max(math.Round(tr.TopSpeedKmH*share), control.DefaultConfig.Commands.MinSpeed)
+		if speed >= tr.TopSpeedKmH {
+			speed = 0
+		}
+	} else if ours && hw.Forward < line.Target {
+		return
+	}
+	if speed == capped {
+		return
+	}
+	h.send(models.Command{TrainID: tr.ID, Kind: models.CommandSpeedCap, SpeedKmH: speed})
+	line.SpeedCaps++
+	if speed == 0 {
+		delete(h.capped, tr.ID)
+	} else {
+		h.capped[tr.ID] = speed
+	}
+}
+
+// release lifts the speed caps sent by the strategy and forgets the stops it acted
+// on, once it is switched off.
+func (h *Headway) release() {
+	for id := range h.capped {
+		h.send(models.Command{TrainID: id, Kind: models.CommandSpeedCap})
+	}
+	clear(h.capped)
+	clear(h.decided)
+}
+
+// send sends a command of the strategy, the trains log and emit their answers.
+func (h *Headway) send(command models.Command) {
+	if h.sender == nil {
+		return
+	}
+	command.Source = Source
+	h.sender.Send(command)
+}
+
+// Format writes the regularity of the lines for the metrics output.
+func Format(regularity []Regularity, enabled bool) string {
+	if len(regularity) == 0 {
+		return ""
+	}
+	state := "off"
+	if enabled {
+		state = "on"
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "\n--- HEADWAY REGULARITY (control %s) ---\n", state)
+	for _, r := range regularity {
+		aim := "even spacing"
+		if r.Timetable {
+			aim = "timetable"
+		}
+		fmt.Fprintf(&sb, "  %s: %d trains, %.0f s apart (%s) | CV %.2f", r.LineName, r.Trains, r.Target, aim, r.Current)
+		if r.Before >= 0 {
+			fmt.Fprintf(&sb, " (%.2f before) | %d holds, %d speed caps", r.Before, r.Holds, r.SpeedCaps)
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
@@ -0,0 +1,231 @@
+package strategy
+
+import (
+	"math"
+	"testing"
+
+	"github.com/odin-software/metro/control"
+	"github.com/odin-software/metro/internal/models"
+	"github.com/odin-software/metro/internal/tenjin/intelligence"
+)
+
+// fakeSender records the commands sent to the trains.
+type fakeSender struct {
+	commands []models.Command
+}
+
+func (s *fakeSender) Send(command models.Command) <-chan models.CommandAck {
+	s.commands = append(s.commands, command)
+	return nil
+}
+
+// take returns the commands sent since the last call.
+func (s *fakeSender) take() []models.Command {
+	commands := s.commands
+	s.commands = nil
+	return commands
+}
+
+// find returns the command of a kind sent to a train.
+func find(commands []models.Command, trainID int64, kind models.CommandKind) (models.Command, bool) {
+	for _, c := range commands {
+		if c.TrainID == trainID && c.Kind == kind {
+			return c, true
+		}
+	}
+	return models.Command{}, false
+}
+
+var testLine = intelligence.LineState{ID: 1, Name: "L1", Stations: []int64{1, 2, 3, 4}}
+
+// lineState places a train of line 1 at each offset of a round trip of 1200 s, all
+// arrived at the same stop.
+func lineState(time int, stop int64, offsets ...float64) intelligence.State {
+	state := intelligence.State{Time: time, Lines: []intelligence.LineState{testLine}}
+	for i, offset := range offsets {
+		id := int64(i + 1)
+		state.Trains = append(state.Trains, intelligence.TrainState{
+			ID:          id,
+			LineID:      1,
+			Arrival:     models.TrainArrival{StationID: stop, Time: int(stop) * 100},
+			HasArrival:  true,
+			TopSpeedKmH: 80,
+			Positioned:  true,
+			Position:    models.LinePosition{TrainID: id, LineID: 1, StationID: stop, Forward: true, Offset: offset, RoundTrip: 1200},
+		})
+	}
+	return state
+}
+
+// bunched has T2 40 s behind T3, with T1 and T4 300 s apart.
+func bunched(time int, stop int64) intelligence.State {
+	return lineState(time, stop, 0, 300, 340, 900)
+}
+
+func newTestHeadway(enabled bool) (*Headway, *fakeSender) {
+	config := control.DefaultConfig.Headway
+	config.Enabled = enabled
+	sender := &fakeSender{}
+	return NewHeadway(config, sender), sender
+}
+
+func TestHeadwayHoldsBunchedTrain(t *testing.T) {
+	h, sender := newTestHeadway(true)
+
+	h.Evaluate(bunched(100, 2))
+	commands := sender.take()
+	hold, ok := find(commands, 2, models.CommandHold)
+	if !ok || hold.Seconds != int(control.DefaultConfig.Headway.MaxHold.Seconds()) || hold.StationID != 2 || hold.Source != Source {
+		t.Fatalf("A bunched train should be held at its stop up to MaxHold, got %v.", commands)
+	}
+	capped, ok := find(commands, 2, models.CommandSpeedCap)
+	if !ok || capped.SpeedKmH != 40 {
+		t.Fatalf("A train closing on the one ahead should be slowed down, got %v.", commands)
+	}
+	if _, ok := find(commands, 3, models.CommandHold); ok {
+		t.Fatal("A train with a long gap ahead should not be held.")
+	}
+	if _, ok := find(commands, 3, models.CommandSpeedCap); ok {
+		t.Fatal("A train with a long gap ahead should not be slowed down.")
+	}
+
+	h.Evaluate(bunched(105, 2))
+	if commands := sender.take(); len(commands) != 0 {
+		t.Fatalf("A train should be acted on once per stop, got %v.", commands)
+	}
+}
+
+func TestHeadwayReleasesSpeedCap(t *testing.T) {
+	h, sender := newTestHeadway(true)
+	h.Evaluate(bunched(100, 2))
+	sender.take()
+
+	// Closer than the target but no longer closing, the cap stays
+	state := lineState(200, 3, 0, 300, 500, 900)
+	state.Trains[1].SpeedCapKmH = 40
+	h.Evaluate(state)
+	if _, ok := find(sender.take(), 2, models.CommandSpeedCap); ok {
+		t.Fatal("The speed cap should stay until the headway reaches the target.")
+	}
+
+	state = lineState(300, 4, 0, 300, 600, 900)
+	state.Trains[1].SpeedCapKmH = 40
+	h.Evaluate(state)
+	commands := sender.take()
+	if capped, ok := find(commands, 2, models.CommandSpeedCap); !ok || capped.SpeedKmH != 0 {
+		t.Fatalf("The speed cap should be lifted once the headway recovered, got %v.", commands)
+	}
+	if len(h.capped) != 0 {
+		t.Fatal("A lifted speed cap should be forgotten.")
+	}
+}
+
+func TestHeadwayLeavesOtherSpeedCaps(t *testing.T) {
+	h, sender := newTestHeadway(true)
+	state := bunched(100, 2)
+	state.Trains[1].SpeedCapKmH = 30
+
+	h.Evaluate(state)
+	if _, ok := find(sender.take(), 2, models.CommandSpeedCap); ok {
+		t.Fatal("A speed cap of the operator should be left alone.")
+	}
+}
+
+func TestHeadwaySetEnabled(t *testing.T) {
+	h, sender := newTestHeadway(true)
+	state := lineState(100, 2, 0, 100, 600, 650)
+	h.Evaluate(state)
+	sender.take()
+	if len(h.capped) != 2 {
+		t.Fatal("The two trains closing on the ones ahead should have been slowed down.")
+	}
+
+	h.SetEnabled(false)
+	if h.Enabled() {
+		t.Fatal("The strategy should be switched off.")
+	}
+	state.Time = 200
+	for i := range state.Trains {
+		state.Trains[i].Arrival.StationID = 3
+	}
+	h.Evaluate(state)
+	commands := sender.take()
+	lifted := 0
+	for _, c := range commands {
+		if c.Kind != models.CommandSpeedCap || c.SpeedKmH != 0 || c.Source != Source {
+			t.Fatalf("Switched off, the strategy should only lift its speed caps, got %v.", commands)
+		}
+		lifted++
+	}
+	if lifted != 2 || len(h.capped) != 0 || len(h.decided) != 0 {
+		t.Fatal("Switched off, every speed cap of the strategy should be lifted.")
+	}
+
+	h.Evaluate(state)
+	if commands := sender.take(); len(commands) != 0 {
+		t.Fatalf("Switched off, the strategy should send nothing, got %v.", commands)
+	}
+}
+
+func TestHeadwayRegularity(t *testing.T) {
+	h, sender := newTestHeadway(false)
+	cv := models.CoefficientOfVariation([]float64{300, 40, 560, 300})
+
+	h.Evaluate(bunched(100, 2))
+	regularity := h.Regularity()
+	if len(regularity) != 1 || regularity[0].LineName != "L1" || regularity[0].Trains != 4 {
+		t.Fatal("The regularity of the line should be measured.")
+	}
+	if r := regularity[0]; r.Before != -1 || math.Abs(r.Current-cv) > 1e-9 || r.Target != 300 || r.Timetable {
+		t.Fatalf("Switched off, only the current CV should be measured, got %+v.", r)
+	}
+	if len(sender.take()) != 0 {
+		t.Fatal("Switched off, the strategy should send nothing.")
+	}
+
+	h.SetEnabled(true)
+	h.Evaluate(bunched(100, 2))
+	if r := h.Regularity()[0]; math.Abs(r.Before-cv) > 1e-9 || r.Holds != 2 || r.SpeedCaps != 1 {
+		t.Fatalf("Switched on, the CV before should be kept and the commands counted, got %+v.", r)
+	}
+
+	// Half the smoothing later the average moves halfway to the even spacing
+	h.Evaluate(lineState(250, 3, 0, 300, 600, 900))
+	if r := h.Regularity()[0]; math.Abs(r.Before-cv) > 1e-9 || math.Abs(r.Current-cv/2) > 1e-9 {
+		t.Fatalf("The current CV should be averaged over the smoothing, got %+v.", r)
+	}
+}
+
+func TestHeadwayTarget(t *testing.T) {
+	positions := make([]models.LinePosition, 0)
+	for _, tr := range lineState(0, 1, 0, 300, 600, 900).Trains {
+		positions = append(positions, tr.Position)
+	}
+	headways := models.LineHeadways(positions)
+	platform := func(headway float64) intelligence.PlatformState {
+		return intelligence.PlatformState{Platform: models.Platform{LineID: 1, Forward: true}, Headway: headway}
+	}
+	cases := []struct {
+		name      string
+		platforms []intelligence.PlatformState
+		want      float64
+		timetable bool
+	}{
+		{name: "no timetable", want: 300},
+		{name: "timetable", platforms: []intelligence.PlatformState{platform(350)}, want: 350, timetable: true},
+		{name: "median of the platforms", platforms: []intelligence.PlatformState{platform(500), platform(340), platform(350)}, want: 350, timetable: true},
+		{name: "too many trains", platforms: []intelligence.PlatformState{platform(250)}, want: 300},
+		{name: "longer than holds can stretch", platforms: []intelligence.PlatformState{platform(400)}, want: 300},
+	}
+
+	h, _ := newTestHeadway(true)
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			state := intelligence.State{Platforms: c.platforms}
+			target, timetable := h.target(state, testLine, headways)
+			if target != c.want || timetable != c.timetable {
+				t.Fatalf("The target should be %.0f (timetable %t), got %.0f (%t).", c.want, c.timetable, target, timetable)
+			}
+		})
+	}
+}
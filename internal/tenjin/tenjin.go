@@ -7,9 +7,12 @@ import (
 	"time"
 
 	"github.com/odin-software/metro/control"
+	"github.com/odin-software/metro/internal/models"
 	"github.com/odin-software/metro/internal/newspaper"
 	"github.com/odin-software/metro/internal/tenjin/analysis"
+	"github.com/odin-software/metro/internal/tenjin/intelligence"
 	"github.com/odin-software/metro/internal/tenjin/observation"
+	"github.com/odin-software/metro/internal/tenjin/strategy"
 )
 
 // Tenjin is the central brain that observes and manages the simulation
@@ -17,27 +20,33 @@ type Tenjin struct {
 	eventChannel chan interface{}
 	observation  *observation.Collector
 	analysis     *analysis.MetricsEngine
+	intelligence *intelligence.Engine
 	logger       *analysis.MetricsLogger
 	newspaper    *newspaper.Newspaper
+	predictor    *models.ArrivalPredictor // Predicted arrivals of the trains, nil until set
+	simulation   *Simulation              // What the intelligence layer evaluates, nil until set
+	headway      *strategy.Headway        // Headway regularisation of the lines, nil until the simulation is set
+	clock        Clock                    // Simulated day, the daily metrics and editions follow it
 	ticker       *time.Ticker
+	lastReport   time.Time // When the full metrics were last logged
 	ctx          context.Context
 	cancel       context.CancelFunc
 	wg           sync.WaitGroup
 }
 
-// NewTenjin creates a new Tenjin brain
-func NewTenjin(totalTrains int) (*Tenjin, error) {
+// NewTenjin creates a new Tenjin brain following the simulated days of the clock
+func NewTenjin(totalTrains int, clock Clock) (*Tenjin, error) {
 	// Create event channel with buffer of 500
 	eventChannel := make(chan interface{}, 500)
 
 	// Create observation layer
-	collector := observation.NewCollector(eventChannel)
+	collector := observation.NewCollector(eventChannel, control.DefaultConfig.TenjinEventBuffer)
 
 	// Create schedule adapter for punctuality tracking
 	scheduleAdapter := analysis.NewBasoScheduleAdapter()
 
 	// Create analysis layer
-	metricsEngine := analysis.NewMetricsEngine(totalTrains, scheduleAdapter)
+	metricsEngine := analysis.NewMetricsEngine(totalTrains, scheduleAdapter, clock)
 
 	// Create metrics logger
 	metricsDir := control.DefaultConfig.LogsDirectory + "tenjin/"
@@ -59,8 +68,10 @@ func NewTenjin(totalTrains int) (*Tenjin, error) {
 		eventChannel: eventChannel,
 		observation:  collector,
 		analysis:     metricsEngine,
+		intelligence: intelligence.NewEngine(control.DefaultConfig.Intelligence),
 		logger:       logger,
 		newspaper:    news,
+		clock:        clock,
 		ticker:       time.NewTicker(control.DefaultConfig.TenjinTickRate),
 		ctx:          ctx,
 		cancel:       cancel,
@@ -94,7 +105,7 @@ func (t *Tenjin) Start() {
 	}()
 }
 
-// run is the main processing loop - runs every second
+// run is the main processing loop - runs every TenjinTickRate
 func (t *Tenjin) run() {
 	control.Log("Tenjin: Main loop started")
 
@@ -113,21 +124,23 @@ func (t *Tenjin) run() {
 				t.analysis.ProcessEvents(events)
 			}
 
-			// Get formatted metrics output
-			output := t.analysis.GetFormattedOutput()
+			// Compare the arrival predictions with the actual arrivals
+			if t.predictor != nil {
+				t.analysis.SetPredictionAccuracy(t.predictor.Accuracy())
+			}
 
-			// Log to file
-			if err := t.logger.Log(output); err != nil {
-				control.Log(fmt.Sprintf("Tenjin: Error logging metrics: %v", err))
+			// Evaluate the state and recommend interventions
+			if t.simulation != nil {
+				t.evaluate()
 			}
 
-			// Also print to stdout if configured
-			if control.DefaultConfig.StdLogs {
-				fmt.Print(output)
+			// Log the full metrics every report interval
+			if time.Since(t.lastReport) >= control.DefaultConfig.TenjinReportRate {
+				t.report()
 			}
 
-			// Check if newspaper needs new edition (daily + on-demand)
-			if t.newspaper.NeedsNewEdition() && !t.newspaper.IsGenerating() {
+			// Check if newspaper needs new edition (every simulated day + on-demand)
+			if t.newspaper.NeedsNewEdition(t.clock.GetDay()) && !t.newspaper.IsGenerating() {
 				control.Log("Tenjin: Triggering newspaper generation...")
 
 				// Generate in background goroutine (non-blocking)
@@ -145,6 +158,34 @@ func (t *Tenjin) run() {
 	}
 }
 
+// evaluate runs the intelligence layer on the state of the simulation and logs the
+// new recommendations, then lets the strategies act on the same state
+func (t *Tenjin) evaluate() {
+	state := t.snapshot()
+	for _, r := range t.intelligence.Evaluate(state) {
+		control.Log(fmt.Sprintf("Tenjin: [%s] %s: %s", r.Severity, r.String(), r.Rationale))
+	}
+	t.analysis.SetRecommendations(t.intelligence.Recommendations())
+	t.headway.Evaluate(state)
+	t.analysis.SetRegularity(t.headway.Regularity(), t.headway.Enabled())
+}
+
+// report logs the formatted metrics, and prints them to stdout if configured
+func (t *Tenjin) report() {
+	t.lastReport = time.Now()
+	output := t.analysis.GetFormattedOutput()
+
+	// Log to file
+	if err := t.logger.Log(output); err != nil {
+		control.Log(fmt.Sprintf("Tenjin: Error logging metrics: %v", err))
+	}
+
+	// Also print to stdout if configured
+	if control.DefaultConfig.StdLogs {
+		fmt.Print(output)
+	}
+}
+
 // Stop gracefully shuts down Tenjin
 func (t *Tenjin) Stop() {
 	control.Log("Tenjin: Stopping...")
@@ -174,6 +215,59 @@ func (t *Tenjin) GetMetrics() analysis.Metrics {
 	return t.analysis.GetMetrics()
 }
 
+// SetArrivalPredictor gives Tenjin the predicted arrivals of the trains.
+// Must be called before Start.
+func (t *Tenjin) SetArrivalPredictor(predictor *models.ArrivalPredictor) {
+	t.predictor = predictor
+}
+
+// SetSimulation gives Tenjin the trains, stations and lines it evaluates.
+// Must be called before Start.
+func (t *Tenjin) SetSimulation(simulation Simulation) {
+	t.simulation = &simulation
+	t.headway = strategy.NewHeadway(control.DefaultConfig.Headway, simulation.Commands)
+}
+
+// GetRecommendations returns the interventions Tenjin recommends, most severe first
+func (t *Tenjin) GetRecommendations() []intelligence.Recommendation {
+	return t.intelligence.Recommendations()
+}
+
+// SetHeadwayControl switches the headway regularisation on or off, it does nothing
+// before the simulation is set
+func (t *Tenjin) SetHeadwayControl(enabled bool) {
+	if t.headway == nil {
+		return
+	}
+	t.headway.SetEnabled(enabled)
+	state := "off"
+	if enabled {
+		state = "on"
+	}
+	control.Log("Tenjin: Headway regularisation switched " + state)
+}
+
+// HeadwayControl returns true when Tenjin regularises the headways of the lines
+func (t *Tenjin) HeadwayControl() bool {
+	return t.headway != nil && t.headway.Enabled()
+}
+
+// GetRegularity returns how evenly spaced the trains of every line run
+func (t *Tenjin) GetRegularity() []strategy.Regularity {
+	if t.headway == nil {
+		return nil
+	}
+	return t.headway.Regularity()
+}
+
+// GetPredictedArrivals returns the predicted arrivals at a station, soonest first
+func (t *Tenjin) GetPredictedArrivals(stationID int64) []models.ArrivalPrediction {
+	if t.predictor == nil {
+		return nil
+	}
+	return t.predictor.ForStation(stationID)
+}
+
 // GetNewspaper returns the newspaper instance (for UI access)
 func (t *Tenjin) GetNewspaper() *newspaper.Newspaper {
 	return t.newspaper
@@ -2,7 +2,11 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"math/rand"
+	"os"
+	"runtime/debug"
 	"strconv"
 	"sync"
 	"time"
@@ -15,14 +19,41 @@ import (
 	"github.com/odin-software/metro/internal/clock"
 	"github.com/odin-software/metro/internal/models"
 	"github.com/odin-software/metro/internal/sematick"
+	"github.com/odin-software/metro/internal/simulation"
 	"github.com/odin-software/metro/internal/tenjin"
 )
 
-var StationHashFunction = func(station models.Station) string {
+var StationHashFunction = func(station *models.Station) string {
 	return strconv.FormatInt(station.ID, 10)
 }
 
 func main() {
+	// Measure the simulation without display or Tenjin: `metro bench [minutes] [profile]`.
+	benchMinutes := 0
+	if len(os.Args) > 1 && os.Args[1] == "bench" {
+		benchMinutes = 10
+		if len(os.Args) > 2 {
+			minutes, err := strconv.Atoi(os.Args[2])
+			if err != nil || minutes <= 0 {
+				log.Fatal("Invalid number of simulated minutes: ", os.Args[2])
+			}
+			benchMinutes = minutes
+		}
+		if len(os.Args) > 3 {
+			control.DefaultConfig.Profile = os.Args[3]
+		}
+		control.DefaultConfig.TenjinEnabled = false
+		control.DefaultConfig.StdLogs = false
+	}
+
+	// Apply the runtime profile before anything reads the config.
+	if err := control.ApplyProfile(&control.DefaultConfig); err != nil {
+		log.Fatal(err)
+	}
+	if control.DefaultConfig.MemoryLimit > 0 {
+		debug.SetMemoryLimit(control.DefaultConfig.MemoryLimit)
+	}
+
 	// Setup.
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -32,8 +63,6 @@ func main() {
 		control.DefaultConfig.LoopStartingState,
 	)
 	reflexTick := time.NewTicker(control.DefaultConfig.ReflexDuration)
-	spawnTick := time.NewTicker(control.DefaultConfig.PassengerSpawnRate)
-	defer spawnTick.Stop()
 	control.InitLogger()
 
 	// Initialize database (create and run migrations if needed).
@@ -41,6 +70,69 @@ func main() {
 		log.Fatal("Failed to initialize database:", err)
 	}
 
+	// Validate the network data: `metro validate` only prints the report.
+	if len(os.Args) > 1 && os.Args[1] == "validate" {
+		report, err := data.ValidateNetwork()
+		if err != nil {
+			log.Fatal("Failed to validate network:", err)
+		}
+		fmt.Print(report.String())
+		if report.HasErrors() {
+			os.Exit(1)
+		}
+		return
+	}
+	// Import a demand CSV into the database: `metro import-demand <file>`.
+	if len(os.Args) > 1 && os.Args[1] == "import-demand" {
+		if len(os.Args) < 3 {
+			fmt.Fprintln(os.Stderr, "usage: metro import-demand <file>")
+			os.Exit(2)
+		}
+		count, err := data.ImportDemandFile(os.Args[2])
+		if err != nil {
+			log.Fatal("Failed to import demand:", err)
+		}
+		fmt.Printf("Imported %d OD pairs from %s\n", count, os.Args[2])
+		return
+	}
+	// Print the journey of a passenger: `metro timeline <passenger-id>`.
+	if len(os.Args) > 1 && os.Args[1] == "timeline" {
+		if len(os.Args) < 3 {
+			fmt.Fprintln(os.Stderr, "usage: metro timeline <passenger-id>")
+			os.Exit(2)
+		}
+		timeline, err := baso.NewBaso().GetPassengerTimeline(os.Args[2])
+		if err != nil {
+			log.Fatal("Failed to load timeline:", err)
+		}
+		for _, line := range timeline.Describe() {
+			fmt.Println(line)
+		}
+		return
+	}
+	// Add a special event: `metro add-event <name> <station> <start> <end> <attendance> [day]`.
+	if len(os.Args) > 1 && os.Args[1] == "add-event" {
+		event, err := data.CreateSpecialEvent(os.Args[2:])
+		if err != nil {
+			log.Fatal("Failed to add special event:", err)
+		}
+		fmt.Printf("Added special event %d: %s\n", event.ID, event.Name)
+		return
+	}
+
+	if control.DefaultConfig.ValidateNetworkOnStartup {
+		report, err := data.ValidateNetwork()
+		if err != nil {
+			log.Fatal("Failed to validate network:", err)
+		}
+		for _, issue := range report.Issues {
+			control.Log(fmt.Sprintf("Network %s [%s]: %s", issue.Severity, issue.Check, issue.Message))
+		}
+		if report.HasErrors() {
+			log.Fatal("Network validation failed, run `go run . validate` for the full report")
+		}
+	}
+
 	// Creating the city graph.
 	cityNetwork := models.NewNetwork(StationHashFunction)
 
@@ -51,88 +143,146 @@ func main() {
 	if err != nil {
 		return
 	}
-	data.LoadEdges(&cityNetwork)
+	data.LoadEdges(cityNetwork, stations)
+
+	// Initialize simulation clock (needed for trains), the models read the time from it
+	// so pauses and speed changes apply to waits and journeys too.
+	simulationClock := clock.NewSimulationClock()
+	models.SetTimeSource(simulationClock.Now)
+	controls := clock.NewControls(loopTick)
 
 	// Initialize Tenjin (the brain) if enabled
 	var brain *tenjin.Tenjin
-	var eventChannel chan<- interface{}
+	var brainChannel chan<- interface{}
 	if control.DefaultConfig.TenjinEnabled {
 		// Count trains using baso
 		db := baso.NewBaso()
 		trainsData := db.ListTrainsFull()
 		trainCount := len(trainsData)
 
-		brain, err = tenjin.NewTenjin(trainCount)
+		brain, err = tenjin.NewTenjin(trainCount, simulationClock)
 		if err != nil {
 			log.Fatal("Failed to initialize Tenjin:", err)
 		}
-		eventChannel = brain.GetEventChannel()
+		brainChannel = brain.GetEventChannel()
 		control.Log("Tenjin initialized successfully")
 	}
 
-	// Initialize simulation clock (needed for trains)
-	simulationClock := clock.NewSimulationClock()
+	// The scheduler ticks the simulation in phases, the events of each tick go to Tenjin
+	// at its end. Every random choice comes from the seed, so a run can be repeated.
+	scheduler := simulation.NewScheduler(simulationClock, brainChannel)
+	eventChannel := scheduler.Events()
+	seed := control.DefaultConfig.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	control.Log(fmt.Sprintf("Simulation seed: %d, %s profile", seed, control.DefaultConfig.Profile))
 
 	// Creating the train with lines.
-	trains := data.LoadTrains(stations, lines, &cityNetwork, eventChannel, simulationClock)
-	control.Log("Simulation clock initialized - Starting time: " + simulationClock.GetCurrentTime())
-
-	// Starting the goroutines for the trains.
-	for i := range len(trains) {
-		wg.Add(1)
-		go func(idx int) {
-			defer wg.Done()
-			defer func() {
-				// Recover from panic if channel is closed during shutdown
-				if r := recover(); r != nil {
-					control.Log("Train goroutine recovered from panic during shutdown")
-				}
-			}()
-			sub := loopTick.Subscribe()
-			for range sub {
-				trains[idx].Tick()
-			}
-		}(i)
+	trains := data.LoadTrains(stations, lines, cityNetwork, eventChannel, simulationClock)
+	control.Log("Simulation clock initialized - Starting time: " + simulationClock.GetCurrentDate() + " " + simulationClock.GetCurrentTime())
+
+	// The timetable gives the delays of the trains and the headways passengers expect.
+	timetable := data.LoadTimetable(lines)
+	for i := range trains {
+		trains[i].SetTimetable(timetable)
 	}
 
-	// Start simulation clock updater
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		sub := loopTick.Subscribe()
-		for range sub {
-			simulationClock.Update()
-		}
-	}()
+	// Gather the predicted arrivals of the trains for the countdowns and Tenjin.
+	trainPtrs := make([]*models.Train, len(trains))
+	for i := range trains {
+		trainPtrs[i] = &trains[i]
+		trainPtrs[i].SetRand(rand.New(rand.NewSource(seed + trains[i].ID)))
+	}
+	scheduler.SetEntities(trainPtrs, stations)
+	predictor := models.NewArrivalPredictor(trainPtrs)
+	scheduler.SetPredictor(predictor, int(control.DefaultConfig.PredictionRate/control.DefaultConfig.LoopDuration))
+
+	// Network editor, edits are applied between two ticks by the scheduler, persisted and
+	// picked up by trains, the spawner, the display and Tenjin.
+	editor := models.NewNetworkEditor(cityNetwork, stations, lines, trainPtrs, data.NewBasoNetworkStore())
+	scheduler.SetEditor(editor)
+
+	// Commands for the trains, shared by Tenjin, the operator and external agents.
+	dispatcher := models.NewDispatcher(trainPtrs)
 
 	// Start Tenjin if enabled
 	if control.DefaultConfig.TenjinEnabled && brain != nil {
+		brain.SetArrivalPredictor(predictor)
+		brain.SetSimulation(tenjin.Simulation{
+			Locker:    scheduler,
+			Trains:    trainPtrs,
+			Stations:  editor.Stations,
+			Lines:     editor.Lines,
+			Timetable: timetable,
+			Commands:  dispatcher,
+		})
 		brain.Start()
 		control.Log("Tenjin brain started")
 	}
 
 	// Start passenger spawning
-	data.SpawnPassengers(ctx, &wg, stations, lines, spawnTick, eventChannel)
+	demand := data.LoadDemand()
+	if demand.IsEmpty() {
+		control.Log("No demand data found, passengers will spawn at random stations")
+	}
+	sentiment := models.NewSentimentModel(control.DefaultConfig.Sentiment, timetable)
+	sentiment.DayType = simulationClock.GetDayType
+	fares := models.NewFareModel(control.DefaultConfig.Fares, simulationClock)
+	commuters := data.LoadCommuters(stations, lines, seed)
+	control.Log(fmt.Sprintf("Loaded %d commuters", len(commuters)))
+	specialEvents := data.LoadSpecialEvents(stations)
+	control.Log(fmt.Sprintf("Loaded %d special events", len(specialEvents)))
+	journeyLog := data.NewJourneyLog(simulationClock)
+	spawner := data.NewPassengerSpawner(stations, lines, demand, sentiment, fares, journeyLog, commuters, specialEvents, simulationClock, data.StartRun(seed), rand.New(rand.NewSource(seed)), eventChannel)
+	spawner.SetLimit(control.DefaultConfig.MaxPassengers, scheduler.Passengers)
+	scheduler.SetSpawner(spawner, int(control.DefaultConfig.PassengerSpawnRate/control.DefaultConfig.LoopDuration))
+
+	if benchMinutes > 0 {
+		fmt.Print(scheduler.Benchmark(benchMinutes))
+		return
+	}
+
+	// Start the simulation
+	simulationDone := make(chan struct{})
+	go func() {
+		defer close(simulationDone)
+		scheduler.Run(ctx, loopTick)
+	}()
 
 	// Reflect what's on memory on the DB.
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
 		for range reflexTick.C {
+			scheduler.Lock()
 			data.DumpTrainsData(trains)
-			data.DumpPassengersData(stations, trains)
+			data.DumpPassengersData(editor.Stations(), trains)
+			scheduler.Unlock()
+			data.DumpCommutersData(commuters)
+			journeyLog.Flush()
 		}
 	}()
 
 	// Initialize schedule adapter for UI
 	scheduleAdapter := display.NewBasoScheduleAdapter()
-	game := display.NewGame(trains, stations, lines, brain, simulationClock, scheduleAdapter)
+	game := display.NewGame(trains, stations, lines, editor, brain, simulationClock, scheduleAdapter)
+	game.SetSpecialEvents(specialEvents)
+	game.SetTimelines(journeyLog)
+	game.SetPredictions(predictor)
+	game.SetControls(controls)
+	game.SetSimulation(scheduler)
+	game.SetCommands(dispatcher)
 	ebiten.SetWindowSize(
 		control.DefaultConfig.DisplayScreenWidth*2,
 		control.DefaultConfig.DisplayScreenHeight*2,
 	)
 	ebiten.SetWindowTitle("Metro")
 
+	// The display updates at its own rate, and only draws when something changed
+	ebiten.SetTPS(control.DefaultConfig.RenderTPS)
+	ebiten.SetScreenClearedEveryFrame(!control.DefaultConfig.RenderOnChange)
+
 	// Set monitor if configured (0 = primary, 1+ = other monitors)
 	monitors := ebiten.AppendMonitors(nil)
 	if control.DefaultConfig.DisplayMonitor >= 0 && control.DefaultConfig.DisplayMonitor < len(monitors) {
@@ -143,6 +293,12 @@ func main() {
 		log.Fatal(err)
 	}
 
+	// Cleanup: Stop the simulation first, Tenjin closes the channel of its events
+	cancel()
+	<-simulationDone
+	stats := scheduler.Stats()
+	control.Log(fmt.Sprintf("Simulation ran %d ticks, skipped %d and dropped %d events", stats.Ticks, stats.Skipped, stats.Dropped))
+
 	// Cleanup: Stop Tenjin gracefully
 	if control.DefaultConfig.TenjinEnabled && brain != nil {
 		brain.Stop()
@@ -58,6 +58,9 @@ type Station struct {
 	Y     float64
 	Lines []string
 	Color string
+	// Complex is the name of the interchange when the station is the platform of one
+	// of several lines listed under the same name, empty otherwise
+	Complex string
 }
 
 type Line struct {
@@ -184,8 +187,14 @@ func getCuratedData() ([]Station, []Line) {
 		{"Concepción Bona", 18.4610, -69.9630, "2"},
 	}
 
+	// Stations listed once per line are separate platforms of the same complex
+	listed := make(map[string]int)
+	for _, sd := range stationsData {
+		listed[sd.name]++
+	}
+
 	stations := []Station{}
-	stationIDs := make(map[string]int64)
+	stationIDs := make(map[string]int64) // By "line/name"
 	idCounter := int64(1)
 
 	for _, sd := range stationsData {
@@ -200,9 +209,14 @@ func getCuratedData() ([]Station, []Line) {
 			Lines: []string{"Línea " + sd.line},
 			Color: "#FFFFFF",
 		}
+		if listed[sd.name] > 1 {
+			station.Complex = sd.name
+		}
 
 		stations = append(stations, station)
-		stationIDs[sd.name] = osmID
+		for _, line := range strings.Split(sd.line, ",") {
+			stationIDs[line+"/"+sd.name] = osmID
+		}
 	}
 
 	// Calculate positions with fixed scale
@@ -214,46 +228,46 @@ func getCuratedData() ([]Station, []Line) {
 			Name:  "Línea 1",
 			Color: "#E84B28",
 			Stations: []int64{
-				stationIDs["Mamá Tingó"],
-				stationIDs["Gregorio Urbano Gilbert"],
-				stationIDs["Gregorio Luperón"],
-				stationIDs["José Francisco Peña Gómez"],
-				stationIDs["Hermanas Mirabal"],
-				stationIDs["Máximo Gómez"],
-				stationIDs["Los Taínos"],
-				stationIDs["Pedro Livio Cedeño"],
-				stationIDs["Manuel Arturo Peña Batlle"],
-				stationIDs["Juan Pablo Duarte"],
-				stationIDs["Profesor Juan Bosch"],
-				stationIDs["Casandra Damirón"],
-				stationIDs["Joaquín Balaguer"],
-				stationIDs["Amín Abel Hasbún"],
-				stationIDs["Francisco Alberto Caamaño Deñó"],
-				stationIDs["Centro de los Héroes"],
+				stationIDs["1/Mamá Tingó"],
+				stationIDs["1/Gregorio Urbano Gilbert"],
+				stationIDs["1/Gregorio Luperón"],
+				stationIDs["1/José Francisco Peña Gómez"],
+				stationIDs["1/Hermanas Mirabal"],
+				stationIDs["1/Máximo Gómez"],
+				stationIDs["1/Los Taínos"],
+				stationIDs["1/Pedro Livio Cedeño"],
+				stationIDs["1/Manuel Arturo Peña Batlle"],
+				stationIDs["1/Juan Pablo Duarte"],
+				stationIDs["1/Profesor Juan Bosch"],
+				stationIDs["1/Casandra Damirón"],
+				stationIDs["1/Joaquín Balaguer"],
+				stationIDs["1/Amín Abel Hasbún"],
+				stationIDs["1/Francisco Alberto Caamaño Deñó"],
+				stationIDs["1/Centro de los Héroes"],
 			},
 		},
 		{
 			Name:  "Línea 2",
 			Color: "#0066B3",
 			Stations: []int64{
-				stationIDs["María Montez"],
-				stationIDs["Pedro Francisco Bonó"],
-				stationIDs["Francisco Gregorio Billini"],
-				stationIDs["Ulises Francisco Espaillat"],
-				stationIDs["Pedro Mir"],
-				stationIDs["Freddy Beras-Goico"],
-				stationIDs["Juan Ulises García Saleta"],
-				stationIDs["Juan Pablo Duarte"],
-				stationIDs["Coronel Rafael Tomás Fernández Domínguez"],
-				stationIDs["Mauricio Báez"],
-				stationIDs["Ramón Cáceres"],
-				stationIDs["Horacio Vásquez"],
-				stationIDs["Manuel de Jesús Galván"],
-				stationIDs["Eduardo Brito"],
-				stationIDs["Ercilia Pepín"],
-				stationIDs["Rosa Duarte"],
-				stationIDs["Trina de Moya de Vásquez"],
-				stationIDs["Concepción Bona"],
+				stationIDs["2/María Montez"],
+				stationIDs["2/Pedro Francisco Bonó"],
+				stationIDs["2/Francisco Gregorio Billini"],
+				stationIDs["2/Ulises Francisco Espaillat"],
+				stationIDs["2/Pedro Mir"],
+				stationIDs["2/Freddy Beras-Goico"],
+				stationIDs["2/Juan Ulises García Saleta"],
+				stationIDs["2/Juan Pablo Duarte"],
+				stationIDs["2/Coronel Rafael Tomás Fernández Domínguez"],
+				stationIDs["2/Mauricio Báez"],
+				stationIDs["2/Ramón Cáceres"],
+				stationIDs["2/Horacio Vásquez"],
+				stationIDs["2/Manuel de Jesús Galván"],
+				stationIDs["2/Eduardo Brito"],
+				stationIDs["2/Ercilia Pepín"],
+				stationIDs["2/Rosa Duarte"],
+				stationIDs["2/Trina de Moya de Vásquez"],
+				stationIDs["2/Concepción Bona"],
 			},
 		},
 	}
@@ -696,14 +710,33 @@ func generateSQL(filename string, stations []Station, lines []Line) error {
 	// Generate station IDs starting from a high number to avoid conflicts
 	stationIDStart := 1000
 	lineIDStart := 100
+	complexIDStart := 100
 	stationIDMap := make(map[int64]int)
 
+	// Complexes group the platforms of the lines of an interchange
+	w("-- Santo Domingo Metro Station Complexes\n")
+	complexIDMap := make(map[string]int)
+	for _, s := range stations {
+		if _, ok := complexIDMap[s.Complex]; ok || s.Complex == "" {
+			continue
+		}
+		dbID := complexIDStart + len(complexIDMap)
+		complexIDMap[s.Complex] = dbID
+		w(fmt.Sprintf("INSERT OR IGNORE INTO station_complex (id, name) VALUES (%d, '%s');\n",
+			dbID, escapeSQLString(s.Complex)))
+	}
+	w("\n")
+
 	w("-- Santo Domingo Metro Stations\n")
 	for i, s := range stations {
 		dbID := stationIDStart + i
 		stationIDMap[s.OSMID] = dbID
-		w(fmt.Sprintf("INSERT OR IGNORE INTO station (id, name, x, y, z, color) VALUES (%d, '%s', %.2f, %.2f, 0.0, '%s');\n",
-			dbID, escapeSQLString(s.Name), s.X, s.Y, s.Color))
+		complexID := "NULL"
+		if id, ok := complexIDMap[s.Complex]; ok {
+			complexID = fmt.Sprintf("%d", id)
+		}
+		w(fmt.Sprintf("INSERT OR IGNORE INTO station (id, name, x, y, z, color, complex_id) VALUES (%d, '%s', %.2f, %.2f, 0.0, '%s', %s);\n",
+			dbID, escapeSQLString(s.Name), s.X, s.Y, s.Color, complexID))
 	}
 	w("\n")
 
@@ -761,6 +794,7 @@ func generateSQL(filename string, stations []Station, lines []Line) error {
 	w(fmt.Sprintf("DELETE FROM line WHERE id >= %d;\n", lineIDStart))
 	w(fmt.Sprintf("DELETE FROM station_line WHERE id >= %d;\n", 1000))
 	w(fmt.Sprintf("DELETE FROM edge WHERE id >= %d;\n", 1000))
+	w(fmt.Sprintf("DELETE FROM station_complex WHERE id >= %d;\n", complexIDStart))
 	w("-- +goose StatementEnd\n")
 
 	return nil